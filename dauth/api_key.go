@@ -0,0 +1,395 @@
+package dauth
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dhaifley/dlib"
+	"github.com/dhaifley/dlib/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+// APIKeyPrefix begins every API key string, so that API keys can be told
+// apart from tokens.
+const APIKeyPrefix = "dk_"
+
+// APIKey values represent a single long-lived API key. Each key is owned by
+// a user, which may be a service account: a user with no password, which
+// therefore can not log in. Only the SHA-256 hash of the key is stored in
+// Key; Prefix is the visible start of the key, which identifies it in
+// listings. Scope limits the key to a subset of its owner's permissions,
+// as "service:name" permission claims which may use wildcards. An empty
+// scope allows all of the owner's permissions. A key belongs to the tenant
+// of its owner.
+type APIKey struct {
+	ID       int64      `json:"id,omitempty" bson:"_id"`
+	Name     string     `json:"name,omitempty" bson:"name"`
+	Prefix   string     `json:"prefix,omitempty" bson:"prefix"`
+	Key      string     `json:"key,omitempty" bson:"key" secret:"true"`
+	UserID   int64      `json:"user_id,omitempty" bson:"user_id"`
+	Scope    []string   `json:"scope,omitempty" bson:"scope,omitempty"`
+	Created  *time.Time `json:"created,omitempty" bson:"created,omitempty"`
+	Expires  *time.Time `json:"expires,omitempty" bson:"expires,omitempty"`
+	TenantID int64      `json:"tenant_id,omitempty" bson:"tenant_id"`
+}
+
+// APIKeyRow values represent a single row in the api_key table. The scope
+// is stored as a space separated list.
+type APIKeyRow struct {
+	ID       int64
+	Name     string
+	Prefix   string
+	Key      string
+	UserID   int64
+	Scope    string
+	Created  dlib.NullTime
+	Expires  dlib.NullTime
+	TenantID int64
+}
+
+// APIKeyFind values are used to find API key records in the database.
+type APIKeyFind struct {
+	ID       *int64  `json:"id,omitempty"`
+	Name     *string `json:"name,omitempty"`
+	Prefix   *string `json:"prefix,omitempty"`
+	Key      *string `json:"key,omitempty" secret:"true"`
+	UserID   *int64  `json:"user_id,omitempty"`
+	TenantID *int64  `json:"tenant_id,omitempty"`
+}
+
+// NewAPIKey initializes and returns a pointer to a new API key value.
+func NewAPIKey(id int64, name, prefix, key string, userID int64, scope []string,
+	created, expires *time.Time) *APIKey {
+	return &APIKey{
+		ID:      id,
+		Name:    name,
+		Prefix:  prefix,
+		Key:     key,
+		UserID:  userID,
+		Scope:   scope,
+		Created: created,
+		Expires: expires,
+	}
+}
+
+// IsAPIKey tests whether a token string is an API key.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// Expired tests whether the key has expired at a time.
+func (k *APIKey) Expired(now time.Time) bool {
+	return k.Expires != nil && !k.Expires.After(now)
+}
+
+// Allows tests whether the scope of the key includes a permission.
+func (k *APIKey) Allows(p *Perm) bool {
+	return scopeAllows(k.Scope, p)
+}
+
+// Equals tests for deep equality between API key values.
+func (k *APIKey) Equals(b *APIKey) bool {
+	switch {
+	case k == nil || b == nil:
+		return false
+	case k == b:
+		return true
+	case k.ID != b.ID || k.Name != b.Name || k.Prefix != b.Prefix:
+		return false
+	case k.Key != b.Key || k.UserID != b.UserID || k.TenantID != b.TenantID:
+		return false
+	case strings.Join(k.Scope, " ") != strings.Join(b.Scope, " "):
+		return false
+	case (k.Created == nil) != (b.Created == nil):
+		return false
+	case k.Created != nil && !k.Created.Equal(*b.Created):
+		return false
+	case (k.Expires == nil) != (b.Expires == nil):
+		return false
+	case k.Expires != nil && !k.Expires.Equal(*b.Expires):
+		return false
+	default:
+		return true
+	}
+}
+
+// Copy returns an exact copy of the value.
+func (k *APIKey) Copy() APIKey {
+	b := *k
+	if k.Scope != nil {
+		b.Scope = append([]string{}, k.Scope...)
+	}
+
+	if k.Created != nil {
+		d := *k.Created
+		b.Created = &d
+	}
+
+	if k.Expires != nil {
+		d := *k.Expires
+		b.Expires = &d
+	}
+
+	return b
+}
+
+// String formats an API key value as a JSON format string. The key is
+// redacted.
+func (k *APIKey) String() string {
+	b := k.Copy()
+	b.Key = ""
+	str, err := json.Marshal(&b)
+	if err != nil {
+		return ""
+	}
+
+	return string(str)
+}
+
+// FromRequest populates this value from a protobuf request.
+func (k *APIKey) FromRequest(req *ptypes.APIKeyRequest) error {
+	k.ID = req.ID
+	k.Name = req.Name
+	k.Prefix = req.Prefix
+	k.UserID = req.UserID
+	k.Scope = req.Scope
+	k.TenantID = req.TenantID
+	k.Expires = nil
+	if req.Expires != nil {
+		tt := time.Unix(req.Expires.Seconds, 0)
+		k.Expires = &tt
+	}
+
+	return nil
+}
+
+// ToRequest returns a protobuf request created from this value.
+func (k *APIKey) ToRequest() ptypes.APIKeyRequest {
+	req := ptypes.APIKeyRequest{
+		ID:       k.ID,
+		Name:     k.Name,
+		Prefix:   k.Prefix,
+		UserID:   k.UserID,
+		Scope:    k.Scope,
+		TenantID: k.TenantID,
+	}
+
+	if k.Expires != nil {
+		req.Expires = &timestamp.Timestamp{Seconds: k.Expires.Unix()}
+	}
+
+	return req
+}
+
+// FromResponse populates this value from a protobuf response.
+func (k *APIKey) FromResponse(res *ptypes.APIKeyResponse) error {
+	k.ID = res.ID
+	k.Name = res.Name
+	k.Prefix = res.Prefix
+	k.Key = res.Key
+	k.UserID = res.UserID
+	k.Scope = res.Scope
+	k.TenantID = res.TenantID
+	k.Created = nil
+	if res.Created != nil {
+		tt := time.Unix(res.Created.Seconds, 0)
+		k.Created = &tt
+	}
+
+	k.Expires = nil
+	if res.Expires != nil {
+		tt := time.Unix(res.Expires.Seconds, 0)
+		k.Expires = &tt
+	}
+
+	return nil
+}
+
+// ToResponse returns a protobuf response created from this value.
+func (k *APIKey) ToResponse() ptypes.APIKeyResponse {
+	res := ptypes.APIKeyResponse{
+		ID:       k.ID,
+		Name:     k.Name,
+		Prefix:   k.Prefix,
+		Key:      k.Key,
+		UserID:   k.UserID,
+		Scope:    k.Scope,
+		TenantID: k.TenantID,
+	}
+
+	if k.Created != nil {
+		res.Created = &timestamp.Timestamp{Seconds: k.Created.Unix()}
+	}
+
+	if k.Expires != nil {
+		res.Expires = &timestamp.Timestamp{Seconds: k.Expires.Unix()}
+	}
+
+	return res
+}
+
+// FromQueryValues populates this value from a query string map. The scope
+// may be given as repeated scope values.
+func (k *APIKey) FromQueryValues(vals url.Values) error {
+	var err error
+	k.ID = 0
+	if vals.Get("id") != "" {
+		k.ID, err = strconv.ParseInt(vals.Get("id"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	k.Name = vals.Get("name")
+	k.Prefix = vals.Get("prefix")
+	k.UserID = 0
+	if vals.Get("user_id") != "" {
+		k.UserID, err = strconv.ParseInt(vals.Get("user_id"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	k.Scope = vals["scope"]
+	k.TenantID = 0
+	if vals.Get("tenant_id") != "" {
+		k.TenantID, err = strconv.ParseInt(vals.Get("tenant_id"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	k.Expires = nil
+	if vals.Get("expires") != "" {
+		pt, err := time.ParseInLocation("2006-01-02T15:04:05-0700",
+			vals.Get("expires"), time.Local)
+		if err != nil {
+			return err
+		}
+
+		k.Expires = &pt
+	}
+
+	return nil
+}
+
+// FromAPIKey populates a row value from an API key value.
+func (r *APIKeyRow) FromAPIKey(k *APIKey) error {
+	r.ID = k.ID
+	r.Name = k.Name
+	r.Prefix = k.Prefix
+	r.Key = k.Key
+	r.UserID = k.UserID
+	r.Scope = strings.Join(k.Scope, " ")
+	r.TenantID = k.TenantID
+	r.Created = dlib.NullTime{}
+	if k.Created != nil {
+		r.Created = dlib.NullTime{Valid: true, Time: *k.Created}
+	}
+
+	r.Expires = dlib.NullTime{}
+	if k.Expires != nil {
+		r.Expires = dlib.NullTime{Valid: true, Time: *k.Expires}
+	}
+
+	return nil
+}
+
+// ToAPIKey returns a value created from this row value.
+func (r *APIKeyRow) ToAPIKey() APIKey {
+	k := APIKey{
+		ID:       r.ID,
+		Name:     r.Name,
+		Prefix:   r.Prefix,
+		Key:      r.Key,
+		UserID:   r.UserID,
+		Scope:    strings.Fields(r.Scope),
+		TenantID: r.TenantID,
+	}
+
+	if len(k.Scope) == 0 {
+		k.Scope = nil
+	}
+
+	if r.Created.Valid {
+		tt := r.Created.Time
+		k.Created = &tt
+	}
+
+	if r.Expires.Valid {
+		tt := r.Expires.Time
+		k.Expires = &tt
+	}
+
+	return k
+}
+
+// FromAPIKey populates an API key find value from an API key value.
+func (f *APIKeyFind) FromAPIKey(k *APIKey) error {
+	f.ID = nil
+	if k.ID != 0 {
+		f.ID = &k.ID
+	}
+
+	f.Name = nil
+	if k.Name != "" {
+		f.Name = &k.Name
+	}
+
+	f.Prefix = nil
+	if k.Prefix != "" {
+		f.Prefix = &k.Prefix
+	}
+
+	f.Key = nil
+	if k.Key != "" {
+		f.Key = &k.Key
+	}
+
+	f.UserID = nil
+	if k.UserID != 0 {
+		f.UserID = &k.UserID
+	}
+
+	f.TenantID = nil
+	if k.TenantID != 0 {
+		f.TenantID = &k.TenantID
+	}
+
+	return nil
+}
+
+// FromAPIKeyRequest populates an API key find value from an API key
+// protobuf request.
+func (f *APIKeyFind) FromAPIKeyRequest(r *ptypes.APIKeyRequest) error {
+	f.ID = nil
+	if r.ID != 0 {
+		f.ID = &r.ID
+	}
+
+	f.Name = nil
+	if r.Name != "" {
+		f.Name = &r.Name
+	}
+
+	f.Prefix = nil
+	if r.Prefix != "" {
+		f.Prefix = &r.Prefix
+	}
+
+	f.Key = nil
+	f.UserID = nil
+	if r.UserID != 0 {
+		f.UserID = &r.UserID
+	}
+
+	f.TenantID = nil
+	if r.TenantID != 0 {
+		f.TenantID = &r.TenantID
+	}
+
+	return nil
+}
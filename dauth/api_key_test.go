@@ -0,0 +1,188 @@
+package dauth
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dhaifley/dlib/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+func TestIsAPIKey(t *testing.T) {
+	cases := []struct {
+		token    string
+		expected bool
+	}{
+		{"dk_0123abcd_secret", true},
+		{"test", false},
+		{"a.b.c", false},
+	}
+
+	for _, c := range cases {
+		if result := IsAPIKey(c.token); result != c.expected {
+			t.Errorf("Expected bool: %v, got: %v", c.expected, result)
+		}
+	}
+}
+
+func TestAPIKeyAllows(t *testing.T) {
+	cases := []struct {
+		scope    []string
+		perm     *Perm
+		expected bool
+	}{
+		{nil, NewPerm(0, "test", "test"), true},
+		{[]string{"test:test"}, NewPerm(0, "test", "test"), true},
+		{[]string{"test:test"}, NewPerm(0, "test", "other"), false},
+		{[]string{"other:*", "test:*"}, NewPerm(0, "test", "other"), true},
+		{[]string{"invalid"}, NewPerm(0, "test", "test"), false},
+	}
+
+	for _, c := range cases {
+		k := APIKey{Scope: c.scope}
+		if result := k.Allows(c.perm); result != c.expected {
+			t.Errorf("Expected bool for %v: %v, got: %v", c.scope, c.expected, result)
+		}
+	}
+}
+
+func TestAPIKeyExpired(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	cases := []struct {
+		expires  *time.Time
+		expected bool
+	}{
+		{nil, false},
+		{&past, true},
+	}
+
+	for _, c := range cases {
+		k := APIKey{Expires: c.expires}
+		if result := k.Expired(now); result != c.expected {
+			t.Errorf("Expected bool: %v, got: %v", c.expected, result)
+		}
+	}
+}
+
+func TestAPIKeyEquals(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		a        *APIKey
+		b        *APIKey
+		expected bool
+	}{
+		{
+			a:        NewAPIKey(1, "test", "dk_a", "hash", 1, []string{"a:b"}, &now, nil),
+			b:        NewAPIKey(1, "test", "dk_a", "hash", 1, []string{"a:b"}, &now, nil),
+			expected: true,
+		},
+		{
+			a:        NewAPIKey(1, "test", "dk_a", "hash", 1, []string{"a:b"}, &now, nil),
+			b:        NewAPIKey(1, "test", "dk_a", "hash", 1, nil, &now, nil),
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		result := c.a.Equals(c.b)
+		if result != c.expected {
+			t.Errorf("Expected bool: %v, got: %v", c.expected, result)
+		}
+	}
+}
+
+func TestAPIKeyCopy(t *testing.T) {
+	a := NewAPIKey(1, "test", "dk_a", "hash", 1, []string{"a:b"}, nil, nil)
+	result := a.Copy()
+	if !result.Equals(a) {
+		t.Errorf("Expected API key: %v, got: %v", a, result)
+	}
+
+	result.Scope[0] = "c:d"
+	if a.Scope[0] != "a:b" {
+		t.Errorf("Expected copied scope, got: %v", a.Scope)
+	}
+}
+
+func TestAPIKeyString(t *testing.T) {
+	a := NewAPIKey(1, "test", "dk_a", "hash", 1, nil, nil, nil)
+	expected := `{"id":1,"name":"test","prefix":"dk_a","user_id":1}`
+	if result := a.String(); result != expected {
+		t.Errorf("Expected string: %v, got: %v", expected, result)
+	}
+}
+
+func TestAPIKeyFromRequest(t *testing.T) {
+	req := ptypes.APIKeyRequest{
+		ID:      1,
+		Name:    "test",
+		UserID:  2,
+		Scope:   []string{"a:b"},
+		Expires: &timestamp.Timestamp{Seconds: 1514764800},
+	}
+
+	dv := APIKey{}
+	if err := dv.FromRequest(&req); err != nil {
+		t.Error(err)
+	}
+
+	if dv.UserID != 2 || len(dv.Scope) != 1 || dv.Expires.Unix() != 1514764800 {
+		t.Errorf("Unexpected API key: %v", dv)
+	}
+}
+
+func TestAPIKeyToResponse(t *testing.T) {
+	now := time.Now()
+	a := NewAPIKey(1, "test", "dk_a", "key", 1, []string{"a:b"}, &now, nil)
+	res := a.ToResponse()
+	if res.Key != "key" || res.Created == nil || res.Expires != nil {
+		t.Errorf("Unexpected response: %v", res)
+	}
+}
+
+func TestAPIKeyFromQueryValues(t *testing.T) {
+	vals := url.Values{}
+	vals.Set("name", "test")
+	vals.Set("user_id", "2")
+	vals.Add("scope", "a:b")
+	vals.Add("scope", "c:d")
+	dv := APIKey{}
+	if err := dv.FromQueryValues(vals); err != nil {
+		t.Error(err)
+	}
+
+	if dv.UserID != 2 || len(dv.Scope) != 2 {
+		t.Errorf("Unexpected API key: %v", dv)
+	}
+}
+
+func TestAPIKeyRow(t *testing.T) {
+	now := time.Now()
+	a := NewAPIKey(1, "test", "dk_a", "hash", 1, []string{"a:b", "c:d"}, &now, nil)
+	r := APIKeyRow{}
+	if err := r.FromAPIKey(a); err != nil {
+		t.Error(err)
+	}
+
+	if r.Scope != "a:b c:d" {
+		t.Errorf("Scope expected: a:b c:d, got: %v", r.Scope)
+	}
+
+	result := r.ToAPIKey()
+	if !result.Equals(a) {
+		t.Errorf("Expected API key: %v, got: %v", a, result)
+	}
+}
+
+func TestAPIKeyFindFromAPIKeyRequest(t *testing.T) {
+	f := APIKeyFind{}
+	if err := f.FromAPIKeyRequest(&ptypes.APIKeyRequest{Prefix: "dk_a"}); err != nil {
+		t.Error(err)
+	}
+
+	if f.Prefix == nil || *f.Prefix != "dk_a" || f.Key != nil || f.UserID != nil {
+		t.Errorf("Unexpected find: %v", f)
+	}
+}
@@ -0,0 +1,366 @@
+package dauth
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dhaifley/dlib"
+	"github.com/dhaifley/dlib/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Audit actions recorded by the auth server. Permission changes include
+// changes to roles and to the assignment of permissions and roles.
+const (
+	AuditLogin         = "login"
+	AuditLogout        = "logout"
+	AuditAuthFailed    = "auth_failed"
+	AuditUserSave      = "user_save"
+	AuditUserDelete    = "user_delete"
+	AuditPermSave      = "perm_save"
+	AuditPermDelete    = "perm_delete"
+	AuditImpersonate   = "impersonate"
+	AuditUserProvision = "user_provision"
+	AuditTokenSave     = "token_save"
+	AuditTokenDelete   = "token_delete"
+	AuditTokenRefresh  = "token_refresh"
+	AuditTokenScope    = "token_scope"
+	AuditAPIKeyCreate  = "api_key_create"
+	AuditAPIKeyRevoke  = "api_key_revoke"
+	AuditUnlock        = "unlock"
+	AuditMFAEnroll     = "mfa_enroll"
+	AuditMFAConfirm    = "mfa_confirm"
+	AuditMFADisable    = "mfa_disable"
+)
+
+// Audit outcomes. A login which requires a second factor is recorded with
+// the challenge outcome when the challenge is issued.
+const (
+	AuditSuccess   = "success"
+	AuditFailure   = "failure"
+	AuditChallenge = "challenge"
+)
+
+// AuditEvent values represent a single entry in the audit trail. The actor
+// is the user who performed the action, and the target names the record
+// acted on as "kind:id", or the kind alone where a change applies to all
+// records matching the criteria in Detail. Detail also holds the error of
+// a failed action. If the actor was impersonated, the impersonator is the
+// user who was acting as them.
+type AuditEvent struct {
+	ID             string    `json:"id,omitempty" bson:"-"`
+	ActorID        int64     `json:"actor_id,omitempty" bson:"actor_id"`
+	Actor          string    `json:"actor,omitempty" bson:"actor,omitempty"`
+	TenantID       int64     `json:"tenant_id,omitempty" bson:"tenant_id"`
+	Action         string    `json:"action,omitempty" bson:"action"`
+	Target         string    `json:"target,omitempty" bson:"target,omitempty"`
+	Outcome        string    `json:"outcome,omitempty" bson:"outcome"`
+	Detail         string    `json:"detail,omitempty" bson:"detail,omitempty"`
+	Addr           string    `json:"addr,omitempty" bson:"addr,omitempty"`
+	Time           time.Time `json:"time" bson:"time"`
+	ImpersonatorID int64     `json:"impersonator_id,omitempty" bson:"impersonator_id,omitempty"`
+	Impersonator   string    `json:"impersonator,omitempty" bson:"impersonator,omitempty"`
+}
+
+// AuditFind values are used to find audit events. Start and End limit the
+// time of events, inclusively. Limit, if greater than zero, is the maximum
+// number of events returned, most recent first.
+type AuditFind struct {
+	ActorID        *int64     `json:"actor_id,omitempty"`
+	Actor          *string    `json:"actor,omitempty"`
+	TenantID       *int64     `json:"tenant_id,omitempty"`
+	Action         *string    `json:"action,omitempty"`
+	Start          *time.Time `json:"start,omitempty"`
+	End            *time.Time `json:"end,omitempty"`
+	Limit          int        `json:"limit,omitempty"`
+	ImpersonatorID *int64     `json:"impersonator_id,omitempty"`
+}
+
+// AuditTarget returns the target string for a record of a kind.
+func AuditTarget(kind string, id int64) string {
+	return kind + ":" + strconv.FormatInt(id, 10)
+}
+
+// String formats an audit event value as a JSON format string.
+func (e *AuditEvent) String() string {
+	str, err := json.Marshal(e)
+	if err != nil {
+		return ""
+	}
+
+	return string(str)
+}
+
+// ToResponse returns a protobuf response created from this value.
+func (e *AuditEvent) ToResponse() ptypes.AuditResponse {
+	return ptypes.AuditResponse{
+		ID:             e.ID,
+		ActorID:        e.ActorID,
+		Actor:          e.Actor,
+		TenantID:       e.TenantID,
+		Action:         e.Action,
+		Target:         e.Target,
+		Outcome:        e.Outcome,
+		Detail:         e.Detail,
+		Addr:           e.Addr,
+		Time:           &timestamp.Timestamp{Seconds: e.Time.Unix()},
+		ImpersonatorID: e.ImpersonatorID,
+		Impersonator:   e.Impersonator,
+	}
+}
+
+// FromAuditRequest populates an audit find value from an audit protobuf
+// request.
+func (f *AuditFind) FromAuditRequest(r *ptypes.AuditRequest) error {
+	f.ActorID = nil
+	if r.ActorID != 0 {
+		f.ActorID = &r.ActorID
+	}
+
+	f.Actor = nil
+	if r.Actor != "" {
+		f.Actor = &r.Actor
+	}
+
+	f.TenantID = nil
+	if r.TenantID != 0 {
+		f.TenantID = &r.TenantID
+	}
+
+	f.Action = nil
+	if r.Action != "" {
+		f.Action = &r.Action
+	}
+
+	f.Start = nil
+	if r.Start != nil {
+		dt := time.Unix(r.Start.Seconds, 0)
+		f.Start = &dt
+	}
+
+	f.End = nil
+	if r.End != nil {
+		dt := time.Unix(r.End.Seconds, 0)
+		f.End = &dt
+	}
+
+	f.Limit = int(r.Limit)
+	f.ImpersonatorID = nil
+	if r.ImpersonatorID != 0 {
+		f.ImpersonatorID = &r.ImpersonatorID
+	}
+
+	return nil
+}
+
+// filter builds a log query filter from an audit find value.
+func (f *AuditFind) filter() bson.M {
+	q := bson.M{"entry.action": bson.M{"$exists": true}}
+	if f.ActorID != nil {
+		q["entry.actor_id"] = *f.ActorID
+	}
+
+	if f.Actor != nil {
+		q["entry.actor"] = *f.Actor
+	}
+
+	if f.TenantID != nil {
+		q["entry.tenant_id"] = *f.TenantID
+	}
+
+	if f.Action != nil {
+		q["entry.action"] = *f.Action
+	}
+
+	if f.ImpersonatorID != nil {
+		q["entry.impersonator_id"] = *f.ImpersonatorID
+	}
+
+	ts := bson.M{}
+	if f.Start != nil {
+		ts["$gte"] = *f.Start
+	}
+
+	if f.End != nil {
+		ts["$lte"] = *f.End
+	}
+
+	if len(ts) > 0 {
+		q["ts"] = ts
+	}
+
+	return q
+}
+
+// match tests whether an audit event satisfies an audit find value.
+func (f *AuditFind) match(e *AuditEvent) bool {
+	switch {
+	case f.ActorID != nil && *f.ActorID != e.ActorID:
+		return false
+	case f.Actor != nil && *f.Actor != e.Actor:
+		return false
+	case f.TenantID != nil && *f.TenantID != e.TenantID:
+		return false
+	case f.Action != nil && *f.Action != e.Action:
+		return false
+	case f.ImpersonatorID != nil && *f.ImpersonatorID != e.ImpersonatorID:
+		return false
+	case f.Start != nil && e.Time.Before(*f.Start):
+		return false
+	case f.End != nil && e.Time.After(*f.End):
+		return false
+	default:
+		return true
+	}
+}
+
+// DefaultAuditQueueSize is the default number of audit events which may be
+// waiting to be written by an auditor.
+const DefaultAuditQueueSize = 1024
+
+// ErrAuditQueueFull is passed to the error handler of an auditor when an
+// event is dropped because its queue is full.
+var ErrAuditQueueFull = &dlib.Error{Code: 503, Msg: "audit queue full"}
+
+// Auditor values write audit events to, and find them in, a log. Errors
+// writing events are passed to OnError, if it is set, so that a failure to
+// audit does not fail the action audited. Events may be written directly
+// by Record, or queued by Enqueue to be written in the background, so that
+// actions do not wait on the log. At most QueueSize events wait to be
+// written, and events queued while the queue is full are dropped.
+type Auditor struct {
+	Logs      dlib.LogAccessor
+	OnError   func(err error)
+	QueueSize int
+	once      sync.Once
+	queue     chan *AuditEvent
+	pending   sync.WaitGroup
+}
+
+// NewAuditor initializes and returns a pointer to a new auditor value
+// which writes events to a log.
+func NewAuditor(la dlib.LogAccessor) *Auditor {
+	return &Auditor{Logs: la, QueueSize: DefaultAuditQueueSize}
+}
+
+// Enqueue queues an audit event to be written to the log in the background.
+// Events with no time are given the current time when they are queued. If
+// the queue is full, the event is dropped and ErrAuditQueueFull is passed to
+// OnError.
+func (a *Auditor) Enqueue(e *AuditEvent) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	a.once.Do(func() {
+		n := a.QueueSize
+		if n <= 0 {
+			n = DefaultAuditQueueSize
+		}
+
+		a.queue = make(chan *AuditEvent, n)
+		go a.write()
+	})
+
+	a.pending.Add(1)
+	select {
+	case a.queue <- e:
+	default:
+		a.pending.Done()
+		if a.OnError != nil {
+			a.OnError(ErrAuditQueueFull)
+		}
+	}
+}
+
+// write records the queued events until the auditor is discarded.
+func (a *Auditor) write() {
+	for e := range a.queue {
+		a.Record(e)
+		a.pending.Done()
+	}
+}
+
+// Flush waits until all queued events have been written. It is intended for
+// use during shutdown, once no more events are being queued.
+func (a *Auditor) Flush() {
+	a.pending.Wait()
+}
+
+// Record writes an audit event to the log. Events with no time are given
+// the current time.
+func (a *Auditor) Record(e *AuditEvent) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	ts := e.Time
+	var err error
+	for r := range a.Logs.SaveLog(&dlib.Log{TS: &ts, Entry: e}) {
+		if r.Err != nil {
+			err = r.Err
+		}
+	}
+
+	if err != nil && a.OnError != nil {
+		a.OnError(err)
+	}
+
+	return err
+}
+
+// auditEvent returns the audit event held by a log entry, which may have
+// been decoded from the database as a generic document.
+func auditEvent(l *dlib.Log) (*AuditEvent, bool) {
+	e := AuditEvent{}
+	switch v := l.Entry.(type) {
+	case *AuditEvent:
+		e = *v
+	case AuditEvent:
+		e = v
+	default:
+		b, err := bson.Marshal(v)
+		if err != nil || bson.Unmarshal(b, &e) != nil || e.Action == "" {
+			return nil, false
+		}
+	}
+
+	if l.ID.Valid() {
+		e.ID = l.ID.Hex()
+	}
+
+	return &e, true
+}
+
+// Find finds audit events in the log, most recent first.
+// It returns the results of the operation in a Result channel.
+func (a *Auditor) Find(f *AuditFind) <-chan dlib.Result {
+	c := make(chan dlib.Result, 256)
+
+	go func() {
+		defer close(c)
+		n := 0
+		for r := range a.Logs.GetLogs(f.filter(), f.Limit) {
+			if r.Err != nil {
+				if e, ok := r.Err.(*dlib.Error); !ok || e.Code != 404 {
+					c <- dlib.Result{Err: r.Err}
+				}
+
+				continue
+			}
+
+			e, ok := auditEvent(r.Val.(*dlib.Log))
+			if !ok || !f.match(e) || (f.Limit > 0 && n >= f.Limit) {
+				continue
+			}
+
+			c <- dlib.Result{Val: e}
+			n++
+		}
+	}()
+
+	return c
+}
@@ -0,0 +1,224 @@
+package dauth
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dhaifley/dlib"
+	"github.com/dhaifley/dlib/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type FakeLogAccessor struct {
+	mu    sync.Mutex
+	logs  []*dlib.Log
+	err   error
+	block chan struct{}
+}
+
+func (fk *FakeLogAccessor) GetLogs(filter interface{}, limit int) <-chan dlib.Result {
+	fk.mu.Lock()
+	defer fk.mu.Unlock()
+	c := make(chan dlib.Result, len(fk.logs)+1)
+	if len(fk.logs) == 0 {
+		c <- dlib.Result{Err: dlib.NewError(404, "Resource not found")}
+	}
+
+	for i := len(fk.logs) - 1; i >= 0; i-- {
+		c <- dlib.Result{Val: fk.logs[i]}
+	}
+
+	close(c)
+	return c
+}
+
+func (fk *FakeLogAccessor) GetLogByID(id bson.ObjectId) <-chan dlib.Result {
+	return errorResult(dlib.NewError(404, "Resource not found"))
+}
+
+func (fk *FakeLogAccessor) SaveLog(l *dlib.Log) <-chan dlib.Result {
+	if fk.block != nil {
+		<-fk.block
+	}
+
+	if fk.err != nil {
+		return errorResult(fk.err)
+	}
+
+	fk.mu.Lock()
+	defer fk.mu.Unlock()
+	l.ID = bson.NewObjectId()
+	fk.logs = append(fk.logs, l)
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Val: l, Num: 1}
+	close(c)
+	return c
+}
+
+func (fk *FakeLogAccessor) events() []*AuditEvent {
+	fk.mu.Lock()
+	defer fk.mu.Unlock()
+	events := []*AuditEvent{}
+	for _, l := range fk.logs {
+		events = append(events, l.Entry.(*AuditEvent))
+	}
+
+	return events
+}
+
+func TestAuditorRecord(t *testing.T) {
+	la := &FakeLogAccessor{}
+	a := NewAuditor(la)
+	if err := a.Record(&AuditEvent{Action: AuditLogin, Outcome: AuditSuccess}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(la.logs) != 1 {
+		t.Fatalf("Length expected: 1, got: %v", len(la.logs))
+	}
+
+	e := la.logs[0].Entry.(*AuditEvent)
+	if e.Time.IsZero() || !la.logs[0].TS.Equal(e.Time) {
+		t.Errorf("Time expected: %v, got: %v", la.logs[0].TS, e.Time)
+	}
+
+	var got error
+	la.err = errors.New("test")
+	a.OnError = func(err error) { got = err }
+	if err := a.Record(&AuditEvent{Action: AuditLogout}); err != la.err {
+		t.Errorf("Error expected: %v, got: %v", la.err, err)
+	}
+
+	if got != la.err {
+		t.Errorf("Error expected: %v, got: %v", la.err, got)
+	}
+}
+
+func TestAuditorFind(t *testing.T) {
+	la := &FakeLogAccessor{}
+	a := NewAuditor(la)
+	now := time.Now()
+	a.Record(&AuditEvent{ActorID: 1, Action: AuditLogin, Time: now.Add(-2 * time.Hour)})
+	a.Record(&AuditEvent{ActorID: 1, Action: AuditLogout, Time: now.Add(-time.Hour)})
+	a.Record(&AuditEvent{ActorID: 2, Action: AuditLogin, Time: now})
+	la.SaveLog(&dlib.Log{TS: &now, Entry: bson.M{"msg": "test"}})
+	la.SaveLog(&dlib.Log{TS: &now, Entry: bson.M{
+		"actor_id": int64(3),
+		"action":   AuditLogin,
+		"time":     now,
+	}})
+
+	id1, id3 := int64(1), int64(3)
+	login := AuditLogin
+	start, end := now.Add(-90*time.Minute), now.Add(-30*time.Minute)
+	cases := []struct {
+		f   AuditFind
+		exp []int64
+	}{
+		{AuditFind{}, []int64{3, 2, 1, 1}},
+		{AuditFind{ActorID: &id1}, []int64{1, 1}},
+		{AuditFind{ActorID: &id3}, []int64{3}},
+		{AuditFind{Action: &login}, []int64{3, 2, 1}},
+		{AuditFind{Start: &start, End: &end}, []int64{1}},
+		{AuditFind{Limit: 2}, []int64{3, 2}},
+	}
+
+	for _, c := range cases {
+		got := []int64{}
+		for r := range a.Find(&c.f) {
+			if r.Err != nil {
+				t.Fatal(r.Err)
+			}
+
+			e := r.Val.(*AuditEvent)
+			if e.ID == "" {
+				t.Error("Expected ID")
+			}
+
+			got = append(got, e.ActorID)
+		}
+
+		if len(got) != len(c.exp) {
+			t.Errorf("Value expected: %v, got: %v", c.exp, got)
+			continue
+		}
+
+		for i := range got {
+			if got[i] != c.exp[i] {
+				t.Errorf("Value expected: %v, got: %v", c.exp, got)
+				break
+			}
+		}
+	}
+}
+
+func TestAuditFindFromAuditRequest(t *testing.T) {
+	f := AuditFind{}
+	err := f.FromAuditRequest(&ptypes.AuditRequest{
+		ActorID: 1,
+		Action:  AuditLogin,
+		Start:   &timestamp.Timestamp{Seconds: 100},
+		Limit:   5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f.ActorID == nil || *f.ActorID != 1 {
+		t.Errorf("ActorID expected: 1, got: %v", f.ActorID)
+	}
+
+	if f.Action == nil || *f.Action != AuditLogin {
+		t.Errorf("Action expected: %v, got: %v", AuditLogin, f.Action)
+	}
+
+	if f.Start == nil || f.Start.Unix() != 100 {
+		t.Errorf("Start expected: 100, got: %v", f.Start)
+	}
+
+	if f.Actor != nil || f.End != nil || f.TenantID != nil {
+		t.Errorf("Expected unset criteria, got: %+v", f)
+	}
+
+	if f.Limit != 5 {
+		t.Errorf("Limit expected: 5, got: %v", f.Limit)
+	}
+
+	q := f.filter()
+	if q["entry.actor_id"] != int64(1) || q["entry.action"] != AuditLogin {
+		t.Errorf("Unexpected filter: %v", q)
+	}
+
+	if ts, ok := q["ts"].(bson.M); !ok || ts["$gte"] == nil || ts["$lte"] != nil {
+		t.Errorf("Unexpected ts filter: %v", q["ts"])
+	}
+}
+
+func TestAuditorEnqueue(t *testing.T) {
+	la := &FakeLogAccessor{block: make(chan struct{})}
+	a := NewAuditor(la)
+	a.QueueSize = 1
+	dropped := 0
+	a.OnError = func(err error) {
+		if err == ErrAuditQueueFull {
+			dropped++
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		a.Enqueue(&AuditEvent{Action: AuditLogin})
+	}
+
+	if dropped == 0 {
+		t.Errorf("Dropped events expected")
+	}
+
+	close(la.block)
+	a.Flush()
+	if n := len(la.events()); n+dropped != 3 {
+		t.Errorf("Length expected: %v, got: %v", 3-dropped, n)
+	}
+}
@@ -0,0 +1,416 @@
+package dauth
+
+import (
+	"bufio"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/dhaifley/dlib"
+)
+
+// errInvalidLogin is returned by authenticators when a user name and
+// password do not match.
+var errInvalidLogin = &dlib.Error{Code: 401, Msg: "invalid user or pass"}
+
+// Authenticator is an interface describing types capable of verifying a
+// user name and password in a tenant. User names are only unique within a
+// tenant, so users of other tenants never match. Authenticate returns the
+// verified user, which has an ID only if it is a user of the store. Users of
+// external identity providers have no ID and carry the name of their
+// provider, and are provisioned into the store by the auth server on their
+// first login. It returns a 401 error if the user does not exist or the
+// password is wrong.
+type Authenticator interface {
+	Authenticate(tenantID int64, user, pass string) (*User, error)
+}
+
+// AuthenticatorFunc is an adapter allowing the use of ordinary functions
+// as authenticators.
+type AuthenticatorFunc func(tenantID int64, user, pass string) (*User, error)
+
+// Authenticate calls f(tenantID, user, pass).
+func (f AuthenticatorFunc) Authenticate(tenantID int64, user, pass string) (*User, error) {
+	return f(tenantID, user, pass)
+}
+
+// MultiAuthenticator values implement the Authenticator interface by trying
+// each of a list of authenticators in turn, until one verifies the user or
+// returns an error other than a 401 error.
+type MultiAuthenticator []Authenticator
+
+// Authenticate verifies a user name and password using the first
+// authenticator which accepts them.
+func (ma MultiAuthenticator) Authenticate(tenantID int64, user, pass string) (*User, error) {
+	for _, a := range ma {
+		u, err := a.Authenticate(tenantID, user, pass)
+		if e, ok := err.(*dlib.Error); ok && e.Code == 401 {
+			continue
+		}
+
+		return u, err
+	}
+
+	return nil, errInvalidLogin
+}
+
+// StoreAuthenticator values implement the Authenticator interface using
+// the password hashes of the users in a store. A stored hash that no longer
+// matches the parameters of Hasher is replaced after successful
+// verification.
+type StoreAuthenticator struct {
+	Store  Store
+	Hasher PasswordHasher
+}
+
+// NewStoreAuthenticator initializes and returns a pointer to a new store
+// authenticator value using the default password hasher.
+func NewStoreAuthenticator(st Store) *StoreAuthenticator {
+	return &StoreAuthenticator{Store: st, Hasher: DefaultPasswordHasher}
+}
+
+// hasher returns the password hasher used to verify users.
+func (sa *StoreAuthenticator) hasher() PasswordHasher {
+	if sa.Hasher == nil {
+		return DefaultPasswordHasher
+	}
+
+	return sa.Hasher
+}
+
+// Authenticate returns the user of the store matching a tenant, user name
+// and password. Users with no password hash, such as users provisioned from
+// an external identity provider, can not be verified.
+func (sa *StoreAuthenticator) Authenticate(tenantID int64, user, pass string) (*User, error) {
+	var u *User
+	f := UserFind{User: &user, TenantID: &tenantID}
+	for r := range sa.Store.GetUsers(&f) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		u = r.Val.(*User)
+	}
+
+	if u == nil || !IsPasswordHash(u.Pass) {
+		// Hash anyway so that unknown users take as long as known ones.
+		sa.hasher().Hash(pass)
+		return nil, errInvalidLogin
+	}
+
+	ok, err := VerifyPassword(u.Pass, pass)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, errInvalidLogin
+	}
+
+	if sa.hasher().NeedsRehash(u.Pass) {
+		if u.Pass, err = sa.hasher().Hash(pass); err != nil {
+			return nil, err
+		}
+
+		for r := range sa.Store.SaveUser(u) {
+			if r.Err != nil {
+				return nil, r.Err
+			}
+		}
+	}
+
+	return u, nil
+}
+
+// HtpasswdAuthenticator values implement the Authenticator interface using
+// an Apache htpasswd format file of "user:hash" lines. Hashes may be
+// bcrypt, Apache MD5 ("$apr1$"), SHA-1 ("{SHA}") or argon2id hashes. The
+// file is read when the authenticator is created, and again by Reload.
+// Verified users are in the tenant TenantID, and are provisioned as users
+// of the provider named by Provider.
+type HtpasswdAuthenticator struct {
+	Path     string
+	TenantID int64
+	Provider string
+	mu       sync.RWMutex
+	users    map[string]string
+}
+
+// NewHtpasswdAuthenticator initializes and returns a pointer to a new
+// htpasswd authenticator value reading the file at a path. Its provider
+// name is "htpasswd".
+func NewHtpasswdAuthenticator(path string) (*HtpasswdAuthenticator, error) {
+	ha := &HtpasswdAuthenticator{Path: path, Provider: "htpasswd"}
+	if err := ha.Reload(); err != nil {
+		return nil, err
+	}
+
+	return ha, nil
+}
+
+// Reload reads the htpasswd file again, replacing the users read before.
+func (ha *HtpasswdAuthenticator) Reload() error {
+	f, err := os.Open(ha.Path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+	users, err := ParseHtpasswd(f)
+	if err != nil {
+		return err
+	}
+
+	ha.mu.Lock()
+	ha.users = users
+	ha.mu.Unlock()
+	return nil
+}
+
+// ParseHtpasswd reads htpasswd format lines and returns the password hash
+// of each user. Blank lines and lines starting with "#" are ignored.
+func ParseHtpasswd(r io.Reader) (map[string]string, error) {
+	users := map[string]string{}
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		i := strings.Index(line, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("htpasswd line %d: expected user:hash", n)
+		}
+
+		users[line[:i]] = line[i+1:]
+	}
+
+	return users, sc.Err()
+}
+
+// Authenticate returns a user with a user name if the tenant is the tenant
+// of the authenticator and the password matches the hash for the user in
+// the htpasswd file.
+func (ha *HtpasswdAuthenticator) Authenticate(tenantID int64, user, pass string) (*User, error) {
+	ha.mu.RLock()
+	hash, ok := ha.users[user]
+	ha.mu.RUnlock()
+	if !ok || tenantID != ha.TenantID {
+		DefaultPasswordHasher.Hash(pass)
+		return nil, errInvalidLogin
+	}
+
+	ok, err := VerifyHtpasswd(hash, pass)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, errInvalidLogin
+	}
+
+	return &User{User: user, TenantID: ha.TenantID, Provider: ha.Provider}, nil
+}
+
+// VerifyHtpasswd tests whether a password matches an htpasswd password
+// hash. It returns ErrUnknownHash for hashes in other formats, such as
+// crypt or plain text.
+func VerifyHtpasswd(hash, pass string) (bool, error) {
+	switch {
+	case strings.HasPrefix(hash, "{SHA}"):
+		h := sha1.Sum([]byte(pass))
+		exp := "{SHA}" + base64.StdEncoding.EncodeToString(h[:])
+		return subtle.ConstantTimeCompare([]byte(exp), []byte(hash)) == 1, nil
+	case strings.HasPrefix(hash, apr1Magic):
+		salt := strings.TrimPrefix(hash, apr1Magic)
+		i := strings.Index(salt, "$")
+		if i < 0 {
+			return false, ErrUnknownHash
+		}
+
+		exp := apr1(pass, salt[:i])
+		return subtle.ConstantTimeCompare([]byte(exp), []byte(hash)) == 1, nil
+	default:
+		return VerifyPassword(hash, pass)
+	}
+}
+
+// apr1Magic is the prefix of Apache MD5 password hashes.
+const apr1Magic = "$apr1$"
+
+// apr1Alphabet is the alphabet of the base 64 encoding used by Apache MD5
+// password hashes.
+const apr1Alphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// apr1 returns the Apache MD5 hash of a password with a salt.
+func apr1(pass, salt string) string {
+	if len(salt) > 8 {
+		salt = salt[:8]
+	}
+
+	p, s := []byte(pass), []byte(salt)
+	alt := md5.New()
+	alt.Write(p)
+	alt.Write(s)
+	alt.Write(p)
+	sum := alt.Sum(nil)
+
+	h := md5.New()
+	h.Write(p)
+	h.Write([]byte(apr1Magic))
+	h.Write(s)
+	for i := len(p); i > 0; i -= 16 {
+		if i > 16 {
+			h.Write(sum)
+		} else {
+			h.Write(sum[:i])
+		}
+	}
+
+	for i := len(p); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			h.Write([]byte{0})
+		} else {
+			h.Write(p[:1])
+		}
+	}
+
+	sum = h.Sum(nil)
+	for i := 0; i < 1000; i++ {
+		r := md5.New()
+		if i&1 != 0 {
+			r.Write(p)
+		} else {
+			r.Write(sum)
+		}
+
+		if i%3 != 0 {
+			r.Write(s)
+		}
+
+		if i%7 != 0 {
+			r.Write(p)
+		}
+
+		if i&1 != 0 {
+			r.Write(sum)
+		} else {
+			r.Write(p)
+		}
+
+		sum = r.Sum(nil)
+	}
+
+	out := []byte{}
+	encode := func(a, b, c byte, n int) {
+		v := uint(a)<<16 | uint(b)<<8 | uint(c)
+		for ; n > 0; n-- {
+			out = append(out, apr1Alphabet[v&0x3f])
+			v >>= 6
+		}
+	}
+
+	encode(sum[0], sum[6], sum[12], 4)
+	encode(sum[1], sum[7], sum[13], 4)
+	encode(sum[2], sum[8], sum[14], 4)
+	encode(sum[3], sum[9], sum[15], 4)
+	encode(sum[4], sum[10], sum[5], 4)
+	encode(0, 0, sum[11], 2)
+	return apr1Magic + salt + "$" + string(out)
+}
+
+// DirectoryEntry values represent an entry of a directory service, such as
+// an LDAP server, with the values of its attributes.
+type DirectoryEntry struct {
+	DN    string
+	Attrs map[string][]string
+}
+
+// Attr returns the first value of an attribute of the entry, or an empty
+// string if it has none.
+func (de *DirectoryEntry) Attr(name string) string {
+	if v := de.Attrs[name]; len(v) > 0 {
+		return v[0]
+	}
+
+	return ""
+}
+
+// DirectoryClient is an interface describing clients of a directory
+// service, such as an LDAP server. Find returns the entry of a user name,
+// or nil if there is none, such as by searching the directory as a service
+// account. Bind tests whether a password is valid for the entry with a DN
+// by binding to the directory as the entry.
+type DirectoryClient interface {
+	Find(user string) (*DirectoryEntry, error)
+	Bind(dn, pass string) (bool, error)
+}
+
+// DirectoryAuthenticator values implement the Authenticator interface by
+// binding to a directory service as the user. The name and email of
+// verified users are read from the entry attributes named by NameAttr and
+// EmailAttr, and users are in the tenant TenantID. Verified users are
+// provisioned as users of the provider named by Provider.
+type DirectoryAuthenticator struct {
+	Client    DirectoryClient
+	NameAttr  string
+	EmailAttr string
+	TenantID  int64
+	Provider  string
+}
+
+// NewDirectoryAuthenticator initializes and returns a pointer to a new
+// directory authenticator value using a directory client and the common
+// LDAP name and email attributes. Its provider name is "directory".
+func NewDirectoryAuthenticator(c DirectoryClient) *DirectoryAuthenticator {
+	return &DirectoryAuthenticator{
+		Client:    c,
+		NameAttr:  "cn",
+		EmailAttr: "mail",
+		Provider:  "directory",
+	}
+}
+
+// Authenticate returns a user created from the directory entry of a user
+// name if the tenant is the tenant of the authenticator and a bind as the
+// entry with the password succeeds. An empty password is always rejected,
+// since many directories treat a bind with one as an anonymous bind which
+// succeeds.
+func (da *DirectoryAuthenticator) Authenticate(tenantID int64, user, pass string) (*User, error) {
+	if user == "" || pass == "" || tenantID != da.TenantID {
+		return nil, errInvalidLogin
+	}
+
+	de, err := da.Client.Find(user)
+	if err != nil {
+		return nil, err
+	}
+
+	if de == nil {
+		return nil, errInvalidLogin
+	}
+
+	ok, err := da.Client.Bind(de.DN, pass)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, errInvalidLogin
+	}
+
+	return &User{
+		User:     user,
+		Name:     de.Attr(da.NameAttr),
+		Email:    de.Attr(da.EmailAttr),
+		TenantID: da.TenantID,
+		Provider: da.Provider,
+	}, nil
+}
@@ -0,0 +1,240 @@
+package dauth
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dhaifley/dlib"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type FakeDirectory struct {
+	entries map[string]*DirectoryEntry
+	pass    map[string]string
+}
+
+func (fk *FakeDirectory) Find(user string) (*DirectoryEntry, error) {
+	return fk.entries[user], nil
+}
+
+func (fk *FakeDirectory) Bind(dn, pass string) (bool, error) {
+	if pass == "" {
+		return true, nil
+	}
+
+	return fk.pass[dn] == pass, nil
+}
+
+func newFakeDirectory() *FakeDirectory {
+	return &FakeDirectory{
+		entries: map[string]*DirectoryEntry{
+			"dir": {
+				DN: "uid=dir,ou=people,dc=example,dc=com",
+				Attrs: map[string][]string{
+					"cn":   {"Directory User"},
+					"mail": {"dir@example.com"},
+				},
+			},
+			"test": {DN: "uid=test,ou=people,dc=example,dc=com"},
+		},
+		pass: map[string]string{
+			"uid=dir,ou=people,dc=example,dc=com":  "secret",
+			"uid=test,ou=people,dc=example,dc=com": "secret",
+		},
+	}
+}
+
+func TestStoreAuthenticator(t *testing.T) {
+	ms := newTestStore()
+	pass, _ := testHasher.Hash("other")
+	ms.SaveUser(&User{User: "test", Pass: pass, TenantID: 2})
+	sa := &StoreAuthenticator{Store: ms, Hasher: testHasher}
+	cases := []struct {
+		tenant int64
+		user   string
+		pass   string
+		id     int64
+		code   int
+	}{
+		{0, "test", "test", 1, 0},
+		{2, "test", "other", 2, 0},
+		{0, "test", "other", 0, 401},
+		{2, "test", "test", 0, 401},
+		{0, "test", "wrong", 0, 401},
+		{0, "other", "test", 0, 401},
+	}
+
+	for _, c := range cases {
+		u, err := sa.Authenticate(c.tenant, c.user, c.pass)
+		if c.code == 0 {
+			if err != nil || u.ID != c.id {
+				t.Errorf("Expected user %v, got: %v, %v", c.id, u, err)
+			}
+
+			continue
+		}
+
+		if e, ok := err.(*dlib.Error); !ok || e.Code != c.code {
+			t.Errorf("Error expected: %v, got: %v", c.code, err)
+		}
+	}
+}
+
+func TestMultiAuthenticator(t *testing.T) {
+	ma := MultiAuthenticator{
+		&StoreAuthenticator{Store: newTestStore(), Hasher: testHasher},
+		NewDirectoryAuthenticator(newFakeDirectory()),
+	}
+
+	cases := []struct {
+		user string
+		pass string
+		id   int64
+		code int
+	}{
+		{"test", "test", 1, 0},
+		{"test", "secret", 0, 0},
+		{"dir", "secret", 0, 0},
+		{"dir", "wrong", 0, 401},
+	}
+
+	for _, c := range cases {
+		u, err := ma.Authenticate(0, c.user, c.pass)
+		if c.code == 0 {
+			if err != nil || u.User != c.user || u.ID != c.id {
+				t.Errorf("Expected user %v with ID %v, got: %v, %v", c.user, c.id, u, err)
+			}
+
+			continue
+		}
+
+		if e, ok := err.(*dlib.Error); !ok || e.Code != c.code {
+			t.Errorf("Error expected: %v, got: %v", c.code, err)
+		}
+	}
+}
+
+func TestVerifyHtpasswd(t *testing.T) {
+	bc, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		hash string
+		pass string
+		exp  bool
+		err  bool
+	}{
+		{"$apr1$saltsalt$LrttParrLPdxvgutaSXWJ0", "secret", true, false},
+		{"$apr1$saltsalt$LrttParrLPdxvgutaSXWJ0", "wrong", false, false},
+		{"{SHA}5en6G6MezRroT3XKqkdPOmY/BfQ=", "secret", true, false},
+		{"{SHA}5en6G6MezRroT3XKqkdPOmY/BfQ=", "wrong", false, false},
+		{string(bc), "secret", true, false},
+		{string(bc), "wrong", false, false},
+		{"secret", "secret", false, true},
+	}
+
+	for _, c := range cases {
+		ok, err := VerifyHtpasswd(c.hash, c.pass)
+		if ok != c.exp || (err != nil) != c.err {
+			t.Errorf("Expected: %v, %v, got: %v, %v for %v", c.exp, c.err, ok, err, c.hash)
+		}
+	}
+}
+
+func TestParseHtpasswd(t *testing.T) {
+	users, err := ParseHtpasswd(strings.NewReader(
+		"# users\n\ntest:{SHA}5en6G6MezRroT3XKqkdPOmY/BfQ=\nother:$apr1$a$b\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(users) != 2 || users["other"] != "$apr1$a$b" {
+		t.Errorf("Expected 2 users, got: %v", users)
+	}
+
+	if _, err := ParseHtpasswd(strings.NewReader("invalid\n")); err == nil {
+		t.Error("Expected error parsing invalid line")
+	}
+}
+
+func TestHtpasswdAuthenticator(t *testing.T) {
+	f, err := ioutil.TempFile("", "htpasswd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(f.Name())
+	f.WriteString("test:$apr1$saltsalt$LrttParrLPdxvgutaSXWJ0\n")
+	f.Close()
+	ha, err := NewHtpasswdAuthenticator(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ha.TenantID = 2
+	u, err := ha.Authenticate(2, "test", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u.User != "test" || u.ID != 0 || u.TenantID != 2 || u.Provider != "htpasswd" {
+		t.Errorf("Expected unprovisioned user test in tenant 2, got: %v", u)
+	}
+
+	_, err = ha.Authenticate(2, "test", "wrong")
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 401 {
+		t.Errorf("Error expected: 401, got: %v", err)
+	}
+
+	_, err = ha.Authenticate(0, "test", "secret")
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 401 {
+		t.Errorf("Error expected: 401, got: %v", err)
+	}
+
+	ioutil.WriteFile(f.Name(), []byte("other:{SHA}5en6G6MezRroT3XKqkdPOmY/BfQ=\n"), 0600)
+	if err := ha.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ha.Authenticate(2, "test", "secret"); err == nil {
+		t.Error("Expected error for user removed by reload")
+	}
+
+	if _, err := ha.Authenticate(2, "other", "secret"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDirectoryAuthenticator(t *testing.T) {
+	da := NewDirectoryAuthenticator(newFakeDirectory())
+	da.TenantID = 2
+	u, err := da.Authenticate(2, "dir", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u.User != "dir" || u.Name != "Directory User" || u.Email != "dir@example.com" ||
+		u.TenantID != 2 || u.ID != 0 || u.Provider != "directory" {
+		t.Errorf("Expected directory user, got: %v", u)
+	}
+
+	cases := []struct {
+		user string
+		pass string
+	}{
+		{"dir", "wrong"},
+		{"dir", ""},
+		{"other", "secret"},
+	}
+
+	for _, c := range cases {
+		_, err := da.Authenticate(2, c.user, c.pass)
+		if e, ok := err.(*dlib.Error); !ok || e.Code != 401 {
+			t.Errorf("Error expected: 401, got: %v for %v", err, c)
+		}
+	}
+}
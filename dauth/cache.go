@@ -0,0 +1,287 @@
+package dauth
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dhaifley/dlib/ptypes"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+)
+
+// Default auth cache settings.
+const (
+	DefaultCacheSize   = 10000
+	DefaultCacheTTL    = time.Minute
+	DefaultCacheNegTTL = 10 * time.Second
+	DefaultCacheWait   = 10 * time.Second
+)
+
+// authEntry values hold a cached auth response.
+type authEntry struct {
+	key     string
+	token   string
+	res     *ptypes.AuthResponse
+	expires time.Time
+}
+
+// authCall values represent an auth call in progress, which concurrent
+// identical lookups wait for. Done is closed once the call is complete.
+type authCall struct {
+	done chan struct{}
+	res  *ptypes.AuthResponse
+	err  error
+}
+
+// detachedContext values carry the values of a parent context, but not its
+// deadline or cancellation, so that a call shared by several callers is not
+// cancelled when the caller which started it gives up.
+type detachedContext struct {
+	context.Context
+}
+
+// Deadline returns no deadline.
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Done returns a nil channel, since the context is never cancelled.
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+// Err returns nil, since the context is never cancelled.
+func (detachedContext) Err() error {
+	return nil
+}
+
+// CachingClient values wrap a ptypes.AuthClient, caching the responses of
+// Auth for each token and permission. Positive decisions are cached for
+// TTL, and negative decisions for NegTTL. At most Size responses are kept,
+// and the least recently used are evicted first. Concurrent identical
+// lookups are collapsed into a single call, which is not cancelled with the
+// context of any one caller, but is limited to Wait if it is positive. Each
+// caller waits for the call until its own context is done. Errors are not
+// cached, and each caller receives its own copy of a response.
+//
+// Cached responses for a token are removed when the token is logged out,
+// refreshed or deleted through the client. Deleting tokens by criteria
+// other than the token string, or revoking API keys, clears the whole
+// cache. Changes to permissions, and grants whose time conditions lapse,
+// take effect once cached responses expire.
+type CachingClient struct {
+	ptypes.AuthClient
+	Size   int
+	TTL    time.Duration
+	NegTTL time.Duration
+	Wait   time.Duration
+	mu     sync.Mutex
+	lru    *list.List
+	items  map[string]*list.Element
+	tokens map[string]map[string]bool
+	calls  map[string]*authCall
+	gen    int64
+}
+
+// NewCachingClient initializes and returns a pointer to a new caching auth
+// client value using the default settings.
+func NewCachingClient(c ptypes.AuthClient) *CachingClient {
+	return &CachingClient{
+		AuthClient: c,
+		Size:       DefaultCacheSize,
+		TTL:        DefaultCacheTTL,
+		NegTTL:     DefaultCacheNegTTL,
+		Wait:       DefaultCacheWait,
+	}
+}
+
+// authKey returns the cache key of an auth request. Tokens are hashed so
+// that they are not held in memory. The client address is part of the key,
+// since it may be a condition of a grant.
+func authKey(req *ptypes.AuthRequest) (string, string) {
+	token := HashToken(req.Token.Token)
+	p := req.Perm
+	return fmt.Sprintf("%s\x00%d\x00%s\x00%s\x00%d\x00%s", token, p.ID, p.Service,
+		p.Name, req.TenantID, req.Addr), token
+}
+
+// init initializes the internal state of the client, if needed. The client
+// must be locked.
+func (cc *CachingClient) init() {
+	if cc.items == nil {
+		cc.lru = list.New()
+		cc.items = make(map[string]*list.Element)
+		cc.tokens = make(map[string]map[string]bool)
+		cc.calls = make(map[string]*authCall)
+	}
+}
+
+// Auth authenticates a provided token and returns a user value, using a
+// cached response if there is one.
+func (cc *CachingClient) Auth(ctx context.Context, in *ptypes.AuthRequest,
+	opts ...grpc.CallOption) (*ptypes.AuthResponse, error) {
+	if in.Token == nil || in.Token.Token == "" || in.Perm == nil {
+		return cc.AuthClient.Auth(ctx, in, opts...)
+	}
+
+	key, token := authKey(in)
+	cc.mu.Lock()
+	cc.init()
+	if el, ok := cc.items[key]; ok {
+		e := el.Value.(*authEntry)
+		if time.Now().Before(e.expires) {
+			cc.lru.MoveToFront(el)
+			cc.mu.Unlock()
+			return cloneAuthResponse(e.res), nil
+		}
+
+		cc.remove(el)
+	}
+
+	c, ok := cc.calls[key]
+	if !ok {
+		c = &authCall{done: make(chan struct{})}
+		cc.calls[key] = c
+		go cc.call(ctx, key, token, cc.gen, c, in, opts...)
+	}
+
+	cc.mu.Unlock()
+	select {
+	case <-c.done:
+		return cloneAuthResponse(c.res), c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// call makes a shared auth call on a context detached from that of the
+// caller which started it, and caches its response unless the cache was
+// invalidated while it was in progress.
+func (cc *CachingClient) call(ctx context.Context, key, token string, gen int64,
+	c *authCall, in *ptypes.AuthRequest, opts ...grpc.CallOption) {
+	ctx = detachedContext{ctx}
+	if cc.Wait > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cc.Wait)
+		defer cancel()
+	}
+
+	c.res, c.err = cc.AuthClient.Auth(ctx, in, opts...)
+	cc.mu.Lock()
+	delete(cc.calls, key)
+	if c.err == nil && gen == cc.gen {
+		cc.add(key, token, c.res)
+	}
+
+	cc.mu.Unlock()
+	close(c.done)
+}
+
+// cloneAuthResponse returns a copy of a shared auth response, so that
+// callers may modify it without affecting the cache or other callers.
+func cloneAuthResponse(res *ptypes.AuthResponse) *ptypes.AuthResponse {
+	if res == nil {
+		return nil
+	}
+
+	return proto.Clone(res).(*ptypes.AuthResponse)
+}
+
+// add caches a response. The client must be locked.
+func (cc *CachingClient) add(key, token string, res *ptypes.AuthResponse) {
+	ttl := cc.NegTTL
+	if res.Ok {
+		ttl = cc.TTL
+	}
+
+	if ttl <= 0 || cc.Size <= 0 {
+		return
+	}
+
+	e := &authEntry{key: key, token: token, res: res, expires: time.Now().Add(ttl)}
+	cc.items[key] = cc.lru.PushFront(e)
+	if cc.tokens[token] == nil {
+		cc.tokens[token] = make(map[string]bool)
+	}
+
+	cc.tokens[token][key] = true
+	for cc.lru.Len() > cc.Size {
+		cc.remove(cc.lru.Back())
+	}
+}
+
+// remove removes a cached response. The client must be locked.
+func (cc *CachingClient) remove(el *list.Element) {
+	e := cc.lru.Remove(el).(*authEntry)
+	delete(cc.items, e.key)
+	delete(cc.tokens[e.token], e.key)
+	if len(cc.tokens[e.token]) == 0 {
+		delete(cc.tokens, e.token)
+	}
+}
+
+// Invalidate removes the cached responses for a token, or every cached
+// response if the token is empty. Responses to calls in progress are not
+// cached.
+func (cc *CachingClient) Invalidate(token string) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.init()
+	cc.gen++
+	if token == "" {
+		cc.lru.Init()
+		cc.items = make(map[string]*list.Element)
+		cc.tokens = make(map[string]map[string]bool)
+		return
+	}
+
+	for key := range cc.tokens[HashToken(token)] {
+		cc.remove(cc.items[key])
+	}
+}
+
+// Len returns the number of cached responses.
+func (cc *CachingClient) Len() int {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.init()
+	return cc.lru.Len()
+}
+
+// Logout destroys the provided token and removes its cached responses.
+func (cc *CachingClient) Logout(ctx context.Context, in *ptypes.TokenRequest,
+	opts ...grpc.CallOption) (*ptypes.TokenResponse, error) {
+	res, err := cc.AuthClient.Logout(ctx, in, opts...)
+	cc.Invalidate(in.Token)
+	return res, err
+}
+
+// Refresh replaces the provided token with a new one and removes the cached
+// responses of the replaced token.
+func (cc *CachingClient) Refresh(ctx context.Context, in *ptypes.TokenRequest,
+	opts ...grpc.CallOption) (*ptypes.TokenResponse, error) {
+	res, err := cc.AuthClient.Refresh(ctx, in, opts...)
+	cc.Invalidate(in.Token)
+	return res, err
+}
+
+// RevokeAPIKeys deletes API keys from the database and clears the cache,
+// since the key strings of the revoked keys are not known to the client.
+func (cc *CachingClient) RevokeAPIKeys(ctx context.Context, in *ptypes.APIKeyRequest,
+	opts ...grpc.CallOption) (*ptypes.DeleteResponse, error) {
+	res, err := cc.AuthClient.RevokeAPIKeys(ctx, in, opts...)
+	cc.Invalidate("")
+	return res, err
+}
+
+// DeleteTokens deletes tokens from the database and removes their cached
+// responses.
+func (cc *CachingClient) DeleteTokens(ctx context.Context, in *ptypes.TokenRequest,
+	opts ...grpc.CallOption) (*ptypes.DeleteResponse, error) {
+	res, err := cc.AuthClient.DeleteTokens(ctx, in, opts...)
+	cc.Invalidate(in.Token)
+	return res, err
+}
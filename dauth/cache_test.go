@@ -0,0 +1,205 @@
+package dauth
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dhaifley/dlib/ptypes"
+	"google.golang.org/grpc"
+)
+
+type FakeAuthClient struct {
+	ptypes.AuthClient
+	calls int64
+	wait  chan struct{}
+}
+
+func (fk *FakeAuthClient) Auth(ctx context.Context, in *ptypes.AuthRequest,
+	opts ...grpc.CallOption) (*ptypes.AuthResponse, error) {
+	atomic.AddInt64(&fk.calls, 1)
+	if fk.wait != nil {
+		<-fk.wait
+	}
+
+	return &ptypes.AuthResponse{Ok: in.Perm.Name == "ok"}, nil
+}
+
+func (fk *FakeAuthClient) Logout(ctx context.Context, in *ptypes.TokenRequest,
+	opts ...grpc.CallOption) (*ptypes.TokenResponse, error) {
+	return &ptypes.TokenResponse{}, nil
+}
+
+func (fk *FakeAuthClient) Refresh(ctx context.Context, in *ptypes.TokenRequest,
+	opts ...grpc.CallOption) (*ptypes.TokenResponse, error) {
+	return &ptypes.TokenResponse{}, nil
+}
+
+func (fk *FakeAuthClient) RevokeAPIKeys(ctx context.Context, in *ptypes.APIKeyRequest,
+	opts ...grpc.CallOption) (*ptypes.DeleteResponse, error) {
+	return &ptypes.DeleteResponse{}, nil
+}
+
+func (fk *FakeAuthClient) DeleteTokens(ctx context.Context, in *ptypes.TokenRequest,
+	opts ...grpc.CallOption) (*ptypes.DeleteResponse, error) {
+	return &ptypes.DeleteResponse{}, nil
+}
+
+func cacheRequest(token, name string) *ptypes.AuthRequest {
+	return &ptypes.AuthRequest{
+		Token: &ptypes.TokenRequest{Token: token},
+		Perm:  &ptypes.PermRequest{Service: "test", Name: name},
+	}
+}
+
+func TestCachingClientAuth(t *testing.T) {
+	fk := &FakeAuthClient{}
+	cc := NewCachingClient(fk)
+	cc.NegTTL = time.Millisecond
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		res, err := cc.Auth(ctx, cacheRequest("a", "ok"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !res.Ok {
+			t.Errorf("Ok expected: true, got: %v", res.Ok)
+		}
+
+		res.Ok = false
+	}
+
+	if fk.calls != 1 {
+		t.Errorf("Calls expected: 1, got: %v", fk.calls)
+	}
+
+	cc.Auth(ctx, cacheRequest("a", "no"))
+	time.Sleep(5 * time.Millisecond)
+	cc.Auth(ctx, cacheRequest("a", "no"))
+	if fk.calls != 3 {
+		t.Errorf("Calls expected: 3, got: %v", fk.calls)
+	}
+
+	cc.Auth(ctx, cacheRequest("a", "ok"))
+	if fk.calls != 3 {
+		t.Errorf("Calls expected: 3, got: %v", fk.calls)
+	}
+}
+
+func TestCachingClientEvict(t *testing.T) {
+	fk := &FakeAuthClient{}
+	cc := NewCachingClient(fk)
+	cc.Size = 2
+	ctx := context.Background()
+	cc.Auth(ctx, cacheRequest("a", "ok"))
+	cc.Auth(ctx, cacheRequest("b", "ok"))
+	cc.Auth(ctx, cacheRequest("a", "ok"))
+	cc.Auth(ctx, cacheRequest("c", "ok"))
+	if cc.Len() != 2 {
+		t.Errorf("Length expected: 2, got: %v", cc.Len())
+	}
+
+	cc.Auth(ctx, cacheRequest("a", "ok"))
+	if fk.calls != 3 {
+		t.Errorf("Calls expected: 3, got: %v", fk.calls)
+	}
+
+	cc.Auth(ctx, cacheRequest("b", "ok"))
+	if fk.calls != 4 {
+		t.Errorf("Calls expected: 4, got: %v", fk.calls)
+	}
+}
+
+func TestCachingClientSingleflight(t *testing.T) {
+	fk := &FakeAuthClient{wait: make(chan struct{})}
+	cc := NewCachingClient(fk)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if res, err := cc.Auth(context.Background(), cacheRequest("a", "ok")); err != nil || !res.Ok {
+				t.Errorf("Expected ok response, got: %v, %v", res, err)
+			}
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(fk.wait)
+	wg.Wait()
+	if fk.calls != 1 {
+		t.Errorf("Calls expected: 1, got: %v", fk.calls)
+	}
+}
+
+func TestCachingClientCancel(t *testing.T) {
+	fk := &FakeAuthClient{wait: make(chan struct{})}
+	cc := NewCachingClient(fk)
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error)
+	go func() {
+		_, err := cc.Auth(ctx, cacheRequest("a", "ok"))
+		errs <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-errs; err != context.Canceled {
+		t.Errorf("Error expected: %v, got: %v", context.Canceled, err)
+	}
+
+	close(fk.wait)
+	res, err := cc.Auth(context.Background(), cacheRequest("a", "ok"))
+	if err != nil || !res.Ok {
+		t.Errorf("Expected ok response, got: %v, %v", res, err)
+	}
+
+	if fk.calls != 1 {
+		t.Errorf("Calls expected: 1, got: %v", fk.calls)
+	}
+}
+
+func TestCachingClientInvalidate(t *testing.T) {
+	fk := &FakeAuthClient{}
+	cc := NewCachingClient(fk)
+	ctx := context.Background()
+	cc.Auth(ctx, cacheRequest("a", "ok"))
+	cc.Auth(ctx, cacheRequest("a", "no"))
+	cc.Auth(ctx, cacheRequest("b", "ok"))
+	if _, err := cc.Logout(ctx, &ptypes.TokenRequest{Token: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if cc.Len() != 1 {
+		t.Errorf("Length expected: 1, got: %v", cc.Len())
+	}
+
+	if _, err := cc.Refresh(ctx, &ptypes.TokenRequest{Token: "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if cc.Len() != 0 {
+		t.Errorf("Length expected: 0, got: %v", cc.Len())
+	}
+
+	cc.Auth(ctx, cacheRequest("a", "ok"))
+	if _, err := cc.DeleteTokens(ctx, &ptypes.TokenRequest{UserID: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if cc.Len() != 0 {
+		t.Errorf("Length expected: 0, got: %v", cc.Len())
+	}
+
+	cc.Auth(ctx, cacheRequest("a", "ok"))
+	if _, err := cc.RevokeAPIKeys(ctx, &ptypes.APIKeyRequest{ID: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if cc.Len() != 0 {
+		t.Errorf("Length expected: 0, got: %v", cc.Len())
+	}
+}
@@ -0,0 +1,266 @@
+package dauth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dhaifley/dlib"
+	"github.com/dhaifley/dlib/ptypes"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Authorizer is an interface describing types capable of deciding whether
+// the holder of a token has a permission. Authorize returns the user the
+// token belongs to. It returns a 401 error if the token is not valid and a
+// 403 error if the user does not have the permission.
+type Authorizer interface {
+	Authorize(ctx context.Context, token string, perm *Perm) (*User, error)
+}
+
+// AuthorizerFunc is an adapter allowing the use of ordinary functions as
+// authorizers.
+type AuthorizerFunc func(ctx context.Context, token string, perm *Perm) (*User, error)
+
+// Authorize calls f(ctx, token, perm).
+func (f AuthorizerFunc) Authorize(ctx context.Context, token string, perm *Perm) (*User, error) {
+	return f(ctx, token, perm)
+}
+
+// ServerAuthorizer values implement the Authorizer interface by calling the
+// Auth method of an in process auth server.
+type ServerAuthorizer struct {
+	Server ptypes.AuthServer
+}
+
+// Authorize authorizes a token for a permission using the auth server.
+func (sa *ServerAuthorizer) Authorize(ctx context.Context, token string, perm *Perm) (*User, error) {
+	res, err := sa.Server.Auth(ctx, authRequest(token, perm, ClientAddr(ctx)))
+	if err != nil {
+		return nil, err
+	}
+
+	return authUser(res)
+}
+
+// ClientAuthorizer values implement the Authorizer interface by calling the
+// Auth RPC of a remote auth service.
+type ClientAuthorizer struct {
+	Client ptypes.AuthClient
+}
+
+// Authorize authorizes a token for a permission using the auth service.
+func (ca *ClientAuthorizer) Authorize(ctx context.Context, token string, perm *Perm) (*User, error) {
+	res, err := ca.Client.Auth(ctx, authRequest(token, perm, ClientAddr(ctx)))
+	if err != nil {
+		return nil, err
+	}
+
+	return authUser(res)
+}
+
+// SignedAuthorizer values implement the Authorizer interface by verifying
+// signed tokens locally, without a call to an auth service. The returned
+// user holds only the user and tenant IDs carried by the token. API keys
+// are not signed, and are not accepted.
+type SignedAuthorizer struct {
+	Signer *TokenSigner
+}
+
+// Authorize authorizes a signed token for a permission using the claims
+// carried by the token.
+func (sa *SignedAuthorizer) Authorize(ctx context.Context, token string, perm *Perm) (*User, error) {
+	c, err := sa.Signer.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.HasPerm(perm.Service, perm.Name) {
+		return nil, dlib.NewError(403, "permission denied")
+	}
+
+	return &User{ID: c.UserID, TenantID: c.TenantID}, nil
+}
+
+// authRequest returns an auth protobuf request for a token and permission,
+// made on behalf of a client at an address.
+func authRequest(token string, perm *Perm, addr string) *ptypes.AuthRequest {
+	pr := perm.ToRequest()
+	return &ptypes.AuthRequest{
+		Token: &ptypes.TokenRequest{Token: token},
+		Perm:  &pr,
+		Addr:  addr,
+	}
+}
+
+// authUser returns the user from an auth protobuf response, carrying the
+// user acting as them if there is one, or an error if the response is not
+// Ok.
+func authUser(res *ptypes.AuthResponse) (*User, error) {
+	if res.User == nil {
+		return nil, dlib.NewError(401, "invalid token")
+	}
+
+	if !res.Ok {
+		return nil, dlib.NewError(403, "permission denied")
+	}
+
+	u := User{}
+	if err := u.FromResponse(res.User); err != nil {
+		return nil, err
+	}
+
+	if res.Actor != nil {
+		a := User{}
+		if err := a.FromResponse(res.Actor); err != nil {
+			return nil, err
+		}
+
+		u.Actor = &a
+	}
+
+	return &u, nil
+}
+
+// userKey is the context key for the authenticated user.
+type userKey struct{}
+
+// NewUserContext returns a copy of a context carrying an authenticated user.
+func NewUserContext(ctx context.Context, u *User) context.Context {
+	return context.WithValue(ctx, userKey{}, u)
+}
+
+// UserFromContext returns the authenticated user carried by a context, if
+// any.
+func UserFromContext(ctx context.Context) (*User, bool) {
+	u, ok := ctx.Value(userKey{}).(*User)
+	return u, ok
+}
+
+// PermFromMethod maps a full gRPC method name, such as
+// "/dlib.Auth/GetUsers", to the permission required to call it, with the
+// service as Service and the method as Name.
+func PermFromMethod(method string) *Perm {
+	i := strings.LastIndex(method, "/")
+	if i < 0 {
+		return NewPerm(0, "", method)
+	}
+
+	return NewPerm(0, strings.TrimPrefix(method[:i], "/"), method[i+1:])
+}
+
+// TokenFromMetadata returns the bearer token from the authorization entry
+// of incoming gRPC metadata in a context, or the API key from the
+// x-api-key entry, or an empty string if there is neither.
+func TokenFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	for _, v := range md.Get("authorization") {
+		if len(v) > 7 && strings.EqualFold(v[:7], "bearer ") {
+			return strings.TrimSpace(v[7:])
+		}
+	}
+
+	for _, v := range md.Get("x-api-key") {
+		if v != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+
+	return ""
+}
+
+// Interceptor values authorize gRPC calls before they are handled. Perm
+// maps full method names to the permission required, where a nil
+// permission allows the call without authorization. If Perm is nil,
+// PermFromMethod is used.
+type Interceptor struct {
+	Authorizer Authorizer
+	Perm       func(method string) *Perm
+}
+
+// NewInterceptor initializes and returns a pointer to a new interceptor
+// value using an authorizer and the default method to permission mapping.
+func NewInterceptor(a Authorizer) *Interceptor {
+	return &Interceptor{Authorizer: a, Perm: PermFromMethod}
+}
+
+// authorize authorizes a call to a method. It returns a context carrying
+// the authenticated user, or a gRPC status error.
+func (in *Interceptor) authorize(ctx context.Context, method string) (context.Context, error) {
+	pf := in.Perm
+	if pf == nil {
+		pf = PermFromMethod
+	}
+
+	p := pf(method)
+	if p == nil {
+		return ctx, nil
+	}
+
+	token := TokenFromMetadata(ctx)
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "token required")
+	}
+
+	u, err := in.Authorizer.Authorize(ctx, token, p)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return NewUserContext(ctx, u), nil
+}
+
+// statusError converts an authorization error to a gRPC status error.
+// Errors which are not status errors, or dlib errors with a status, are
+// internal errors.
+func statusError(err error) error {
+	if st, ok := status.FromError(err); ok {
+		return st.Err()
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+// Unary returns a unary server interceptor which authorizes calls.
+func (in *Interceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := in.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns a stream server interceptor which authorizes calls.
+func (in *Interceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		ctx, err := in.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &userServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// userServerStream values wrap a server stream to replace its context with
+// one carrying the authenticated user.
+type userServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the stream.
+func (s *userServerStream) Context() context.Context {
+	return s.ctx
+}
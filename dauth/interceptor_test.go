@@ -0,0 +1,223 @@
+package dauth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dhaifley/dlib"
+	"github.com/dhaifley/dlib/ptypes"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type FakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (fk *FakeServerStream) Context() context.Context {
+	if fk.ctx == nil {
+		return context.Background()
+	}
+
+	return fk.ctx
+}
+
+func bearerContext(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestPermFromMethod(t *testing.T) {
+	cases := []struct {
+		method  string
+		service string
+		name    string
+	}{
+		{"/dlib.Auth/GetUsers", "dlib.Auth", "GetUsers"},
+		{"/a.b.C/D", "a.b.C", "D"},
+		{"Test", "", "Test"},
+	}
+
+	for _, c := range cases {
+		p := PermFromMethod(c.method)
+		if p.Service != c.service || p.Name != c.name {
+			t.Errorf("Perm expected: %v/%v, got: %v", c.service, c.name, p)
+		}
+	}
+
+	if p := PermFromMethod("/dlib.Auth/Impersonate"); !p.Implies(ImpersonatePerm) {
+		t.Errorf("Expected %v to imply %v", p, ImpersonatePerm)
+	}
+}
+
+func TestTokenFromMetadata(t *testing.T) {
+	cases := []struct {
+		ctx context.Context
+		exp string
+	}{
+		{context.Background(), ""},
+		{bearerContext("test"), "test"},
+		{metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs("authorization", "bearer test")), "test"},
+		{metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs("authorization", "Basic test")), ""},
+		{metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs("x-api-key", "dk_test")), "dk_test"},
+	}
+
+	for _, c := range cases {
+		if v := TokenFromMetadata(c.ctx); v != c.exp {
+			t.Errorf("Value expected: %v, got: %v", c.exp, v)
+		}
+	}
+}
+
+func TestServerAuthorizer(t *testing.T) {
+	sa := ServerAuthorizer{Server: NewServer(newTestStore())}
+	cases := []struct {
+		token string
+		perm  *Perm
+		code  int
+	}{
+		{"test", NewPerm(0, "test", "test"), 0},
+		{"test", NewPerm(0, "test", "other"), 403},
+		{"other", NewPerm(0, "test", "test"), 401},
+	}
+
+	for _, c := range cases {
+		u, err := sa.Authorize(context.Background(), c.token, c.perm)
+		if c.code == 0 {
+			if err != nil || u.ID != 1 {
+				t.Errorf("Expected user 1, got: %v, %v", u, err)
+			}
+
+			continue
+		}
+
+		if e, ok := err.(*dlib.Error); !ok || e.Code != c.code {
+			t.Errorf("Error expected: %v, got: %v", c.code, err)
+		}
+	}
+}
+
+func TestAuthUserActor(t *testing.T) {
+	u, err := authUser(&ptypes.AuthResponse{
+		Ok:    true,
+		User:  &ptypes.UserResponse{ID: 2, User: "target"},
+		Actor: &ptypes.UserResponse{ID: 1, User: "test"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u.ID != 2 || u.Actor == nil || u.Actor.ID != 1 {
+		t.Errorf("Expected user 2 acted on by user 1, got: %v", u)
+	}
+}
+
+func TestInterceptorUnary(t *testing.T) {
+	in := NewInterceptor(&ServerAuthorizer{Server: NewServer(newTestStore())})
+	perms := in.Perm
+	in.Perm = func(method string) *Perm {
+		if method == "/dlib.Auth/Login" {
+			return nil
+		}
+
+		return perms(method)
+	}
+
+	var user *User
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		user, _ = UserFromContext(ctx)
+		return req, nil
+	}
+
+	cases := []struct {
+		ctx    context.Context
+		method string
+		code   codes.Code
+		user   bool
+	}{
+		{bearerContext("test"), "/test/test", codes.OK, true},
+		{bearerContext("test"), "/test/other", codes.PermissionDenied, false},
+		{bearerContext("other"), "/test/test", codes.Unauthenticated, false},
+		{context.Background(), "/test/test", codes.Unauthenticated, false},
+		{context.Background(), "/dlib.Auth/Login", codes.OK, false},
+	}
+
+	for _, c := range cases {
+		user = nil
+		_, err := in.Unary()(c.ctx, nil,
+			&grpc.UnaryServerInfo{FullMethod: c.method}, handler)
+		if code := status.Code(err); code != c.code {
+			t.Errorf("Code expected: %v, got: %v", c.code, code)
+		}
+
+		if (user != nil) != c.user {
+			t.Errorf("User expected: %v, got: %v", c.user, user)
+		}
+	}
+}
+
+func TestInterceptorStream(t *testing.T) {
+	in := NewInterceptor(AuthorizerFunc(
+		func(ctx context.Context, token string, perm *Perm) (*User, error) {
+			if perm.Service != "test" {
+				return nil, dlib.NewError(403, "permission denied")
+			}
+
+			return NewUser(1, "test", "", "", ""), nil
+		}))
+
+	var user *User
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		user, _ = UserFromContext(ss.Context())
+		return nil
+	}
+
+	ss := &FakeServerStream{ctx: bearerContext("test")}
+	err := in.Stream()(nil, ss, &grpc.StreamServerInfo{FullMethod: "/test/test"}, handler)
+	if err != nil || user == nil || user.ID != 1 {
+		t.Errorf("Expected user 1, got: %v, %v", user, err)
+	}
+
+	err = in.Stream()(nil, ss, &grpc.StreamServerInfo{FullMethod: "/other/test"}, handler)
+	if code := status.Code(err); code != codes.PermissionDenied {
+		t.Errorf("Code expected: %v, got: %v", codes.PermissionDenied, code)
+	}
+}
+
+func TestSignedAuthorizer(t *testing.T) {
+	k, _ := NewHMACKey()
+	ts := NewTokenSigner(NewKeySet(k), time.Minute)
+	tk, _ := ts.Sign(1, []Perm{{Service: "orders", Name: "*"}})
+	sa := SignedAuthorizer{Signer: ts}
+	cases := []struct {
+		token string
+		perm  *Perm
+		code  int
+	}{
+		{tk.Token, NewPerm(0, "orders", "read"), 0},
+		{tk.Token, NewPerm(0, "users", "read"), 403},
+		{"test", NewPerm(0, "orders", "read"), 401},
+	}
+
+	for _, c := range cases {
+		u, err := sa.Authorize(context.Background(), c.token, c.perm)
+		if c.code == 0 {
+			if err != nil || u.ID != 1 {
+				t.Errorf("Expected user 1, got: %v, %v", u, err)
+			}
+
+			continue
+		}
+
+		if e, ok := err.(*dlib.Error); !ok || e.Code != c.code {
+			t.Errorf("Error expected: %v, got: %v", c.code, err)
+		}
+	}
+}
@@ -0,0 +1,454 @@
+package dauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/dhaifley/dlib"
+)
+
+// DefaultTokenTTL is the lifetime of tokens issued by a token issuer with
+// no TTL configured.
+const DefaultTokenTTL = time.Hour * 24
+
+// tokenBytes is the number of random bytes in an issued token.
+const tokenBytes = 32
+
+// TokenIssuer values issue, find and revoke opaque API tokens. Only the
+// SHA-256 hash of each token is kept in the store, so a leaked store does
+// not expose live tokens. If Signer is set, signed stateless tokens are
+// issued instead.
+//
+// If Sliding is set, a token used before it expires has its expiration
+// extended to a full TTL from the time of use. MaxLifetime, if set, limits
+// how long after its creation a token, or a token obtained by refreshing
+// it, can remain valid. RefreshWindow, if set, limits refreshing to tokens
+// which expire within the window.
+type TokenIssuer struct {
+	Store         Store
+	TTL           time.Duration
+	Hasher        PasswordHasher
+	Signer        *TokenSigner
+	Sliding       bool
+	MaxLifetime   time.Duration
+	RefreshWindow time.Duration
+}
+
+// NewTokenIssuer initializes and returns a pointer to a new token issuer
+// value. Issued tokens expire after the provided TTL.
+func NewTokenIssuer(st Store, ttl time.Duration) *TokenIssuer {
+	return &TokenIssuer{Store: st, TTL: ttl, Hasher: DefaultPasswordHasher}
+}
+
+// HashToken returns the hex encoded SHA-256 hash of a token string, which
+// is the form in which tokens are stored.
+func HashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+// ttl returns the lifetime of issued tokens.
+func (ti *TokenIssuer) ttl() time.Duration {
+	if ti.TTL <= 0 {
+		return DefaultTokenTTL
+	}
+
+	return ti.TTL
+}
+
+// expires returns the expiration time of a token created at a time and
+// extended or refreshed now, limited by the maximum lifetime.
+func (ti *TokenIssuer) expires(created, now time.Time) time.Time {
+	exp := now.Add(ti.ttl())
+	if ti.MaxLifetime > 0 {
+		if max := created.Add(ti.MaxLifetime); max.Before(exp) {
+			return max
+		}
+	}
+
+	return exp
+}
+
+// hasher returns the password hasher used to verify users.
+func (ti *TokenIssuer) hasher() PasswordHasher {
+	if ti.Hasher == nil {
+		return DefaultPasswordHasher
+	}
+
+	return ti.Hasher
+}
+
+// Issue creates and stores a new token for a user. The returned token value
+// contains the token string, which is not stored and can not be recovered.
+// If the issuer has a signer, a signed token carrying the user's permissions
+// is returned and nothing is stored.
+func (ti *TokenIssuer) Issue(userID int64) (*Token, error) {
+	return ti.IssueScoped(userID, nil)
+}
+
+// IssueScoped creates and stores a new token for a user which is limited
+// to a scope of "service:name" permission claims. The token grants only
+// those of the user's permissions which the scope allows. A 400 error is
+// returned if a claim is not in "service:name" form.
+func (ti *TokenIssuer) IssueScoped(userID int64, scope []string) (*Token, error) {
+	if err := checkScope(scope, nil); err != nil {
+		return nil, err
+	}
+
+	u, err := getUser(ti.Store, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantID := SuperTenant
+	if u != nil {
+		tenantID = u.TenantID
+	}
+
+	if ti.Signer != nil {
+		perms, err := EffectivePerms(ti.Store, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		return ti.Signer.SignScoped(userID, tenantID, perms, scope)
+	}
+
+	now := time.Now()
+	exp := ti.expires(now, now)
+	return ti.issue(&Token{
+		UserID:   userID,
+		TenantID: tenantID,
+		Created:  &now,
+		Expires:  &exp,
+		Scope:    scope,
+	})
+}
+
+// Impersonate creates and stores a new token for a user which records the
+// ID of the user acting as them. The token expires after a TTL and can not
+// be refreshed or extended. Impersonation tokens are always opaque, even if
+// the issuer has a signer, so that they can be found and revoked by actor.
+func (ti *TokenIssuer) Impersonate(actorID, userID int64, ttl time.Duration) (*Token, error) {
+	u, err := getUser(ti.Store, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u == nil {
+		return nil, dlib.NewError(404, "user not found")
+	}
+
+	now := time.Now()
+	exp := now.Add(ttl)
+	return ti.issue(&Token{
+		UserID:   userID,
+		TenantID: u.TenantID,
+		Created:  &now,
+		Expires:  &exp,
+		ActorID:  actorID,
+	})
+}
+
+// issue stores a new opaque token with the user, tenant, creation and
+// expiration times and scope of the provided value.
+func (ti *TokenIssuer) issue(t *Token) (*Token, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	token := hex.EncodeToString(b)
+	t.ID = 0
+	t.Token = HashToken(token)
+	for r := range ti.Store.SaveToken(t) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+	}
+
+	t.Token = token
+	return t, nil
+}
+
+// Find returns the stored token value matching a token string, or nil if
+// no such token exists. Expired tokens are returned; callers are expected
+// to check the expiration time. Signed tokens are verified by the signer
+// instead, and are not found if they are invalid or expired.
+func (ti *TokenIssuer) Find(token string) (*Token, error) {
+	if ti.Signer != nil && IsSignedToken(token) {
+		c, err := ti.Signer.Verify(token)
+		if err != nil {
+			return nil, nil
+		}
+
+		t := c.ToToken(token)
+		return &t, nil
+	}
+
+	var t *Token
+	f := TokenFind{}
+	hash := HashToken(token)
+	f.Token = &hash
+	for r := range ti.Store.GetTokens(&f) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		t = r.Val.(*Token)
+		t.Token = token
+	}
+
+	return t, nil
+}
+
+// Revoke deletes the token matching a token string. A 404 error is returned
+// if no such token exists. Signed tokens are not stored, so they can not be
+// revoked and remain valid until they expire, and a 400 error is returned
+// rather than reporting a revocation which did not happen.
+func (ti *TokenIssuer) Revoke(token string) (*Token, error) {
+	if IsSignedToken(token) {
+		return nil, dlib.NewError(400, "signed tokens can not be revoked")
+	}
+
+	t, err := ti.Find(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if t == nil {
+		return nil, dlib.NewError(404, "token not found")
+	}
+
+	f := TokenFind{ID: &t.ID}
+	for r := range ti.Store.DeleteTokens(&f) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+	}
+
+	return t, nil
+}
+
+// IssueAPIKey creates and stores a new API key with the name, owner, scope
+// and expiration of the provided value, which is updated with the ID,
+// prefix and creation time of the new key. The returned value holds the
+// full key string, which is not stored and can not be recovered.
+func (ti *TokenIssuer) IssueAPIKey(k *APIKey) (*APIKey, error) {
+	pb := make([]byte, 4)
+	if _, err := rand.Read(pb); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	k.Prefix = APIKeyPrefix + hex.EncodeToString(pb)
+	key := k.Prefix + "_" + hex.EncodeToString(b)
+	k.Key = HashToken(key)
+	k.Created = &now
+	for r := range ti.Store.SaveAPIKey(k) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+	}
+
+	nk := k.Copy()
+	nk.Key = key
+	return &nk, nil
+}
+
+// FindAPIKey returns the stored API key value matching a key string, or
+// nil if no such key exists. Expired keys are returned; callers are
+// expected to check the expiration time.
+func (ti *TokenIssuer) FindAPIKey(key string) (*APIKey, error) {
+	var k *APIKey
+	hash := HashToken(key)
+	for r := range ti.Store.GetAPIKeys(&APIKeyFind{Key: &hash}) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		k = r.Val.(*APIKey)
+	}
+
+	return k, nil
+}
+
+// Touch extends the expiration of a valid token which has been used, if the
+// issuer has sliding expiration enabled. To limit writes to the store, the
+// token is only updated once at least half of its TTL has passed since it
+// was last extended. Signed, impersonation and expired tokens are not
+// changed.
+func (ti *TokenIssuer) Touch(t *Token) error {
+	if !ti.Sliding || t == nil || t.ID == 0 || t.ActorID != 0 || t.Created == nil ||
+		t.Expires == nil {
+		return nil
+	}
+
+	now := time.Now()
+	if !t.Expires.After(now) {
+		return nil
+	}
+
+	exp := ti.expires(*t.Created, now)
+	if exp.Sub(*t.Expires) < ti.ttl()/2 {
+		return nil
+	}
+
+	st := t.Copy()
+	st.Token = HashToken(t.Token)
+	st.Expires = &exp
+	for r := range ti.Store.SaveToken(&st) {
+		if r.Err != nil {
+			return r.Err
+		}
+	}
+
+	t.Expires = &exp
+	return nil
+}
+
+// Refresh replaces a valid token with a new one for the same user and
+// revokes the old token. The new token keeps the creation time of the old
+// one, so refreshing does not extend a session past the maximum lifetime.
+// The old token is deleted before the new one is issued, and only the
+// caller whose delete removed it receives a new token, so a token can be
+// refreshed at most once. A 401 error is returned if the token is not
+// valid, has reached its maximum lifetime, or was already refreshed, and a
+// 400 error if it is a signed or impersonation token, or does not yet fall
+// within the refresh window.
+func (ti *TokenIssuer) Refresh(token string) (*Token, error) {
+	if IsSignedToken(token) {
+		return nil, dlib.NewError(400, "signed tokens can not be refreshed")
+	}
+
+	t, err := ti.Find(token)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if t == nil || t.Created == nil || t.Expires == nil || !t.Expires.After(now) {
+		return nil, dlib.NewError(401, "invalid token")
+	}
+
+	if t.ActorID != 0 {
+		return nil, dlib.NewError(400, "impersonation tokens can not be refreshed")
+	}
+
+	if ti.RefreshWindow > 0 && t.Expires.Sub(now) > ti.RefreshWindow {
+		return nil, dlib.NewError(400, "token not within refresh window")
+	}
+
+	exp := ti.expires(*t.Created, now)
+	if !exp.After(now) {
+		return nil, dlib.NewError(401, "token lifetime exceeded")
+	}
+
+	n := 0
+	f := TokenFind{ID: &t.ID}
+	for r := range ti.Store.DeleteTokens(&f) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		n += r.Num
+	}
+
+	if n == 0 {
+		return nil, dlib.NewError(401, "invalid token")
+	}
+
+	t.Expires = &exp
+	return ti.issue(t)
+}
+
+// Restrict issues a new token for the user of a valid token which is
+// limited to a scope within the scope of the original token, such as a read
+// only token to hand to a reporting dashboard. The original token remains
+// valid. The new token keeps the creation time of the original, and
+// expires no later than the original token or the maximum lifetime from
+// its creation, so restricting a token can not extend its lifetime. A
+// token restricted from an impersonation token is also an impersonation
+// token by the same actor. A 401 error is returned if the token is not
+// valid, and a 400 error if the scope is empty or not within the original
+// scope.
+func (ti *TokenIssuer) Restrict(token string, scope []string) (*Token, error) {
+	if len(scope) == 0 {
+		return nil, dlib.NewError(400, "scope required")
+	}
+
+	t, err := ti.Find(token)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if t == nil || t.Created == nil || t.Expires == nil || !t.Expires.After(now) {
+		return nil, dlib.NewError(401, "invalid token")
+	}
+
+	if err := checkScope(scope, t.Scope); err != nil {
+		return nil, err
+	}
+
+	exp := ti.expires(*t.Created, now)
+	if exp.After(*t.Expires) {
+		exp = *t.Expires
+	}
+
+	if ti.Signer != nil && t.ActorID == 0 {
+		perms, err := EffectivePerms(ti.Store, t.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		return ti.Signer.restrict(t, perms, scope, exp)
+	}
+
+	t.Expires = &exp
+	t.Scope = scope
+	return ti.issue(t)
+}
+
+// Login verifies a user name and password of a user in a tenant and issues
+// a new token for the user. A stored password hash that no longer matches
+// the issuer's hasher parameters is replaced after successful verification.
+// Users with MFA enabled can not log in with a password alone, and
+// ErrMFARequired is returned for them.
+func (ti *TokenIssuer) Login(tenantID int64, user, pass string) (*Token, error) {
+	u, err := ti.verifyUser(tenantID, user, pass)
+	if err != nil {
+		return nil, err
+	}
+
+	on, err := MFAEnabled(ti.Store, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if on {
+		return nil, ErrMFARequired
+	}
+
+	return ti.Issue(u.ID)
+}
+
+// Logout revokes a token. Signed tokens can not be logged out.
+func (ti *TokenIssuer) Logout(token string) (*Token, error) {
+	return ti.Revoke(token)
+}
+
+// verifyUser returns the user of a tenant matching a user name and
+// password. A 401 error is returned if the user does not exist or the
+// password is wrong.
+func (ti *TokenIssuer) verifyUser(tenantID int64, user, pass string) (*User, error) {
+	sa := StoreAuthenticator{Store: ti.Store, Hasher: ti.hasher()}
+	return sa.Authenticate(tenantID, user, pass)
+}
@@ -0,0 +1,326 @@
+package dauth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhaifley/dlib"
+)
+
+func TestHashToken(t *testing.T) {
+	exp := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	if v := HashToken("test"); v != exp {
+		t.Errorf("Value expected: %v, got: %v", exp, v)
+	}
+}
+
+func TestTokenIssuerIssue(t *testing.T) {
+	ms := NewMemoryStore()
+	ti := NewTokenIssuer(ms, time.Minute)
+	tk, err := ti.Issue(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tk.Token) != 64 {
+		t.Errorf("Length expected: 64, got: %v", len(tk.Token))
+	}
+
+	if d := tk.Expires.Sub(*tk.Created); d != time.Minute {
+		t.Errorf("TTL expected: %v, got: %v", time.Minute, d)
+	}
+
+	for r := range ms.GetTokens(&TokenFind{ID: &tk.ID}) {
+		if v := r.Val.(*Token).Token; v != HashToken(tk.Token) {
+			t.Errorf("Expected stored hash, got: %v", v)
+		}
+	}
+
+	other, _ := ti.Issue(1)
+	if other.Token == tk.Token {
+		t.Errorf("Expected unique tokens, got: %v twice", tk.Token)
+	}
+
+	ti.TTL = 0
+	tk, _ = ti.Issue(1)
+	if d := tk.Expires.Sub(*tk.Created); d != DefaultTokenTTL {
+		t.Errorf("TTL expected: %v, got: %v", DefaultTokenTTL, d)
+	}
+}
+
+func TestTokenIssuerFindRevoke(t *testing.T) {
+	ti := NewTokenIssuer(NewMemoryStore(), time.Minute)
+	tk, _ := ti.Issue(1)
+	cases := []struct {
+		token string
+		found bool
+	}{
+		{tk.Token, true},
+		{HashToken(tk.Token), false},
+		{"test", false},
+	}
+
+	for _, c := range cases {
+		v, err := ti.Find(c.token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if (v != nil) != c.found {
+			t.Errorf("Found expected: %v, got: %v", c.found, v != nil)
+		}
+	}
+
+	if _, err := ti.Logout(tk.Token); err != nil {
+		t.Error(err)
+	}
+
+	if v, _ := ti.Find(tk.Token); v != nil {
+		t.Errorf("Expected revoked token, got: %v", v)
+	}
+
+	_, err := ti.Revoke(tk.Token)
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 404 {
+		t.Errorf("Error expected: 404, got: %v", err)
+	}
+}
+
+func TestTokenIssuerLogin(t *testing.T) {
+	ti := NewTokenIssuer(newTestStore(), time.Minute)
+	ti.Hasher = testHasher
+	cases := []struct {
+		user string
+		pass string
+		code int
+	}{
+		{"test", "test", 0},
+		{"test", "wrong", 401},
+		{"none", "test", 401},
+	}
+
+	for _, c := range cases {
+		tk, err := ti.Login(0, c.user, c.pass)
+		if c.code == 0 {
+			if err != nil || tk.UserID != 1 {
+				t.Errorf("Expected token for user 1, got: %v, %v", tk, err)
+			}
+
+			continue
+		}
+
+		if e, ok := err.(*dlib.Error); !ok || e.Code != c.code {
+			t.Errorf("Error expected: %v, got: %v", c.code, err)
+		}
+	}
+}
+
+func TestTokenIssuerTouch(t *testing.T) {
+	ms := NewMemoryStore()
+	ti := NewTokenIssuer(ms, time.Hour)
+	now := time.Now()
+	created := now.Add(-2 * time.Hour)
+	cases := []struct {
+		sliding bool
+		max     time.Duration
+		expires time.Time
+		exp     time.Duration
+	}{
+		{false, 0, now.Add(time.Minute), time.Minute},
+		{true, 0, now.Add(time.Minute), time.Hour},
+		{true, 0, now.Add(50 * time.Minute), 50 * time.Minute},
+		{true, 170 * time.Minute, now.Add(time.Minute), 50 * time.Minute},
+		{true, 0, now.Add(-time.Minute), -time.Minute},
+	}
+
+	for _, c := range cases {
+		ti.Sliding = c.sliding
+		ti.MaxLifetime = c.max
+		exp := c.expires
+		tk := NewToken(0, HashToken("touch"), 1, &created, &exp)
+		ms.SaveToken(tk)
+		tk.Token = "touch"
+		if err := ti.Touch(tk); err != nil {
+			t.Fatal(err)
+		}
+
+		if d := tk.Expires.Sub(now).Round(time.Minute); d != c.exp {
+			t.Errorf("Expires expected: %v, got: %v", c.exp, d)
+		}
+
+		for r := range ms.GetTokens(&TokenFind{ID: &tk.ID}) {
+			st := r.Val.(*Token)
+			if st.Token != HashToken("touch") {
+				t.Errorf("Expected stored hash, got: %v", st.Token)
+			}
+
+			if !st.Expires.Equal(*tk.Expires) {
+				t.Errorf("Value expected: %v, got: %v", tk.Expires, st.Expires)
+			}
+		}
+	}
+}
+
+func TestTokenIssuerRefresh(t *testing.T) {
+	ms := NewMemoryStore()
+	ti := NewTokenIssuer(ms, time.Hour)
+	tk, _ := ti.Issue(1)
+	nt, err := ti.Refresh(tk.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if nt.Token == tk.Token || nt.UserID != 1 {
+		t.Errorf("Expected new token for user 1, got: %v", nt)
+	}
+
+	if !nt.Created.Equal(*tk.Created) {
+		t.Errorf("Created expected: %v, got: %v", tk.Created, nt.Created)
+	}
+
+	if f, _ := ti.Find(tk.Token); f != nil {
+		t.Errorf("Expected old token revoked, got: %v", f)
+	}
+
+	if _, err := ti.Refresh(tk.Token); err == nil {
+		t.Error("Expected error refreshing a revoked token")
+	}
+
+	ti.RefreshWindow = time.Minute
+	_, err = ti.Refresh(nt.Token)
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 400 {
+		t.Errorf("Error expected: 400, got: %v", err)
+	}
+
+	ti.RefreshWindow = 0
+	ti.MaxLifetime = time.Nanosecond
+	_, err = ti.Refresh(nt.Token)
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 401 {
+		t.Errorf("Error expected: 401, got: %v", err)
+	}
+}
+
+func TestTokenIssuerRestrict(t *testing.T) {
+	ms := NewMemoryStore()
+	ti := NewTokenIssuer(ms, time.Hour)
+	tk, _ := ti.Issue(1)
+	st, err := ti.Restrict(tk.Token, []string{"test:read.*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, _ := ti.Find(st.Token)
+	if f == nil || len(f.Scope) != 1 || f.Scope[0] != "test:read.*" {
+		t.Fatalf("Expected scoped token, got: %v", f)
+	}
+
+	if f.Expires.After(*tk.Expires) {
+		t.Errorf("Expires expected before: %v, got: %v", tk.Expires, f.Expires)
+	}
+
+	if _, err := ti.Restrict(st.Token, []string{"test:read.all"}); err != nil {
+		t.Errorf("Expected narrower scope, got: %v", err)
+	}
+
+	_, err = ti.Restrict(st.Token, []string{"test:write"})
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 400 {
+		t.Errorf("Error expected: 400, got: %v", err)
+	}
+
+	_, err = ti.Restrict("invalid", []string{"test:read"})
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 401 {
+		t.Errorf("Error expected: 401, got: %v", err)
+	}
+
+	rt, err := ti.Refresh(st.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rt.Scope) != 1 || rt.Scope[0] != "test:read.*" {
+		t.Errorf("Scope expected: [test:read.*], got: %v", rt.Scope)
+	}
+}
+
+func TestTokenIssuerIssueAPIKey(t *testing.T) {
+	ms := NewMemoryStore()
+	ti := NewTokenIssuer(ms, time.Minute)
+	k, err := ti.IssueAPIKey(&APIKey{Name: "test", UserID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !IsAPIKey(k.Key) || !strings.HasPrefix(k.Key, k.Prefix+"_") {
+		t.Errorf("Expected key with prefix %v, got: %v", k.Prefix, k.Key)
+	}
+
+	if len(k.Prefix) != len(APIKeyPrefix)+8 {
+		t.Errorf("Unexpected prefix: %v", k.Prefix)
+	}
+
+	for r := range ms.GetAPIKeys(&APIKeyFind{ID: &k.ID}) {
+		if v := r.Val.(*APIKey).Key; v != HashToken(k.Key) {
+			t.Errorf("Expected stored hash, got: %v", v)
+		}
+	}
+
+	f, err := ti.FindAPIKey(k.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f == nil || f.ID != k.ID {
+		t.Errorf("Expected key %v, got: %v", k.ID, f)
+	}
+
+	if f, _ := ti.FindAPIKey(k.Prefix + "_wrong"); f != nil {
+		t.Errorf("Expected no key, got: %v", f)
+	}
+}
+
+func TestTokenIssuerImpersonate(t *testing.T) {
+	ms := NewMemoryStore()
+	ms.SaveUser(&User{User: "target", TenantID: 2})
+	k, err := NewHMACKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ti := NewTokenIssuer(ms, time.Hour)
+	ti.Signer = NewTokenSigner(NewKeySet(k), time.Hour)
+	tk, err := ti.Impersonate(5, 1, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if IsSignedToken(tk.Token) {
+		t.Error("Expected opaque token")
+	}
+
+	if tk.UserID != 1 || tk.ActorID != 5 || tk.TenantID != 2 {
+		t.Errorf("Expected token for user 1 by actor 5, got: %v", tk)
+	}
+
+	if d := tk.Expires.Sub(*tk.Created); d != time.Minute {
+		t.Errorf("TTL expected: %v, got: %v", time.Minute, d)
+	}
+
+	f, err := ti.Find(tk.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f == nil || f.ActorID != 5 {
+		t.Errorf("ActorID expected: 5, got: %v", f)
+	}
+
+	_, err = ti.Refresh(tk.Token)
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 400 {
+		t.Errorf("Error expected: 400, got: %v", err)
+	}
+
+	_, err = ti.Impersonate(5, 9, time.Minute)
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 404 {
+		t.Errorf("Error expected: 404, got: %v", err)
+	}
+}
@@ -0,0 +1,330 @@
+package dauth
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/dhaifley/dlib"
+	"github.com/dhaifley/dlib/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+// Lockout values represent the failed login attempts recorded for a single
+// user name or client address. Either User or Addr is set. Logins for the
+// user name, or from the address, are refused until Until has passed. A
+// user name lockout belongs to the tenant of the user name, since user
+// names are only unique within a tenant. Address lockouts belong to the
+// super-tenant.
+type Lockout struct {
+	ID       int64      `json:"id,omitempty" bson:"_id"`
+	User     string     `json:"user,omitempty" bson:"user,omitempty"`
+	Addr     string     `json:"addr,omitempty" bson:"addr,omitempty"`
+	TenantID int64      `json:"tenant_id,omitempty" bson:"tenant_id"`
+	Failures int64      `json:"failures,omitempty" bson:"failures"`
+	Last     *time.Time `json:"last,omitempty" bson:"last,omitempty"`
+	Until    *time.Time `json:"until,omitempty" bson:"until,omitempty"`
+}
+
+// LockoutRow values represent a single row in the lockout table.
+type LockoutRow struct {
+	ID       int64
+	User     string
+	Addr     string
+	TenantID int64
+	Failures int64
+	Last     dlib.NullTime
+	Until    dlib.NullTime
+}
+
+// LockoutFind values are used to find lockout records in the database.
+// Expired finds lockouts whose Until is before it.
+type LockoutFind struct {
+	ID       *int64     `json:"id,omitempty"`
+	User     *string    `json:"user,omitempty"`
+	Addr     *string    `json:"addr,omitempty"`
+	TenantID *int64     `json:"tenant_id,omitempty"`
+	Expired  *time.Time `json:"expired,omitempty"`
+}
+
+// NewLockout initializes and returns a pointer to a new lockout value.
+func NewLockout(id int64, user, addr string, failures int64, last, until *time.Time) *Lockout {
+	return &Lockout{
+		ID:       id,
+		User:     user,
+		Addr:     addr,
+		Failures: failures,
+		Last:     last,
+		Until:    until,
+	}
+}
+
+// Locked tests whether the lockout refuses logins at a time.
+func (l *Lockout) Locked(now time.Time) bool {
+	return l.Until != nil && l.Until.After(now)
+}
+
+// Equals tests for deep equality between lockout values.
+func (l *Lockout) Equals(b *Lockout) bool {
+	switch {
+	case l == nil || b == nil:
+		return false
+	case l == b:
+		return true
+	case l.ID != b.ID || l.User != b.User || l.Addr != b.Addr:
+		return false
+	case l.TenantID != b.TenantID:
+		return false
+	case l.Failures != b.Failures:
+		return false
+	case (l.Last == nil) != (b.Last == nil):
+		return false
+	case l.Last != nil && !l.Last.Equal(*b.Last):
+		return false
+	case (l.Until == nil) != (b.Until == nil):
+		return false
+	case l.Until != nil && !l.Until.Equal(*b.Until):
+		return false
+	default:
+		return true
+	}
+}
+
+// Copy returns an exact copy of the value.
+func (l *Lockout) Copy() Lockout {
+	b := *l
+	if l.Last != nil {
+		d := *l.Last
+		b.Last = &d
+	}
+
+	if l.Until != nil {
+		d := *l.Until
+		b.Until = &d
+	}
+
+	return b
+}
+
+// String formats a lockout value as a JSON format string.
+func (l *Lockout) String() string {
+	str, err := json.Marshal(l)
+	if err != nil {
+		return ""
+	}
+
+	return string(str)
+}
+
+// FromRequest populates this value from a protobuf request.
+func (l *Lockout) FromRequest(req *ptypes.LockoutRequest) error {
+	l.ID = req.ID
+	l.User = req.User
+	l.Addr = req.Addr
+	l.TenantID = req.TenantID
+	l.Failures = req.Failures
+	l.Last = nil
+	if req.Last != nil {
+		tt := time.Unix(req.Last.Seconds, 0)
+		l.Last = &tt
+	}
+
+	l.Until = nil
+	if req.Until != nil {
+		tt := time.Unix(req.Until.Seconds, 0)
+		l.Until = &tt
+	}
+
+	return nil
+}
+
+// ToRequest returns a protobuf request created from this value.
+func (l *Lockout) ToRequest() ptypes.LockoutRequest {
+	req := ptypes.LockoutRequest{
+		ID:       l.ID,
+		User:     l.User,
+		Addr:     l.Addr,
+		TenantID: l.TenantID,
+		Failures: l.Failures,
+	}
+
+	if l.Last != nil {
+		req.Last = &timestamp.Timestamp{Seconds: l.Last.Unix()}
+	}
+
+	if l.Until != nil {
+		req.Until = &timestamp.Timestamp{Seconds: l.Until.Unix()}
+	}
+
+	return req
+}
+
+// FromResponse populates this value from a protobuf response.
+func (l *Lockout) FromResponse(res *ptypes.LockoutResponse) error {
+	l.ID = res.ID
+	l.User = res.User
+	l.Addr = res.Addr
+	l.TenantID = res.TenantID
+	l.Failures = res.Failures
+	l.Last = nil
+	if res.Last != nil {
+		tt := time.Unix(res.Last.Seconds, 0)
+		l.Last = &tt
+	}
+
+	l.Until = nil
+	if res.Until != nil {
+		tt := time.Unix(res.Until.Seconds, 0)
+		l.Until = &tt
+	}
+
+	return nil
+}
+
+// ToResponse returns a protobuf response created from this value.
+func (l *Lockout) ToResponse() ptypes.LockoutResponse {
+	res := ptypes.LockoutResponse{
+		ID:       l.ID,
+		User:     l.User,
+		Addr:     l.Addr,
+		TenantID: l.TenantID,
+		Failures: l.Failures,
+	}
+
+	if l.Last != nil {
+		res.Last = &timestamp.Timestamp{Seconds: l.Last.Unix()}
+	}
+
+	if l.Until != nil {
+		res.Until = &timestamp.Timestamp{Seconds: l.Until.Unix()}
+	}
+
+	return res
+}
+
+// FromQueryValues populates this value from a query string map.
+func (l *Lockout) FromQueryValues(vals url.Values) error {
+	var err error
+	l.ID = 0
+	if vals.Get("id") != "" {
+		l.ID, err = strconv.ParseInt(vals.Get("id"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	l.User = vals.Get("user")
+	l.Addr = vals.Get("addr")
+	l.TenantID = 0
+	if vals.Get("tenant_id") != "" {
+		l.TenantID, err = strconv.ParseInt(vals.Get("tenant_id"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	l.Failures = 0
+	if vals.Get("failures") != "" {
+		l.Failures, err = strconv.ParseInt(vals.Get("failures"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FromLockout populates a row value from a lockout value.
+func (r *LockoutRow) FromLockout(l *Lockout) error {
+	r.ID = l.ID
+	r.User = l.User
+	r.Addr = l.Addr
+	r.TenantID = l.TenantID
+	r.Failures = l.Failures
+	r.Last = dlib.NullTime{}
+	if l.Last != nil {
+		r.Last = dlib.NullTime{Valid: true, Time: *l.Last}
+	}
+
+	r.Until = dlib.NullTime{}
+	if l.Until != nil {
+		r.Until = dlib.NullTime{Valid: true, Time: *l.Until}
+	}
+
+	return nil
+}
+
+// ToLockout returns a value created from this row value.
+func (r *LockoutRow) ToLockout() Lockout {
+	l := Lockout{
+		ID:       r.ID,
+		User:     r.User,
+		Addr:     r.Addr,
+		TenantID: r.TenantID,
+		Failures: r.Failures,
+	}
+
+	if r.Last.Valid {
+		tt := r.Last.Time
+		l.Last = &tt
+	}
+
+	if r.Until.Valid {
+		tt := r.Until.Time
+		l.Until = &tt
+	}
+
+	return l
+}
+
+// FromLockout populates a lockout find value from a lockout value.
+func (f *LockoutFind) FromLockout(l *Lockout) error {
+	f.ID = nil
+	if l.ID != 0 {
+		f.ID = &l.ID
+	}
+
+	f.User = nil
+	if l.User != "" {
+		f.User = &l.User
+	}
+
+	f.Addr = nil
+	if l.Addr != "" {
+		f.Addr = &l.Addr
+	}
+
+	f.TenantID = nil
+	if l.TenantID != 0 {
+		f.TenantID = &l.TenantID
+	}
+
+	return nil
+}
+
+// FromLockoutRequest populates a lockout find value from a lockout protobuf
+// request.
+func (f *LockoutFind) FromLockoutRequest(r *ptypes.LockoutRequest) error {
+	f.ID = nil
+	if r.ID != 0 {
+		f.ID = &r.ID
+	}
+
+	f.User = nil
+	if r.User != "" {
+		f.User = &r.User
+	}
+
+	f.Addr = nil
+	if r.Addr != "" {
+		f.Addr = &r.Addr
+	}
+
+	f.TenantID = nil
+	if r.TenantID != 0 {
+		f.TenantID = &r.TenantID
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package dauth
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dhaifley/dlib/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+func TestLockoutLocked(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+	cases := []struct {
+		l        *Lockout
+		expected bool
+	}{
+		{NewLockout(1, "test", "", 1, &now, nil), false},
+		{NewLockout(1, "test", "", 1, &now, &past), false},
+		{NewLockout(1, "test", "", 1, &now, &future), true},
+	}
+
+	for _, c := range cases {
+		if result := c.l.Locked(now); result != c.expected {
+			t.Errorf("Expected bool: %v, got: %v", c.expected, result)
+		}
+	}
+}
+
+func TestLockoutEquals(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Minute)
+	cases := []struct {
+		a        *Lockout
+		b        *Lockout
+		expected bool
+	}{
+		{
+			a:        NewLockout(1, "test", "", 1, &now, &later),
+			b:        NewLockout(1, "test", "", 1, &now, &later),
+			expected: true,
+		},
+		{
+			a:        NewLockout(1, "test", "", 1, &now, &later),
+			b:        NewLockout(1, "test", "", 1, &now, nil),
+			expected: false,
+		},
+		{
+			a:        NewLockout(1, "test", "", 1, &now, &later),
+			b:        NewLockout(1, "test", "", 2, &now, &later),
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		result := c.a.Equals(c.b)
+		if result != c.expected {
+			t.Errorf("Expected bool: %v, got: %v", c.expected, result)
+		}
+	}
+}
+
+func TestLockoutCopy(t *testing.T) {
+	now := time.Now()
+	a := NewLockout(1, "test", "", 1, &now, &now)
+	result := a.Copy()
+	if !result.Equals(a) {
+		t.Errorf("Expected lockout: %v, got: %v", a, result)
+	}
+
+	if result.Until == a.Until {
+		t.Error("Expected copied time values")
+	}
+}
+
+func TestLockoutString(t *testing.T) {
+	a := Lockout{ID: 1, Addr: "127.0.0.1", Failures: 2}
+	expected := `{"id":1,"addr":"127.0.0.1","failures":2}`
+	result := a.String()
+	if result != expected {
+		t.Errorf("Expected string: %v, got: %v", expected, result)
+	}
+}
+
+func TestLockoutFromRequest(t *testing.T) {
+	req := ptypes.LockoutRequest{
+		ID:       1,
+		User:     "test",
+		Failures: 3,
+		Until:    &timestamp.Timestamp{Seconds: 1514764800},
+	}
+
+	dv := Lockout{}
+	if err := dv.FromRequest(&req); err != nil {
+		t.Error(err)
+	}
+
+	if dv.Failures != 3 {
+		t.Errorf("Failures expected: 3, got: %v", dv.Failures)
+	}
+
+	if dv.Until == nil || dv.Until.Unix() != 1514764800 {
+		t.Errorf("Until expected: 1514764800, got: %v", dv.Until)
+	}
+}
+
+func TestLockoutToResponse(t *testing.T) {
+	now := time.Now()
+	a := NewLockout(1, "test", "", 1, &now, nil)
+	res := a.ToResponse()
+	if res.User != "test" || res.Last == nil || res.Until != nil {
+		t.Errorf("Unexpected response: %v", res)
+	}
+}
+
+func TestLockoutFromQueryValues(t *testing.T) {
+	vals := url.Values{}
+	vals.Set("id", "1")
+	vals.Set("addr", "127.0.0.1")
+	vals.Set("failures", "2")
+	dv := Lockout{}
+	if err := dv.FromQueryValues(vals); err != nil {
+		t.Error(err)
+	}
+
+	if dv.Addr != "127.0.0.1" || dv.Failures != 2 {
+		t.Errorf("Unexpected lockout: %v", dv)
+	}
+}
+
+func TestLockoutRow(t *testing.T) {
+	now := time.Now()
+	a := NewLockout(1, "test", "", 1, &now, nil)
+	r := LockoutRow{}
+	if err := r.FromLockout(a); err != nil {
+		t.Error(err)
+	}
+
+	if !r.Last.Valid || r.Until.Valid {
+		t.Errorf("Unexpected row: %v", r)
+	}
+
+	result := r.ToLockout()
+	if !result.Equals(a) {
+		t.Errorf("Expected lockout: %v, got: %v", a, result)
+	}
+}
+
+func TestLockoutFindFromLockoutRequest(t *testing.T) {
+	f := LockoutFind{}
+	if err := f.FromLockoutRequest(&ptypes.LockoutRequest{User: "test"}); err != nil {
+		t.Error(err)
+	}
+
+	if f.User == nil || *f.User != "test" || f.ID != nil || f.Addr != nil {
+		t.Errorf("Unexpected find: %v", f)
+	}
+}
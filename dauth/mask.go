@@ -0,0 +1,66 @@
+package dauth
+
+import (
+	"strings"
+
+	"github.com/dhaifley/dlib"
+	"google.golang.org/genproto/protobuf/field_mask"
+)
+
+// UpdateMask values hold the fields of a record which are updated by a save
+// request. Requests without a field mask leave the fields which are empty in
+// the request unchanged. Requests with a field mask update exactly the
+// fields named in the mask, so that a field in the mask which is empty in
+// the request is cleared, and a field not in the mask is left unchanged,
+// even if it is set in the request.
+//
+// Each field is therefore either left unchanged or set, and a field set to
+// empty is cleared. Unset and set to empty are the same state. Request
+// fields are plain protobuf scalars, so an empty field can not be told apart
+// from an unset one, and stores keep empty optional fields, such as the name
+// and email of a user, as NULL, which is read back as empty. Fields which
+// must never be empty, such as the pass of a user, reject a mask which would
+// clear them.
+type UpdateMask map[string]bool
+
+// maskField normalizes a field mask path so that paths may be given either
+// as protobuf field names or as JSON field names.
+func maskField(path string) string {
+	return strings.ToLower(strings.Replace(path, "_", "", -1))
+}
+
+// NewUpdateMask returns the update mask for a protobuf field mask which may
+// name any of a set of fields. It returns nil if the field mask is nil or
+// empty, and a 400 error if it names any other field.
+func NewUpdateMask(fm *field_mask.FieldMask, fields ...string) (UpdateMask, error) {
+	if fm == nil || len(fm.Paths) == 0 {
+		return nil, nil
+	}
+
+	valid := map[string]bool{}
+	for _, f := range fields {
+		valid[maskField(f)] = true
+	}
+
+	m := UpdateMask{}
+	for _, p := range fm.Paths {
+		f := maskField(p)
+		if !valid[f] {
+			return nil, dlib.NewError(400, "invalid update mask path: "+p)
+		}
+
+		m[f] = true
+	}
+
+	return m, nil
+}
+
+// Applies tests whether a field is updated by a request in which it is set,
+// or not set, as indicated.
+func (m UpdateMask) Applies(field string, set bool) bool {
+	if m == nil {
+		return set
+	}
+
+	return m[maskField(field)]
+}
@@ -0,0 +1,68 @@
+package dauth
+
+import (
+	"testing"
+
+	"github.com/dhaifley/dlib"
+	"google.golang.org/genproto/protobuf/field_mask"
+)
+
+func TestNewUpdateMask(t *testing.T) {
+	cases := []struct {
+		paths []string
+		null  bool
+		code  int
+	}{
+		{nil, true, 0},
+		{[]string{}, true, 0},
+		{[]string{"name", "tenant_id"}, false, 0},
+		{[]string{"Name", "TenantID"}, false, 0},
+		{[]string{"pass"}, false, 400},
+	}
+
+	for _, c := range cases {
+		var fm *field_mask.FieldMask
+		if c.paths != nil {
+			fm = &field_mask.FieldMask{Paths: c.paths}
+		}
+
+		m, err := NewUpdateMask(fm, "name", "tenant_id")
+		if c.code != 0 {
+			if e, ok := err.(*dlib.Error); !ok || e.Code != c.code {
+				t.Errorf("Error expected: %v, got: %v", c.code, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if (m == nil) != c.null {
+			t.Errorf("Nil expected: %v, got: %v", c.null, m)
+		}
+	}
+}
+
+func TestUpdateMaskApplies(t *testing.T) {
+	m := UpdateMask{"name": true, "tenantid": true}
+	cases := []struct {
+		m     UpdateMask
+		field string
+		set   bool
+		exp   bool
+	}{
+		{nil, "name", true, true},
+		{nil, "name", false, false},
+		{m, "name", false, true},
+		{m, "tenant_id", true, true},
+		{m, "email", true, false},
+	}
+
+	for _, c := range cases {
+		if got := c.m.Applies(c.field, c.set); got != c.exp {
+			t.Errorf("Value expected: %v, got: %v", c.exp, got)
+		}
+	}
+}
@@ -0,0 +1,1003 @@
+package dauth
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/dhaifley/dlib"
+)
+
+// MemoryStore values implement the Store interface by holding all values
+// in memory. They are safe for concurrent use and are intended for testing.
+type MemoryStore struct {
+	mu             sync.RWMutex
+	tokens         map[int64]Token
+	users          map[int64]User
+	perms          map[int64]Perm
+	userPerms      map[int64]UserPerm
+	roles          map[int64]Role
+	rolePerms      map[int64]RolePerm
+	userRoles      map[int64]UserRole
+	lockouts       map[int64]Lockout
+	userMFAs       map[int64]UserMFA
+	recoveryCodes  map[int64]RecoveryCode
+	apiKeys        map[int64]APIKey
+	tokenID        int64
+	userID         int64
+	permID         int64
+	userPermID     int64
+	roleID         int64
+	rolePermID     int64
+	userRoleID     int64
+	lockoutID      int64
+	userMFAID      int64
+	recoveryCodeID int64
+	apiKeyID       int64
+}
+
+// NewMemoryStore initializes and returns a pointer to a new, empty memory
+// store value.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		tokens:        make(map[int64]Token),
+		users:         make(map[int64]User),
+		perms:         make(map[int64]Perm),
+		userPerms:     make(map[int64]UserPerm),
+		roles:         make(map[int64]Role),
+		rolePerms:     make(map[int64]RolePerm),
+		userRoles:     make(map[int64]UserRole),
+		lockouts:      make(map[int64]Lockout),
+		userMFAs:      make(map[int64]UserMFA),
+		recoveryCodes: make(map[int64]RecoveryCode),
+		apiKeys:       make(map[int64]APIKey),
+	}
+}
+
+// sortedIDs returns the keys of an ID set in ascending order.
+func sortedIDs(ids []int64) []int64 {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// hasID tests whether a list of IDs contains an ID.
+func hasID(ids []int64, id int64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nextID assigns an ID to a value being saved if it does not have one.
+func nextID(seq *int64, id int64) int64 {
+	if id == 0 {
+		*seq++
+		return *seq
+	}
+
+	return id
+}
+
+// matchToken tests whether a token value satisfies a token find value.
+func matchToken(f *TokenFind, t *Token) bool {
+	switch {
+	case f.ID != nil && *f.ID != t.ID:
+		return false
+	case f.Token != nil && *f.Token != t.Token:
+		return false
+	case f.UserID != nil && *f.UserID != t.UserID:
+		return false
+	case f.Created != nil && (t.Created == nil || !t.Created.Equal(*f.Created)):
+		return false
+	case f.Expires != nil && (t.Expires == nil || !t.Expires.Equal(*f.Expires)):
+		return false
+	case f.Start != nil && (t.Created == nil || t.Created.Before(*f.Start)):
+		return false
+	case f.End != nil && (t.Created == nil || t.Created.After(*f.End)):
+		return false
+	case f.Old != nil && (t.Created == nil || !t.Created.Before(*f.Old)):
+		return false
+	case f.Expired != nil && (t.Expires == nil || !t.Expires.Before(*f.Expired)):
+		return false
+	case f.TenantID != nil && *f.TenantID != t.TenantID:
+		return false
+	case f.ActorID != nil && *f.ActorID != t.ActorID:
+		return false
+	default:
+		return true
+	}
+}
+
+// GetTokens finds tokens in the store.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) GetTokens(f *TokenFind) <-chan dlib.Result {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	ids := []int64{}
+	for id, t := range ms.tokens {
+		if matchToken(f, &t) {
+			ids = append(ids, id)
+		}
+	}
+
+	c := make(chan dlib.Result, len(ids))
+	for _, id := range f.Page.ids(ids) {
+		t := ms.tokens[id]
+		tv := t.Copy()
+		c <- dlib.Result{Val: &tv}
+	}
+
+	close(c)
+	return c
+}
+
+// SaveToken inserts or updates a token in the store. A token with no ID
+// is inserted and receives a new ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) SaveToken(t *Token) <-chan dlib.Result {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if _, ok := ms.tokens[t.ID]; t.ID != 0 && !ok {
+		return errorResult(errNotFound)
+	}
+
+	t.ID = nextID(&ms.tokenID, t.ID)
+	ms.tokens[t.ID] = t.Copy()
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Val: t, Num: 1}
+	close(c)
+	return c
+}
+
+// DeleteTokens deletes tokens from the store. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) DeleteTokens(f *TokenFind) <-chan dlib.Result {
+	if *f == (TokenFind{}) {
+		return errorResult(errNoCriteria)
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	n := 0
+	for id, t := range ms.tokens {
+		if matchToken(f, &t) {
+			delete(ms.tokens, id)
+			n++
+		}
+	}
+
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Num: n}
+	close(c)
+	return c
+}
+
+// matchUser tests whether a user value satisfies a user find value.
+func matchUser(f *UserFind, u *User) bool {
+	switch {
+	case f.ID != nil && *f.ID != u.ID:
+		return false
+	case f.User != nil && *f.User != u.User:
+		return false
+	case f.Pass != nil && *f.Pass != u.Pass:
+		return false
+	case f.Name != nil && *f.Name != u.Name:
+		return false
+	case f.Email != nil && *f.Email != u.Email:
+		return false
+	case f.TenantID != nil && *f.TenantID != u.TenantID:
+		return false
+	case f.Provider != nil && *f.Provider != u.Provider:
+		return false
+	default:
+		return true
+	}
+}
+
+// GetUsers finds users in the store.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) GetUsers(f *UserFind) <-chan dlib.Result {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	ids := []int64{}
+	for id, u := range ms.users {
+		if matchUser(f, &u) {
+			ids = append(ids, id)
+		}
+	}
+
+	c := make(chan dlib.Result, len(ids))
+	for _, id := range f.Page.ids(ids) {
+		u := ms.users[id]
+		uv := u.Copy()
+		c <- dlib.Result{Val: &uv}
+	}
+
+	close(c)
+	return c
+}
+
+// SaveUser inserts or updates a user in the store. A user with no ID
+// is inserted and receives a new ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) SaveUser(u *User) <-chan dlib.Result {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if _, ok := ms.users[u.ID]; u.ID != 0 && !ok {
+		return errorResult(errNotFound)
+	}
+
+	u.ID = nextID(&ms.userID, u.ID)
+	ms.users[u.ID] = u.Copy()
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Val: u, Num: 1}
+	close(c)
+	return c
+}
+
+// DeleteUsers deletes users from the store. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) DeleteUsers(f *UserFind) <-chan dlib.Result {
+	if *f == (UserFind{}) {
+		return errorResult(errNoCriteria)
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	n := 0
+	for id, u := range ms.users {
+		if matchUser(f, &u) {
+			delete(ms.users, id)
+			n++
+		}
+	}
+
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Num: n}
+	close(c)
+	return c
+}
+
+// matchPerm tests whether a perm value satisfies a perm find value.
+func matchPerm(f *PermFind, p *Perm) bool {
+	switch {
+	case f.ID != nil && *f.ID != p.ID:
+		return false
+	case f.IDs != nil && !hasID(f.IDs, p.ID):
+		return false
+	case f.Service != nil && *f.Service != p.Service:
+		return false
+	case f.Name != nil && *f.Name != p.Name:
+		return false
+	case f.TenantID != nil && *f.TenantID != p.TenantID:
+		return false
+	default:
+		return true
+	}
+}
+
+// GetPerms finds permissions in the store.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) GetPerms(f *PermFind) <-chan dlib.Result {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	ids := []int64{}
+	for id, p := range ms.perms {
+		if matchPerm(f, &p) {
+			ids = append(ids, id)
+		}
+	}
+
+	c := make(chan dlib.Result, len(ids))
+	for _, id := range f.Page.ids(ids) {
+		p := ms.perms[id]
+		pv := p.Copy()
+		c <- dlib.Result{Val: &pv}
+	}
+
+	close(c)
+	return c
+}
+
+// SavePerm inserts or updates a permission in the store. A permission
+// with no ID is inserted and receives a new ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) SavePerm(p *Perm) <-chan dlib.Result {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if _, ok := ms.perms[p.ID]; p.ID != 0 && !ok {
+		return errorResult(errNotFound)
+	}
+
+	p.ID = nextID(&ms.permID, p.ID)
+	ms.perms[p.ID] = p.Copy()
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Val: p, Num: 1}
+	close(c)
+	return c
+}
+
+// DeletePerms deletes permissions from the store. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) DeletePerms(f *PermFind) <-chan dlib.Result {
+	if f.Empty() {
+		return errorResult(errNoCriteria)
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	n := 0
+	for id, p := range ms.perms {
+		if matchPerm(f, &p) {
+			delete(ms.perms, id)
+			n++
+		}
+	}
+
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Num: n}
+	close(c)
+	return c
+}
+
+// matchUserPerm tests whether a user_perm value satisfies a user_perm
+// find value.
+func matchUserPerm(f *UserPermFind, up *UserPerm) bool {
+	switch {
+	case f.ID != nil && *f.ID != up.ID:
+		return false
+	case f.UserID != nil && *f.UserID != up.UserID:
+		return false
+	case f.PermID != nil && *f.PermID != up.PermID:
+		return false
+	case f.TenantID != nil && *f.TenantID != up.TenantID:
+		return false
+	case f.Expired != nil && (up.Expires == nil || !up.Expires.Before(*f.Expired)):
+		return false
+	default:
+		return true
+	}
+}
+
+// GetUserPerms finds user permissions in the store.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) GetUserPerms(f *UserPermFind) <-chan dlib.Result {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	ids := []int64{}
+	for id, up := range ms.userPerms {
+		if matchUserPerm(f, &up) {
+			ids = append(ids, id)
+		}
+	}
+
+	c := make(chan dlib.Result, len(ids))
+	for _, id := range f.Page.ids(ids) {
+		up := ms.userPerms[id]
+		upv := up.Copy()
+		c <- dlib.Result{Val: &upv}
+	}
+
+	close(c)
+	return c
+}
+
+// SaveUserPerm inserts or updates a user permission in the store. A user
+// permission with no ID is inserted and receives a new ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) SaveUserPerm(up *UserPerm) <-chan dlib.Result {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if _, ok := ms.userPerms[up.ID]; up.ID != 0 && !ok {
+		return errorResult(errNotFound)
+	}
+
+	up.ID = nextID(&ms.userPermID, up.ID)
+	ms.userPerms[up.ID] = up.Copy()
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Val: up, Num: 1}
+	close(c)
+	return c
+}
+
+// DeleteUserPerms deletes user permissions from the store. At least one
+// search criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) DeleteUserPerms(f *UserPermFind) <-chan dlib.Result {
+	if *f == (UserPermFind{}) {
+		return errorResult(errNoCriteria)
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	n := 0
+	for id, up := range ms.userPerms {
+		if matchUserPerm(f, &up) {
+			delete(ms.userPerms, id)
+			n++
+		}
+	}
+
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Num: n}
+	close(c)
+	return c
+}
+
+// matchRole tests whether a role value satisfies a role find value.
+func matchRole(f *RoleFind, ro *Role) bool {
+	switch {
+	case f.ID != nil && *f.ID != ro.ID:
+		return false
+	case f.ParentID != nil && *f.ParentID != ro.ParentID:
+		return false
+	case f.Name != nil && *f.Name != ro.Name:
+		return false
+	case f.TenantID != nil && *f.TenantID != ro.TenantID:
+		return false
+	default:
+		return true
+	}
+}
+
+// GetRoles finds roles in the store.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) GetRoles(f *RoleFind) <-chan dlib.Result {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	ids := []int64{}
+	for id, ro := range ms.roles {
+		if matchRole(f, &ro) {
+			ids = append(ids, id)
+		}
+	}
+
+	c := make(chan dlib.Result, len(ids))
+	for _, id := range sortedIDs(ids) {
+		ro := ms.roles[id]
+		rv := ro.Copy()
+		c <- dlib.Result{Val: &rv}
+	}
+
+	close(c)
+	return c
+}
+
+// SaveRole inserts or updates a role in the store. A role with
+// no ID is inserted and receives a new ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) SaveRole(ro *Role) <-chan dlib.Result {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if _, ok := ms.roles[ro.ID]; ro.ID != 0 && !ok {
+		return errorResult(errNotFound)
+	}
+
+	ro.ID = nextID(&ms.roleID, ro.ID)
+	ms.roles[ro.ID] = ro.Copy()
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Val: ro, Num: 1}
+	close(c)
+	return c
+}
+
+// DeleteRoles deletes roles from the store. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) DeleteRoles(f *RoleFind) <-chan dlib.Result {
+	if *f == (RoleFind{}) {
+		return errorResult(errNoCriteria)
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	n := 0
+	for id, ro := range ms.roles {
+		if matchRole(f, &ro) {
+			delete(ms.roles, id)
+			n++
+		}
+	}
+
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Num: n}
+	close(c)
+	return c
+}
+
+// matchRolePerm tests whether a role_perm value satisfies a role_perm
+// find value.
+func matchRolePerm(f *RolePermFind, rp *RolePerm) bool {
+	switch {
+	case f.ID != nil && *f.ID != rp.ID:
+		return false
+	case f.RoleID != nil && *f.RoleID != rp.RoleID:
+		return false
+	case f.RoleIDs != nil && !hasID(f.RoleIDs, rp.RoleID):
+		return false
+	case f.PermID != nil && *f.PermID != rp.PermID:
+		return false
+	case f.TenantID != nil && *f.TenantID != rp.TenantID:
+		return false
+	default:
+		return true
+	}
+}
+
+// GetRolePerms finds role permissions in the store.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) GetRolePerms(f *RolePermFind) <-chan dlib.Result {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	ids := []int64{}
+	for id, rp := range ms.rolePerms {
+		if matchRolePerm(f, &rp) {
+			ids = append(ids, id)
+		}
+	}
+
+	c := make(chan dlib.Result, len(ids))
+	for _, id := range sortedIDs(ids) {
+		rp := ms.rolePerms[id]
+		rpv := rp.Copy()
+		c <- dlib.Result{Val: &rpv}
+	}
+
+	close(c)
+	return c
+}
+
+// SaveRolePerm inserts or updates a role permission in the store. A user
+// permission with no ID is inserted and receives a new ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) SaveRolePerm(rp *RolePerm) <-chan dlib.Result {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if _, ok := ms.rolePerms[rp.ID]; rp.ID != 0 && !ok {
+		return errorResult(errNotFound)
+	}
+
+	rp.ID = nextID(&ms.rolePermID, rp.ID)
+	ms.rolePerms[rp.ID] = rp.Copy()
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Val: rp, Num: 1}
+	close(c)
+	return c
+}
+
+// DeleteRolePerms deletes role permissions from the store. At least one
+// search criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) DeleteRolePerms(f *RolePermFind) <-chan dlib.Result {
+	if f.Empty() {
+		return errorResult(errNoCriteria)
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	n := 0
+	for id, rp := range ms.rolePerms {
+		if matchRolePerm(f, &rp) {
+			delete(ms.rolePerms, id)
+			n++
+		}
+	}
+
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Num: n}
+	close(c)
+	return c
+}
+
+// matchUserRole tests whether a user_role value satisfies a user_role
+// find value.
+func matchUserRole(f *UserRoleFind, ur *UserRole) bool {
+	switch {
+	case f.ID != nil && *f.ID != ur.ID:
+		return false
+	case f.UserID != nil && *f.UserID != ur.UserID:
+		return false
+	case f.RoleID != nil && *f.RoleID != ur.RoleID:
+		return false
+	case f.TenantID != nil && *f.TenantID != ur.TenantID:
+		return false
+	default:
+		return true
+	}
+}
+
+// GetUserRoles finds user roles in the store.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) GetUserRoles(f *UserRoleFind) <-chan dlib.Result {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	ids := []int64{}
+	for id, ur := range ms.userRoles {
+		if matchUserRole(f, &ur) {
+			ids = append(ids, id)
+		}
+	}
+
+	c := make(chan dlib.Result, len(ids))
+	for _, id := range sortedIDs(ids) {
+		ur := ms.userRoles[id]
+		urv := ur.Copy()
+		c <- dlib.Result{Val: &urv}
+	}
+
+	close(c)
+	return c
+}
+
+// SaveUserRole inserts or updates a user role in the store. A user
+// permission with no ID is inserted and receives a new ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) SaveUserRole(ur *UserRole) <-chan dlib.Result {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if _, ok := ms.userRoles[ur.ID]; ur.ID != 0 && !ok {
+		return errorResult(errNotFound)
+	}
+
+	ur.ID = nextID(&ms.userRoleID, ur.ID)
+	ms.userRoles[ur.ID] = ur.Copy()
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Val: ur, Num: 1}
+	close(c)
+	return c
+}
+
+// DeleteUserRoles deletes user roles from the store. At least one
+// search criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) DeleteUserRoles(f *UserRoleFind) <-chan dlib.Result {
+	if *f == (UserRoleFind{}) {
+		return errorResult(errNoCriteria)
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	n := 0
+	for id, ur := range ms.userRoles {
+		if matchUserRole(f, &ur) {
+			delete(ms.userRoles, id)
+			n++
+		}
+	}
+
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Num: n}
+	close(c)
+	return c
+}
+
+// matchLockout tests whether a lockout value satisfies a lockout find value.
+func matchLockout(f *LockoutFind, l *Lockout) bool {
+	switch {
+	case f.ID != nil && *f.ID != l.ID:
+		return false
+	case f.User != nil && *f.User != l.User:
+		return false
+	case f.Addr != nil && *f.Addr != l.Addr:
+		return false
+	case f.TenantID != nil && *f.TenantID != l.TenantID:
+		return false
+	case f.Expired != nil && (l.Until == nil || !l.Until.Before(*f.Expired)):
+		return false
+	default:
+		return true
+	}
+}
+
+// GetLockouts finds lockouts in the store.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) GetLockouts(f *LockoutFind) <-chan dlib.Result {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	ids := []int64{}
+	for id, l := range ms.lockouts {
+		if matchLockout(f, &l) {
+			ids = append(ids, id)
+		}
+	}
+
+	c := make(chan dlib.Result, len(ids))
+	for _, id := range sortedIDs(ids) {
+		l := ms.lockouts[id]
+		lv := l.Copy()
+		c <- dlib.Result{Val: &lv}
+	}
+
+	close(c)
+	return c
+}
+
+// SaveLockout inserts or updates a lockout in the store. A lockout with
+// no ID is inserted and receives a new ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) SaveLockout(l *Lockout) <-chan dlib.Result {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if _, ok := ms.lockouts[l.ID]; l.ID != 0 && !ok {
+		return errorResult(errNotFound)
+	}
+
+	l.ID = nextID(&ms.lockoutID, l.ID)
+	ms.lockouts[l.ID] = l.Copy()
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Val: l, Num: 1}
+	close(c)
+	return c
+}
+
+// DeleteLockouts deletes lockouts from the store. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) DeleteLockouts(f *LockoutFind) <-chan dlib.Result {
+	if *f == (LockoutFind{}) {
+		return errorResult(errNoCriteria)
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	n := 0
+	for id, l := range ms.lockouts {
+		if matchLockout(f, &l) {
+			delete(ms.lockouts, id)
+			n++
+		}
+	}
+
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Num: n}
+	close(c)
+	return c
+}
+
+// matchUserMFA tests whether a user MFA value satisfies a user MFA find value.
+func matchUserMFA(f *UserMFAFind, um *UserMFA) bool {
+	switch {
+	case f.ID != nil && *f.ID != um.ID:
+		return false
+	case f.UserID != nil && *f.UserID != um.UserID:
+		return false
+	case f.Enabled != nil && *f.Enabled != um.Enabled:
+		return false
+	default:
+		return true
+	}
+}
+
+// GetUserMFAs finds user MFAs in the store.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) GetUserMFAs(f *UserMFAFind) <-chan dlib.Result {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	ids := []int64{}
+	for id, um := range ms.userMFAs {
+		if matchUserMFA(f, &um) {
+			ids = append(ids, id)
+		}
+	}
+
+	c := make(chan dlib.Result, len(ids))
+	for _, id := range sortedIDs(ids) {
+		um := ms.userMFAs[id]
+		v := um.Copy()
+		c <- dlib.Result{Val: &v}
+	}
+
+	close(c)
+	return c
+}
+
+// SaveUserMFA inserts or updates a user MFA in the store. A user MFA with
+// no ID is inserted and receives a new ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) SaveUserMFA(um *UserMFA) <-chan dlib.Result {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if _, ok := ms.userMFAs[um.ID]; um.ID != 0 && !ok {
+		return errorResult(errNotFound)
+	}
+
+	um.ID = nextID(&ms.userMFAID, um.ID)
+	ms.userMFAs[um.ID] = um.Copy()
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Val: um, Num: 1}
+	close(c)
+	return c
+}
+
+// DeleteUserMFAs deletes user MFAs from the store. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) DeleteUserMFAs(f *UserMFAFind) <-chan dlib.Result {
+	if *f == (UserMFAFind{}) {
+		return errorResult(errNoCriteria)
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	n := 0
+	for id, um := range ms.userMFAs {
+		if matchUserMFA(f, &um) {
+			delete(ms.userMFAs, id)
+			n++
+		}
+	}
+
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Num: n}
+	close(c)
+	return c
+}
+
+// matchRecoveryCode tests whether a recovery code value satisfies a recovery code find value.
+func matchRecoveryCode(f *RecoveryCodeFind, rc *RecoveryCode) bool {
+	switch {
+	case f.ID != nil && *f.ID != rc.ID:
+		return false
+	case f.UserID != nil && *f.UserID != rc.UserID:
+		return false
+	case f.Code != nil && *f.Code != rc.Code:
+		return false
+	default:
+		return true
+	}
+}
+
+// GetRecoveryCodes finds recovery codes in the store.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) GetRecoveryCodes(f *RecoveryCodeFind) <-chan dlib.Result {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	ids := []int64{}
+	for id, rc := range ms.recoveryCodes {
+		if matchRecoveryCode(f, &rc) {
+			ids = append(ids, id)
+		}
+	}
+
+	c := make(chan dlib.Result, len(ids))
+	for _, id := range sortedIDs(ids) {
+		rc := ms.recoveryCodes[id]
+		v := rc.Copy()
+		c <- dlib.Result{Val: &v}
+	}
+
+	close(c)
+	return c
+}
+
+// SaveRecoveryCode inserts or updates a recovery code in the store. A recovery code with
+// no ID is inserted and receives a new ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) SaveRecoveryCode(rc *RecoveryCode) <-chan dlib.Result {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if _, ok := ms.recoveryCodes[rc.ID]; rc.ID != 0 && !ok {
+		return errorResult(errNotFound)
+	}
+
+	rc.ID = nextID(&ms.recoveryCodeID, rc.ID)
+	ms.recoveryCodes[rc.ID] = rc.Copy()
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Val: rc, Num: 1}
+	close(c)
+	return c
+}
+
+// DeleteRecoveryCodes deletes recovery codes from the store. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) DeleteRecoveryCodes(f *RecoveryCodeFind) <-chan dlib.Result {
+	if *f == (RecoveryCodeFind{}) {
+		return errorResult(errNoCriteria)
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	n := 0
+	for id, rc := range ms.recoveryCodes {
+		if matchRecoveryCode(f, &rc) {
+			delete(ms.recoveryCodes, id)
+			n++
+		}
+	}
+
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Num: n}
+	close(c)
+	return c
+}
+
+// matchAPIKey tests whether a API key value satisfies a API key find value.
+func matchAPIKey(f *APIKeyFind, k *APIKey) bool {
+	switch {
+	case f.ID != nil && *f.ID != k.ID:
+		return false
+	case f.Name != nil && *f.Name != k.Name:
+		return false
+	case f.Prefix != nil && *f.Prefix != k.Prefix:
+		return false
+	case f.Key != nil && *f.Key != k.Key:
+		return false
+	case f.UserID != nil && *f.UserID != k.UserID:
+		return false
+	case f.TenantID != nil && *f.TenantID != k.TenantID:
+		return false
+	default:
+		return true
+	}
+}
+
+// GetAPIKeys finds API keys in the store.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) GetAPIKeys(f *APIKeyFind) <-chan dlib.Result {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	ids := []int64{}
+	for id, k := range ms.apiKeys {
+		if matchAPIKey(f, &k) {
+			ids = append(ids, id)
+		}
+	}
+
+	c := make(chan dlib.Result, len(ids))
+	for _, id := range sortedIDs(ids) {
+		k := ms.apiKeys[id]
+		v := k.Copy()
+		c <- dlib.Result{Val: &v}
+	}
+
+	close(c)
+	return c
+}
+
+// SaveAPIKey inserts or updates a API key in the store. A API key with
+// no ID is inserted and receives a new ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) SaveAPIKey(k *APIKey) <-chan dlib.Result {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if _, ok := ms.apiKeys[k.ID]; k.ID != 0 && !ok {
+		return errorResult(errNotFound)
+	}
+
+	k.ID = nextID(&ms.apiKeyID, k.ID)
+	ms.apiKeys[k.ID] = k.Copy()
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Val: k, Num: 1}
+	close(c)
+	return c
+}
+
+// DeleteAPIKeys deletes API keys from the store. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MemoryStore) DeleteAPIKeys(f *APIKeyFind) <-chan dlib.Result {
+	if *f == (APIKeyFind{}) {
+		return errorResult(errNoCriteria)
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	n := 0
+	for id, k := range ms.apiKeys {
+		if matchAPIKey(f, &k) {
+			delete(ms.apiKeys, id)
+			n++
+		}
+	}
+
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Num: n}
+	close(c)
+	return c
+}
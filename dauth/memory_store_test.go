@@ -0,0 +1,241 @@
+package dauth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dhaifley/dlib"
+)
+
+func TestMemoryStoreSaveUser(t *testing.T) {
+	ms := NewMemoryStore()
+	cases := []struct {
+		u    User
+		exp  int64
+		code int
+	}{
+		{User{User: "a"}, 1, 0},
+		{User{ID: 5, User: "b"}, 5, 404},
+		{User{User: "c"}, 2, 0},
+		{User{ID: 1, User: "d"}, 1, 0},
+	}
+
+	for _, c := range cases {
+		for r := range ms.SaveUser(&c.u) {
+			code := 0
+			if e, ok := r.Err.(*dlib.Error); ok {
+				code = e.Code
+			} else if r.Err != nil {
+				t.Error(r.Err)
+			}
+
+			if code != c.code {
+				t.Errorf("Error expected: %v, got: %v", c.code, r.Err)
+			}
+		}
+
+		if c.u.ID != c.exp {
+			t.Errorf("ID expected: %v, got: %v", c.exp, c.u.ID)
+		}
+	}
+
+	name := "d"
+	n := 0
+	for r := range ms.GetUsers(&UserFind{User: &name}) {
+		n++
+		if u := r.Val.(*User); u.ID != 1 {
+			t.Errorf("ID expected: 1, got: %v", u.ID)
+		}
+	}
+
+	if n != 1 {
+		t.Errorf("Count expected: 1, got: %v", n)
+	}
+}
+
+func TestMemoryStoreGetTokens(t *testing.T) {
+	ms := NewMemoryStore()
+	t1 := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC)
+	ms.SaveToken(NewToken(0, "a", 1, &t1, nil))
+	ms.SaveToken(NewToken(0, "b", 1, &t2, &t1))
+	ms.SaveToken(NewToken(0, "c", 2, &t2, &t2))
+	uid := int64(1)
+	cases := []struct {
+		f   TokenFind
+		exp []string
+	}{
+		{TokenFind{}, []string{"a", "b", "c"}},
+		{TokenFind{UserID: &uid}, []string{"a", "b"}},
+		{TokenFind{Old: &t2}, []string{"a"}},
+		{TokenFind{Start: &t2}, []string{"b", "c"}},
+		{TokenFind{End: &t1}, []string{"a"}},
+		{TokenFind{Expired: &t2}, []string{"b"}},
+	}
+
+	for _, c := range cases {
+		v := []string{}
+		for r := range ms.GetTokens(&c.f) {
+			v = append(v, r.Val.(*Token).Token)
+		}
+
+		if len(v) != len(c.exp) {
+			t.Errorf("Value expected: %v, got: %v", c.exp, v)
+			continue
+		}
+
+		for i := range v {
+			if v[i] != c.exp[i] {
+				t.Errorf("Value expected: %v, got: %v", c.exp, v)
+			}
+		}
+	}
+}
+
+func TestMemoryStoreGetUsersCopy(t *testing.T) {
+	ms := NewMemoryStore()
+	ms.SaveUser(&User{User: "test"})
+	for r := range ms.GetUsers(&UserFind{}) {
+		r.Val.(*User).User = "changed"
+	}
+
+	for r := range ms.GetUsers(&UserFind{}) {
+		if u := r.Val.(*User); u.User != "test" {
+			t.Errorf("Value expected: test, got: %v", u.User)
+		}
+	}
+}
+
+func TestMemoryStoreDeletePerms(t *testing.T) {
+	ms := NewMemoryStore()
+	ms.SavePerm(&Perm{Service: "a", Name: "a"})
+	ms.SavePerm(&Perm{Service: "a", Name: "b"})
+	ms.SavePerm(&Perm{Service: "b", Name: "a"})
+	svc := "a"
+	for r := range ms.DeletePerms(&PermFind{Service: &svc}) {
+		if r.Err != nil {
+			t.Error(r.Err)
+		}
+
+		if r.Num != 2 {
+			t.Errorf("Num expected: 2, got: %v", r.Num)
+		}
+	}
+
+	for r := range ms.DeletePerms(&PermFind{}) {
+		if r.Err == nil {
+			t.Error("Expected error for delete without criteria")
+		}
+	}
+}
+
+func TestMemoryStoreUserRoles(t *testing.T) {
+	ms := NewMemoryStore()
+	ms.SaveUserRole(&UserRole{UserID: 1, RoleID: 1})
+	ms.SaveUserRole(&UserRole{UserID: 1, RoleID: 2})
+	ms.SaveUserRole(&UserRole{UserID: 2, RoleID: 2})
+	rid := int64(2)
+	n := 0
+	for r := range ms.GetUserRoles(&UserRoleFind{RoleID: &rid}) {
+		n++
+		if ur := r.Val.(*UserRole); ur.RoleID != 2 {
+			t.Errorf("RoleID expected: 2, got: %v", ur.RoleID)
+		}
+	}
+
+	if n != 2 {
+		t.Errorf("Count expected: 2, got: %v", n)
+	}
+
+	for r := range ms.DeleteUserRoles(&UserRoleFind{RoleID: &rid}) {
+		if r.Num != 2 {
+			t.Errorf("Num expected: 2, got: %v", r.Num)
+		}
+	}
+}
+
+func TestMemoryStoreLockouts(t *testing.T) {
+	ms := NewMemoryStore()
+	ms.SaveLockout(&Lockout{User: "a", Failures: 1})
+	ms.SaveLockout(&Lockout{Addr: "127.0.0.1", Failures: 2})
+	user := "a"
+	for r := range ms.GetLockouts(&LockoutFind{User: &user}) {
+		if l := r.Val.(*Lockout); l.ID != 1 || l.Failures != 1 {
+			t.Errorf("Value expected: 1, got: %v", l)
+		}
+	}
+
+	for r := range ms.DeleteLockouts(&LockoutFind{User: &user}) {
+		if r.Num != 1 {
+			t.Errorf("Num expected: 1, got: %v", r.Num)
+		}
+	}
+
+	n := 0
+	for range ms.GetLockouts(&LockoutFind{}) {
+		n++
+	}
+
+	if n != 1 {
+		t.Errorf("Count expected: 1, got: %v", n)
+	}
+}
+
+func TestMemoryStoreRecoveryCodes(t *testing.T) {
+	ms := NewMemoryStore()
+	ms.SaveRecoveryCode(&RecoveryCode{UserID: 1, Code: "a"})
+	ms.SaveRecoveryCode(&RecoveryCode{UserID: 1, Code: "b"})
+	uid := int64(1)
+	code := "a"
+	cases := []int{1, 0}
+	for _, exp := range cases {
+		for r := range ms.DeleteRecoveryCodes(&RecoveryCodeFind{UserID: &uid, Code: &code}) {
+			if r.Num != exp {
+				t.Errorf("Num expected: %v, got: %v", exp, r.Num)
+			}
+		}
+	}
+
+	n := 0
+	for range ms.GetRecoveryCodes(&RecoveryCodeFind{UserID: &uid}) {
+		n++
+	}
+
+	if n != 1 {
+		t.Errorf("Count expected: 1, got: %v", n)
+	}
+}
+
+func TestMemoryStoreAPIKeys(t *testing.T) {
+	ms := NewMemoryStore()
+	k := APIKey{Name: "a", UserID: 1, Scope: []string{"test:test"}}
+	ms.SaveAPIKey(&k)
+	k.Scope[0] = "changed"
+	name := "a"
+	for r := range ms.GetAPIKeys(&APIKeyFind{Name: &name}) {
+		if v := r.Val.(*APIKey); v.ID != 1 || v.Scope[0] != "test:test" {
+			t.Errorf("Value expected: test:test, got: %v", v)
+		}
+	}
+}
+
+func TestMemoryStoreGetPermsPage(t *testing.T) {
+	ms := NewMemoryStore()
+	for i := 0; i < 5; i++ {
+		ms.SavePerm(&Perm{Service: "test", Name: "test"})
+	}
+
+	after := int64(4)
+	ids := []int64{}
+	for r := range ms.GetPerms(&PermFind{Page: Page{Limit: 2, After: &after, Desc: true}}) {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+
+		ids = append(ids, r.Val.(*Perm).ID)
+	}
+
+	if len(ids) != 2 || ids[0] != 3 || ids[1] != 2 {
+		t.Errorf("Value expected: [3 2], got: %v", ids)
+	}
+}
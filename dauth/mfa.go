@@ -0,0 +1,408 @@
+package dauth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dhaifley/dlib"
+	"github.com/dhaifley/dlib/ptypes"
+)
+
+// DefaultChallengeTTL is the time a user has to complete the second step of
+// a login after the first.
+const DefaultChallengeTTL = 5 * time.Minute
+
+// recoveryCodeCount is the number of recovery codes issued on enrollment.
+const recoveryCodeCount = 10
+
+// Errors returned by multi-factor authentication.
+var (
+	ErrMFARequired = &dlib.Error{Code: 401, Msg: "mfa code required"}
+	ErrMFAInvalid  = &dlib.Error{Code: 401, Msg: "invalid mfa code"}
+	ErrMFAEnrolled = &dlib.Error{Code: 400, Msg: "mfa already enabled"}
+)
+
+// Enrollment values hold the details of a new MFA enrollment, which are
+// shown to the user once and can not be recovered.
+type Enrollment struct {
+	Secret        string   `json:"secret,omitempty"`
+	URI           string   `json:"uri,omitempty"`
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+// ToResponse returns a protobuf response created from this value.
+func (e *Enrollment) ToResponse() ptypes.MFAResponse {
+	return ptypes.MFAResponse{
+		Secret:        e.Secret,
+		URI:           e.URI,
+		RecoveryCodes: e.RecoveryCodes,
+	}
+}
+
+// MFA values manage TOTP multi-factor authentication for users. TOTP
+// secrets are stored encrypted with AES-GCM using Key, which must be 16, 24
+// or 32 bytes long, and which also signs login challenges. Issuer names the
+// service in authenticator apps.
+type MFA struct {
+	Store        Store
+	Key          []byte
+	Issuer       string
+	TOTP         *TOTP
+	ChallengeTTL time.Duration
+}
+
+// NewMFA initializes and returns a pointer to a new MFA value using the
+// default TOTP settings.
+func NewMFA(st Store, key []byte, issuer string) *MFA {
+	return &MFA{
+		Store:        st,
+		Key:          key,
+		Issuer:       issuer,
+		TOTP:         NewTOTP(),
+		ChallengeTTL: DefaultChallengeTTL,
+	}
+}
+
+// MFAEnabled tests whether a user has a confirmed MFA enrollment.
+func MFAEnabled(st Store, userID int64) (bool, error) {
+	on := true
+	n := 0
+	for r := range st.GetUserMFAs(&UserMFAFind{UserID: &userID, Enabled: &on}) {
+		if r.Err != nil {
+			return false, r.Err
+		}
+
+		n++
+	}
+
+	return n > 0, nil
+}
+
+// totp returns the TOTP settings used.
+func (m *MFA) totp() *TOTP {
+	if m.TOTP == nil {
+		return NewTOTP()
+	}
+
+	return m.TOTP
+}
+
+// get returns the MFA enrollment of a user, or nil if there is none.
+func (m *MFA) get(userID int64) (*UserMFA, error) {
+	var um *UserMFA
+	for r := range m.Store.GetUserMFAs(&UserMFAFind{UserID: &userID}) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		um = r.Val.(*UserMFA)
+	}
+
+	return um, nil
+}
+
+// gcm returns the AEAD cipher used to encrypt secrets.
+func (m *MFA) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(m.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// encrypt encrypts a secret with a random nonce. It returns the hex encoded
+// nonce and cipher text.
+func (m *MFA) encrypt(s string) (string, error) {
+	gcm, err := m.gcm()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(gcm.Seal(nonce, nonce, []byte(s), nil)), nil
+}
+
+// decrypt decrypts a secret encrypted by encrypt.
+func (m *MFA) decrypt(s string) (string, error) {
+	gcm, err := m.gcm()
+	if err != nil {
+		return "", err
+	}
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return "", err
+	}
+
+	if len(b) < gcm.NonceSize() {
+		return "", dlib.NewError(500, "invalid mfa secret")
+	}
+
+	pt, err := gcm.Open(nil, b[:gcm.NonceSize()], b[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(pt), nil
+}
+
+// newRecoveryCode returns a new random recovery code.
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	s := strings.ToLower(totpEncoding.EncodeToString(b))
+	return s[:4] + "-" + s[4:8] + "-" + s[8:12] + "-" + s[12:], nil
+}
+
+// hashRecoveryCode returns the stored form of a recovery code, ignoring
+// case, spaces and dashes.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return HashToken(code)
+}
+
+// Enroll creates a new, unconfirmed MFA enrollment for a user, replacing
+// any earlier unconfirmed one, along with a new set of recovery codes. The
+// enrollment takes effect once it is confirmed with a valid code. It returns
+// ErrMFAEnrolled if the user already has a confirmed enrollment.
+func (m *MFA) Enroll(u *User) (*Enrollment, error) {
+	um, err := m.get(u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if um != nil && um.Enabled {
+		return nil, ErrMFAEnrolled
+	}
+
+	if err := RemoveMFA(m.Store, u.ID); err != nil {
+		return nil, err
+	}
+
+	secret, err := NewTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	enc, err := m.encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	for r := range m.Store.SaveUserMFA(&UserMFA{UserID: u.ID, Secret: enc}) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+	}
+
+	e := Enrollment{Secret: secret, URI: m.totp().URI(m.Issuer, u.User, secret)}
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		rc := RecoveryCode{UserID: u.ID, Code: hashRecoveryCode(code)}
+		for r := range m.Store.SaveRecoveryCode(&rc) {
+			if r.Err != nil {
+				return nil, r.Err
+			}
+		}
+
+		e.RecoveryCodes = append(e.RecoveryCodes, code)
+	}
+
+	return &e, nil
+}
+
+// validate tests a TOTP code against an enrollment and records the time
+// step it matched, so that the code can not be used again.
+func (m *MFA) validate(um *UserMFA, code string) (bool, error) {
+	secret, err := m.decrypt(um.Secret)
+	if err != nil {
+		return false, err
+	}
+
+	step, ok, err := m.totp().Validate(secret, code, time.Now(), um.LastStep)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	um.LastStep = step
+	return true, nil
+}
+
+// Confirm enables the unconfirmed MFA enrollment of a user after checking
+// that the user can produce a valid code. It returns ErrMFAInvalid if the
+// code is not valid.
+func (m *MFA) Confirm(userID int64, code string) error {
+	um, err := m.get(userID)
+	if err != nil {
+		return err
+	}
+
+	if um == nil {
+		return dlib.NewError(404, "mfa enrollment not found")
+	}
+
+	if um.Enabled {
+		return ErrMFAEnrolled
+	}
+
+	ok, err := m.validate(um, code)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrMFAInvalid
+	}
+
+	um.Enabled = true
+	for r := range m.Store.SaveUserMFA(um) {
+		if r.Err != nil {
+			return r.Err
+		}
+	}
+
+	return nil
+}
+
+// Verify tests whether a code is a valid TOTP code or an unused recovery
+// code for a user with a confirmed enrollment. Each code is accepted only
+// once; a recovery code is deleted when it is used.
+func (m *MFA) Verify(userID int64, code string) (bool, error) {
+	um, err := m.get(userID)
+	if err != nil || um == nil || !um.Enabled {
+		return false, err
+	}
+
+	if len(code) == m.totp().Digits {
+		ok, err := m.validate(um, code)
+		if err != nil || !ok {
+			return false, err
+		}
+
+		for r := range m.Store.SaveUserMFA(um) {
+			if r.Err != nil {
+				return false, r.Err
+			}
+		}
+
+		return true, nil
+	}
+
+	hash := hashRecoveryCode(code)
+	n := 0
+	for r := range m.Store.DeleteRecoveryCodes(&RecoveryCodeFind{
+		UserID: &userID,
+		Code:   &hash,
+	}) {
+		if r.Err != nil {
+			return false, r.Err
+		}
+
+		n += r.Num
+	}
+
+	return n > 0, nil
+}
+
+// RemoveMFA deletes the MFA enrollment and recovery codes of a user, such
+// as when the user has lost their device.
+func RemoveMFA(st Store, userID int64) error {
+	for r := range st.DeleteUserMFAs(&UserMFAFind{UserID: &userID}) {
+		if r.Err != nil {
+			return r.Err
+		}
+	}
+
+	for r := range st.DeleteRecoveryCodes(&RecoveryCodeFind{UserID: &userID}) {
+		if r.Err != nil {
+			return r.Err
+		}
+	}
+
+	return nil
+}
+
+// challengeKey returns the key used to sign login challenges, derived from
+// the encryption key.
+func (m *MFA) challengeKey() []byte {
+	h := hmac.New(sha256.New, m.Key)
+	h.Write([]byte("dauth mfa challenge"))
+	return h.Sum(nil)
+}
+
+// challengeSig returns the signature of a challenge payload.
+func (m *MFA) challengeSig(payload string) string {
+	h := hmac.New(sha256.New, m.challengeKey())
+	h.Write([]byte(payload))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Challenge returns a signed login challenge for a user who has passed the
+// first step of a login, and the time it expires. The challenge is
+// exchanged, with a valid code, for a token in the second step.
+func (m *MFA) Challenge(userID int64) (string, time.Time) {
+	ttl := m.ChallengeTTL
+	if ttl <= 0 {
+		ttl = DefaultChallengeTTL
+	}
+
+	exp := time.Now().Add(ttl)
+	payload := strconv.FormatInt(userID, 10) + ":" + strconv.FormatInt(exp.Unix(), 10)
+	return hex.EncodeToString([]byte(payload)) + "." + m.challengeSig(payload), exp
+}
+
+// VerifyChallenge checks the signature and expiration of a login challenge.
+// It returns the ID of the user it was issued to.
+func (m *MFA) VerifyChallenge(ch string) (int64, error) {
+	parts := strings.Split(ch, ".")
+	if len(parts) != 2 {
+		return 0, ErrInvalidToken
+	}
+
+	pb, err := hex.DecodeString(parts[0])
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+
+	payload := string(pb)
+	if !hmac.Equal([]byte(m.challengeSig(payload)), []byte(parts[1])) {
+		return 0, ErrInvalidToken
+	}
+
+	vals := strings.Split(payload, ":")
+	if len(vals) != 2 {
+		return 0, ErrInvalidToken
+	}
+
+	id, err := strconv.ParseInt(vals[0], 10, 64)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+
+	exp, err := strconv.ParseInt(vals[1], 10, 64)
+	if err != nil || time.Now().Unix() >= exp {
+		return 0, ErrInvalidToken
+	}
+
+	return id, nil
+}
@@ -0,0 +1,169 @@
+package dauth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhaifley/dlib"
+)
+
+var testMFAKey = []byte("0123456789abcdef0123456789abcdef")
+
+// enrollTestMFA enrolls and confirms MFA for user 1 of a test store. It
+// returns the enrollment.
+func enrollTestMFA(t *testing.T, m *MFA) *Enrollment {
+	e, err := m.Enroll(&User{ID: 1, User: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, _ := m.TOTP.Code(e.Secret, time.Now())
+	if err := m.Confirm(1, code); err != nil {
+		t.Fatal(err)
+	}
+
+	return e
+}
+
+func TestMFAEnroll(t *testing.T) {
+	ms := newTestStore()
+	m := NewMFA(ms, testMFAKey, "dauth")
+	e, err := m.Enroll(&User{ID: 1, User: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(e.URI, "otpauth://totp/dauth:test?") {
+		t.Errorf("Unexpected URI: %v", e.URI)
+	}
+
+	if len(e.RecoveryCodes) != recoveryCodeCount {
+		t.Errorf("Count expected: %v, got: %v", recoveryCodeCount, len(e.RecoveryCodes))
+	}
+
+	for r := range ms.GetUserMFAs(&UserMFAFind{}) {
+		um := r.Val.(*UserMFA)
+		if um.Secret == e.Secret || um.Enabled {
+			t.Errorf("Expected encrypted, unconfirmed enrollment, got: %v", um)
+		}
+	}
+
+	if on, _ := MFAEnabled(ms, 1); on {
+		t.Error("Expected MFA disabled before confirmation")
+	}
+
+	if err := m.Confirm(1, "000000"); err != ErrMFAInvalid {
+		t.Errorf("Error expected: %v, got: %v", ErrMFAInvalid, err)
+	}
+
+	code, _ := m.TOTP.Code(e.Secret, time.Now())
+	if err := m.Confirm(1, code); err != nil {
+		t.Fatal(err)
+	}
+
+	if on, _ := MFAEnabled(ms, 1); !on {
+		t.Error("Expected MFA enabled after confirmation")
+	}
+
+	if _, err := m.Enroll(&User{ID: 1, User: "test"}); err != ErrMFAEnrolled {
+		t.Errorf("Error expected: %v, got: %v", ErrMFAEnrolled, err)
+	}
+}
+
+func TestMFAVerify(t *testing.T) {
+	ms := newTestStore()
+	m := NewMFA(ms, testMFAKey, "dauth")
+	e := enrollTestMFA(t, m)
+	rc := strings.ToUpper(e.RecoveryCodes[0])
+	cases := []struct {
+		code string
+		ok   bool
+	}{
+		{rc, true},
+		{rc, false},
+		{"000000", false},
+		{"wrong", false},
+	}
+
+	for _, c := range cases {
+		ok, err := m.Verify(1, c.code)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ok != c.ok {
+			t.Errorf("Expected bool for %v: %v, got: %v", c.code, c.ok, ok)
+		}
+	}
+
+	if ok, _ := m.Verify(2, rc); ok {
+		t.Error("Expected no verification for a user without MFA")
+	}
+}
+
+func TestMFAVerifyReplay(t *testing.T) {
+	ms := newTestStore()
+	m := NewMFA(ms, testMFAKey, "dauth")
+	m.TOTP.Skew = 0
+	e := enrollTestMFA(t, m)
+	for r := range ms.GetUserMFAs(&UserMFAFind{}) {
+		um := r.Val.(*UserMFA)
+		um.LastStep = 0
+		ms.SaveUserMFA(um)
+	}
+
+	code, _ := m.TOTP.Code(e.Secret, time.Now())
+	if ok, _ := m.Verify(1, code); !ok {
+		t.Error("Expected valid code")
+	}
+
+	if ok, _ := m.Verify(1, code); ok {
+		t.Error("Expected replayed code to be rejected")
+	}
+}
+
+func TestMFAChallenge(t *testing.T) {
+	m := NewMFA(NewMemoryStore(), testMFAKey, "dauth")
+	ch, exp := m.Challenge(5)
+	if exp.Before(time.Now()) {
+		t.Errorf("Expected future expiration, got: %v", exp)
+	}
+
+	if IsSignedToken(ch) {
+		t.Errorf("Expected challenge not to look like a signed token: %v", ch)
+	}
+
+	id, err := m.VerifyChallenge(ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id != 5 {
+		t.Errorf("ID expected: 5, got: %v", id)
+	}
+
+	cases := []string{"", ch + "0", "00." + strings.Split(ch, ".")[1]}
+	for _, c := range cases {
+		if _, err := m.VerifyChallenge(c); err != ErrInvalidToken {
+			t.Errorf("Error expected: %v, got: %v", ErrInvalidToken, err)
+		}
+	}
+
+	m.ChallengeTTL = time.Nanosecond
+	ch, _ = m.Challenge(5)
+	if _, err := m.VerifyChallenge(ch); err != ErrInvalidToken {
+		t.Errorf("Error expected: %v, got: %v", ErrInvalidToken, err)
+	}
+}
+
+func TestTokenIssuerLoginMFA(t *testing.T) {
+	ms := newTestStore()
+	enrollTestMFA(t, NewMFA(ms, testMFAKey, "dauth"))
+	ti := NewTokenIssuer(ms, time.Minute)
+	ti.Hasher = testHasher
+	_, err := ti.Login(0, "test", "test")
+	if e, ok := err.(*dlib.Error); !ok || e != ErrMFARequired {
+		t.Errorf("Error expected: %v, got: %v", ErrMFARequired, err)
+	}
+}
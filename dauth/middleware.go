@@ -0,0 +1,128 @@
+package dauth
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/dhaifley/dlib"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Middleware values authenticate HTTP requests and check their permissions
+// using an authorizer. The token is read from a bearer authorization header,
+// then from the cookie named by Cookie, then from the query parameter named
+// by Query. An empty name disables that source.
+type Middleware struct {
+	Authorizer Authorizer
+	Cookie     string
+	Query      string
+}
+
+// NewMiddleware initializes and returns a pointer to a new HTTP middleware
+// value reading tokens from the "token" cookie and query parameter in
+// addition to the authorization header.
+func NewMiddleware(a Authorizer) *Middleware {
+	return &Middleware{Authorizer: a, Cookie: "token", Query: "token"}
+}
+
+// TokenFromRequest returns the token provided with an HTTP request, or an
+// empty string if there is none.
+func (m *Middleware) TokenFromRequest(r *http.Request) string {
+	if v := r.Header.Get("Authorization"); len(v) > 7 &&
+		strings.EqualFold(v[:7], "bearer ") {
+		return strings.TrimSpace(v[7:])
+	}
+
+	if v := r.Header.Get("X-API-Key"); v != "" {
+		return strings.TrimSpace(v)
+	}
+
+	if m.Cookie != "" {
+		if c, err := r.Cookie(m.Cookie); err == nil && c.Value != "" {
+			return c.Value
+		}
+	}
+
+	if m.Query != "" {
+		return r.URL.Query().Get(m.Query)
+	}
+
+	return ""
+}
+
+// Require returns a middleware function which allows requests to reach the
+// handler it wraps only if they carry a token authorized for a permission.
+// The host of the remote address of the request is added to the context
+// passed to the authorizer as the client address, so that grants limited to
+// addresses are checked against the HTTP client. The authenticated user is
+// added to the request context.
+func (m *Middleware) Require(perm *Perm) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := m.TokenFromRequest(r)
+			if token == "" {
+				writeError(w, dlib.NewError(401, "token required"))
+				return
+			}
+
+			ctx := NewAddrContext(r.Context(), remoteHost(r.RemoteAddr))
+			u, err := m.Authorizer.Authorize(ctx, token, perm)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+
+			h.ServeHTTP(w, r.WithContext(NewUserContext(ctx, u)))
+		})
+	}
+}
+
+// writeError writes an error to an HTTP response as a JSON dlib.Error
+// body. gRPC status errors from remote authorizers are converted to the
+// equivalent HTTP codes. Client errors, with 4xx codes, are passed through,
+// and all other errors are reported as 500 errors without detail.
+func writeError(w http.ResponseWriter, err error) {
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.InvalidArgument:
+			err = dlib.NewError(400, st.Message())
+		case codes.Unauthenticated:
+			err = dlib.NewError(401, st.Message())
+		case codes.PermissionDenied:
+			err = dlib.NewError(403, st.Message())
+		case codes.NotFound:
+			err = dlib.NewError(404, st.Message())
+		case codes.AlreadyExists:
+			err = dlib.NewError(409, st.Message())
+		case codes.FailedPrecondition:
+			err = dlib.NewError(412, st.Message())
+		case codes.ResourceExhausted:
+			err = dlib.NewError(429, st.Message())
+		}
+	}
+
+	e, ok := err.(*dlib.Error)
+	if !ok || e.Code < 400 || e.Code >= 500 {
+		e = dlib.NewError(500, "authorization failed")
+	}
+
+	if e.Code == 401 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.Code)
+	w.Write([]byte(e.String()))
+}
+
+// remoteHost returns the host part of the remote address of an HTTP
+// request, or the whole address if it has no port.
+func remoteHost(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
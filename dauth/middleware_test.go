@@ -0,0 +1,160 @@
+package dauth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dhaifley/dlib"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMiddlewareTokenFromRequest(t *testing.T) {
+	m := NewMiddleware(nil)
+	cases := []struct {
+		header string
+		cookie string
+		query  string
+		exp    string
+	}{
+		{"Bearer a", "b", "c", "a"},
+		{"", "b", "c", "b"},
+		{"Basic a", "", "c", "c"},
+		{"", "", "", ""},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest("GET", "/test?token="+c.query, nil)
+		if c.header != "" {
+			r.Header.Set("Authorization", c.header)
+		}
+
+		if c.cookie != "" {
+			r.AddCookie(&http.Cookie{Name: "token", Value: c.cookie})
+		}
+
+		if v := m.TokenFromRequest(r); v != c.exp {
+			t.Errorf("Value expected: %v, got: %v", c.exp, v)
+		}
+	}
+
+	r := httptest.NewRequest("GET", "/test?token=c", nil)
+	r.Header.Set("X-API-Key", "dk_test")
+	if v := m.TokenFromRequest(r); v != "dk_test" {
+		t.Errorf("Value expected: dk_test, got: %v", v)
+	}
+
+	m.Query = ""
+	r = httptest.NewRequest("GET", "/test?token=c", nil)
+	if v := m.TokenFromRequest(r); v != "" {
+		t.Errorf("Value expected: empty, got: %v", v)
+	}
+}
+
+func TestMiddlewareRequire(t *testing.T) {
+	m := NewMiddleware(&ServerAuthorizer{Server: NewServer(newTestStore())})
+	var user *User
+	h := m.Require(NewPerm(0, "test", "test"))(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			user, _ = UserFromContext(r.Context())
+		}))
+
+	cases := []struct {
+		target string
+		code   int
+	}{
+		{"/test?token=test", 200},
+		{"/test?token=other", 401},
+		{"/test", 401},
+	}
+
+	for _, c := range cases {
+		user = nil
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", c.target, nil))
+		if w.Code != c.code {
+			t.Errorf("Code expected: %v, got: %v", c.code, w.Code)
+		}
+
+		if c.code == 200 {
+			if user == nil || user.ID != 1 {
+				t.Errorf("Expected user 1, got: %v", user)
+			}
+
+			continue
+		}
+
+		e := dlib.Error{}
+		if err := json.Unmarshal(w.Body.Bytes(), &e); err != nil || e.Code != c.code {
+			t.Errorf("Error expected: %v, got: %v", c.code, w.Body.String())
+		}
+	}
+
+	h = m.Require(NewPerm(0, "test", "other"))(http.NotFoundHandler())
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/test?token=test", nil))
+	if w.Code != 403 {
+		t.Errorf("Code expected: 403, got: %v", w.Code)
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	cases := []struct {
+		err  error
+		code int
+	}{
+		{dlib.NewError(401, "test"), 401},
+		{dlib.NewError(404, "test"), 404},
+		{dlib.NewError(429, "test"), 429},
+		{dlib.NewError(503, "test"), 500},
+		{errors.New("test"), 500},
+		{status.Error(codes.PermissionDenied, "test"), 403},
+		{status.Error(codes.ResourceExhausted, "test"), 429},
+		{status.Error(codes.Unavailable, "test"), 500},
+	}
+
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		writeError(w, c.err)
+		if w.Code != c.code {
+			t.Errorf("Code expected: %v, got: %v", c.code, w.Code)
+		}
+	}
+}
+
+func TestMiddlewareRequireAddr(t *testing.T) {
+	ms := newTestStore()
+	ms.SavePerm(&Perm{Service: "test", Name: "net"})
+	ms.SaveUserPerm(&UserPerm{UserID: 1, PermID: 2, CIDRs: []string{"203.0.113.0/24"}})
+	m := NewMiddleware(&ServerAuthorizer{Server: NewServer(ms)})
+	var addr string
+	h := m.Require(NewPerm(0, "test", "net"))(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			addr = ClientAddr(r.Context())
+		}))
+
+	cases := []struct {
+		remote string
+		code   int
+	}{
+		{"203.0.113.5:1234", 200},
+		{"198.51.100.5:1234", 403},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest("GET", "/test?token=test", nil)
+		r.RemoteAddr = c.remote
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != c.code {
+			t.Errorf("Code expected: %v, got: %v from %v", c.code, w.Code, c.remote)
+		}
+	}
+
+	if addr != "203.0.113.5" {
+		t.Errorf("Addr expected: 203.0.113.5, got: %v", addr)
+	}
+}
@@ -0,0 +1,718 @@
+package dauth
+
+import (
+	"github.com/dhaifley/dlib"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// maxInsertAttempts is the number of times an insert is retried when
+// another writer claims the same ID first.
+const maxInsertAttempts = 5
+
+// MongoStore values implement the Store interface using a MongoDB database.
+type MongoStore struct {
+	DB dlib.MongoDBDatabase
+}
+
+// NewMongoStore initializes and returns a pointer to a new MongoDB store
+// value.
+func NewMongoStore(db dlib.MongoDBDatabase) *MongoStore {
+	return &MongoStore{DB: db}
+}
+
+// mongoIndexes contains the unique indexes of each collection, which
+// ensure that token and API key hashes identify a single document, that
+// user names are unique within each tenant, and that each user has at most
+// one MFA enrollment.
+var mongoIndexes = map[string][][]string{
+	"tokens":    {{"token"}},
+	"users":     {{"tenant_id", "user"}},
+	"user_mfas": {{"user_id"}},
+	"api_keys":  {{"key"}},
+}
+
+// EnsureIndexes creates the unique indexes of the store collections if they
+// do not already exist.
+func (ms *MongoStore) EnsureIndexes() error {
+	for col, keys := range mongoIndexes {
+		for _, k := range keys {
+			err := ms.DB.C(col).EnsureIndex(mgo.Index{Key: k, Unique: true})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// find queries a collection, decoding each document using a function that
+// returns a new value to decode into.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) find(col string, filter bson.M,
+	val func() interface{}) <-chan dlib.Result {
+	return ms.findPage(col, filter, &Page{}, val)
+}
+
+// findPage queries a collection for a page of documents, decoding each
+// document using a function that returns a new value to decode into.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) findPage(col string, filter bson.M, p *Page,
+	val func() interface{}) <-chan dlib.Result {
+	c := make(chan dlib.Result, 256)
+
+	go func() {
+		defer close(c)
+		sort := p.mongo(filter)
+		q := ms.DB.C(col).Find(filter).Sort(sort)
+		if p.Limit > 0 {
+			q = q.Limit(p.Limit)
+		}
+
+		cur := q.Iter()
+		defer cur.Close()
+		v := val()
+		for cur.Next(v) {
+			c <- dlib.Result{Val: v}
+			v = val()
+		}
+
+		if cur.Err() != nil {
+			c <- dlib.Result{Err: cur.Err()}
+		}
+	}()
+
+	return c
+}
+
+// lastID returns the highest ID in use in a collection.
+func (ms *MongoStore) lastID(col string) (int64, error) {
+	doc := struct {
+		ID int64 `bson:"_id"`
+	}{}
+
+	cur := ms.DB.C(col).Find(nil).Sort("-_id").Limit(1).Iter()
+	defer cur.Close()
+	cur.Next(&doc)
+	return doc.ID, cur.Err()
+}
+
+// save inserts or replaces a document in a collection. Documents with an ID
+// replace the existing document with that ID in a single update, and a 404
+// error is returned if there is none. Documents with no ID are assigned the
+// next available ID through setID before insertion.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) save(col string, id int64, setID func(int64),
+	doc interface{}) <-chan dlib.Result {
+	c := make(chan dlib.Result, 1)
+
+	go func() {
+		defer close(c)
+		if id != 0 {
+			if err := ms.DB.C(col).UpdateID(id, doc); err != nil {
+				if err == mgo.ErrNotFound {
+					err = errNotFound
+				}
+
+				c <- dlib.Result{Err: err}
+				return
+			}
+
+			c <- dlib.Result{Val: doc, Num: 1}
+			return
+		}
+
+		var err error
+		for i := 0; i < maxInsertAttempts; i++ {
+			var last int64
+			if last, err = ms.lastID(col); err != nil {
+				break
+			}
+
+			setID(last + 1)
+			if err = ms.DB.C(col).Insert(doc); !mgo.IsDup(err) {
+				break
+			}
+		}
+
+		if err != nil {
+			setID(0)
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		c <- dlib.Result{Val: doc, Num: 1}
+	}()
+
+	return c
+}
+
+// remove deletes the documents in a collection matching a filter. At least
+// one search criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) remove(col string, filter bson.M) <-chan dlib.Result {
+	if len(filter) == 0 {
+		return errorResult(errNoCriteria)
+	}
+
+	c := make(chan dlib.Result, 1)
+
+	go func() {
+		defer close(c)
+		info, err := ms.DB.C(col).RemoveAll(filter)
+		if err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		c <- dlib.Result{Num: info.Removed}
+	}()
+
+	return c
+}
+
+// tokenFilter builds a query filter from a token find value.
+func tokenFilter(f *TokenFind) bson.M {
+	q := bson.M{}
+	if f.ID != nil {
+		q["_id"] = *f.ID
+	}
+
+	if f.Token != nil {
+		q["token"] = *f.Token
+	}
+
+	if f.UserID != nil {
+		q["user_id"] = *f.UserID
+	}
+
+	if f.Expires != nil {
+		q["expires"] = *f.Expires
+	}
+
+	if f.Expired != nil {
+		q["expires"] = bson.M{"$lt": *f.Expired}
+	}
+
+	if f.TenantID != nil {
+		q["tenant_id"] = *f.TenantID
+	}
+
+	if f.ActorID != nil {
+		q["actor_id"] = *f.ActorID
+	}
+
+	created := bson.M{}
+	if f.Created != nil {
+		created["$eq"] = *f.Created
+	}
+
+	if f.Start != nil {
+		created["$gte"] = *f.Start
+	}
+
+	if f.End != nil {
+		created["$lte"] = *f.End
+	}
+
+	if f.Old != nil {
+		created["$lt"] = *f.Old
+	}
+
+	if len(created) > 0 {
+		q["created"] = created
+	}
+
+	return q
+}
+
+// GetTokens finds tokens in the database.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) GetTokens(f *TokenFind) <-chan dlib.Result {
+	return ms.findPage("tokens", tokenFilter(f), &f.Page, func() interface{} {
+		return &Token{}
+	})
+}
+
+// SaveToken inserts or replaces a token in the database. A token with no
+// ID is inserted and receives the next available ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) SaveToken(t *Token) <-chan dlib.Result {
+	return ms.save("tokens", t.ID, func(id int64) { t.ID = id }, t)
+}
+
+// DeleteTokens deletes tokens from the database. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) DeleteTokens(f *TokenFind) <-chan dlib.Result {
+	return ms.remove("tokens", tokenFilter(f))
+}
+
+// userFilter builds a query filter from a user find value.
+func userFilter(f *UserFind) bson.M {
+	q := bson.M{}
+	if f.ID != nil {
+		q["_id"] = *f.ID
+	}
+
+	if f.User != nil {
+		q["user"] = *f.User
+	}
+
+	if f.Pass != nil {
+		q["pass"] = *f.Pass
+	}
+
+	if f.Name != nil {
+		q["name"] = *f.Name
+	}
+
+	if f.Email != nil {
+		q["email"] = *f.Email
+	}
+
+	if f.TenantID != nil {
+		q["tenant_id"] = *f.TenantID
+	}
+
+	if f.Provider != nil {
+		q["provider"] = *f.Provider
+	}
+
+	return q
+}
+
+// GetUsers finds users in the database.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) GetUsers(f *UserFind) <-chan dlib.Result {
+	return ms.findPage("users", userFilter(f), &f.Page, func() interface{} {
+		return &User{}
+	})
+}
+
+// SaveUser inserts or replaces a user in the database. A user with no ID
+// is inserted and receives the next available ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) SaveUser(u *User) <-chan dlib.Result {
+	return ms.save("users", u.ID, func(id int64) { u.ID = id }, u)
+}
+
+// DeleteUsers deletes users from the database. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) DeleteUsers(f *UserFind) <-chan dlib.Result {
+	return ms.remove("users", userFilter(f))
+}
+
+// permFilter builds a query filter from a perm find value.
+func permFilter(f *PermFind) bson.M {
+	q := bson.M{}
+	if f.ID != nil {
+		q["_id"] = *f.ID
+	}
+
+	if f.IDs != nil {
+		q["_id"] = bson.M{"$in": f.IDs}
+	}
+
+	if f.Service != nil {
+		q["service"] = *f.Service
+	}
+
+	if f.Name != nil {
+		q["name"] = *f.Name
+	}
+
+	if f.TenantID != nil {
+		q["tenant_id"] = *f.TenantID
+	}
+
+	return q
+}
+
+// GetPerms finds permissions in the database.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) GetPerms(f *PermFind) <-chan dlib.Result {
+	return ms.findPage("perms", permFilter(f), &f.Page, func() interface{} {
+		return &Perm{}
+	})
+}
+
+// SavePerm inserts or replaces a permission in the database. A permission
+// with no ID is inserted and receives the next available ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) SavePerm(p *Perm) <-chan dlib.Result {
+	return ms.save("perms", p.ID, func(id int64) { p.ID = id }, p)
+}
+
+// DeletePerms deletes permissions from the database. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) DeletePerms(f *PermFind) <-chan dlib.Result {
+	return ms.remove("perms", permFilter(f))
+}
+
+// userPermFilter builds a query filter from a user_perm find value.
+func userPermFilter(f *UserPermFind) bson.M {
+	q := bson.M{}
+	if f.ID != nil {
+		q["_id"] = *f.ID
+	}
+
+	if f.UserID != nil {
+		q["user_id"] = *f.UserID
+	}
+
+	if f.PermID != nil {
+		q["perm_id"] = *f.PermID
+	}
+
+	if f.TenantID != nil {
+		q["tenant_id"] = *f.TenantID
+	}
+
+	if f.Expired != nil {
+		q["expires"] = bson.M{"$lt": *f.Expired}
+	}
+
+	return q
+}
+
+// GetUserPerms finds user permissions in the database.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) GetUserPerms(f *UserPermFind) <-chan dlib.Result {
+	return ms.findPage("user_perms", userPermFilter(f), &f.Page, func() interface{} {
+		return &UserPerm{}
+	})
+}
+
+// SaveUserPerm inserts or replaces a user permission in the database. A
+// user permission with no ID is inserted and receives the next available ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) SaveUserPerm(up *UserPerm) <-chan dlib.Result {
+	return ms.save("user_perms", up.ID, func(id int64) { up.ID = id }, up)
+}
+
+// DeleteUserPerms deletes user permissions from the database. At least one
+// search criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) DeleteUserPerms(f *UserPermFind) <-chan dlib.Result {
+	return ms.remove("user_perms", userPermFilter(f))
+}
+
+// roleFilter builds a query filter from a role find value.
+func roleFilter(f *RoleFind) bson.M {
+	q := bson.M{}
+	if f.ID != nil {
+		q["_id"] = *f.ID
+	}
+
+	if f.ParentID != nil {
+		q["parent_id"] = *f.ParentID
+	}
+
+	if f.Name != nil {
+		q["name"] = *f.Name
+	}
+
+	if f.TenantID != nil {
+		q["tenant_id"] = *f.TenantID
+	}
+
+	return q
+}
+
+// GetRoles finds roles in the database.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) GetRoles(f *RoleFind) <-chan dlib.Result {
+	return ms.find("roles", roleFilter(f), func() interface{} {
+		return &Role{}
+	})
+}
+
+// SaveRole inserts or replaces a role in the database. A role with
+// no ID is inserted and receives the next available ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) SaveRole(ro *Role) <-chan dlib.Result {
+	return ms.save("roles", ro.ID, func(id int64) { ro.ID = id }, ro)
+}
+
+// DeleteRoles deletes roles from the database. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) DeleteRoles(f *RoleFind) <-chan dlib.Result {
+	return ms.remove("roles", roleFilter(f))
+}
+
+// rolePermFilter builds a query filter from a role_perm find value.
+func rolePermFilter(f *RolePermFind) bson.M {
+	q := bson.M{}
+	if f.ID != nil {
+		q["_id"] = *f.ID
+	}
+
+	if f.RoleID != nil {
+		q["role_id"] = *f.RoleID
+	}
+
+	if f.RoleIDs != nil {
+		q["role_id"] = bson.M{"$in": f.RoleIDs}
+	}
+
+	if f.PermID != nil {
+		q["perm_id"] = *f.PermID
+	}
+
+	if f.TenantID != nil {
+		q["tenant_id"] = *f.TenantID
+	}
+
+	return q
+}
+
+// GetRolePerms finds role permissions in the database.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) GetRolePerms(f *RolePermFind) <-chan dlib.Result {
+	return ms.find("role_perms", rolePermFilter(f), func() interface{} {
+		return &RolePerm{}
+	})
+}
+
+// SaveRolePerm inserts or replaces a role permission in the database. A
+// role permission with no ID is inserted and receives the next available ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) SaveRolePerm(rp *RolePerm) <-chan dlib.Result {
+	return ms.save("role_perms", rp.ID, func(id int64) { rp.ID = id }, rp)
+}
+
+// DeleteRolePerms deletes role permissions from the database. At least one
+// search criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) DeleteRolePerms(f *RolePermFind) <-chan dlib.Result {
+	return ms.remove("role_perms", rolePermFilter(f))
+}
+
+// userRoleFilter builds a query filter from a user_role find value.
+func userRoleFilter(f *UserRoleFind) bson.M {
+	q := bson.M{}
+	if f.ID != nil {
+		q["_id"] = *f.ID
+	}
+
+	if f.UserID != nil {
+		q["user_id"] = *f.UserID
+	}
+
+	if f.RoleID != nil {
+		q["role_id"] = *f.RoleID
+	}
+
+	if f.TenantID != nil {
+		q["tenant_id"] = *f.TenantID
+	}
+
+	return q
+}
+
+// GetUserRoles finds user roles in the database.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) GetUserRoles(f *UserRoleFind) <-chan dlib.Result {
+	return ms.find("user_roles", userRoleFilter(f), func() interface{} {
+		return &UserRole{}
+	})
+}
+
+// SaveUserRole inserts or replaces a user role in the database. A
+// user role with no ID is inserted and receives the next available ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) SaveUserRole(ur *UserRole) <-chan dlib.Result {
+	return ms.save("user_roles", ur.ID, func(id int64) { ur.ID = id }, ur)
+}
+
+// DeleteUserRoles deletes user roles from the database. At least one
+// search criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) DeleteUserRoles(f *UserRoleFind) <-chan dlib.Result {
+	return ms.remove("user_roles", userRoleFilter(f))
+}
+
+// lockoutFilter builds a query filter from a lockout find value.
+func lockoutFilter(f *LockoutFind) bson.M {
+	q := bson.M{}
+	if f.ID != nil {
+		q["_id"] = *f.ID
+	}
+
+	if f.User != nil {
+		q["user"] = *f.User
+	}
+
+	if f.Addr != nil {
+		q["addr"] = *f.Addr
+	}
+
+	if f.TenantID != nil {
+		q["tenant_id"] = *f.TenantID
+	}
+
+	if f.Expired != nil {
+		q["until"] = bson.M{"$lt": *f.Expired}
+	}
+
+	return q
+}
+
+// GetLockouts finds lockouts in the database.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) GetLockouts(f *LockoutFind) <-chan dlib.Result {
+	return ms.find("lockouts", lockoutFilter(f), func() interface{} {
+		return &Lockout{}
+	})
+}
+
+// SaveLockout inserts or replaces a lockout in the database. A lockout with
+// no ID is inserted and receives the next available ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) SaveLockout(l *Lockout) <-chan dlib.Result {
+	return ms.save("lockouts", l.ID, func(id int64) { l.ID = id }, l)
+}
+
+// DeleteLockouts deletes lockouts from the database. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) DeleteLockouts(f *LockoutFind) <-chan dlib.Result {
+	return ms.remove("lockouts", lockoutFilter(f))
+}
+
+// userMFAFilter builds a query filter from a user MFA find value.
+func userMFAFilter(f *UserMFAFind) bson.M {
+	q := bson.M{}
+	if f.ID != nil {
+		q["_id"] = *f.ID
+	}
+
+	if f.UserID != nil {
+		q["user_id"] = *f.UserID
+	}
+
+	if f.Enabled != nil {
+		q["enabled"] = *f.Enabled
+	}
+
+	return q
+}
+
+// GetUserMFAs finds user MFAs in the database.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) GetUserMFAs(f *UserMFAFind) <-chan dlib.Result {
+	return ms.find("user_mfas", userMFAFilter(f), func() interface{} {
+		return &UserMFA{}
+	})
+}
+
+// SaveUserMFA inserts or replaces a user MFA in the database. A user MFA with
+// no ID is inserted and receives the next available ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) SaveUserMFA(um *UserMFA) <-chan dlib.Result {
+	return ms.save("user_mfas", um.ID, func(id int64) { um.ID = id }, um)
+}
+
+// DeleteUserMFAs deletes user MFAs from the database. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) DeleteUserMFAs(f *UserMFAFind) <-chan dlib.Result {
+	return ms.remove("user_mfas", userMFAFilter(f))
+}
+
+// recoveryCodeFilter builds a query filter from a recovery code find value.
+func recoveryCodeFilter(f *RecoveryCodeFind) bson.M {
+	q := bson.M{}
+	if f.ID != nil {
+		q["_id"] = *f.ID
+	}
+
+	if f.UserID != nil {
+		q["user_id"] = *f.UserID
+	}
+
+	if f.Code != nil {
+		q["code"] = *f.Code
+	}
+
+	return q
+}
+
+// GetRecoveryCodes finds recovery codes in the database.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) GetRecoveryCodes(f *RecoveryCodeFind) <-chan dlib.Result {
+	return ms.find("recovery_codes", recoveryCodeFilter(f), func() interface{} {
+		return &RecoveryCode{}
+	})
+}
+
+// SaveRecoveryCode inserts or replaces a recovery code in the database. A recovery code with
+// no ID is inserted and receives the next available ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) SaveRecoveryCode(rc *RecoveryCode) <-chan dlib.Result {
+	return ms.save("recovery_codes", rc.ID, func(id int64) { rc.ID = id }, rc)
+}
+
+// DeleteRecoveryCodes deletes recovery codes from the database. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) DeleteRecoveryCodes(f *RecoveryCodeFind) <-chan dlib.Result {
+	return ms.remove("recovery_codes", recoveryCodeFilter(f))
+}
+
+// apiKeyFilter builds a query filter from a API key find value.
+func apiKeyFilter(f *APIKeyFind) bson.M {
+	q := bson.M{}
+	if f.ID != nil {
+		q["_id"] = *f.ID
+	}
+
+	if f.Name != nil {
+		q["name"] = *f.Name
+	}
+
+	if f.Prefix != nil {
+		q["prefix"] = *f.Prefix
+	}
+
+	if f.Key != nil {
+		q["key"] = *f.Key
+	}
+
+	if f.UserID != nil {
+		q["user_id"] = *f.UserID
+	}
+
+	if f.TenantID != nil {
+		q["tenant_id"] = *f.TenantID
+	}
+
+	return q
+}
+
+// GetAPIKeys finds API keys in the database.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) GetAPIKeys(f *APIKeyFind) <-chan dlib.Result {
+	return ms.find("api_keys", apiKeyFilter(f), func() interface{} {
+		return &APIKey{}
+	})
+}
+
+// SaveAPIKey inserts or replaces a API key in the database. A API key with
+// no ID is inserted and receives the next available ID.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) SaveAPIKey(k *APIKey) <-chan dlib.Result {
+	return ms.save("api_keys", k.ID, func(id int64) { k.ID = id }, k)
+}
+
+// DeleteAPIKeys deletes API keys from the database. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ms *MongoStore) DeleteAPIKeys(f *APIKeyFind) <-chan dlib.Result {
+	return ms.remove("api_keys", apiKeyFilter(f))
+}
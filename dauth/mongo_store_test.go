@@ -0,0 +1,323 @@
+package dauth
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dhaifley/dlib"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type FakeMongoDBIterStore struct {
+	docs []interface{}
+	i    int
+}
+
+func (fk *FakeMongoDBIterStore) Close() error {
+	return nil
+}
+
+func (fk *FakeMongoDBIterStore) Done() bool {
+	return fk.i >= len(fk.docs)
+}
+
+func (fk *FakeMongoDBIterStore) Next(result interface{}) bool {
+	if fk.i >= len(fk.docs) {
+		return false
+	}
+
+	v := reflect.ValueOf(fk.docs[fk.i])
+	rv := reflect.ValueOf(result).Elem()
+	if v.Type().AssignableTo(rv.Type()) {
+		rv.Set(v)
+	} else {
+		b, _ := bson.Marshal(fk.docs[fk.i])
+		bson.Unmarshal(b, result)
+	}
+
+	fk.i++
+	return true
+}
+
+func (fk *FakeMongoDBIterStore) Err() error {
+	return nil
+}
+
+type FakeMongoDBQueryStore struct {
+	docs []interface{}
+}
+
+func (fk *FakeMongoDBQueryStore) Sort(fields ...string) dlib.MongoDBQuery {
+	return fk
+}
+
+func (fk *FakeMongoDBQueryStore) Limit(n int) dlib.MongoDBQuery {
+	if n < len(fk.docs) {
+		return &FakeMongoDBQueryStore{docs: fk.docs[:n]}
+	}
+
+	return fk
+}
+
+func (fk *FakeMongoDBQueryStore) Iter() dlib.MongoDBIter {
+	return &FakeMongoDBIterStore{docs: fk.docs}
+}
+
+type FakeMongoDBCollectionStore struct {
+	docs    []interface{}
+	filters []interface{}
+	removed []interface{}
+	updated []interface{}
+	indexes []mgo.Index
+}
+
+func (fk *FakeMongoDBCollectionStore) Find(query interface{}) dlib.MongoDBQuery {
+	fk.filters = append(fk.filters, query)
+	return &FakeMongoDBQueryStore{docs: fk.docs}
+}
+
+func (fk *FakeMongoDBCollectionStore) FindID(id interface{}) dlib.MongoDBQuery {
+	return &FakeMongoDBQueryStore{docs: fk.docs}
+}
+
+func (fk *FakeMongoDBCollectionStore) Insert(docs ...interface{}) error {
+	fk.docs = append(docs, fk.docs...)
+	return nil
+}
+
+func (fk *FakeMongoDBCollectionStore) RemoveAll(selector interface{}) (info *mgo.ChangeInfo, err error) {
+	fk.filters = append(fk.filters, selector)
+	return &mgo.ChangeInfo{Removed: len(fk.docs)}, nil
+}
+
+func (fk *FakeMongoDBCollectionStore) RemoveID(id interface{}) error {
+	fk.removed = append(fk.removed, id)
+	return mgo.ErrNotFound
+}
+
+func (fk *FakeMongoDBCollectionStore) UpdateID(id interface{}, update interface{}) error {
+	if id == int64(404) {
+		return mgo.ErrNotFound
+	}
+
+	fk.updated = append(fk.updated, id)
+	return nil
+}
+
+func (fk *FakeMongoDBCollectionStore) EnsureIndex(index mgo.Index) error {
+	fk.indexes = append(fk.indexes, index)
+	return nil
+}
+
+type FakeMongoDBDatabaseStore struct {
+	cols map[string]*FakeMongoDBCollectionStore
+}
+
+func (fk *FakeMongoDBDatabaseStore) C(name string) dlib.MongoDBCollection {
+	if fk.cols[name] == nil {
+		fk.cols[name] = &FakeMongoDBCollectionStore{}
+	}
+
+	return fk.cols[name]
+}
+
+func newFakeMongoDBDatabaseStore() *FakeMongoDBDatabaseStore {
+	return &FakeMongoDBDatabaseStore{cols: map[string]*FakeMongoDBCollectionStore{}}
+}
+
+func TestMongoStoreGetUsers(t *testing.T) {
+	db := newFakeMongoDBDatabaseStore()
+	db.C("users").Insert(User{ID: 1, User: "test"})
+	ms := NewMongoStore(db)
+	name := "test"
+	n := 0
+	for r := range ms.GetUsers(&UserFind{User: &name}) {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+
+		n++
+		if u := r.Val.(*User); u.ID != 1 {
+			t.Errorf("ID expected: 1, got: %v", u.ID)
+		}
+	}
+
+	if n != 1 {
+		t.Errorf("Count expected: 1, got: %v", n)
+	}
+
+	exp := bson.M{"user": "test"}
+	if !reflect.DeepEqual(db.cols["users"].filters[0], exp) {
+		t.Errorf("Filter expected: %v, got: %v", exp, db.cols["users"].filters[0])
+	}
+}
+
+func TestMongoStoreEnsureIndexes(t *testing.T) {
+	db := newFakeMongoDBDatabaseStore()
+	if err := NewMongoStore(db).EnsureIndexes(); err != nil {
+		t.Fatal(err)
+	}
+
+	idx := db.cols["users"].indexes
+	exp := []mgo.Index{{Key: []string{"tenant_id", "user"}, Unique: true}}
+	if !reflect.DeepEqual(idx, exp) {
+		t.Errorf("Indexes expected: %v, got: %v", exp, idx)
+	}
+
+	for _, col := range []string{"tokens", "api_keys"} {
+		if idx := db.cols[col].indexes; len(idx) != 1 || !idx[0].Unique {
+			t.Errorf("Unique index expected on %v, got: %v", col, idx)
+		}
+	}
+}
+
+func TestMongoStoreSavePerm(t *testing.T) {
+	db := newFakeMongoDBDatabaseStore()
+	db.C("perms").Insert(Perm{ID: 4, Service: "test", Name: "test"})
+	ms := NewMongoStore(db)
+	p := Perm{Service: "test", Name: "test2"}
+	for r := range ms.SavePerm(&p) {
+		if r.Err != nil {
+			t.Error(r.Err)
+		}
+	}
+
+	if p.ID != 5 {
+		t.Errorf("ID expected: 5, got: %v", p.ID)
+	}
+
+	for r := range ms.SavePerm(&p) {
+		if r.Err != nil {
+			t.Error(r.Err)
+		}
+	}
+
+	updated := db.cols["perms"].updated
+	if len(updated) != 1 || updated[0] != int64(5) {
+		t.Errorf("Updated expected: [5], got: %v", updated)
+	}
+
+	if removed := db.cols["perms"].removed; len(removed) != 0 {
+		t.Errorf("Removed expected: [], got: %v", removed)
+	}
+
+	p.ID = 404
+	for r := range ms.SavePerm(&p) {
+		if e, ok := r.Err.(*dlib.Error); !ok || e.Code != 404 {
+			t.Errorf("Error expected: 404, got: %v", r.Err)
+		}
+	}
+}
+
+func TestMongoStoreDeleteTokens(t *testing.T) {
+	db := newFakeMongoDBDatabaseStore()
+	db.C("tokens").Insert(Token{ID: 1, Token: "test"})
+	ms := NewMongoStore(db)
+	uid := int64(1)
+	for r := range ms.DeleteTokens(&TokenFind{UserID: &uid}) {
+		if r.Err != nil {
+			t.Error(r.Err)
+		}
+
+		if r.Num != 1 {
+			t.Errorf("Num expected: 1, got: %v", r.Num)
+		}
+	}
+
+	for r := range ms.DeleteTokens(&TokenFind{}) {
+		if r.Err == nil {
+			t.Error("Expected error for delete without criteria")
+		}
+	}
+}
+
+func TestMongoStoreGetRolePerms(t *testing.T) {
+	db := newFakeMongoDBDatabaseStore()
+	db.C("role_perms").Insert(RolePerm{ID: 1, RoleID: 2, PermID: 3})
+	ms := NewMongoStore(db)
+	rid := int64(2)
+	for r := range ms.GetRolePerms(&RolePermFind{RoleID: &rid}) {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+
+		if rp := r.Val.(*RolePerm); rp.PermID != 3 {
+			t.Errorf("PermID expected: 3, got: %v", rp.PermID)
+		}
+	}
+
+	exp := bson.M{"role_id": int64(2)}
+	if !reflect.DeepEqual(db.cols["role_perms"].filters[0], exp) {
+		t.Errorf("Filter expected: %v, got: %v", exp, db.cols["role_perms"].filters[0])
+	}
+}
+
+func TestMongoStoreGetLockouts(t *testing.T) {
+	db := newFakeMongoDBDatabaseStore()
+	db.C("lockouts").Insert(Lockout{ID: 1, Addr: "127.0.0.1", Failures: 3})
+	ms := NewMongoStore(db)
+	addr := "127.0.0.1"
+	n := 0
+	for r := range ms.GetLockouts(&LockoutFind{Addr: &addr}) {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+
+		n++
+		if l := r.Val.(*Lockout); l.Failures != 3 {
+			t.Errorf("Failures expected: 3, got: %v", l.Failures)
+		}
+	}
+
+	if n != 1 {
+		t.Errorf("Count expected: 1, got: %v", n)
+	}
+
+	exp := bson.M{"addr": "127.0.0.1"}
+	if !reflect.DeepEqual(db.cols["lockouts"].filters[0], exp) {
+		t.Errorf("Filter expected: %v, got: %v", exp, db.cols["lockouts"].filters[0])
+	}
+}
+
+func TestMongoStoreDeleteRecoveryCodes(t *testing.T) {
+	db := newFakeMongoDBDatabaseStore()
+	db.C("recovery_codes").Insert(RecoveryCode{ID: 1, UserID: 1, Code: "test"})
+	ms := NewMongoStore(db)
+	uid := int64(1)
+	code := "test"
+	for r := range ms.DeleteRecoveryCodes(&RecoveryCodeFind{UserID: &uid, Code: &code}) {
+		if r.Err != nil {
+			t.Error(r.Err)
+		}
+
+		if r.Num != 1 {
+			t.Errorf("Num expected: 1, got: %v", r.Num)
+		}
+	}
+}
+
+func TestMongoStoreGetUsersPage(t *testing.T) {
+	db := newFakeMongoDBDatabaseStore()
+	db.C("users").Insert(User{ID: 3, User: "c"})
+	db.C("users").Insert(User{ID: 2, User: "b"})
+	ms := NewMongoStore(db)
+	after := int64(1)
+	n := 0
+	for r := range ms.GetUsers(&UserFind{Page: Page{Limit: 1, After: &after}}) {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+
+		n++
+	}
+
+	if n != 1 {
+		t.Errorf("Count expected: 1, got: %v", n)
+	}
+
+	exp := bson.M{"_id": bson.M{"$gt": after}}
+	if !reflect.DeepEqual(db.cols["users"].filters[0], exp) {
+		t.Errorf("Filter expected: %v, got: %v", exp, db.cols["users"].filters[0])
+	}
+}
@@ -0,0 +1,146 @@
+package dauth
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/dhaifley/dlib"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// MaxPageSize is the largest number of records which may be requested in
+// a single page.
+const MaxPageSize = 1000
+
+// Sort orders of paged requests. Records are ordered by ID.
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// errInvalidPageToken is returned for page tokens which can not be decoded.
+var errInvalidPageToken = &dlib.Error{Code: 400, Msg: "invalid page token"}
+
+// Page values limit and order the records returned by a find operation.
+// Records are ordered by ID, descending if Desc is set. Pages are found by
+// keyset rather than by offset: After, if set, is the ID of the last record
+// of the previous page. Limit, if greater than zero, is the largest number
+// of records returned.
+type Page struct {
+	Limit int    `json:"limit,omitempty"`
+	After *int64 `json:"after,omitempty"`
+	Desc  bool   `json:"desc,omitempty"`
+}
+
+// PageToken returns the opaque continuation token for the page following
+// the record with an ID.
+func PageToken(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+// FromRequest populates a page value from the page size, continuation token
+// and sort order of a protobuf request. A page size of zero returns every
+// record, and an empty order is ascending.
+func (p *Page) FromRequest(size int64, token, order string) error {
+	if size < 0 || size > MaxPageSize {
+		return dlib.NewError(400, fmt.Sprintf("page size must be between 0 and %d",
+			MaxPageSize))
+	}
+
+	p.Limit = int(size)
+	p.After = nil
+	if token != "" {
+		b, err := base64.RawURLEncoding.DecodeString(token)
+		if err != nil {
+			return errInvalidPageToken
+		}
+
+		id, err := strconv.ParseInt(string(b), 10, 64)
+		if err != nil {
+			return errInvalidPageToken
+		}
+
+		p.After = &id
+	}
+
+	switch order {
+	case "", OrderAsc:
+		p.Desc = false
+	case OrderDesc:
+		p.Desc = true
+	default:
+		return dlib.NewError(400, "invalid order: "+order)
+	}
+
+	return nil
+}
+
+// ids orders a set of IDs and returns those in the page.
+func (p *Page) ids(ids []int64) []int64 {
+	sort.Slice(ids, func(i, j int) bool {
+		if p.Desc {
+			return ids[i] > ids[j]
+		}
+
+		return ids[i] < ids[j]
+	})
+
+	if p.After != nil {
+		n := 0
+		for _, id := range ids {
+			if (p.Desc && id < *p.After) || (!p.Desc && id > *p.After) {
+				ids[n] = id
+				n++
+			}
+		}
+
+		ids = ids[:n]
+	}
+
+	if p.Limit > 0 && len(ids) > p.Limit {
+		ids = ids[:p.Limit]
+	}
+
+	return ids
+}
+
+// sql adds the keyset condition of the page to a WHERE clause and returns
+// the ORDER BY and LIMIT clauses of the page.
+func (p *Page) sql(w *sqlWhere) string {
+	op, order := ">", " ORDER BY id"
+	if p.Desc {
+		op, order = "<", " ORDER BY id DESC"
+	}
+
+	if p.After != nil {
+		w.add("id "+op+" $%d", *p.After)
+	}
+
+	if p.Limit > 0 {
+		order += " LIMIT " + strconv.Itoa(p.Limit)
+	}
+
+	return order
+}
+
+// mongo adds the keyset condition of the page to a query filter and
+// returns the sort field of the page.
+func (p *Page) mongo(q bson.M) string {
+	op, sort := "$gt", "_id"
+	if p.Desc {
+		op, sort = "$lt", "-_id"
+	}
+
+	if p.After != nil {
+		cond := bson.M{op: *p.After}
+		if id, ok := q["_id"]; ok {
+			cond["$eq"] = id
+		}
+
+		q["_id"] = cond
+	}
+
+	return sort
+}
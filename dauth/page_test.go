@@ -0,0 +1,114 @@
+package dauth
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dhaifley/dlib"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestPageFromRequest(t *testing.T) {
+	cases := []struct {
+		size  int64
+		token string
+		order string
+		exp   Page
+		code  int
+	}{
+		{0, "", "", Page{}, 0},
+		{10, "", OrderAsc, Page{Limit: 10}, 0},
+		{10, PageToken(5), OrderDesc, Page{Limit: 10, Desc: true}, 0},
+		{-1, "", "", Page{}, 400},
+		{MaxPageSize + 1, "", "", Page{}, 400},
+		{10, "!", "", Page{}, 400},
+		{10, "YWJj", "", Page{}, 400},
+		{10, "", "sideways", Page{}, 400},
+	}
+
+	for _, c := range cases {
+		p := Page{}
+		err := p.FromRequest(c.size, c.token, c.order)
+		if c.code != 0 {
+			if e, ok := err.(*dlib.Error); !ok || e.Code != c.code {
+				t.Errorf("Error expected: %v, got: %v", c.code, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if p.Limit != c.exp.Limit || p.Desc != c.exp.Desc {
+			t.Errorf("Value expected: %+v, got: %+v", c.exp, p)
+		}
+
+		if c.token != "" && (p.After == nil || *p.After != 5) {
+			t.Errorf("After expected: 5, got: %v", p.After)
+		}
+	}
+}
+
+func TestPageIDs(t *testing.T) {
+	after := int64(2)
+	cases := []struct {
+		p   Page
+		exp []int64
+	}{
+		{Page{}, []int64{1, 2, 3, 4}},
+		{Page{Limit: 2}, []int64{1, 2}},
+		{Page{After: &after}, []int64{3, 4}},
+		{Page{Desc: true, Limit: 3}, []int64{4, 3, 2}},
+		{Page{Desc: true, After: &after}, []int64{1}},
+	}
+
+	for _, c := range cases {
+		got := c.p.ids([]int64{3, 1, 4, 2})
+		if !reflect.DeepEqual(got, c.exp) {
+			t.Errorf("Value expected: %v, got: %v", c.exp, got)
+		}
+	}
+}
+
+func TestPageSQL(t *testing.T) {
+	after := int64(7)
+	id := int64(1)
+	w := userWhere(&UserFind{ID: &id})
+	p := Page{Limit: 10, After: &after, Desc: true}
+	order := p.sql(w)
+	exp := " WHERE id = $1 AND id < $2 ORDER BY id DESC LIMIT 10"
+	if w.String()+order != exp {
+		t.Errorf("Query expected: %v, got: %v", exp, w.String()+order)
+	}
+
+	if len(w.args) != 2 || w.args[1] != after {
+		t.Errorf("Args expected: [1 7], got: %v", w.args)
+	}
+}
+
+func TestPageMongo(t *testing.T) {
+	after := int64(7)
+	p := Page{After: &after}
+	q := bson.M{"_id": int64(9)}
+	if sort := p.mongo(q); sort != "_id" {
+		t.Errorf("Sort expected: _id, got: %v", sort)
+	}
+
+	exp := bson.M{"_id": bson.M{"$gt": after, "$eq": int64(9)}}
+	if !reflect.DeepEqual(q, exp) {
+		t.Errorf("Filter expected: %v, got: %v", exp, q)
+	}
+
+	p.Desc = true
+	q = bson.M{}
+	if sort := p.mongo(q); sort != "-_id" {
+		t.Errorf("Sort expected: -_id, got: %v", sort)
+	}
+
+	exp = bson.M{"_id": bson.M{"$lt": after}}
+	if !reflect.DeepEqual(q, exp) {
+		t.Errorf("Filter expected: %v, got: %v", exp, q)
+	}
+}
@@ -0,0 +1,193 @@
+package dauth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/dhaifley/dlib"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordHasher is an interface describing types capable of producing
+// salted, self-describing password hashes. NeedsRehash reports whether an
+// encoded hash was produced by a different algorithm or with different cost
+// parameters than the hasher currently uses.
+type PasswordHasher interface {
+	Hash(pass string) (string, error)
+	NeedsRehash(hash string) bool
+}
+
+// ErrUnknownHash is returned when an encoded password hash is not in a
+// recognized format.
+var ErrUnknownHash = &dlib.Error{Code: 500, Msg: "unknown password hash format"}
+
+// DefaultPasswordHasher is the hasher used by servers created without an
+// explicit password hasher.
+var DefaultPasswordHasher PasswordHasher = NewArgon2Hasher()
+
+// BcryptHasher values hash passwords using bcrypt.
+type BcryptHasher struct {
+	Cost int
+}
+
+// NewBcryptHasher initializes and returns a pointer to a new bcrypt password
+// hasher value using the default cost.
+func NewBcryptHasher() *BcryptHasher {
+	return &BcryptHasher{Cost: bcrypt.DefaultCost}
+}
+
+// Hash returns a bcrypt hash of a password in modular crypt format.
+func (h *BcryptHasher) Hash(pass string) (string, error) {
+	b, err := bcrypt.GenerateFromPassword([]byte(pass), h.Cost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// NeedsRehash reports whether a hash is not a bcrypt hash of the configured
+// cost.
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.Cost
+}
+
+// Argon2Hasher values hash passwords using argon2id.
+type Argon2Hasher struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+	KeyLen  uint32
+	SaltLen uint32
+}
+
+// NewArgon2Hasher initializes and returns a pointer to a new argon2id
+// password hasher value using the recommended default parameters.
+func NewArgon2Hasher() *Argon2Hasher {
+	return &Argon2Hasher{
+		Time:    1,
+		Memory:  64 * 1024,
+		Threads: 4,
+		KeyLen:  32,
+		SaltLen: 16,
+	}
+}
+
+// ErrArgon2Params is returned when hashing with argon2id parameters which
+// can not produce a hash.
+var ErrArgon2Params = &dlib.Error{Code: 500, Msg: "invalid argon2id parameters"}
+
+// valid tests whether the time, memory, threads and key length parameters
+// are all non-zero, as argon2id requires.
+func (h *Argon2Hasher) valid() bool {
+	return h.Time != 0 && h.Memory != 0 && h.Threads != 0 && h.KeyLen != 0
+}
+
+// Hash returns an argon2id hash of a password in PHC string format.
+func (h *Argon2Hasher) Hash(pass string) (string, error) {
+	if !h.valid() {
+		return "", ErrArgon2Params
+	}
+
+	salt := make([]byte, h.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(pass), salt, h.Time, h.Memory, h.Threads, h.KeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.Memory, h.Time, h.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// NeedsRehash reports whether a hash is not an argon2id hash using the
+// configured parameters.
+func (h *Argon2Hasher) NeedsRehash(hash string) bool {
+	p, salt, key, err := decodeArgon2(hash)
+	if err != nil {
+		return true
+	}
+
+	return p.Time != h.Time || p.Memory != h.Memory || p.Threads != h.Threads ||
+		uint32(len(key)) != h.KeyLen || uint32(len(salt)) != h.SaltLen
+}
+
+// decodeArgon2 parses an argon2id hash in PHC string format.
+func decodeArgon2(hash string) (*Argon2Hasher, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return nil, nil, nil, ErrUnknownHash
+	}
+
+	var v int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &v); err != nil || v != argon2.Version {
+		return nil, nil, nil, ErrUnknownHash
+	}
+
+	p := Argon2Hasher{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d",
+		&p.Memory, &p.Time, &p.Threads); err != nil {
+		return nil, nil, nil, ErrUnknownHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, ErrUnknownHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return nil, nil, nil, ErrUnknownHash
+	}
+
+	p.KeyLen = uint32(len(key))
+	p.SaltLen = uint32(len(salt))
+	if !p.valid() {
+		return nil, nil, nil, ErrUnknownHash
+	}
+
+	return &p, salt, key, nil
+}
+
+// IsPasswordHash reports whether a string is an encoded password hash in a
+// format recognized by VerifyPassword.
+func IsPasswordHash(hash string) bool {
+	if _, err := bcrypt.Cost([]byte(hash)); err == nil {
+		return true
+	}
+
+	_, _, _, err := decodeArgon2(hash)
+	return err == nil
+}
+
+// VerifyPassword tests whether a password matches an encoded password hash.
+// The algorithm and parameters are read from the hash itself, so hashes
+// produced by any supported hasher can be verified.
+func VerifyPassword(hash, pass string) (bool, error) {
+	if strings.HasPrefix(hash, "$argon2id$") {
+		p, salt, key, err := decodeArgon2(hash)
+		if err != nil {
+			return false, err
+		}
+
+		k := argon2.IDKey([]byte(pass), salt, p.Time, p.Memory, p.Threads, p.KeyLen)
+		return subtle.ConstantTimeCompare(k, key) == 1, nil
+	}
+
+	if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+		return false, ErrUnknownHash
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(pass))
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return false, nil
+	}
+
+	return err == nil, err
+}
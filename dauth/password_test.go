@@ -0,0 +1,96 @@
+package dauth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPasswordHashers(t *testing.T) {
+	cases := []struct {
+		h      PasswordHasher
+		prefix string
+	}{
+		{&BcryptHasher{Cost: 4}, "$2a$04$"},
+		{testHasher, "$argon2id$v=19$m=1024,t=1,p=1$"},
+	}
+
+	for _, c := range cases {
+		hash, err := c.h.Hash("test")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.HasPrefix(hash, c.prefix) {
+			t.Errorf("Prefix expected: %v, got: %v", c.prefix, hash)
+		}
+
+		if !IsPasswordHash(hash) {
+			t.Errorf("Expected password hash: %v", hash)
+		}
+
+		if c.h.NeedsRehash(hash) {
+			t.Errorf("Unexpected rehash: %v", hash)
+		}
+
+		if ok, err := VerifyPassword(hash, "test"); !ok || err != nil {
+			t.Errorf("Expected valid password, got: %v, %v", ok, err)
+		}
+
+		if ok, err := VerifyPassword(hash, "wrong"); ok || err != nil {
+			t.Errorf("Expected invalid password, got: %v, %v", ok, err)
+		}
+
+		again, _ := c.h.Hash("test")
+		if again == hash {
+			t.Errorf("Expected salted hash, got: %v twice", hash)
+		}
+	}
+}
+
+func TestPasswordNeedsRehash(t *testing.T) {
+	bh, _ := (&BcryptHasher{Cost: 4}).Hash("test")
+	ah, _ := testHasher.Hash("test")
+	cases := []struct {
+		h    PasswordHasher
+		hash string
+		exp  bool
+	}{
+		{&BcryptHasher{Cost: 5}, bh, true},
+		{&BcryptHasher{Cost: 4}, ah, true},
+		{&Argon2Hasher{Time: 2, Memory: 1024, Threads: 1, KeyLen: 32, SaltLen: 16}, ah, true},
+		{&Argon2Hasher{Time: 1, Memory: 2048, Threads: 1, KeyLen: 32, SaltLen: 16}, ah, true},
+		{testHasher, bh, true},
+		{testHasher, "test", true},
+	}
+
+	for _, c := range cases {
+		if v := c.h.NeedsRehash(c.hash); v != c.exp {
+			t.Errorf("Value expected: %v, got: %v", c.exp, v)
+		}
+	}
+}
+
+func TestVerifyPasswordUnknown(t *testing.T) {
+	cases := []string{"test", "$argon2id$v=19$bad", "$argon2i$v=19$m=1,t=1,p=1$AA$AA", "",
+		"$argon2id$v=19$m=0,t=1,p=1$AAAA$AAAA",
+		"$argon2id$v=19$m=1024,t=0,p=1$AAAA$AAAA",
+		"$argon2id$v=19$m=1024,t=1,p=0$AAAA$AAAA",
+		"$argon2id$v=19$m=1024,t=1,p=1$AAAA$",
+	}
+	for _, c := range cases {
+		if IsPasswordHash(c) {
+			t.Errorf("Unexpected password hash: %v", c)
+		}
+
+		if _, err := VerifyPassword(c, "test"); err != ErrUnknownHash {
+			t.Errorf("Error expected: %v, got: %v", ErrUnknownHash, err)
+		}
+	}
+}
+
+func TestArgon2HasherParams(t *testing.T) {
+	h := &Argon2Hasher{Time: 0, Memory: 1024, Threads: 1, KeyLen: 32, SaltLen: 16}
+	if _, err := h.Hash("test"); err != ErrArgon2Params {
+		t.Errorf("Error expected: %v, got: %v", ErrArgon2Params, err)
+	}
+}
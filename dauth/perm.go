@@ -4,29 +4,38 @@ import (
 	"encoding/json"
 	"net/url"
 	"strconv"
+	"strings"
 
+	"github.com/dhaifley/dlib"
 	"github.com/dhaifley/dlib/ptypes"
 )
 
 // Perm values represenst a single API permissions.
 type Perm struct {
-	ID      int64  `json:"id,omitempty"`
-	Service string `json:"service,omitempty"`
-	Name    string `json:"name,omitempty"`
+	ID       int64  `json:"id,omitempty" bson:"_id"`
+	Service  string `json:"service,omitempty" bson:"service"`
+	Name     string `json:"name,omitempty" bson:"name"`
+	TenantID int64  `json:"tenant_id,omitempty" bson:"tenant_id"`
 }
 
 // PermRow values represent a single row in the perm table.
 type PermRow struct {
-	ID      int64
-	Service string
-	Name    string
+	ID       int64
+	Service  string
+	Name     string
+	TenantID int64
 }
 
 // PermFind values are used to find perm records in the database.
+// The page limits and orders the records found. A non-nil IDs list matches
+// the records with any of its IDs.
 type PermFind struct {
-	ID      *int64  `json:"id,omitempty"`
-	Service *string `json:"service,omitempty"`
-	Name    *string `json:"name,omitempty"`
+	ID       *int64  `json:"id,omitempty"`
+	IDs      []int64 `json:"ids,omitempty"`
+	Service  *string `json:"service,omitempty"`
+	Name     *string `json:"name,omitempty"`
+	TenantID *int64  `json:"tenant_id,omitempty"`
+	Page
 }
 
 // NewPerm initializes and returns a pointer to a new permission value.
@@ -53,9 +62,10 @@ func (p *Perm) Equals(b *Perm) bool {
 // Copy returns an exact copy of the value.
 func (p *Perm) Copy() Perm {
 	return Perm{
-		ID:      p.ID,
-		Service: p.Service,
-		Name:    p.Name,
+		ID:       p.ID,
+		Service:  p.Service,
+		Name:     p.Name,
+		TenantID: p.TenantID,
 	}
 }
 
@@ -74,15 +84,44 @@ func (p *Perm) FromRequest(req *ptypes.PermRequest) error {
 	p.ID = req.ID
 	p.Service = req.Service
 	p.Name = req.Name
+	p.TenantID = req.TenantID
+	return nil
+}
+
+// Update applies a permission protobuf request to this value, subject to the
+// update mask of the request. The ID is set from the request if it has one.
+func (p *Perm) Update(req *ptypes.PermRequest) error {
+	m, err := NewUpdateMask(req.UpdateMask, "service", "name", "tenant_id")
+	if err != nil {
+		return err
+	}
+
+	if req.ID != 0 {
+		p.ID = req.ID
+	}
+
+	if m.Applies("service", req.Service != "") {
+		p.Service = req.Service
+	}
+
+	if m.Applies("name", req.Name != "") {
+		p.Name = req.Name
+	}
+
+	if m.Applies("tenant_id", req.TenantID != 0) {
+		p.TenantID = req.TenantID
+	}
+
 	return nil
 }
 
 // ToRequest returns a protobuf request created from this value.
 func (p *Perm) ToRequest() ptypes.PermRequest {
 	return ptypes.PermRequest{
-		ID:      p.ID,
-		Service: p.Service,
-		Name:    p.Name,
+		ID:       p.ID,
+		Service:  p.Service,
+		Name:     p.Name,
+		TenantID: p.TenantID,
 	}
 }
 
@@ -91,15 +130,17 @@ func (p *Perm) FromResponse(res *ptypes.PermResponse) error {
 	p.ID = res.ID
 	p.Service = res.Service
 	p.Name = res.Name
+	p.TenantID = res.TenantID
 	return nil
 }
 
 // ToResponse returns a protobuf response created from this value.
 func (p *Perm) ToResponse() ptypes.PermResponse {
 	return ptypes.PermResponse{
-		ID:      p.ID,
-		Service: p.Service,
-		Name:    p.Name,
+		ID:       p.ID,
+		Service:  p.Service,
+		Name:     p.Name,
+		TenantID: p.TenantID,
 	}
 }
 
@@ -116,15 +157,33 @@ func (p *Perm) FromQueryValues(vals url.Values) error {
 
 	p.Service = vals.Get("service")
 	p.Name = vals.Get("name")
+	p.TenantID = 0
+	if vals.Get("tenant_id") != "" {
+		p.TenantID, err = strconv.ParseInt(vals.Get("tenant_id"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FromPerm populates a row value from a perm value.
+func (r *PermRow) FromPerm(p *Perm) error {
+	r.ID = p.ID
+	r.Service = p.Service
+	r.Name = p.Name
+	r.TenantID = p.TenantID
 	return nil
 }
 
 // ToPerm returns a value created from this row value.
 func (r *PermRow) ToPerm() Perm {
 	return Perm{
-		ID:      r.ID,
-		Service: r.Service,
-		Name:    r.Name,
+		ID:       r.ID,
+		Service:  r.Service,
+		Name:     r.Name,
+		TenantID: r.TenantID,
 	}
 }
 
@@ -135,6 +194,8 @@ func (f *PermFind) FromPerm(p *Perm) error {
 		f.ID = &p.ID
 	}
 
+	f.IDs = nil
+
 	f.Service = nil
 	if p.Service != "" {
 		f.Service = &p.Service
@@ -145,6 +206,11 @@ func (f *PermFind) FromPerm(p *Perm) error {
 		f.Name = &p.Name
 	}
 
+	f.TenantID = nil
+	if p.TenantID != 0 {
+		f.TenantID = &p.TenantID
+	}
+
 	return nil
 }
 
@@ -155,6 +221,8 @@ func (f *PermFind) FromPermRequest(r *ptypes.PermRequest) error {
 		f.ID = &r.ID
 	}
 
+	f.IDs = nil
+
 	f.Service = nil
 	if r.Service != "" {
 		f.Service = &r.Service
@@ -165,5 +233,105 @@ func (f *PermFind) FromPermRequest(r *ptypes.PermRequest) error {
 		f.Name = &r.Name
 	}
 
+	f.TenantID = nil
+	if r.TenantID != 0 {
+		f.TenantID = &r.TenantID
+	}
+
+	return nil
+}
+
+// Empty tests whether a perm find value has no search criteria.
+func (f *PermFind) Empty() bool {
+	return f.ID == nil && f.IDs == nil && f.Service == nil && f.Name == nil &&
+		f.TenantID == nil
+}
+
+// PermWildcard is the pattern matching any service, or any name segment.
+const PermWildcard = "*"
+
+// Implies tests whether holding this permission grants a requested
+// permission. A service of "*" matches any service; otherwise services
+// must be equal. Names are compared as sequences of segments separated by
+// ".". A "*" segment matches any single segment, except as the last
+// segment, where it matches one or more remaining segments. Other segments
+// must be equal. So "orders.*" implies "orders.read" and "orders.read.all"
+// but not "orders", and a name of "*" implies any non-empty name. Wildcards
+// in the requested permission are compared literally.
+func (p *Perm) Implies(req *Perm) bool {
+	if p == nil || req == nil {
+		return false
+	}
+
+	if p.Service != PermWildcard && p.Service != req.Service {
+		return false
+	}
+
+	if p.Name == req.Name {
+		return true
+	}
+
+	if p.Name == "" || req.Name == "" {
+		return false
+	}
+
+	ps := strings.Split(p.Name, ".")
+	rs := strings.Split(req.Name, ".")
+	for i, s := range ps {
+		last := i == len(ps)-1
+		switch {
+		case i >= len(rs):
+			return false
+		case s == PermWildcard && last:
+			return true
+		case s != PermWildcard && s != rs[i]:
+			return false
+		}
+	}
+
+	return len(ps) == len(rs)
+}
+
+// permFromClaim returns the permission named by a "service:name" claim
+// string, as produced by PermClaim.
+func permFromClaim(c string) (Perm, bool) {
+	i := strings.Index(c, ":")
+	if i < 0 {
+		return Perm{}, false
+	}
+
+	return Perm{Service: c[:i], Name: c[i+1:]}, true
+}
+
+// scopeAllows tests whether some claim in a scope implies a permission. An
+// empty scope allows every permission.
+func scopeAllows(scope []string, p *Perm) bool {
+	if len(scope) == 0 {
+		return true
+	}
+
+	for _, c := range scope {
+		if sp, ok := permFromClaim(c); ok && sp.Implies(p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkScope returns a 400 error if a scope contains a claim which is not
+// in "service:name" form, or which is not allowed by a parent scope.
+func checkScope(scope, parent []string) error {
+	for _, c := range scope {
+		p, ok := permFromClaim(c)
+		if !ok {
+			return dlib.NewError(400, "invalid scope: "+c)
+		}
+
+		if !scopeAllows(parent, &p) {
+			return dlib.NewError(400, "scope not allowed: "+c)
+		}
+	}
+
 	return nil
 }
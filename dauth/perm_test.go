@@ -119,6 +119,23 @@ func TestPermToResponse(t *testing.T) {
 	}
 }
 
+func TestPermRowFromPerm(t *testing.T) {
+	p := Perm{
+		ID:   1,
+		Name: "test",
+	}
+
+	pr := PermRow{}
+	if err := pr.FromPerm(&p); err != nil {
+		t.Error(err)
+	}
+
+	exp := "test"
+	if pr.Name != exp {
+		t.Errorf("Value expected: %v, got: %v", exp, pr.Name)
+	}
+}
+
 func TestPermRowToPerm(t *testing.T) {
 	ur := PermRow{
 		ID:   1,
@@ -175,3 +192,65 @@ func TestPermFindFromPermRequest(t *testing.T) {
 		t.Errorf("ID expected: 1, got: %v", *uf.ID)
 	}
 }
+
+func TestPermImplies(t *testing.T) {
+	cases := []struct {
+		held *Perm
+		req  *Perm
+		exp  bool
+	}{
+		{NewPerm(0, "orders", "read"), NewPerm(0, "orders", "read"), true},
+		{NewPerm(0, "orders", "read"), NewPerm(0, "orders", "write"), false},
+		{NewPerm(0, "orders", "read"), NewPerm(0, "users", "read"), false},
+		{NewPerm(0, "orders", "*"), NewPerm(0, "orders", "read"), true},
+		{NewPerm(0, "orders", "*"), NewPerm(0, "orders", "read.all"), true},
+		{NewPerm(0, "orders", "*"), NewPerm(0, "orders", ""), false},
+		{NewPerm(0, "orders", "*"), NewPerm(0, "users", "read"), false},
+		{NewPerm(0, "*", "*"), NewPerm(0, "users", "read"), true},
+		{NewPerm(0, "*", "read"), NewPerm(0, "users", "read"), true},
+		{NewPerm(0, "*", "read"), NewPerm(0, "users", "write"), false},
+		{NewPerm(0, "orders", "read.*"), NewPerm(0, "orders", "read.all"), true},
+		{NewPerm(0, "orders", "read.*"), NewPerm(0, "orders", "read.all.x"), true},
+		{NewPerm(0, "orders", "read.*"), NewPerm(0, "orders", "read"), false},
+		{NewPerm(0, "orders", "read.*"), NewPerm(0, "orders", "reader.all"), false},
+		{NewPerm(0, "orders", "*.read"), NewPerm(0, "orders", "a.read"), true},
+		{NewPerm(0, "orders", "*.read"), NewPerm(0, "orders", "a.b.read"), false},
+		{NewPerm(0, "orders", "*.read"), NewPerm(0, "orders", "a.write"), false},
+		{NewPerm(0, "orders", "a.*.c"), NewPerm(0, "orders", "a.b.c"), true},
+		{NewPerm(0, "orders", "a.*.c"), NewPerm(0, "orders", "a.b"), false},
+		{NewPerm(0, "orders", "read*"), NewPerm(0, "orders", "reader"), false},
+		{NewPerm(0, "orders", "read"), NewPerm(0, "orders", "*"), false},
+		{NewPerm(0, "orders", "read"), NewPerm(0, "*", "read"), false},
+		{NewPerm(0, "orders", "*"), NewPerm(0, "orders", "*"), true},
+		{NewPerm(0, "", ""), NewPerm(0, "", ""), true},
+		{nil, NewPerm(0, "orders", "read"), false},
+		{NewPerm(0, "orders", "read"), nil, false},
+	}
+
+	for _, c := range cases {
+		if v := c.held.Implies(c.req); v != c.exp {
+			t.Errorf("Implies %v %v expected: %v, got: %v", c.held, c.req, c.exp, v)
+		}
+	}
+}
+
+func TestCheckScope(t *testing.T) {
+	cases := []struct {
+		scope  []string
+		parent []string
+		exp    bool
+	}{
+		{[]string{"test:read"}, nil, true},
+		{[]string{"invalid"}, nil, false},
+		{[]string{"test:read"}, []string{"test:*"}, true},
+		{[]string{"test:read.*"}, []string{"test:read.*"}, true},
+		{[]string{"test:*"}, []string{"test:read"}, false},
+		{[]string{"test:read", "other:read"}, []string{"test:*"}, false},
+	}
+
+	for _, c := range cases {
+		if err := checkScope(c.scope, c.parent); (err == nil) != c.exp {
+			t.Errorf("checkScope %v %v expected: %v, got: %v", c.scope, c.parent, c.exp, err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package dauth
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/dhaifley/dlib"
+	"github.com/dhaifley/dlib/ptypes"
+)
+
+// DefaultReapInterval is the default interval between reaper runs.
+const DefaultReapInterval = 10 * time.Minute
+
+// Reaper values periodically delete expired tokens, and tokens created
+// longer ago than MaxAge, from a store. If LockoutAge is positive, login
+// lockouts whose lock ended longer ago than LockoutAge are also deleted, so
+// that failures recorded for user names which do not exist, and for client
+// addresses, do not accumulate. It should be at least the failure window
+// of the login throttle, after which old failures are forgotten anyway.
+// Each run is a set of conditional deletes, so several replicas may run
+// reapers against the same store at once; a record removed by one replica
+// is simply not counted by the others.
+type Reaper struct {
+	Store      Store
+	Interval   time.Duration
+	Jitter     time.Duration
+	MaxAge     time.Duration
+	LockoutAge time.Duration
+}
+
+// NewReaper initializes and returns a pointer to a new reaper value. The
+// reaper runs on the provided interval, with up to a tenth of the interval
+// added as jitter so that replicas do not run in lockstep. If maxAge is
+// zero, only expired tokens are deleted.
+func NewReaper(st Store, interval, maxAge time.Duration) *Reaper {
+	if interval <= 0 {
+		interval = DefaultReapInterval
+	}
+
+	return &Reaper{
+		Store:    st,
+		Interval: interval,
+		Jitter:   interval / 10,
+		MaxAge:   maxAge,
+	}
+}
+
+// Reap deletes expired and stale tokens, and old lockouts, from the store
+// once. It returns the number of records removed.
+func (rp *Reaper) Reap() (*ptypes.DeleteResponse, error) {
+	now := time.Now()
+	res, err := deleteResponse(rp.Store.DeleteTokens(&TokenFind{Expired: &now}))
+	if err != nil {
+		return nil, err
+	}
+
+	if rp.MaxAge > 0 {
+		old := now.Add(-rp.MaxAge)
+		ores, err := deleteResponse(rp.Store.DeleteTokens(&TokenFind{Old: &old}))
+		if err != nil {
+			return nil, err
+		}
+
+		res.Num += ores.Num
+	}
+
+	if rp.LockoutAge > 0 {
+		old := now.Add(-rp.LockoutAge)
+		lres, err := deleteResponse(rp.Store.DeleteLockouts(&LockoutFind{Expired: &old}))
+		if err != nil {
+			return nil, err
+		}
+
+		res.Num += lres.Num
+	}
+
+	return res, nil
+}
+
+// delay returns the time to wait before the next run.
+func (rp *Reaper) delay() time.Duration {
+	d := rp.Interval
+	if d <= 0 {
+		d = DefaultReapInterval
+	}
+
+	if rp.Jitter > 0 {
+		d += time.Duration(rand.Int63n(int64(rp.Jitter)))
+	}
+
+	return d
+}
+
+// Run reaps tokens on the reaper interval until the context is done. The
+// result of each run is sent on the returned channel, with Val holding the
+// delete response and Num the number of tokens removed. The channel holds
+// one result, and results are dropped while it is full, so callers which
+// are not interested in them need not drain it. The channel is closed when
+// the context is done.
+func (rp *Reaper) Run(ctx context.Context) <-chan dlib.Result {
+	ch := make(chan dlib.Result, 1)
+	go func() {
+		defer close(ch)
+		for {
+			t := time.NewTimer(rp.delay())
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return
+			case <-t.C:
+			}
+
+			r := dlib.Result{}
+			res, err := rp.Reap()
+			if err != nil {
+				r.Err = err
+			} else {
+				r.Val = res
+				r.Num = int(res.Num)
+			}
+
+			select {
+			case ch <- r:
+			default:
+			}
+		}
+	}()
+
+	return ch
+}
@@ -0,0 +1,126 @@
+package dauth
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newReaperStore() *MemoryStore {
+	ms := NewMemoryStore()
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	stale := now.Add(-48 * time.Hour)
+	ms.SaveToken(NewToken(0, "expired", 1, &past, &past))
+	ms.SaveToken(NewToken(0, "valid", 1, &now, &future))
+	ms.SaveToken(NewToken(0, "stale", 1, &stale, &future))
+	return ms
+}
+
+func TestReaperReap(t *testing.T) {
+	cases := []struct {
+		maxAge time.Duration
+		exp    int64
+		left   int
+	}{
+		{0, 1, 2},
+		{24 * time.Hour, 2, 1},
+	}
+
+	for _, c := range cases {
+		ms := newReaperStore()
+		rp := NewReaper(ms, time.Minute, c.maxAge)
+		res, err := rp.Reap()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.Num != c.exp {
+			t.Errorf("Num expected: %v, got: %v", c.exp, res.Num)
+		}
+
+		n := 0
+		for range ms.GetTokens(&TokenFind{}) {
+			n++
+		}
+
+		if n != c.left {
+			t.Errorf("Count expected: %v, got: %v", c.left, n)
+		}
+
+		res, err = rp.Reap()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.Num != 0 {
+			t.Errorf("Num expected: 0, got: %v", res.Num)
+		}
+	}
+}
+
+func TestReaperReapLockouts(t *testing.T) {
+	ms := newReaperStore()
+	now := time.Now()
+	old := now.Add(-2 * time.Hour)
+	recent := now.Add(-time.Minute)
+	ms.SaveLockout(NewLockout(0, "none", "", 1, &old, &old))
+	ms.SaveLockout(NewLockout(0, "", "127.0.0.1", 1, &old, &old))
+	ms.SaveLockout(NewLockout(0, "test", "", 1, &recent, &recent))
+	rp := NewReaper(ms, time.Minute, 0)
+	rp.LockoutAge = time.Hour
+	res, err := rp.Reap()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Num != 3 {
+		t.Errorf("Num expected: 3, got: %v", res.Num)
+	}
+
+	left := []string{}
+	for r := range ms.GetLockouts(&LockoutFind{}) {
+		left = append(left, r.Val.(*Lockout).User)
+	}
+
+	if len(left) != 1 || left[0] != "test" {
+		t.Errorf("Expected lockout for test, got: %v", left)
+	}
+}
+
+func TestReaperRun(t *testing.T) {
+	ms := newReaperStore()
+	rp := &Reaper{Store: ms, Interval: time.Millisecond, MaxAge: 24 * time.Hour}
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := rp.Run(ctx)
+	r := <-ch
+	if r.Err != nil {
+		t.Fatal(r.Err)
+	}
+
+	if r.Num != 2 {
+		t.Errorf("Num expected: 2, got: %v", r.Num)
+	}
+
+	cancel()
+	for range ch {
+	}
+}
+
+func TestReaperRunUndrained(t *testing.T) {
+	ms := newReaperStore()
+	rp := &Reaper{Store: ms, Interval: time.Millisecond}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rp.Run(ctx)
+	time.Sleep(10 * time.Millisecond)
+	past := time.Now().Add(-time.Hour)
+	ms.SaveToken(NewToken(0, "later", 1, &past, &past))
+	time.Sleep(10 * time.Millisecond)
+	for r := range ms.GetTokens(&TokenFind{}) {
+		if tk := r.Val.(*Token); tk.Token == "later" {
+			t.Errorf("Expected expired token to be reaped, got: %v", tk)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package dauth
+
+import "github.com/dhaifley/dlib"
+
+// ErrRoleCycle is returned when saving a role would make it inherit from
+// itself through its parent roles.
+var ErrRoleCycle = &dlib.Error{Code: 400, Msg: "role inheritance cycle"}
+
+// getRole returns the role with an ID, or nil if there is none.
+func getRole(st Store, id int64) (*Role, error) {
+	var ro *Role
+	f := RoleFind{ID: &id}
+	for r := range st.GetRoles(&f) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		ro = r.Val.(*Role)
+	}
+
+	return ro, nil
+}
+
+// CheckRoleCycle tests whether saving a role with its parent would create
+// a cycle of inherited roles. It returns ErrRoleCycle if it would.
+func CheckRoleCycle(st Store, ro *Role) error {
+	seen := map[int64]bool{}
+	for id := ro.ParentID; id != 0; {
+		if id == ro.ID {
+			return ErrRoleCycle
+		}
+
+		if seen[id] {
+			return nil
+		}
+
+		seen[id] = true
+		p, err := getRole(st, id)
+		if err != nil {
+			return err
+		}
+
+		if p == nil {
+			return nil
+		}
+
+		id = p.ParentID
+	}
+
+	return nil
+}
+
+// effectivePermIDs returns the set of IDs of the permissions a user has been
+// assigned directly or through roles, including roles inherited by them.
+// Conditional grants are included only if their conditions hold for the
+// grant context, and never if it is nil. The role graph is read in a single
+// query, and the permissions of all reached roles in another.
+func effectivePermIDs(st Store, userID int64, gc *GrantContext) (map[int64]bool, error) {
+	ids := map[int64]bool{}
+	upf := UserPermFind{UserID: &userID}
+	for r := range st.GetUserPerms(&upf) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		up := r.Val.(*UserPerm)
+		if up.Conditional() && (gc == nil || !up.Allows(gc)) {
+			continue
+		}
+
+		ids[up.PermID] = true
+	}
+
+	roles := []int64{}
+	urf := UserRoleFind{UserID: &userID}
+	for r := range st.GetUserRoles(&urf) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		roles = append(roles, r.Val.(*UserRole).RoleID)
+	}
+
+	if len(roles) == 0 {
+		return ids, nil
+	}
+
+	parents := map[int64]int64{}
+	for r := range st.GetRoles(&RoleFind{}) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		ro := r.Val.(*Role)
+		parents[ro.ID] = ro.ParentID
+	}
+
+	seen := map[int64]bool{}
+	for len(roles) > 0 {
+		id := roles[0]
+		roles = roles[1:]
+		if seen[id] {
+			continue
+		}
+
+		seen[id] = true
+		if p := parents[id]; p != 0 {
+			roles = append(roles, p)
+		}
+	}
+
+	rpf := RolePermFind{RoleIDs: sortedIDs(keys(seen))}
+	for r := range st.GetRolePerms(&rpf) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		ids[r.Val.(*RolePerm).PermID] = true
+	}
+
+	return ids, nil
+}
+
+// EffectivePerms returns the permissions a user has been assigned directly
+// or through roles, including the permissions of roles inherited by them.
+// Each permission is returned once, in ID order. Conditional grants are not
+// included, since whether they hold depends on the request.
+func EffectivePerms(st Store, userID int64) ([]Perm, error) {
+	return EffectivePermsWith(st, userID, nil)
+}
+
+// EffectivePermsWith returns the effective permissions of a user, as
+// EffectivePerms does, including the conditional grants whose conditions
+// hold for a grant context.
+func EffectivePermsWith(st Store, userID int64, gc *GrantContext) ([]Perm, error) {
+	ids, err := effectivePermIDs(st, userID, gc)
+	if err != nil {
+		return nil, err
+	}
+
+	perms := []Perm{}
+	if len(ids) == 0 {
+		return perms, nil
+	}
+
+	pf := PermFind{IDs: sortedIDs(keys(ids))}
+	for r := range st.GetPerms(&pf) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		perms = append(perms, *r.Val.(*Perm))
+	}
+
+	return perms, nil
+}
+
+// keys returns the keys of an ID set.
+func keys(ids map[int64]bool) []int64 {
+	v := make([]int64, 0, len(ids))
+	for id := range ids {
+		v = append(v, id)
+	}
+
+	return v
+}
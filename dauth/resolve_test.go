@@ -0,0 +1,155 @@
+package dauth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dhaifley/dlib"
+)
+
+func newTestRoleStore() *MemoryStore {
+	ms := NewMemoryStore()
+	for _, n := range []string{"a", "b", "c", "d"} {
+		ms.SavePerm(&Perm{Service: "test", Name: n})
+	}
+
+	ms.SaveRole(&Role{Name: "base"})
+	ms.SaveRole(&Role{Name: "staff", ParentID: 1})
+	ms.SaveRole(&Role{Name: "other"})
+	ms.SaveRolePerm(&RolePerm{RoleID: 1, PermID: 1})
+	ms.SaveRolePerm(&RolePerm{RoleID: 2, PermID: 2})
+	ms.SaveRolePerm(&RolePerm{RoleID: 3, PermID: 4})
+	ms.SaveUserPerm(&UserPerm{UserID: 1, PermID: 3})
+	ms.SaveUserPerm(&UserPerm{UserID: 1, PermID: 1})
+	ms.SaveUserRole(&UserRole{UserID: 1, RoleID: 2})
+	return ms
+}
+
+func TestEffectivePerms(t *testing.T) {
+	ms := newTestRoleStore()
+	cases := []struct {
+		userID int64
+		exp    []string
+	}{
+		{1, []string{"a", "b", "c"}},
+		{2, []string{}},
+	}
+
+	for _, c := range cases {
+		perms, err := EffectivePerms(ms, c.userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(perms) != len(c.exp) {
+			t.Errorf("Perms expected: %v, got: %v", c.exp, perms)
+			continue
+		}
+
+		for i, p := range perms {
+			if p.Name != c.exp[i] {
+				t.Errorf("Perms expected: %v, got: %v", c.exp, perms)
+			}
+		}
+	}
+}
+
+func TestEffectivePermsWith(t *testing.T) {
+	ms := newTestRoleStore()
+	now := time.Now()
+	exp := now.Add(time.Hour)
+	ms.SaveUserPerm(&UserPerm{UserID: 1, PermID: 4, Expires: &exp,
+		CIDRs: []string{"10.0.0.0/8"}})
+	cases := []struct {
+		gc  *GrantContext
+		exp int
+	}{
+		{nil, 3},
+		{&GrantContext{Time: now, Addr: "10.0.0.1"}, 4},
+		{&GrantContext{Time: now, Addr: "192.168.0.1"}, 3},
+		{&GrantContext{Time: exp, Addr: "10.0.0.1"}, 3},
+	}
+
+	for _, c := range cases {
+		perms, err := EffectivePermsWith(ms, 1, c.gc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(perms) != c.exp {
+			t.Errorf("Length expected: %v, got: %v", c.exp, len(perms))
+		}
+	}
+}
+
+func TestEffectivePermsCycle(t *testing.T) {
+	ms := newTestRoleStore()
+	ms.SaveRole(&Role{ID: 1, Name: "base", ParentID: 2})
+	perms, err := EffectivePerms(ms, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(perms) != 3 {
+		t.Errorf("Length expected: 3, got: %v", len(perms))
+	}
+}
+
+func TestCheckRoleCycle(t *testing.T) {
+	ms := newTestRoleStore()
+	cases := []struct {
+		ro  Role
+		exp error
+	}{
+		{Role{Name: "new", ParentID: 2}, nil},
+		{Role{ID: 3, Name: "other", ParentID: 2}, nil},
+		{Role{ID: 1, Name: "base", ParentID: 1}, ErrRoleCycle},
+		{Role{ID: 1, Name: "base", ParentID: 2}, ErrRoleCycle},
+		{Role{ID: 1, Name: "base", ParentID: 9}, nil},
+	}
+
+	for _, c := range cases {
+		if err := CheckRoleCycle(ms, &c.ro); err != c.exp {
+			t.Errorf("Error expected: %v, got: %v", c.exp, err)
+		}
+	}
+}
+
+type countStore struct {
+	*MemoryStore
+	calls int
+}
+
+func (cs *countStore) GetPerms(f *PermFind) <-chan dlib.Result {
+	cs.calls++
+	return cs.MemoryStore.GetPerms(f)
+}
+
+func (cs *countStore) GetRoles(f *RoleFind) <-chan dlib.Result {
+	cs.calls++
+	return cs.MemoryStore.GetRoles(f)
+}
+
+func (cs *countStore) GetRolePerms(f *RolePermFind) <-chan dlib.Result {
+	cs.calls++
+	return cs.MemoryStore.GetRolePerms(f)
+}
+
+func TestEffectivePermsBatch(t *testing.T) {
+	ms := newTestRoleStore()
+	ms.SaveRole(&Role{Name: "lead", ParentID: 2})
+	ms.SaveUserRole(&UserRole{UserID: 1, RoleID: 4})
+	cs := &countStore{MemoryStore: ms}
+	perms, err := EffectivePerms(cs, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(perms) != 3 {
+		t.Errorf("Length expected: 3, got: %v", len(perms))
+	}
+
+	if cs.calls != 3 {
+		t.Errorf("Calls expected: 3, got: %v", cs.calls)
+	}
+}
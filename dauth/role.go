@@ -0,0 +1,247 @@
+package dauth
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/dhaifley/dlib/ptypes"
+)
+
+// Role values represent a single named group of API permissions. A role
+// inherits the permissions of its parent role, if it has one, which must
+// belong to the same tenant.
+type Role struct {
+	ID       int64  `json:"id,omitempty" bson:"_id"`
+	Name     string `json:"name,omitempty" bson:"name"`
+	ParentID int64  `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	TenantID int64  `json:"tenant_id,omitempty" bson:"tenant_id"`
+}
+
+// RoleRow values represent a single row in the role table.
+type RoleRow struct {
+	ID       int64
+	Name     string
+	ParentID sql.NullInt64
+	TenantID int64
+}
+
+// RoleFind values are used to find role records in the database.
+type RoleFind struct {
+	ID       *int64  `json:"id,omitempty"`
+	Name     *string `json:"name,omitempty"`
+	ParentID *int64  `json:"parent_id,omitempty"`
+	TenantID *int64  `json:"tenant_id,omitempty"`
+}
+
+// NewRole initializes and returns a pointer to a new role value.
+func NewRole(id int64, name string, parentID int64) *Role {
+	return &Role{
+		ID:       id,
+		Name:     name,
+		ParentID: parentID,
+	}
+}
+
+// Equals tests for deep equality between role values.
+func (ro *Role) Equals(b *Role) bool {
+	switch {
+	case ro == nil || b == nil:
+		return false
+	case *ro != *b:
+		return false
+	default:
+		return true
+	}
+}
+
+// Copy returns an exact copy of the value.
+func (ro *Role) Copy() Role {
+	return Role{
+		ID:       ro.ID,
+		Name:     ro.Name,
+		ParentID: ro.ParentID,
+		TenantID: ro.TenantID,
+	}
+}
+
+// String formats a role value as a JSON format string.
+func (ro *Role) String() string {
+	str, err := json.Marshal(ro)
+	if err != nil {
+		return ""
+	}
+
+	return string(str)
+}
+
+// FromRequest populates this value from a protobuf request.
+func (ro *Role) FromRequest(req *ptypes.RoleRequest) error {
+	ro.ID = req.ID
+	ro.Name = req.Name
+	ro.ParentID = req.ParentID
+	ro.TenantID = req.TenantID
+	return nil
+}
+
+// Update applies a role protobuf request to this value, subject to the
+// update mask of the request. The ID is set from the request if it has one.
+func (ro *Role) Update(req *ptypes.RoleRequest) error {
+	m, err := NewUpdateMask(req.UpdateMask, "name", "parent_id", "tenant_id")
+	if err != nil {
+		return err
+	}
+
+	if req.ID != 0 {
+		ro.ID = req.ID
+	}
+
+	if m.Applies("name", req.Name != "") {
+		ro.Name = req.Name
+	}
+
+	if m.Applies("parent_id", req.ParentID != 0) {
+		ro.ParentID = req.ParentID
+	}
+
+	if m.Applies("tenant_id", req.TenantID != 0) {
+		ro.TenantID = req.TenantID
+	}
+
+	return nil
+}
+
+// ToRequest returns a protobuf request created from this value.
+func (ro *Role) ToRequest() ptypes.RoleRequest {
+	return ptypes.RoleRequest{
+		ID:       ro.ID,
+		Name:     ro.Name,
+		ParentID: ro.ParentID,
+		TenantID: ro.TenantID,
+	}
+}
+
+// FromResponse populates this value from a protobuf response.
+func (ro *Role) FromResponse(res *ptypes.RoleResponse) error {
+	ro.ID = res.ID
+	ro.Name = res.Name
+	ro.ParentID = res.ParentID
+	ro.TenantID = res.TenantID
+	return nil
+}
+
+// ToResponse returns a protobuf response created from this value.
+func (ro *Role) ToResponse() ptypes.RoleResponse {
+	return ptypes.RoleResponse{
+		ID:       ro.ID,
+		Name:     ro.Name,
+		ParentID: ro.ParentID,
+		TenantID: ro.TenantID,
+	}
+}
+
+// FromQueryValues populates this value from a query string map.
+func (ro *Role) FromQueryValues(vals url.Values) error {
+	var err error
+	ro.ID = 0
+	if vals.Get("id") != "" {
+		ro.ID, err = strconv.ParseInt(vals.Get("id"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	ro.Name = vals.Get("name")
+	ro.ParentID = 0
+	if vals.Get("parent_id") != "" {
+		ro.ParentID, err = strconv.ParseInt(vals.Get("parent_id"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	ro.TenantID = 0
+	if vals.Get("tenant_id") != "" {
+		ro.TenantID, err = strconv.ParseInt(vals.Get("tenant_id"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FromRole populates a row value from a role value.
+func (r *RoleRow) FromRole(ro *Role) error {
+	r.ID = ro.ID
+	r.Name = ro.Name
+	r.ParentID = sql.NullInt64{Int64: ro.ParentID, Valid: ro.ParentID != 0}
+	r.TenantID = ro.TenantID
+	return nil
+}
+
+// ToRole returns a value created from this row value.
+func (r *RoleRow) ToRole() Role {
+	ro := Role{
+		ID:       r.ID,
+		Name:     r.Name,
+		TenantID: r.TenantID,
+	}
+
+	if r.ParentID.Valid {
+		ro.ParentID = r.ParentID.Int64
+	}
+
+	return ro
+}
+
+// FromRole populates a role find value from a role value.
+func (f *RoleFind) FromRole(ro *Role) error {
+	f.ID = nil
+	if ro.ID != 0 {
+		f.ID = &ro.ID
+	}
+
+	f.Name = nil
+	if ro.Name != "" {
+		f.Name = &ro.Name
+	}
+
+	f.ParentID = nil
+	if ro.ParentID != 0 {
+		f.ParentID = &ro.ParentID
+	}
+
+	f.TenantID = nil
+	if ro.TenantID != 0 {
+		f.TenantID = &ro.TenantID
+	}
+
+	return nil
+}
+
+// FromRoleRequest populates a role find value from a role protobuf request.
+func (f *RoleFind) FromRoleRequest(r *ptypes.RoleRequest) error {
+	f.ID = nil
+	if r.ID != 0 {
+		f.ID = &r.ID
+	}
+
+	f.Name = nil
+	if r.Name != "" {
+		f.Name = &r.Name
+	}
+
+	f.ParentID = nil
+	if r.ParentID != 0 {
+		f.ParentID = &r.ParentID
+	}
+
+	f.TenantID = nil
+	if r.TenantID != 0 {
+		f.TenantID = &r.TenantID
+	}
+
+	return nil
+}
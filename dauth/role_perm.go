@@ -0,0 +1,261 @@
+package dauth
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/dhaifley/dlib/ptypes"
+)
+
+// RolePerm values represenst a single API role permission assignment.
+type RolePerm struct {
+	ID       int64 `json:"id,omitempty" bson:"_id"`
+	RoleID   int64 `json:"role_id,omitempty" bson:"role_id"`
+	PermID   int64 `json:"perm_id,omitempty" bson:"perm_id"`
+	TenantID int64 `json:"tenant_id,omitempty" bson:"tenant_id"`
+}
+
+// RolePermRow values represent a single row in the role_perm table.
+type RolePermRow struct {
+	ID       int64
+	RoleID   int64
+	PermID   int64
+	TenantID int64
+}
+
+// RolePermFind values are used to find role_perm records in the database.
+// A non-nil RoleIDs list matches the records of any of its roles.
+type RolePermFind struct {
+	ID       *int64  `json:"id,omitempty"`
+	RoleID   *int64  `json:"role_id,omitempty"`
+	RoleIDs  []int64 `json:"role_ids,omitempty"`
+	PermID   *int64  `json:"perm_id,omitempty"`
+	TenantID *int64  `json:"tenant_id,omitempty"`
+}
+
+// NewRolePerm initializes and returns a pointer to a new
+// role permission value.
+func NewRolePerm(id, roleID, permID int64) *RolePerm {
+	return &RolePerm{
+		ID:     id,
+		RoleID: roleID,
+		PermID: permID,
+	}
+}
+
+// Equals tests for deep equality between role perm assignments.
+func (rp *RolePerm) Equals(b *RolePerm) bool {
+	switch {
+	case rp == nil || b == nil:
+		return false
+	case *rp != *b:
+		return false
+	default:
+		return true
+	}
+}
+
+// Copy returns an exact deep copy of the value.
+func (rp *RolePerm) Copy() RolePerm {
+	return RolePerm{
+		ID:       rp.ID,
+		RoleID:   rp.RoleID,
+		PermID:   rp.PermID,
+		TenantID: rp.TenantID,
+	}
+}
+
+// String formats a role_perm value as a JSON format string.
+func (rp *RolePerm) String() string {
+	str, err := json.Marshal(rp)
+	if err != nil {
+		return ""
+	}
+
+	return string(str)
+}
+
+// FromRequest populates this value from a protobuf request.
+func (rp *RolePerm) FromRequest(req *ptypes.RolePermRequest) error {
+	rp.ID = req.ID
+	rp.RoleID = req.RoleID
+	rp.PermID = req.PermID
+	rp.TenantID = req.TenantID
+	return nil
+
+}
+
+// Update applies a role permission protobuf request to this value, subject to the
+// update mask of the request. The ID is set from the request if it has one.
+func (rp *RolePerm) Update(req *ptypes.RolePermRequest) error {
+	m, err := NewUpdateMask(req.UpdateMask, "role_id", "perm_id", "tenant_id")
+	if err != nil {
+		return err
+	}
+
+	if req.ID != 0 {
+		rp.ID = req.ID
+	}
+
+	if m.Applies("role_id", req.RoleID != 0) {
+		rp.RoleID = req.RoleID
+	}
+
+	if m.Applies("perm_id", req.PermID != 0) {
+		rp.PermID = req.PermID
+	}
+
+	if m.Applies("tenant_id", req.TenantID != 0) {
+		rp.TenantID = req.TenantID
+	}
+
+	return nil
+}
+
+// ToRequest returns a protobuf request created from this value.
+func (rp *RolePerm) ToRequest() ptypes.RolePermRequest {
+	return ptypes.RolePermRequest{
+		ID:       rp.ID,
+		RoleID:   rp.RoleID,
+		PermID:   rp.PermID,
+		TenantID: rp.TenantID,
+	}
+}
+
+// FromResponse populates this value from a protobuf response.
+func (rp *RolePerm) FromResponse(res *ptypes.RolePermResponse) error {
+	rp.ID = res.ID
+	rp.RoleID = res.RoleID
+	rp.PermID = res.PermID
+	rp.TenantID = res.TenantID
+	return nil
+}
+
+// ToResponse returns a protobuf response created from this value.
+func (rp *RolePerm) ToResponse() ptypes.RolePermResponse {
+	return ptypes.RolePermResponse{
+		ID:       rp.ID,
+		RoleID:   rp.RoleID,
+		PermID:   rp.PermID,
+		TenantID: rp.TenantID,
+	}
+}
+
+// FromQueryValues populates this value from a query string map.
+func (rp *RolePerm) FromQueryValues(vals url.Values) error {
+	var err error
+	rp.ID = 0
+	if vals.Get("id") != "" {
+		rp.ID, err = strconv.ParseInt(vals.Get("id"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	rp.RoleID = 0
+	if vals.Get("role_id") != "" {
+		rp.RoleID, err = strconv.ParseInt(vals.Get("role_id"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	rp.PermID = 0
+	if vals.Get("perm_id") != "" {
+		rp.PermID, err = strconv.ParseInt(vals.Get("perm_id"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	rp.TenantID = 0
+	if vals.Get("tenant_id") != "" {
+		rp.TenantID, err = strconv.ParseInt(vals.Get("tenant_id"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FromRolePerm populates a row value from a role_perm value.
+func (r *RolePermRow) FromRolePerm(rp *RolePerm) error {
+	r.ID = rp.ID
+	r.RoleID = rp.RoleID
+	r.PermID = rp.PermID
+	r.TenantID = rp.TenantID
+	return nil
+}
+
+// ToRolePerm returns a value created from this row value.
+func (r RolePermRow) ToRolePerm() RolePerm {
+	return RolePerm{
+		ID:       r.ID,
+		RoleID:   r.RoleID,
+		PermID:   r.PermID,
+		TenantID: r.TenantID,
+	}
+}
+
+// FromRolePerm populates a role_perm find value from a role_perm value.
+func (f *RolePermFind) FromRolePerm(rp *RolePerm) error {
+	f.ID = nil
+	if rp.ID != 0 {
+		f.ID = &rp.ID
+	}
+
+	f.RoleID = nil
+	if rp.RoleID != 0 {
+		f.RoleID = &rp.RoleID
+	}
+
+	f.RoleIDs = nil
+
+	f.PermID = nil
+	if rp.PermID != 0 {
+		f.PermID = &rp.PermID
+	}
+
+	f.TenantID = nil
+	if rp.TenantID != 0 {
+		f.TenantID = &rp.TenantID
+	}
+
+	return nil
+}
+
+// FromRolePermRequest populates a role_perm find value from a role_perm
+// protobuf request.
+func (f *RolePermFind) FromRolePermRequest(r *ptypes.RolePermRequest) error {
+	f.ID = nil
+	if r.ID != 0 {
+		f.ID = &r.ID
+	}
+
+	f.RoleID = nil
+	if r.RoleID != 0 {
+		f.RoleID = &r.RoleID
+	}
+
+	f.RoleIDs = nil
+
+	f.PermID = nil
+	if r.PermID != 0 {
+		f.PermID = &r.PermID
+	}
+
+	f.TenantID = nil
+	if r.TenantID != 0 {
+		f.TenantID = &r.TenantID
+	}
+
+	return nil
+}
+
+// Empty tests whether a role_perm find value has no search criteria.
+func (f *RolePermFind) Empty() bool {
+	return f.ID == nil && f.RoleID == nil && f.RoleIDs == nil && f.PermID == nil &&
+		f.TenantID == nil
+}
@@ -0,0 +1,205 @@
+package dauth
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/dhaifley/dlib/ptypes"
+)
+
+func TestRolePermEquals(t *testing.T) {
+	cases := []struct {
+		a        *RolePerm
+		b        *RolePerm
+		expected bool
+	}{
+		{
+			a:        NewRolePerm(1, 1, 1),
+			b:        NewRolePerm(1, 1, 1),
+			expected: true,
+		},
+		{
+			a:        NewRolePerm(1, 1, 1),
+			b:        NewRolePerm(1, 1, 2),
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		result := c.a.Equals(c.b)
+		if result != c.expected {
+			t.Errorf("Expected bool: %v, got: %v", c.expected, result)
+		}
+	}
+}
+
+func TestRolePermCopy(t *testing.T) {
+	a := RolePerm{
+		ID:     1,
+		RoleID: 1,
+	}
+
+	result := a.Copy()
+	expected := RolePerm{
+		ID:     1,
+		RoleID: 1,
+	}
+
+	if !result.Equals(&expected) {
+		t.Errorf("Expected role perm: %v, got: %v", expected, result)
+	}
+}
+
+func TestRolePermString(t *testing.T) {
+	a := RolePerm{
+		ID:     1,
+		RoleID: 1,
+	}
+
+	expected := `{"id":1,"role_id":1}`
+	result := a.String()
+	if result != expected {
+		t.Errorf("Expected string: %v, got: %v", expected, result)
+	}
+}
+
+func TestRolePermFromRequest(t *testing.T) {
+	req := ptypes.RolePermRequest{
+		ID:     1,
+		RoleID: 1,
+	}
+
+	dv := RolePerm{}
+	if err := dv.FromRequest(&req); err != nil {
+		t.Error(err)
+	}
+
+	if dv.RoleID != 1 {
+		t.Errorf("RoleID expected: 1, got: %v", dv.RoleID)
+	}
+}
+
+func TestRolePermToRequest(t *testing.T) {
+	dv := RolePerm{
+		ID:     1,
+		RoleID: 1,
+	}
+
+	msg := dv.ToRequest()
+	if msg.RoleID != 1 {
+		t.Errorf("RoleID expected: 1, got: %v", msg.RoleID)
+	}
+}
+
+func TestRolePermFromResponse(t *testing.T) {
+	res := ptypes.RolePermResponse{
+		ID:     1,
+		RoleID: 1,
+	}
+
+	dv := RolePerm{}
+	if err := dv.FromResponse(&res); err != nil {
+		t.Error(err)
+	}
+
+	if dv.RoleID != 1 {
+		t.Errorf("RoleID expected: 1, got: %v", dv.RoleID)
+	}
+}
+
+func TestRolePermToResponse(t *testing.T) {
+	dv := RolePerm{
+		ID:     1,
+		RoleID: 1,
+	}
+
+	msg := dv.ToResponse()
+	if msg.RoleID != 1 {
+		t.Errorf("RoleID expected: 1, got: %v", msg.RoleID)
+	}
+}
+
+func TestRolePermRowFromRolePerm(t *testing.T) {
+	rp := RolePerm{
+		ID:     1,
+		RoleID: 1,
+	}
+
+	rpr := RolePermRow{}
+	if err := rpr.FromRolePerm(&rp); err != nil {
+		t.Error(err)
+	}
+
+	if rpr.RoleID != 1 {
+		t.Errorf("RoleID expected: 1, got: %v", rpr.RoleID)
+	}
+}
+
+func TestRolePermRowToRolePerm(t *testing.T) {
+	ur := RolePermRow{
+		ID:     1,
+		RoleID: 1,
+	}
+
+	u := ur.ToRolePerm()
+	if u.RoleID != 1 {
+		t.Errorf("RoleID expected: 1, got: %v", u.RoleID)
+	}
+}
+
+func TestRolePermFromQueryValues(t *testing.T) {
+	vals := url.Values{}
+	vals.Add("id", "1")
+	vals.Add("role_id", "1")
+	dv := RolePerm{}
+	dv.FromQueryValues(vals)
+	if dv.RoleID != int64(1) {
+		t.Errorf("RoleID expected: 1, got: %v", dv.RoleID)
+	}
+
+	if dv.ID != int64(1) {
+		t.Errorf("ID expected: 1, got: %v", dv.ID)
+	}
+}
+
+func TestRolePermFindFromRolePerm(t *testing.T) {
+	u := RolePerm{
+		ID:     1,
+		RoleID: 1,
+	}
+
+	uf := RolePermFind{}
+	err := uf.FromRolePerm(&u)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if *uf.ID != 1 {
+		t.Errorf("ID expected: 1, got: %v", *uf.ID)
+	}
+
+	if *uf.RoleID != 1 {
+		t.Errorf("Value expected: 1, got: %v", *uf.RoleID)
+	}
+}
+
+func TestRolePermFindFromRolePermRequest(t *testing.T) {
+	r := ptypes.RolePermRequest{
+		ID:     1,
+		RoleID: 1,
+	}
+
+	uf := RolePermFind{}
+	err := uf.FromRolePermRequest(&r)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if *uf.ID != 1 {
+		t.Errorf("ID expected: 1, got: %v", *uf.ID)
+	}
+
+	if *uf.RoleID != 1 {
+		t.Errorf("Value expected: 1, got: %v", *uf.RoleID)
+	}
+}
@@ -0,0 +1,216 @@
+package dauth
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/dhaifley/dlib/ptypes"
+)
+
+func TestRoleEquals(t *testing.T) {
+	cases := []struct {
+		a        *Role
+		b        *Role
+		expected bool
+	}{
+		{
+			a:        NewRole(1, "test", 0),
+			b:        NewRole(1, "test", 0),
+			expected: true,
+		},
+		{
+			a:        NewRole(1, "test", 0),
+			b:        NewRole(1, "test", 2),
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		result := c.a.Equals(c.b)
+		if result != c.expected {
+			t.Errorf("Expected bool: %v, got: %v", c.expected, result)
+		}
+	}
+}
+
+func TestRoleCopy(t *testing.T) {
+	a := Role{
+		ID:   1,
+		Name: "test",
+	}
+
+	result := a.Copy()
+	expected := Role{
+		ID:   1,
+		Name: "test",
+	}
+
+	if !result.Equals(&expected) {
+		t.Errorf("Expected role: %v, got: %v", expected, result)
+	}
+}
+
+func TestRoleString(t *testing.T) {
+	a := Role{
+		ID:   1,
+		Name: "test",
+	}
+
+	expected := `{"id":1,"name":"test"}`
+	result := a.String()
+	if result != expected {
+		t.Errorf("Expected string: %v, got: %v", expected, result)
+	}
+}
+
+func TestRoleFromRequest(t *testing.T) {
+	req := ptypes.RoleRequest{
+		ID:   1,
+		Name: "test",
+	}
+
+	dv := Role{}
+	if err := dv.FromRequest(&req); err != nil {
+		t.Error(err)
+	}
+
+	if dv.ID != 1 {
+		t.Errorf("ID expected: 1, got: %v", dv.ID)
+	}
+}
+
+func TestRoleToRequest(t *testing.T) {
+	dv := Role{
+		ID:   1,
+		Name: "test",
+	}
+
+	msg := dv.ToRequest()
+	if msg.ID != 1 {
+		t.Errorf("ID expected: 1, got: %v", msg.ID)
+	}
+}
+
+func TestRoleFromResponse(t *testing.T) {
+	res := ptypes.RoleResponse{
+		ID:   1,
+		Name: "test",
+	}
+
+	dv := Role{}
+	if err := dv.FromResponse(&res); err != nil {
+		t.Error(err)
+	}
+
+	if dv.ID != 1 {
+		t.Errorf("ID expected: 1, got: %v", dv.ID)
+	}
+}
+
+func TestRoleToResponse(t *testing.T) {
+	dv := Role{
+		ID:   1,
+		Name: "test",
+	}
+
+	msg := dv.ToResponse()
+	if msg.ID != 1 {
+		t.Errorf("ID expected: 1, got: %v", msg.ID)
+	}
+}
+
+func TestRoleRowFromRole(t *testing.T) {
+	ro := Role{
+		ID:   1,
+		Name: "test",
+	}
+
+	rr := RoleRow{}
+	if err := rr.FromRole(&ro); err != nil {
+		t.Error(err)
+	}
+
+	exp := "test"
+	if rr.Name != exp {
+		t.Errorf("Value expected: %v, got: %v", exp, rr.Name)
+	}
+}
+
+func TestRoleRowToRole(t *testing.T) {
+	ur := RoleRow{
+		ID:   1,
+		Name: "test",
+	}
+
+	u := ur.ToRole()
+	if u.ID != 1 {
+		t.Errorf("ID expected: 1, got: %v", u.Name)
+	}
+}
+
+func TestRoleFromQueryValues(t *testing.T) {
+	vals := url.Values{}
+	vals.Add("id", "1")
+	vals.Add("name", "test")
+	dv := Role{}
+	dv.FromQueryValues(vals)
+	if dv.ID != 1 {
+		t.Errorf("ID expected: 1, got: %v", dv.ID)
+	}
+}
+
+func TestRoleFindFromRole(t *testing.T) {
+	u := Role{
+		ID:   1,
+		Name: "test",
+	}
+
+	uf := RoleFind{}
+	err := uf.FromRole(&u)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if *uf.ID != 1 {
+		t.Errorf("ID expected: 1, got: %v", *uf.ID)
+	}
+}
+
+func TestRoleFindFromRoleRequest(t *testing.T) {
+	ur := ptypes.RoleRequest{
+		ID:   1,
+		Name: "test",
+	}
+
+	uf := RoleFind{}
+	err := uf.FromRoleRequest(&ur)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if *uf.ID != 1 {
+		t.Errorf("ID expected: 1, got: %v", *uf.ID)
+	}
+}
+
+func TestRoleRowParentID(t *testing.T) {
+	cases := []struct {
+		parentID int64
+		valid    bool
+	}{
+		{0, false},
+		{2, true},
+	}
+
+	for _, c := range cases {
+		rr := RoleRow{}
+		rr.FromRole(NewRole(1, "test", c.parentID))
+		if rr.ParentID.Valid != c.valid {
+			t.Errorf("Valid expected: %v, got: %v", c.valid, rr.ParentID.Valid)
+		}
+
+		if ro := rr.ToRole(); ro.ParentID != c.parentID {
+			t.Errorf("ParentID expected: %v, got: %v", c.parentID, ro.ParentID)
+		}
+	}
+}
@@ -0,0 +1,2027 @@
+package dauth
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/dhaifley/dlib"
+	"github.com/dhaifley/dlib/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+// DefaultImpersonationTTL is the lifetime of impersonation tokens issued by
+// a server with no impersonation TTL configured.
+const DefaultImpersonationTTL = 15 * time.Minute
+
+// ImpersonatePerm is the permission required to impersonate another user.
+// It is the permission to which PermFromMethod maps the Impersonate RPC of
+// the dlib.Auth service, "/dlib.Auth/Impersonate", so the interceptor and
+// the server check the same permission.
+var ImpersonatePerm = NewPerm(0, "dlib.Auth", "Impersonate")
+
+// Server values implement the ptypes.AuthServer interface using a Store
+// for persistence. Tokens are issued and revoked by Issuer, and passwords
+// are stored as hashes produced by its password hasher. Failed logins are
+// throttled by Throttle, unless it is nil. Users may enroll in MFA only if
+// MFA is set. Logins, logouts, failed authentications, impersonations, and
+// changes to users, permissions, tokens, API keys, lockouts and MFA are
+// queued to be recorded by Audit, unless it is nil, so requests do not wait
+// on the audit log. Impersonation tokens expire after ImpersonationTTL.
+//
+// Logins are verified by Authenticator, or by the users of the store if it
+// is nil. Users verified by an external identity provider are provisioned
+// into the store on their first login.
+//
+// Errors are returned as dlib errors, which carry the equivalent gRPC
+// status, so that clients receive codes such as NotFound and
+// PermissionDenied rather than Unknown.
+type Server struct {
+	Store            Store
+	Issuer           *TokenIssuer
+	Throttle         *LoginThrottle
+	MFA              *MFA
+	Audit            *Auditor
+	ImpersonationTTL time.Duration
+	Authenticator    Authenticator
+}
+
+// NewServer initializes and returns a pointer to a new auth server value.
+func NewServer(st Store) *Server {
+	return &Server{
+		Store:            st,
+		Issuer:           NewTokenIssuer(st, DefaultTokenTTL),
+		Throttle:         NewLoginThrottle(st),
+		ImpersonationTTL: DefaultImpersonationTTL,
+	}
+}
+
+// issuer returns the token issuer used by the server.
+func (s *Server) issuer() *TokenIssuer {
+	if s.Issuer == nil {
+		s.Issuer = NewTokenIssuer(s.Store, DefaultTokenTTL)
+	}
+
+	return s.Issuer
+}
+
+// authenticator returns the authenticator used to verify logins.
+func (s *Server) authenticator() Authenticator {
+	if s.Authenticator == nil {
+		return AuthenticatorFunc(s.issuer().verifyUser)
+	}
+
+	return s.Authenticator
+}
+
+// tokenFind returns a token find value for a token request. Tokens are
+// stored hashed, so a token string in the request is matched by its hash.
+func tokenFind(req *ptypes.TokenRequest) (*TokenFind, error) {
+	f := TokenFind{}
+	if err := f.FromTokenRequest(req); err != nil {
+		return nil, err
+	}
+
+	if f.Token != nil {
+		hash := HashToken(*f.Token)
+		f.Token = &hash
+	}
+
+	return &f, nil
+}
+
+// GetTokens returns a stream of tokens from the database. The token field
+// of each response contains the stored hash of the token.
+// Results are paged if the request has a page size, and each response holds
+// the token of the page which follows it.
+func (s *Server) GetTokens(req *ptypes.TokenRequest, stream ptypes.Auth_GetTokensServer) error {
+	f, err := tokenFind(req)
+	if err != nil {
+		return err
+	}
+
+	if err := f.Page.FromRequest(req.PageSize, req.PageToken, req.Order); err != nil {
+		return err
+	}
+
+	if err := scopeFind(stream.Context(), &f.TenantID); err != nil {
+		return err
+	}
+
+	for r := range withContext(s.Store, stream.Context()).GetTokens(f) {
+		if r.Err != nil {
+			return r.Err
+		}
+
+		t := r.Val.(*Token)
+		res := t.ToResponse()
+		res.NextPageToken = PageToken(t.ID)
+		if err := stream.Send(&res); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SaveTokens serializes a stream of tokens to the database. Existing
+// tokens are updated subject to the update mask of each request. Token
+// strings are hashed before they are stored. Each token belongs to the
+// tenant of its user.
+func (s *Server) SaveTokens(stream ptypes.Auth_SaveTokensServer) error {
+	ctx := stream.Context()
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		t := Token{}
+		if req.ID != 0 {
+			f := TokenFind{ID: &req.ID}
+			if err := scopeFind(ctx, &f.TenantID); err != nil {
+				return err
+			}
+
+			for r := range s.Store.GetTokens(&f) {
+				if r.Err != nil {
+					return r.Err
+				}
+
+				t = *r.Val.(*Token)
+			}
+
+			if _, ok := tenantFromContext(ctx); ok && t.ID == 0 {
+				return dlib.NewError(404, "token not found")
+			}
+		}
+
+		if req.Token != "" {
+			req.Token = HashToken(req.Token)
+		}
+
+		if err := t.Update(req); err != nil {
+			return err
+		}
+
+		u, err := getUser(s.Store, t.UserID)
+		if err != nil {
+			return err
+		}
+
+		if u != nil {
+			t.TenantID = u.TenantID
+		}
+
+		if err := checkTenant(ctx, t.TenantID); err != nil {
+			return err
+		}
+
+		if err := s.auditSave(ctx, AuditTokenSave, "token", &t.ID, s.Store.SaveToken(&t)); err != nil {
+			return err
+		}
+
+		res := t.ToResponse()
+		if err := stream.Send(&res); err != nil {
+			return err
+		}
+	}
+}
+
+// DeleteTokens deletes tokens from the database.
+func (s *Server) DeleteTokens(ctx context.Context, req *ptypes.TokenRequest) (*ptypes.DeleteResponse, error) {
+	f, err := tokenFind(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if *f == (TokenFind{}) {
+		return nil, errNoCriteria
+	}
+
+	if err := scopeFind(ctx, &f.TenantID); err != nil {
+		return nil, err
+	}
+
+	return s.auditDelete(ctx, AuditTokenDelete, "token", f, s.Store.DeleteTokens(f))
+}
+
+// GetUsers returns a stream of users from the database. Passwords are
+// stored hashed, so the pass field of the request is not used as criteria.
+// Results are paged if the request has a page size, and each response holds
+// the token of the page which follows it.
+func (s *Server) GetUsers(req *ptypes.UserRequest, stream ptypes.Auth_GetUsersServer) error {
+	f := UserFind{}
+	if err := f.FromUserRequest(req); err != nil {
+		return err
+	}
+
+	if err := f.Page.FromRequest(req.PageSize, req.PageToken, req.Order); err != nil {
+		return err
+	}
+
+	f.Pass = nil
+	if err := scopeFind(stream.Context(), &f.TenantID); err != nil {
+		return err
+	}
+
+	for r := range withContext(s.Store, stream.Context()).GetUsers(&f) {
+		if r.Err != nil {
+			return r.Err
+		}
+
+		u := r.Val.(*User)
+		res := u.ToResponse()
+		res.NextPageToken = PageToken(u.ID)
+		if err := stream.Send(&res); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SaveUsers serializes a stream of users to the database. Fields left empty
+// in a request for an existing user retain their stored values, unless the
+// request has an update mask, in which case exactly the fields in the mask
+// are updated. Passwords are always hashed before they are stored, and can
+// not be cleared. Users saved by a user restricted to a tenant are placed in
+// that tenant.
+func (s *Server) SaveUsers(stream ptypes.Auth_SaveUsersServer) error {
+	ctx := stream.Context()
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		u := User{}
+		if req.ID != 0 {
+			f := UserFind{ID: &req.ID}
+			if err := scopeFind(ctx, &f.TenantID); err != nil {
+				return err
+			}
+
+			for r := range s.Store.GetUsers(&f) {
+				if r.Err != nil {
+					return r.Err
+				}
+
+				u = *r.Val.(*User)
+			}
+
+			if _, ok := tenantFromContext(ctx); ok && u.ID == 0 {
+				return dlib.NewError(404, "user not found")
+			}
+		}
+
+		if req.Pass != "" {
+			if req.Pass, err = s.issuer().hasher().Hash(req.Pass); err != nil {
+				return err
+			}
+		}
+
+		if err := u.Update(req); err != nil {
+			return err
+		}
+
+		if err := scopeTenant(ctx, &u.TenantID); err != nil {
+			return err
+		}
+
+		if err := s.auditSave(ctx, AuditUserSave, "user", &u.ID, s.Store.SaveUser(&u)); err != nil {
+			return err
+		}
+
+		res := u.ToResponse()
+		if err := stream.Send(&res); err != nil {
+			return err
+		}
+	}
+}
+
+// DeleteUsers deletes users from the database. The pass field of the
+// request is not used as criteria.
+func (s *Server) DeleteUsers(ctx context.Context, req *ptypes.UserRequest) (*ptypes.DeleteResponse, error) {
+	f := UserFind{}
+	if err := f.FromUserRequest(req); err != nil {
+		return nil, err
+	}
+
+	f.Pass = nil
+	if f == (UserFind{}) {
+		return nil, errNoCriteria
+	}
+
+	if err := scopeFind(ctx, &f.TenantID); err != nil {
+		return nil, err
+	}
+
+	return s.auditDelete(ctx, AuditUserDelete, "user", &f, s.Store.DeleteUsers(&f))
+}
+
+// GetPerms returns a stream of permissions from the database.
+// Results are paged if the request has a page size, and each response holds
+// the token of the page which follows it.
+func (s *Server) GetPerms(req *ptypes.PermRequest, stream ptypes.Auth_GetPermsServer) error {
+	f := PermFind{}
+	if err := f.FromPermRequest(req); err != nil {
+		return err
+	}
+
+	if err := f.Page.FromRequest(req.PageSize, req.PageToken, req.Order); err != nil {
+		return err
+	}
+
+	if err := scopeFind(stream.Context(), &f.TenantID); err != nil {
+		return err
+	}
+
+	for r := range withContext(s.Store, stream.Context()).GetPerms(&f) {
+		if r.Err != nil {
+			return r.Err
+		}
+
+		p := r.Val.(*Perm)
+		res := p.ToResponse()
+		res.NextPageToken = PageToken(p.ID)
+		if err := stream.Send(&res); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SavePerms serializes a stream of permissions to the database. Existing
+// permissions are updated subject to the update mask of each request.
+func (s *Server) SavePerms(stream ptypes.Auth_SavePermsServer) error {
+	ctx := stream.Context()
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		p := Perm{}
+		if req.ID != 0 {
+			f := PermFind{ID: &req.ID}
+			if err := scopeFind(ctx, &f.TenantID); err != nil {
+				return err
+			}
+
+			for r := range s.Store.GetPerms(&f) {
+				if r.Err != nil {
+					return r.Err
+				}
+
+				p = *r.Val.(*Perm)
+			}
+
+			if _, ok := tenantFromContext(ctx); ok && p.ID == 0 {
+				return dlib.NewError(404, "perm not found")
+			}
+		}
+
+		if err := p.Update(req); err != nil {
+			return err
+		}
+
+		if err := scopeTenant(ctx, &p.TenantID); err != nil {
+			return err
+		}
+
+		if err := s.auditSave(ctx, AuditPermSave, "perm", &p.ID, s.Store.SavePerm(&p)); err != nil {
+			return err
+		}
+
+		res := p.ToResponse()
+		if err := stream.Send(&res); err != nil {
+			return err
+		}
+	}
+}
+
+// DeletePerms deletes permissions from the database.
+func (s *Server) DeletePerms(ctx context.Context, req *ptypes.PermRequest) (*ptypes.DeleteResponse, error) {
+	f := PermFind{}
+	if err := f.FromPermRequest(req); err != nil {
+		return nil, err
+	}
+
+	if f.Empty() {
+		return nil, errNoCriteria
+	}
+
+	if err := scopeFind(ctx, &f.TenantID); err != nil {
+		return nil, err
+	}
+
+	return s.auditDelete(ctx, AuditPermDelete, "perm", &f, s.Store.DeletePerms(&f))
+}
+
+// GetUserPerms returns a stream of user permissions from the database.
+// Results are paged if the request has a page size, and each response holds
+// the token of the page which follows it.
+func (s *Server) GetUserPerms(req *ptypes.UserPermRequest, stream ptypes.Auth_GetUserPermsServer) error {
+	f := UserPermFind{}
+	if err := f.FromUserPermRequest(req); err != nil {
+		return err
+	}
+
+	if err := f.Page.FromRequest(req.PageSize, req.PageToken, req.Order); err != nil {
+		return err
+	}
+
+	if err := scopeFind(stream.Context(), &f.TenantID); err != nil {
+		return err
+	}
+
+	for r := range withContext(s.Store, stream.Context()).GetUserPerms(&f) {
+		if r.Err != nil {
+			return r.Err
+		}
+
+		up := r.Val.(*UserPerm)
+		res := up.ToResponse()
+		res.NextPageToken = PageToken(up.ID)
+		if err := stream.Send(&res); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SaveUserPerms serializes a stream of user permissions to the database.
+// Existing assignments are updated subject to the update mask of each
+// request. The user and permission of each assignment must belong to the same
+// tenant, which the assignment then belongs to.
+func (s *Server) SaveUserPerms(stream ptypes.Auth_SaveUserPermsServer) error {
+	ctx := stream.Context()
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		up := UserPerm{}
+		if req.ID != 0 {
+			f := UserPermFind{ID: &req.ID}
+			if err := scopeFind(ctx, &f.TenantID); err != nil {
+				return err
+			}
+
+			for r := range s.Store.GetUserPerms(&f) {
+				if r.Err != nil {
+					return r.Err
+				}
+
+				up = *r.Val.(*UserPerm)
+			}
+
+			if _, ok := tenantFromContext(ctx); ok && up.ID == 0 {
+				return dlib.NewError(404, "user perm not found")
+			}
+		}
+
+		if err := up.Update(req); err != nil {
+			return err
+		}
+
+		if err := up.Validate(); err != nil {
+			return err
+		}
+
+		u, err := getUser(s.Store, up.UserID)
+		if err != nil {
+			return err
+		}
+
+		p, err := getPerm(s.Store, up.PermID)
+		if err != nil {
+			return err
+		}
+
+		if u == nil || p == nil {
+			return dlib.NewError(404, "user or perm not found")
+		}
+
+		if u.TenantID != p.TenantID {
+			return ErrCrossTenant
+		}
+
+		up.TenantID = u.TenantID
+		if err := checkTenant(ctx, up.TenantID); err != nil {
+			return err
+		}
+
+		if err := s.auditSave(ctx, AuditPermSave, "user_perm", &up.ID, s.Store.SaveUserPerm(&up)); err != nil {
+			return err
+		}
+
+		res := up.ToResponse()
+		if err := stream.Send(&res); err != nil {
+			return err
+		}
+	}
+}
+
+// DeleteUserPerms deletes user permissions from the database.
+func (s *Server) DeleteUserPerms(ctx context.Context, req *ptypes.UserPermRequest) (*ptypes.DeleteResponse, error) {
+	f := UserPermFind{}
+	if err := f.FromUserPermRequest(req); err != nil {
+		return nil, err
+	}
+
+	if f == (UserPermFind{}) {
+		return nil, errNoCriteria
+	}
+
+	if err := scopeFind(ctx, &f.TenantID); err != nil {
+		return nil, err
+	}
+
+	return s.auditDelete(ctx, AuditPermDelete, "user_perm", &f, s.Store.DeleteUserPerms(&f))
+}
+
+// GetRoles returns a stream of roles from the database. Users restricted
+// to a tenant may only find the roles of their tenant.
+func (s *Server) GetRoles(req *ptypes.RoleRequest, stream ptypes.Auth_GetRolesServer) error {
+	f := RoleFind{}
+	if err := f.FromRoleRequest(req); err != nil {
+		return err
+	}
+
+	if err := scopeFind(stream.Context(), &f.TenantID); err != nil {
+		return err
+	}
+
+	for r := range withContext(s.Store, stream.Context()).GetRoles(&f) {
+		if r.Err != nil {
+			return r.Err
+		}
+
+		ro := r.Val.(*Role)
+		res := ro.ToResponse()
+		if err := stream.Send(&res); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SaveRoles serializes a stream of roles to the database. Existing roles
+// are updated subject to the update mask of each request. Roles which would
+// inherit from themselves through their parents are rejected, as are roles
+// whose parent belongs to another tenant. Roles saved by a user restricted
+// to a tenant are placed in that tenant.
+func (s *Server) SaveRoles(stream ptypes.Auth_SaveRolesServer) error {
+	ctx := stream.Context()
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		ro := Role{}
+		if req.ID != 0 {
+			f := RoleFind{ID: &req.ID}
+			if err := scopeFind(ctx, &f.TenantID); err != nil {
+				return err
+			}
+
+			for r := range s.Store.GetRoles(&f) {
+				if r.Err != nil {
+					return r.Err
+				}
+
+				ro = *r.Val.(*Role)
+			}
+
+			if _, ok := tenantFromContext(ctx); ok && ro.ID == 0 {
+				return dlib.NewError(404, "role not found")
+			}
+		}
+
+		if err := ro.Update(req); err != nil {
+			return err
+		}
+
+		if err := scopeTenant(ctx, &ro.TenantID); err != nil {
+			return err
+		}
+
+		if ro.ParentID != 0 {
+			p, err := getRole(s.Store, ro.ParentID)
+			if err != nil {
+				return err
+			}
+
+			if p == nil {
+				return dlib.NewError(404, "parent role not found")
+			}
+
+			if p.TenantID != ro.TenantID {
+				return ErrCrossTenant
+			}
+		}
+
+		if err := CheckRoleCycle(s.Store, &ro); err != nil {
+			return err
+		}
+
+		if err := s.auditSave(ctx, AuditPermSave, "role", &ro.ID, s.Store.SaveRole(&ro)); err != nil {
+			return err
+		}
+
+		res := ro.ToResponse()
+		if err := stream.Send(&res); err != nil {
+			return err
+		}
+	}
+}
+
+// DeleteRoles deletes roles from the database.
+func (s *Server) DeleteRoles(ctx context.Context, req *ptypes.RoleRequest) (*ptypes.DeleteResponse, error) {
+	f := RoleFind{}
+	if err := f.FromRoleRequest(req); err != nil {
+		return nil, err
+	}
+
+	if f == (RoleFind{}) {
+		return nil, errNoCriteria
+	}
+
+	if err := scopeFind(ctx, &f.TenantID); err != nil {
+		return nil, err
+	}
+
+	return s.auditDelete(ctx, AuditPermDelete, "role", &f, s.Store.DeleteRoles(&f))
+}
+
+// GetRolePerms returns a stream of role permissions from the database.
+func (s *Server) GetRolePerms(req *ptypes.RolePermRequest, stream ptypes.Auth_GetRolePermsServer) error {
+	f := RolePermFind{}
+	if err := f.FromRolePermRequest(req); err != nil {
+		return err
+	}
+
+	if err := scopeFind(stream.Context(), &f.TenantID); err != nil {
+		return err
+	}
+
+	for r := range withContext(s.Store, stream.Context()).GetRolePerms(&f) {
+		if r.Err != nil {
+			return r.Err
+		}
+
+		rp := r.Val.(*RolePerm)
+		res := rp.ToResponse()
+		if err := stream.Send(&res); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SaveRolePerms serializes a stream of role permissions to the database.
+// Existing assignments are updated subject to the update mask of each
+// request. The role and permission of each assignment must belong to the
+// same tenant, which the assignment then belongs to.
+func (s *Server) SaveRolePerms(stream ptypes.Auth_SaveRolePermsServer) error {
+	ctx := stream.Context()
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		rp := RolePerm{}
+		if req.ID != 0 {
+			f := RolePermFind{ID: &req.ID}
+			if err := scopeFind(ctx, &f.TenantID); err != nil {
+				return err
+			}
+
+			for r := range s.Store.GetRolePerms(&f) {
+				if r.Err != nil {
+					return r.Err
+				}
+
+				rp = *r.Val.(*RolePerm)
+			}
+
+			if _, ok := tenantFromContext(ctx); ok && rp.ID == 0 {
+				return dlib.NewError(404, "role perm not found")
+			}
+		}
+
+		if err := rp.Update(req); err != nil {
+			return err
+		}
+
+		ro, err := getRole(s.Store, rp.RoleID)
+		if err != nil {
+			return err
+		}
+
+		p, err := getPerm(s.Store, rp.PermID)
+		if err != nil {
+			return err
+		}
+
+		if ro == nil || p == nil {
+			return dlib.NewError(404, "role or perm not found")
+		}
+
+		if ro.TenantID != p.TenantID {
+			return ErrCrossTenant
+		}
+
+		rp.TenantID = ro.TenantID
+		if err := checkTenant(ctx, rp.TenantID); err != nil {
+			return err
+		}
+
+		if err := s.auditSave(ctx, AuditPermSave, "role_perm", &rp.ID, s.Store.SaveRolePerm(&rp)); err != nil {
+			return err
+		}
+
+		res := rp.ToResponse()
+		if err := stream.Send(&res); err != nil {
+			return err
+		}
+	}
+}
+
+// DeleteRolePerms deletes role permissions from the database.
+func (s *Server) DeleteRolePerms(ctx context.Context, req *ptypes.RolePermRequest) (*ptypes.DeleteResponse, error) {
+	f := RolePermFind{}
+	if err := f.FromRolePermRequest(req); err != nil {
+		return nil, err
+	}
+
+	if f.Empty() {
+		return nil, errNoCriteria
+	}
+
+	if err := scopeFind(ctx, &f.TenantID); err != nil {
+		return nil, err
+	}
+
+	return s.auditDelete(ctx, AuditPermDelete, "role_perm", &f, s.Store.DeleteRolePerms(&f))
+}
+
+// GetUserRoles returns a stream of user roles from the database.
+func (s *Server) GetUserRoles(req *ptypes.UserRoleRequest, stream ptypes.Auth_GetUserRolesServer) error {
+	f := UserRoleFind{}
+	if err := f.FromUserRoleRequest(req); err != nil {
+		return err
+	}
+
+	if err := scopeFind(stream.Context(), &f.TenantID); err != nil {
+		return err
+	}
+
+	for r := range withContext(s.Store, stream.Context()).GetUserRoles(&f) {
+		if r.Err != nil {
+			return r.Err
+		}
+
+		ur := r.Val.(*UserRole)
+		res := ur.ToResponse()
+		if err := stream.Send(&res); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SaveUserRoles serializes a stream of user roles to the database.
+// Existing assignments are updated subject to the update mask of each
+// request. The user and role of each assignment must belong to the same
+// tenant, which the assignment then belongs to.
+func (s *Server) SaveUserRoles(stream ptypes.Auth_SaveUserRolesServer) error {
+	ctx := stream.Context()
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		ur := UserRole{}
+		if req.ID != 0 {
+			f := UserRoleFind{ID: &req.ID}
+			if err := scopeFind(ctx, &f.TenantID); err != nil {
+				return err
+			}
+
+			for r := range s.Store.GetUserRoles(&f) {
+				if r.Err != nil {
+					return r.Err
+				}
+
+				ur = *r.Val.(*UserRole)
+			}
+
+			if _, ok := tenantFromContext(ctx); ok && ur.ID == 0 {
+				return dlib.NewError(404, "user role not found")
+			}
+		}
+
+		if err := ur.Update(req); err != nil {
+			return err
+		}
+
+		u, err := getUser(s.Store, ur.UserID)
+		if err != nil {
+			return err
+		}
+
+		ro, err := getRole(s.Store, ur.RoleID)
+		if err != nil {
+			return err
+		}
+
+		if u == nil || ro == nil {
+			return dlib.NewError(404, "user or role not found")
+		}
+
+		if u.TenantID != ro.TenantID {
+			return ErrCrossTenant
+		}
+
+		ur.TenantID = u.TenantID
+		if err := checkTenant(ctx, ur.TenantID); err != nil {
+			return err
+		}
+
+		if err := s.auditSave(ctx, AuditPermSave, "user_role", &ur.ID, s.Store.SaveUserRole(&ur)); err != nil {
+			return err
+		}
+
+		res := ur.ToResponse()
+		if err := stream.Send(&res); err != nil {
+			return err
+		}
+	}
+}
+
+// DeleteUserRoles deletes user roles from the database.
+func (s *Server) DeleteUserRoles(ctx context.Context, req *ptypes.UserRoleRequest) (*ptypes.DeleteResponse, error) {
+	f := UserRoleFind{}
+	if err := f.FromUserRoleRequest(req); err != nil {
+		return nil, err
+	}
+
+	if f == (UserRoleFind{}) {
+		return nil, errNoCriteria
+	}
+
+	if err := scopeFind(ctx, &f.TenantID); err != nil {
+		return nil, err
+	}
+
+	return s.auditDelete(ctx, AuditPermDelete, "user_role", &f, s.Store.DeleteUserRoles(&f))
+}
+
+// GetLockouts returns a stream of login lockouts from the database. Users
+// restricted to a tenant may only find the user name lockouts of their
+// tenant.
+func (s *Server) GetLockouts(req *ptypes.LockoutRequest, stream ptypes.Auth_GetLockoutsServer) error {
+	f := LockoutFind{}
+	if err := f.FromLockoutRequest(req); err != nil {
+		return err
+	}
+
+	if err := scopeFind(stream.Context(), &f.TenantID); err != nil {
+		return err
+	}
+
+	for r := range withContext(s.Store, stream.Context()).GetLockouts(&f) {
+		if r.Err != nil {
+			return r.Err
+		}
+
+		l := r.Val.(*Lockout)
+		res := l.ToResponse()
+		if err := stream.Send(&res); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Unlock deletes login lockouts from the database, allowing logins for the
+// user names and from the addresses they applied to. Users restricted to a
+// tenant may only delete the user name lockouts of their tenant.
+func (s *Server) Unlock(ctx context.Context, req *ptypes.LockoutRequest) (*ptypes.DeleteResponse, error) {
+	f := LockoutFind{}
+	if err := f.FromLockoutRequest(req); err != nil {
+		return nil, err
+	}
+
+	if f == (LockoutFind{}) {
+		return nil, errNoCriteria
+	}
+
+	if err := scopeFind(ctx, &f.TenantID); err != nil {
+		return nil, err
+	}
+
+	return s.auditDelete(ctx, AuditUnlock, "lockout", &f, s.Store.DeleteLockouts(&f))
+}
+
+// errMFAUnconfigured is returned by MFA requests to a server without MFA.
+var errMFAUnconfigured = &dlib.Error{Code: 400, Msg: "mfa not configured"}
+
+// checkLogin tests whether a login may be attempted for a user name in a
+// tenant from a client address.
+func (s *Server) checkLogin(tenantID int64, user, addr string) error {
+	if s.Throttle == nil {
+		return nil
+	}
+
+	return s.Throttle.Check(tenantID, user, addr)
+}
+
+// failLogin records a failed login for a user name in a tenant if the error
+// is an authentication failure. It returns the error.
+func (s *Server) failLogin(tenantID int64, user, addr string, err error) error {
+	if e, ok := err.(*dlib.Error); ok && e.Code == 401 && s.Throttle != nil {
+		if ferr := s.Throttle.Fail(tenantID, user, addr); ferr != nil {
+			return ferr
+		}
+	}
+
+	return err
+}
+
+// issueLogin clears failed logins for a user and issues a token for them.
+func (s *Server) issueLogin(u *User) (*ptypes.TokenResponse, error) {
+	if s.Throttle != nil {
+		if err := s.Throttle.Succeed(u.TenantID, u.User); err != nil {
+			return nil, err
+		}
+	}
+
+	t, err := s.issuer().Issue(u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := t.ToResponse()
+	return &res, nil
+}
+
+// getUser returns the user with an ID, or nil if there is none.
+func getUser(st Store, id int64) (*User, error) {
+	var u *User
+	for r := range st.GetUsers(&UserFind{ID: &id}) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		u = r.Val.(*User)
+	}
+
+	return u, nil
+}
+
+// getPerm returns the permission with an ID, or nil if there is none.
+func getPerm(st Store, id int64) (*Perm, error) {
+	var p *Perm
+	for r := range st.GetPerms(&PermFind{ID: &id}) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		p = r.Val.(*Perm)
+	}
+
+	return p, nil
+}
+
+// Login authenticates a provided user and creates a new token. For users
+// with MFA enabled, a request without a code returns a response with
+// MFARequired set and a challenge instead of a token. The login is then
+// completed by a request holding the challenge and a TOTP or recovery code.
+// A request may also hold the code along with the user and pass. User names
+// are only unique within a tenant, so the user is found in the tenant of
+// the request.
+func (s *Server) Login(ctx context.Context, req *ptypes.UserRequest) (*ptypes.TokenResponse, error) {
+	res, err := s.login(ctx, req)
+	e := AuditEvent{Action: AuditLogin, Actor: req.User}
+	if res != nil {
+		e.ActorID, e.TenantID = res.UserID, res.TenantID
+		if res.MFARequired {
+			e.Outcome = AuditChallenge
+		}
+	}
+
+	s.audit(ctx, e, err)
+	return res, err
+}
+
+// login authenticates a provided user and creates a new token.
+func (s *Server) login(ctx context.Context, req *ptypes.UserRequest) (*ptypes.TokenResponse, error) {
+	if req.Challenge != "" {
+		return s.loginMFA(ctx, req)
+	}
+
+	u, err := s.verifyLogin(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	on, err := MFAEnabled(s.Store, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if on {
+		if s.MFA == nil {
+			return nil, ErrMFARequired
+		}
+
+		if req.Code == "" {
+			ch, exp := s.MFA.Challenge(u.ID)
+			return &ptypes.TokenResponse{
+				UserID:      u.ID,
+				Expires:     &timestamp.Timestamp{Seconds: exp.Unix()},
+				MFARequired: true,
+				Challenge:   ch,
+			}, nil
+		}
+
+		ok, err := s.MFA.Verify(u.ID, req.Code)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			return nil, s.failLogin(u.TenantID, u.User, ClientAddr(ctx), ErrMFAInvalid)
+		}
+	}
+
+	return s.issueLogin(u)
+}
+
+// loginMFA completes a login for a user with MFA enabled using the
+// challenge returned by the first step and a code.
+func (s *Server) loginMFA(ctx context.Context, req *ptypes.UserRequest) (*ptypes.TokenResponse, error) {
+	if s.MFA == nil {
+		return nil, errMFAUnconfigured
+	}
+
+	if req.Code == "" {
+		return nil, dlib.NewError(400, "code required")
+	}
+
+	id, err := s.MFA.VerifyChallenge(req.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := getUser(s.Store, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if u == nil {
+		return nil, ErrInvalidToken
+	}
+
+	addr := ClientAddr(ctx)
+	if err := s.checkLogin(u.TenantID, u.User, addr); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.MFA.Verify(u.ID, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, s.failLogin(u.TenantID, u.User, addr, ErrMFAInvalid)
+	}
+
+	return s.issueLogin(u)
+}
+
+// verifyLogin verifies the user and pass of a request in the tenant of the
+// request, subject to login throttling, and provisions users of external
+// identity providers.
+func (s *Server) verifyLogin(ctx context.Context, req *ptypes.UserRequest) (*User, error) {
+	if req.User == "" || req.Pass == "" {
+		return nil, dlib.NewError(400, "user and pass required")
+	}
+
+	addr := ClientAddr(ctx)
+	if err := s.checkLogin(req.TenantID, req.User, addr); err != nil {
+		return nil, err
+	}
+
+	u, err := s.authenticator().Authenticate(req.TenantID, req.User, req.Pass)
+	if err == nil && u.ID == 0 {
+		u, err = s.provision(ctx, u)
+	}
+
+	if err != nil {
+		return nil, s.failLogin(req.TenantID, req.User, addr, err)
+	}
+
+	return u, nil
+}
+
+// provision returns the user of the store matching a user verified by an
+// external identity provider, saving a new user on their first login. A
+// user of the store matches if it has the same name and tenant and was
+// provisioned by the same provider. Users created in the store, such as
+// service accounts, users of other providers and users with a password of
+// their own are never matched, so that an identity provider can not be used
+// to log in as them, and a 401 error is returned for them. The name and
+// email of a provisioned user are not changed by later logins.
+func (s *Server) provision(ctx context.Context, u *User) (*User, error) {
+	if u.Provider == "" {
+		return nil, dlib.NewError(500, "user has no provider")
+	}
+
+	var su *User
+	f := UserFind{User: &u.User, TenantID: &u.TenantID}
+	for r := range s.Store.GetUsers(&f) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		su = r.Val.(*User)
+	}
+
+	if su != nil {
+		if su.Provider != u.Provider || su.Pass != "" {
+			return nil, errInvalidLogin
+		}
+
+		return su, nil
+	}
+
+	nu := User{
+		User:     u.User,
+		Name:     u.Name,
+		Email:    u.Email,
+		TenantID: u.TenantID,
+		Provider: u.Provider,
+	}
+	e := AuditEvent{
+		Action:   AuditUserProvision,
+		Actor:    nu.User,
+		TenantID: nu.TenantID,
+	}
+
+	var err error
+	for r := range s.Store.SaveUser(&nu) {
+		if r.Err != nil {
+			err = r.Err
+		}
+	}
+
+	e.ActorID, e.Target = nu.ID, AuditTarget("user", nu.ID)
+	s.audit(ctx, e, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nu, nil
+}
+
+// EnrollMFA starts MFA enrollment for the provided user, who must supply
+// their pass. The response holds the TOTP secret, its provisioning URI and
+// recovery codes. MFA is not required until the enrollment is confirmed.
+func (s *Server) EnrollMFA(ctx context.Context, req *ptypes.UserRequest) (*ptypes.MFAResponse, error) {
+	if s.MFA == nil {
+		return nil, errMFAUnconfigured
+	}
+
+	u, err := s.verifyLogin(ctx, req)
+	var me *Enrollment
+	if err == nil {
+		me, err = s.MFA.Enroll(u)
+	}
+
+	s.audit(ctx, mfaEvent(AuditMFAEnroll, req, u), err)
+	if err != nil {
+		return nil, err
+	}
+
+	res := me.ToResponse()
+	return &res, nil
+}
+
+// ConfirmMFA confirms the MFA enrollment of the provided user, who must
+// supply their pass and a valid code.
+func (s *Server) ConfirmMFA(ctx context.Context, req *ptypes.UserRequest) (*ptypes.UserResponse, error) {
+	if s.MFA == nil {
+		return nil, errMFAUnconfigured
+	}
+
+	u, err := s.verifyLogin(ctx, req)
+	if err == nil {
+		if err = s.MFA.Confirm(u.ID, req.Code); err != nil {
+			err = s.failLogin(u.TenantID, u.User, ClientAddr(ctx), err)
+		}
+	}
+
+	s.audit(ctx, mfaEvent(AuditMFAConfirm, req, u), err)
+	if err != nil {
+		return nil, err
+	}
+
+	res := u.ToResponse()
+	return &res, nil
+}
+
+// mfaEvent returns the audit event for an MFA request made by a user with
+// their pass. The user is nil if they could not be verified.
+func mfaEvent(action string, req *ptypes.UserRequest, u *User) AuditEvent {
+	e := AuditEvent{Action: action, Actor: req.User}
+	if u != nil {
+		e.ActorID, e.TenantID, e.Target = u.ID, u.TenantID, AuditTarget("user", u.ID)
+	}
+
+	return e
+}
+
+// DisableMFA removes the MFA enrollment and recovery codes of the users
+// matching the request. It is intended for administrators, such as when a
+// user has lost their device. Users restricted to a tenant may only disable
+// MFA for the users of their tenant.
+func (s *Server) DisableMFA(ctx context.Context, req *ptypes.UserRequest) (*ptypes.DeleteResponse, error) {
+	f := UserFind{}
+	if err := f.FromUserRequest(req); err != nil {
+		return nil, err
+	}
+
+	f.Pass = nil
+	if f == (UserFind{}) {
+		return nil, errNoCriteria
+	}
+
+	if err := scopeFind(ctx, &f.TenantID); err != nil {
+		return nil, err
+	}
+
+	users := []*User{}
+	for r := range s.Store.GetUsers(&f) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		users = append(users, r.Val.(*User))
+	}
+
+	for _, u := range users {
+		err := RemoveMFA(s.Store, u.ID)
+		s.audit(ctx, AuditEvent{Action: AuditMFADisable, Target: AuditTarget("user", u.ID)}, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ptypes.DeleteResponse{Num: int64(len(users))}, nil
+}
+
+// Logout destroys the provided token. Signed tokens are not stored, so
+// they can not be destroyed, and a 400 error is returned for them.
+func (s *Server) Logout(ctx context.Context, req *ptypes.TokenRequest) (*ptypes.TokenResponse, error) {
+	if req.Token == "" {
+		return nil, dlib.NewError(400, "token required")
+	}
+
+	t, err := s.issuer().Logout(req.Token)
+	s.audit(ctx, tokenEvent(AuditLogout, t), err)
+	if err != nil {
+		return nil, err
+	}
+
+	res := t.ToResponse()
+	return &res, nil
+}
+
+// CreateAPIKey creates a new API key. The key is owned by the user with the
+// UserID of the request, or by the authenticated user if none is given.
+// The owner must be in a tenant the authenticated user may access, and
+// every claim of the scope must be implied by the authenticated user's own
+// permissions. A key for another user, or created while impersonating,
+// must have a scope, so that it can not grant permissions the
+// authenticated user does not hold. The response
+// holds the full key, which can not be retrieved later.
+func (s *Server) CreateAPIKey(ctx context.Context, req *ptypes.APIKeyRequest) (*ptypes.APIKeyResponse, error) {
+	k, err := s.createAPIKey(ctx, req)
+	e := AuditEvent{Action: AuditAPIKeyCreate, Target: "api_key", Detail: req.Name}
+	if k != nil {
+		e.Target = AuditTarget("api_key", k.ID)
+	}
+
+	s.audit(ctx, e, err)
+	if err != nil {
+		return nil, err
+	}
+
+	res := k.ToResponse()
+	return &res, nil
+}
+
+// createAPIKey validates and issues the API key of a create request.
+func (s *Server) createAPIKey(ctx context.Context, req *ptypes.APIKeyRequest) (*APIKey, error) {
+	k := APIKey{}
+	if err := k.FromRequest(req); err != nil {
+		return nil, err
+	}
+
+	k.ID = 0
+	cu, ok := UserFromContext(ctx)
+	if ok && cu != nil && k.UserID == 0 {
+		k.UserID = cu.ID
+	}
+
+	if k.Name == "" || k.UserID == 0 {
+		return nil, dlib.NewError(400, "name and user_id required")
+	}
+
+	if err := checkScope(k.Scope, nil); err != nil {
+		return nil, err
+	}
+
+	u, err := getUser(s.Store, k.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u == nil {
+		return nil, dlib.NewError(404, "user not found")
+	}
+
+	if err := checkTenant(ctx, u.TenantID); err != nil {
+		return nil, err
+	}
+
+	k.TenantID = u.TenantID
+	if ok && cu != nil {
+		if err := s.checkKeyScope(ctx, cu, &k); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.issuer().IssueAPIKey(&k)
+}
+
+// checkKeyScope returns a 403 error if the scope of a new API key is not
+// implied by the effective permissions of the user creating it, or if the
+// key has no scope and is for another user or created while impersonating.
+func (s *Server) checkKeyScope(ctx context.Context, u *User, k *APIKey) error {
+	if len(k.Scope) == 0 {
+		if k.UserID != u.ID || u.Actor != nil {
+			return dlib.NewError(403, "scope required")
+		}
+
+		return nil
+	}
+
+	perms, actorPerms, err := s.effectivePerms(u, grantContext(ClientAddr(ctx)))
+	if err != nil {
+		return err
+	}
+
+	for _, c := range k.Scope {
+		p, _ := permFromClaim(c)
+		if grant(u, nil, perms, actorPerms, &p) == nil {
+			return dlib.NewError(403, "scope not held: "+c)
+		}
+	}
+
+	return nil
+}
+
+// GetAPIKeys returns a stream of API keys from the database. The key field
+// of each response is empty. Users restricted to a tenant may only find the
+// keys of their tenant.
+func (s *Server) GetAPIKeys(req *ptypes.APIKeyRequest, stream ptypes.Auth_GetAPIKeysServer) error {
+	f := APIKeyFind{}
+	if err := f.FromAPIKeyRequest(req); err != nil {
+		return err
+	}
+
+	if err := scopeFind(stream.Context(), &f.TenantID); err != nil {
+		return err
+	}
+
+	for r := range withContext(s.Store, stream.Context()).GetAPIKeys(&f) {
+		if r.Err != nil {
+			return r.Err
+		}
+
+		k := r.Val.(*APIKey)
+		k.Key = ""
+		res := k.ToResponse()
+		if err := stream.Send(&res); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RevokeAPIKeys deletes API keys from the database. Users restricted to a
+// tenant may only delete the keys of their tenant.
+func (s *Server) RevokeAPIKeys(ctx context.Context, req *ptypes.APIKeyRequest) (*ptypes.DeleteResponse, error) {
+	f := APIKeyFind{}
+	if err := f.FromAPIKeyRequest(req); err != nil {
+		return nil, err
+	}
+
+	if f == (APIKeyFind{}) {
+		return nil, errNoCriteria
+	}
+
+	if err := scopeFind(ctx, &f.TenantID); err != nil {
+		return nil, err
+	}
+
+	return s.auditDelete(ctx, AuditAPIKeyRevoke, "api_key", &f, s.Store.DeleteAPIKeys(&f))
+}
+
+// Refresh replaces the provided token with a new one and revokes it.
+func (s *Server) Refresh(ctx context.Context, req *ptypes.TokenRequest) (*ptypes.TokenResponse, error) {
+	if req.Token == "" {
+		return nil, dlib.NewError(400, "token required")
+	}
+
+	t, err := s.issuer().Refresh(req.Token)
+	s.audit(ctx, tokenEvent(AuditTokenRefresh, t), err)
+	if err != nil {
+		return nil, err
+	}
+
+	res := t.ToResponse()
+	return &res, nil
+}
+
+// ScopeToken issues a new token, limited to the scope of the request, for
+// the user of the provided token. The scope must be within the scope of
+// the provided token.
+func (s *Server) ScopeToken(ctx context.Context, req *ptypes.TokenRequest) (*ptypes.TokenResponse, error) {
+	if req.Token == "" {
+		return nil, dlib.NewError(400, "token required")
+	}
+
+	t, err := s.issuer().Restrict(req.Token, req.Scope)
+	s.audit(ctx, tokenEvent(AuditTokenScope, t), err)
+	if err != nil {
+		return nil, err
+	}
+
+	res := t.ToResponse()
+	return &res, nil
+}
+
+// Impersonate issues a token for the user with the UserID of the request,
+// with which the user of the provided token may act as them, such as to
+// reproduce a problem they have reported. The provided token must hold
+// ImpersonatePerm, and may not itself be an impersonation token, and the
+// target user must be in a tenant the acting user may access. The issued
+// token grants only the permissions which both users hold. It expires after
+// the impersonation TTL of the server, or after the TTL of the request, in
+// seconds, if that is shorter.
+//
+// Each request is recorded as an audit event, with the reason given in the
+// request as its detail.
+func (s *Server) Impersonate(ctx context.Context, req *ptypes.ImpersonateRequest) (*ptypes.TokenResponse, error) {
+	if req.Token == "" || req.UserID == 0 {
+		return nil, dlib.NewError(400, "token and user_id required")
+	}
+
+	if req.TTL < 0 {
+		return nil, dlib.NewError(400, "ttl must not be negative")
+	}
+
+	e := AuditEvent{
+		Action: AuditImpersonate,
+		Target: AuditTarget("user", req.UserID),
+		Detail: req.Reason,
+	}
+
+	t, err := s.impersonate(ctx, req, &e)
+	s.audit(ctx, e, err)
+	if err != nil {
+		return nil, err
+	}
+
+	res := t.ToResponse()
+	return &res, nil
+}
+
+// impersonate issues an impersonation token, setting the actor of an audit
+// event once the acting user is authenticated.
+func (s *Server) impersonate(ctx context.Context, req *ptypes.ImpersonateRequest, e *AuditEvent) (*Token, error) {
+	a, scope, err := s.authenticate(req.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if a == nil {
+		return nil, dlib.NewError(401, "invalid token")
+	}
+
+	e.ActorID, e.Actor, e.TenantID = a.ID, a.User, a.TenantID
+	if a.Actor != nil {
+		return nil, dlib.NewError(403, "impersonation tokens can not impersonate")
+	}
+
+	perms, err := EffectivePermsWith(s.Store, a.ID, grantContext(ClientAddr(ctx)))
+	if err != nil {
+		return nil, err
+	}
+
+	if grant(a, scope, perms, nil, ImpersonatePerm) == nil {
+		return nil, dlib.NewError(403, "permission denied")
+	}
+
+	u, err := getUser(s.Store, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u == nil {
+		return nil, dlib.NewError(404, "user not found")
+	}
+
+	if u.ID == a.ID {
+		return nil, dlib.NewError(400, "users can not impersonate themselves")
+	}
+
+	if !TenantAllows(a.TenantID, u.TenantID) {
+		return nil, ErrCrossTenant
+	}
+
+	ttl := s.ImpersonationTTL
+	if ttl <= 0 {
+		ttl = DefaultImpersonationTTL
+	}
+
+	if d := time.Duration(req.TTL) * time.Second; d > 0 && d < ttl {
+		ttl = d
+	}
+
+	return s.issuer().Impersonate(a.ID, u.ID, ttl)
+}
+
+// Auth authenticates a provided token and returns a user value. The
+// response is Ok only if the token is valid and its user holds a permission,
+// assigned directly or through a role, which implies the requested one. The
+// requested permission may be identified by ID alone. The response includes
+// the held permission that granted access. If the token, or the API key
+// provided in its place, is scoped, the requested permission must also be
+// within its scope.
+//
+// Access is limited to the tenant of the user. The token must belong to
+// the same tenant as its user, only permissions of that tenant are
+// considered, and the response is not Ok if the request names another
+// tenant. Users in the super-tenant may access every tenant.
+//
+// If the token is an impersonation token, the response includes the user
+// acting as the user of the token, who must also hold a permission which
+// implies the requested one.
+//
+// Grant conditions on the client address are evaluated against the Addr of
+// the request, which the service authorizing its client must forward. The
+// address of the service itself is never used in its place, so grants
+// limited to addresses are not held if the request has no Addr.
+//
+// Requests which fail, or are not Ok, are recorded as audit events.
+func (s *Server) Auth(ctx context.Context, req *ptypes.AuthRequest) (*ptypes.AuthResponse, error) {
+	res, err := s.auth(ctx, req)
+	if err == nil && res.Ok {
+		return res, nil
+	}
+
+	e := AuditEvent{Action: AuditAuthFailed, Outcome: AuditFailure}
+	if req.Perm != nil {
+		e.Target = req.Perm.Service + ":" + req.Perm.Name
+	}
+
+	if res != nil && res.User != nil {
+		e.ActorID, e.Actor, e.TenantID = res.User.ID, res.User.User, res.User.TenantID
+	}
+
+	if res != nil && res.Actor != nil {
+		e.ImpersonatorID, e.Impersonator = res.Actor.ID, res.Actor.User
+	}
+
+	s.audit(NewAddrContext(ctx, req.Addr), e, err)
+	return res, err
+}
+
+// auth authenticates a provided token and returns a user value.
+func (s *Server) auth(ctx context.Context, req *ptypes.AuthRequest) (*ptypes.AuthResponse, error) {
+	if req.Token == nil || req.Token.Token == "" {
+		return nil, dlib.NewError(400, "token required")
+	}
+
+	if req.Perm == nil {
+		return nil, dlib.NewError(400, "perm required")
+	}
+
+	res := ptypes.AuthResponse{}
+	u, scope, err := s.authenticate(req.Token.Token)
+	if err != nil || u == nil {
+		return &res, err
+	}
+
+	res.User, res.Actor = authUsers(u)
+	if req.TenantID != 0 && !TenantAllows(u.TenantID, req.TenantID) {
+		return &res, nil
+	}
+
+	rp, err := s.requestedPerm(req.Perm)
+	if err != nil {
+		return nil, err
+	}
+
+	perms, actorPerms, err := s.effectivePerms(u, grantContext(req.Addr))
+	if err != nil {
+		return nil, err
+	}
+
+	if p := grant(u, scope, perms, actorPerms, rp); p != nil {
+		pr := p.ToResponse()
+		res.Perm = &pr
+		res.Ok = true
+	}
+
+	return &res, nil
+}
+
+// AuthBatch authenticates a provided token and decides whether its user
+// holds each of the requested permissions, as Auth does for one. The
+// response holds a decision for each permission, in the order requested,
+// and the effective permissions of the user in its tenant, limited to those
+// the acting user also holds if the token is an impersonation token. It
+// holds no user if the token is not valid.
+func (s *Server) AuthBatch(ctx context.Context, req *ptypes.AuthBatchRequest) (*ptypes.AuthBatchResponse, error) {
+	if req.Token == nil || req.Token.Token == "" {
+		return nil, dlib.NewError(400, "token required")
+	}
+
+	if len(req.Perms) == 0 {
+		return nil, dlib.NewError(400, "perms required")
+	}
+
+	res := ptypes.AuthBatchResponse{}
+	u, scope, err := s.authenticate(req.Token.Token)
+	if err != nil || u == nil {
+		s.audit(NewAddrContext(ctx, req.Addr),
+			AuditEvent{Action: AuditAuthFailed, Outcome: AuditFailure}, err)
+		return &res, err
+	}
+
+	res.User, res.Actor = authUsers(u)
+	perms, actorPerms, err := s.effectivePerms(u, grantContext(req.Addr))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range perms {
+		if u.Actor != nil && grant(u.Actor, nil, actorPerms, nil, &perms[i]) == nil {
+			continue
+		}
+
+		if TenantAllows(u.TenantID, perms[i].TenantID) {
+			pr := perms[i].ToResponse()
+			res.Perms = append(res.Perms, &pr)
+		}
+	}
+
+	allowed := req.TenantID == 0 || TenantAllows(u.TenantID, req.TenantID)
+	for _, preq := range req.Perms {
+		d := ptypes.AuthDecision{Perm: preq}
+		rp, err := s.requestedPerm(preq)
+		if err != nil {
+			return nil, err
+		}
+
+		if p := grant(u, scope, perms, actorPerms, rp); allowed && p != nil {
+			pr := p.ToResponse()
+			d.Grant = &pr
+			d.Ok = true
+		}
+
+		res.Decisions = append(res.Decisions, &d)
+	}
+
+	return &res, nil
+}
+
+// grantContext returns the context against which the conditions of user
+// permission grants are evaluated for a client at an address. An empty
+// address is unknown, and satisfies no address condition.
+func grantContext(addr string) *GrantContext {
+	return &GrantContext{Time: time.Now(), Addr: addr}
+}
+
+// authUsers returns the protobuf responses for an authenticated user and
+// for the user acting as them, if any.
+func authUsers(u *User) (*ptypes.UserResponse, *ptypes.UserResponse) {
+	ur := u.ToResponse()
+	if u.Actor == nil {
+		return &ur, nil
+	}
+
+	ar := u.Actor.ToResponse()
+	return &ur, &ar
+}
+
+// effectivePerms returns the effective permissions of an authenticated user
+// for a grant context, and those of the user acting as them, if any.
+func (s *Server) effectivePerms(u *User, gc *GrantContext) ([]Perm, []Perm, error) {
+	perms, err := EffectivePermsWith(s.Store, u.ID, gc)
+	if err != nil || u.Actor == nil {
+		return perms, nil, err
+	}
+
+	actorPerms, err := EffectivePermsWith(s.Store, u.Actor.ID, gc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return perms, actorPerms, nil
+}
+
+// authenticate returns the user and scope of a token or API key. The user
+// is nil if the token is not valid or has expired, or if its user does not
+// exist or belongs to another tenant than the token or key. The user of an
+// impersonation token carries the user acting as them, and the token is
+// not valid if that user does not exist or may not access the tenant.
+func (s *Server) authenticate(token string) (*User, []string, error) {
+	var scope []string
+	var userID int64
+	var tok *Token
+	var key *APIKey
+	if IsAPIKey(token) {
+		k, err := s.issuer().FindAPIKey(token)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if k == nil || k.Expired(time.Now()) {
+			return nil, nil, nil
+		}
+
+		scope, userID, key = k.Scope, k.UserID, k
+	} else {
+		t, err := s.issuer().Find(token)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if t == nil || (t.Expires != nil && t.Expires.Before(time.Now())) {
+			return nil, nil, nil
+		}
+
+		if err := s.issuer().Touch(t); err != nil {
+			return nil, nil, err
+		}
+
+		scope, userID, tok = t.Scope, t.UserID, t
+	}
+
+	u, err := getUser(s.Store, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if u == nil || (tok != nil && tok.TenantID != u.TenantID) ||
+		(key != nil && key.TenantID != u.TenantID) {
+		return nil, nil, nil
+	}
+
+	if tok != nil && tok.ActorID != 0 {
+		a, err := getUser(s.Store, tok.ActorID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if a == nil || !TenantAllows(a.TenantID, u.TenantID) {
+			return nil, nil, nil
+		}
+
+		u.Actor = a
+	}
+
+	return u, scope, nil
+}
+
+// requestedPerm returns the permission of a request. A permission
+// identified by ID alone is found in the store.
+func (s *Server) requestedPerm(req *ptypes.PermRequest) (*Perm, error) {
+	rp := Perm{}
+	if err := rp.FromRequest(req); err != nil {
+		return nil, err
+	}
+
+	if rp.ID != 0 && rp.Service == "" && rp.Name == "" {
+		pf := PermFind{ID: &rp.ID}
+		for r := range s.Store.GetPerms(&pf) {
+			if r.Err != nil {
+				return nil, r.Err
+			}
+
+			rp = *r.Val.(*Perm)
+		}
+	}
+
+	return &rp, nil
+}
+
+// grant returns the first of the effective permissions of a user which
+// implies a requested permission, or nil if there is none or the scope of
+// the token does not allow it. Only permissions of the tenant of the user
+// are considered. If the user is impersonated, the effective permissions
+// of the user acting as them must also include one which implies it.
+func grant(u *User, scope []string, perms, actorPerms []Perm, rp *Perm) *Perm {
+	if !scopeAllows(scope, rp) {
+		return nil
+	}
+
+	if u.Actor != nil && grant(u.Actor, nil, actorPerms, nil, rp) == nil {
+		return nil
+	}
+
+	for i := range perms {
+		if TenantAllows(u.TenantID, perms[i].TenantID) && perms[i].Implies(rp) {
+			return &perms[i]
+		}
+	}
+
+	return nil
+}
+
+// GetAuditEvents returns a stream of audit events from the audit log, most
+// recent first. Users restricted to a tenant may only find the events of
+// their tenant.
+func (s *Server) GetAuditEvents(req *ptypes.AuditRequest, stream ptypes.Auth_GetAuditEventsServer) error {
+	if s.Audit == nil {
+		return dlib.NewError(400, "audit not configured")
+	}
+
+	f := AuditFind{}
+	if err := f.FromAuditRequest(req); err != nil {
+		return err
+	}
+
+	if err := scopeFind(stream.Context(), &f.TenantID); err != nil {
+		return err
+	}
+
+	for r := range s.Audit.Find(&f) {
+		if r.Err != nil {
+			return r.Err
+		}
+
+		e := r.Val.(*AuditEvent)
+		res := e.ToResponse()
+		if err := stream.Send(&res); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// audit records an audit event for an action with the error it returned,
+// if the server has an auditor. Events with no actor are attributed to the
+// authenticated user carried by the context, if any, and to the user acting
+// as them. The outcome is set from the error, unless the event already has
+// one.
+func (s *Server) audit(ctx context.Context, e AuditEvent, err error) {
+	if s.Audit == nil {
+		return
+	}
+
+	if u, ok := UserFromContext(ctx); ok && u != nil && e.ActorID == 0 && e.Actor == "" {
+		e.ActorID, e.Actor, e.TenantID = u.ID, u.User, u.TenantID
+		if u.Actor != nil {
+			e.ImpersonatorID, e.Impersonator = u.Actor.ID, u.Actor.User
+		}
+	}
+
+	e.Addr = ClientAddr(ctx)
+	if e.Outcome == "" {
+		e.Outcome = AuditSuccess
+		if err != nil {
+			e.Outcome = AuditFailure
+		}
+	}
+
+	if err != nil {
+		if e.Detail != "" {
+			e.Detail += ": "
+		}
+
+		e.Detail += err.Error()
+	}
+
+	s.Audit.Enqueue(&e)
+}
+
+// tokenEvent returns the audit event for an action on a token, attributed
+// to the user of the token. The token is nil if the action failed, and
+// signed tokens, which are not stored, have no ID to target.
+func tokenEvent(action string, t *Token) AuditEvent {
+	e := AuditEvent{Action: action}
+	if t != nil {
+		e.ActorID, e.TenantID = t.UserID, t.TenantID
+		if t.ID != 0 {
+			e.Target = AuditTarget("token", t.ID)
+		}
+	}
+
+	return e
+}
+
+// auditSave waits for the result of a save operation and records it as an
+// audit event for the saved record of a kind. The ID is read once the save
+// is complete, so that new records are identified.
+func (s *Server) auditSave(ctx context.Context, action, kind string, id *int64, c <-chan dlib.Result) error {
+	var err error
+	for r := range c {
+		if r.Err != nil {
+			err = r.Err
+		}
+	}
+
+	s.audit(ctx, AuditEvent{Action: action, Target: AuditTarget(kind, *id)}, err)
+	return err
+}
+
+// auditDelete converts the result of a delete operation into a protobuf
+// delete response and records it as an audit event for the records of a
+// kind matching the find value.
+func (s *Server) auditDelete(ctx context.Context, action, kind string, f interface{}, c <-chan dlib.Result) (*ptypes.DeleteResponse, error) {
+	res, err := deleteResponse(c)
+	e := AuditEvent{Action: action, Target: kind}
+	if b, jerr := json.Marshal(f); jerr == nil {
+		e.Detail = string(b)
+	}
+
+	s.audit(ctx, e, err)
+	return res, err
+}
+
+// deleteResponse converts the result of a delete operation into a
+// protobuf delete response.
+func deleteResponse(c <-chan dlib.Result) (*ptypes.DeleteResponse, error) {
+	res := ptypes.DeleteResponse{}
+	for r := range c {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		res.Num += int64(r.Num)
+	}
+
+	return &res, nil
+}
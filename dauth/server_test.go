@@ -0,0 +1,1473 @@
+package dauth
+
+import (
+	"context"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhaifley/dlib"
+	"github.com/dhaifley/dlib/ptypes"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type FakeGetTokensServer struct {
+	FakeServerStream
+	res []*ptypes.TokenResponse
+}
+
+func (fk *FakeGetTokensServer) Send(m *ptypes.TokenResponse) error {
+	fk.res = append(fk.res, m)
+	return nil
+}
+
+type FakeSaveUsersServer struct {
+	FakeServerStream
+	req []*ptypes.UserRequest
+	res []*ptypes.UserResponse
+}
+
+func (fk *FakeSaveUsersServer) Send(m *ptypes.UserResponse) error {
+	fk.res = append(fk.res, m)
+	return nil
+}
+
+func (fk *FakeSaveUsersServer) Recv() (*ptypes.UserRequest, error) {
+	if len(fk.req) == 0 {
+		return nil, io.EOF
+	}
+
+	m := fk.req[0]
+	fk.req = fk.req[1:]
+	return m, nil
+}
+
+var testHasher = &Argon2Hasher{Time: 1, Memory: 1024, Threads: 1, KeyLen: 32, SaltLen: 16}
+
+func newTestStore() *MemoryStore {
+	ms := NewMemoryStore()
+	pass, _ := testHasher.Hash("test")
+	now := time.Now()
+	exp := now.Add(time.Hour)
+	ms.SaveToken(NewToken(0, HashToken("test"), 1, &now, &exp))
+	ms.SaveUser(&User{User: "test", Pass: pass, Name: "test"})
+	ms.SavePerm(&Perm{Service: "test", Name: "test"})
+	ms.SaveUserPerm(&UserPerm{UserID: 1, PermID: 1})
+	return ms
+}
+
+func TestServerImplementsAuthServer(t *testing.T) {
+	var s interface{} = NewServer(newTestStore())
+	if _, ok := s.(ptypes.AuthServer); !ok {
+		t.Error("Expected type: ptypes.AuthServer")
+	}
+}
+
+func TestServerGetTokens(t *testing.T) {
+	s := NewServer(newTestStore())
+	stream := FakeGetTokensServer{}
+	if err := s.GetTokens(&ptypes.TokenRequest{UserID: 1}, &stream); err != nil {
+		t.Error(err)
+	}
+
+	if len(stream.res) != 1 {
+		t.Fatalf("Length expected: 1, got: %v", len(stream.res))
+	}
+
+	exp := HashToken("test")
+	if stream.res[0].Token != exp {
+		t.Errorf("Value expected: %v, got: %v", exp, stream.res[0].Token)
+	}
+
+	stream = FakeGetTokensServer{}
+	if err := s.GetTokens(&ptypes.TokenRequest{Token: "test"}, &stream); err != nil {
+		t.Error(err)
+	}
+
+	if len(stream.res) != 1 {
+		t.Errorf("Length expected: 1, got: %v", len(stream.res))
+	}
+}
+
+func TestServerSaveUsers(t *testing.T) {
+	ms := newTestStore()
+	s := NewServer(ms)
+	stream := FakeSaveUsersServer{
+		req: []*ptypes.UserRequest{
+			{User: "new", Pass: "new"},
+			{ID: 1, Email: "test@test.com"},
+		},
+	}
+
+	if err := s.SaveUsers(&stream); err != nil {
+		t.Error(err)
+	}
+
+	if len(stream.res) != 2 {
+		t.Fatalf("Length expected: 2, got: %v", len(stream.res))
+	}
+
+	if stream.res[0].ID != 2 {
+		t.Errorf("ID expected: 2, got: %v", stream.res[0].ID)
+	}
+
+	exp := "test"
+	if stream.res[1].Name != exp {
+		t.Errorf("Value expected: %v, got: %v", exp, stream.res[1].Name)
+	}
+
+	exp = "test@test.com"
+	if stream.res[1].Email != exp {
+		t.Errorf("Value expected: %v, got: %v", exp, stream.res[1].Email)
+	}
+
+	id := int64(2)
+	for r := range ms.GetUsers(&UserFind{ID: &id}) {
+		u := r.Val.(*User)
+		if ok, err := VerifyPassword(u.Pass, "new"); !ok || err != nil {
+			t.Errorf("Expected stored hash of password, got: %v", u.Pass)
+		}
+	}
+}
+
+func TestServerDeleteTokens(t *testing.T) {
+	s := NewServer(newTestStore())
+	res, err := s.DeleteTokens(context.Background(), &ptypes.TokenRequest{ID: 1})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if res.Num != 1 {
+		t.Errorf("Num expected: 1, got: %v", res.Num)
+	}
+
+	if _, err := s.DeleteTokens(context.Background(), &ptypes.TokenRequest{}); err == nil {
+		t.Error("Expected error for delete without criteria")
+	}
+}
+
+func TestServerLogin(t *testing.T) {
+	s := NewServer(newTestStore())
+	res, err := s.Login(context.Background(), &ptypes.UserRequest{
+		User: "test",
+		Pass: "test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.UserID != 1 {
+		t.Errorf("UserID expected: 1, got: %v", res.UserID)
+	}
+
+	if len(res.Token) != 64 {
+		t.Errorf("Length expected: 64, got: %v", len(res.Token))
+	}
+
+	if res.Expires == nil {
+		t.Error("Expected expiration time")
+	}
+
+	_, err = s.Login(context.Background(), &ptypes.UserRequest{
+		User: "test",
+		Pass: "wrong",
+	})
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 401 {
+		t.Errorf("Error expected: 401, got: %v", err)
+	}
+}
+
+func TestServerLoginTenant(t *testing.T) {
+	s := NewServer(newTenantTestStore())
+	s.Throttle.BaseDelay = 0
+	cases := []struct {
+		tenant int64
+		code   int
+	}{
+		{0, 401},
+		{3, 401},
+		{2, 0},
+	}
+
+	for _, c := range cases {
+		res, err := s.Login(context.Background(), &ptypes.UserRequest{
+			User:     "tenant",
+			Pass:     "test",
+			TenantID: c.tenant,
+		})
+		if c.code == 0 {
+			if err != nil || res.UserID != 2 || res.TenantID != 2 {
+				t.Errorf("Expected token for user 2 in tenant 2, got: %v, %v", res, err)
+			}
+
+			continue
+		}
+
+		if e, ok := err.(*dlib.Error); !ok || e.Code != c.code {
+			t.Errorf("Error expected: %v, got: %v for tenant %v", c.code, err, c.tenant)
+		}
+	}
+}
+
+func TestServerLoginProvision(t *testing.T) {
+	la := &FakeLogAccessor{}
+	ms := newTestStore()
+	s := NewServer(ms)
+	s.Audit = NewAuditor(la)
+	fd := newFakeDirectory()
+	fd.entries["svc"] = &DirectoryEntry{DN: "uid=svc,ou=people,dc=example,dc=com"}
+	fd.pass["uid=svc,ou=people,dc=example,dc=com"] = "secret"
+	ms.SaveUser(&User{User: "svc", Name: "service account"})
+	s.Authenticator = MultiAuthenticator{
+		&StoreAuthenticator{Store: ms, Hasher: testHasher},
+		NewDirectoryAuthenticator(fd),
+	}
+
+	ctx := context.Background()
+	var id int64
+	for i := 0; i < 2; i++ {
+		res, err := s.Login(ctx, &ptypes.UserRequest{User: "dir", Pass: "secret"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.UserID == 0 || (id != 0 && res.UserID != id) {
+			t.Errorf("Expected the same provisioned user, got: %v", res.UserID)
+		}
+
+		id = res.UserID
+	}
+
+	u, err := getUser(ms, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u == nil || u.User != "dir" || u.Name != "Directory User" || u.Pass != "" ||
+		u.Provider != "directory" {
+		t.Errorf("Expected provisioned user dir, got: %v", u)
+	}
+
+	_, err = s.Login(ctx, &ptypes.UserRequest{User: "svc", Pass: "secret"})
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 401 {
+		t.Errorf("Error expected: 401, got: %v", err)
+	}
+
+	if _, err := s.Login(ctx, &ptypes.UserRequest{User: "test", Pass: "test"}); err != nil {
+		t.Error(err)
+	}
+
+	_, err = s.Login(ctx, &ptypes.UserRequest{User: "test", Pass: "secret"})
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 401 {
+		t.Errorf("Error expected: 401, got: %v", err)
+	}
+
+	s.Audit.Flush()
+	n := 0
+	for _, e := range la.events() {
+		if e.Action == AuditUserProvision {
+			n++
+			if e.ActorID != id || e.Target != AuditTarget("user", id) {
+				t.Errorf("Expected provision of user %v, got: %v", id, e)
+			}
+		}
+	}
+
+	if n != 1 {
+		t.Errorf("Provisions expected: 1, got: %v", n)
+	}
+}
+
+func TestServerLoginLockout(t *testing.T) {
+	s := NewServer(newTestStore())
+	s.Throttle.BaseDelay = 0
+	s.Throttle.MaxFailures = 2
+	req := ptypes.UserRequest{User: "test", Pass: "wrong"}
+	for i := 0; i < 2; i++ {
+		s.Login(context.Background(), &req)
+	}
+
+	req.Pass = "test"
+	if _, err := s.Login(context.Background(), &req); err != ErrLockedOut {
+		t.Errorf("Error expected: %v, got: %v", ErrLockedOut, err)
+	}
+
+	res, err := s.Unlock(context.Background(), &ptypes.LockoutRequest{User: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Num != 1 {
+		t.Errorf("Num expected: 1, got: %v", res.Num)
+	}
+
+	if _, err := s.Login(context.Background(), &req); err != nil {
+		t.Errorf("Expected login after unlock, got: %v", err)
+	}
+
+	if _, err := s.Unlock(context.Background(), &ptypes.LockoutRequest{}); err == nil {
+		t.Error("Expected error for unlock without criteria")
+	}
+}
+
+func TestServerLoginMFA(t *testing.T) {
+	ms := newTestStore()
+	s := NewServer(ms)
+	s.MFA = NewMFA(ms, testMFAKey, "dauth")
+	s.Throttle.BaseDelay = 0
+	ctx := context.Background()
+	req := ptypes.UserRequest{User: "test", Pass: "test"}
+	e, err := s.EnrollMFA(ctx, &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := s.Login(ctx, &req)
+	if err != nil || res.MFARequired {
+		t.Errorf("Expected token before confirmation, got: %v, %v", res, err)
+	}
+
+	req.Code, _ = s.MFA.TOTP.Code(e.Secret, time.Now())
+	if _, err := s.ConfirmMFA(ctx, &req); err != nil {
+		t.Fatal(err)
+	}
+
+	req.Code = ""
+	res, err = s.Login(ctx, &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !res.MFARequired || res.Token != "" || res.Challenge == "" {
+		t.Errorf("Expected challenge without token, got: %v", res)
+	}
+
+	step := ptypes.UserRequest{Challenge: res.Challenge, Code: "000000"}
+	if _, err := s.Login(ctx, &step); err != ErrMFAInvalid {
+		t.Errorf("Error expected: %v, got: %v", ErrMFAInvalid, err)
+	}
+
+	step.Code = e.RecoveryCodes[0]
+	res, err = s.Login(ctx, &step)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.MFARequired || len(res.Token) != 64 {
+		t.Errorf("Expected token, got: %v", res)
+	}
+
+	req.Code = e.RecoveryCodes[1]
+	if res, err = s.Login(ctx, &req); err != nil || res.Token == "" {
+		t.Errorf("Expected token for single step login, got: %v, %v", res, err)
+	}
+
+	dres, err := s.DisableMFA(ctx, &ptypes.UserRequest{User: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dres.Num != 1 {
+		t.Errorf("Num expected: 1, got: %v", dres.Num)
+	}
+
+	if on, _ := MFAEnabled(ms, 1); on {
+		t.Error("Expected MFA disabled")
+	}
+}
+
+func TestServerLoginRehash(t *testing.T) {
+	ms := newTestStore()
+	s := NewServer(ms)
+	s.Issuer.Hasher = &Argon2Hasher{Time: 2, Memory: 1024, Threads: 1, KeyLen: 32, SaltLen: 16}
+	if _, err := s.Login(context.Background(), &ptypes.UserRequest{
+		User: "test",
+		Pass: "test",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	id := int64(1)
+	for r := range ms.GetUsers(&UserFind{ID: &id}) {
+		u := r.Val.(*User)
+		if s.Issuer.Hasher.NeedsRehash(u.Pass) {
+			t.Errorf("Expected rehashed password, got: %v", u.Pass)
+		}
+	}
+}
+
+func TestServerLogout(t *testing.T) {
+	s := NewServer(newTestStore())
+	res, err := s.Logout(context.Background(), &ptypes.TokenRequest{Token: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.ID != 1 {
+		t.Errorf("ID expected: 1, got: %v", res.ID)
+	}
+}
+
+func TestServerRefresh(t *testing.T) {
+	s := NewServer(newTestStore())
+	res, err := s.Refresh(context.Background(), &ptypes.TokenRequest{Token: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Token == "test" || res.UserID != 1 {
+		t.Errorf("Expected new token for user 1, got: %v", res)
+	}
+
+	_, err = s.Refresh(context.Background(), &ptypes.TokenRequest{Token: "test"})
+	if err == nil {
+		t.Error("Expected error refreshing a revoked token")
+	}
+}
+
+func TestServerAuth(t *testing.T) {
+	ms := newTestStore()
+	s := NewServer(ms)
+	req := ptypes.AuthRequest{
+		Token: &ptypes.TokenRequest{Token: "test"},
+		Perm:  &ptypes.PermRequest{Service: "test", Name: "test"},
+	}
+
+	res, err := s.Auth(context.Background(), &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !res.Ok {
+		t.Errorf("Ok expected: true, got: %v", res.Ok)
+	}
+
+	if res.User == nil || res.User.ID != 1 {
+		t.Fatalf("User expected: 1, got: %v", res.User)
+	}
+
+	pid := int64(1)
+	ms.DeleteUserPerms(&UserPermFind{PermID: &pid})
+	res, err = s.Auth(context.Background(), &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Ok {
+		t.Errorf("Ok expected: false, got: %v", res.Ok)
+	}
+}
+
+func TestServerAuthConditions(t *testing.T) {
+	ms := newTestStore()
+	before := time.Now().Add(-time.Minute)
+	ms.SavePerm(&Perm{Service: "test", Name: "override"})
+	ms.SavePerm(&Perm{Service: "test", Name: "store"})
+	ms.SaveUserPerm(&UserPerm{UserID: 1, PermID: 2, Expires: &before})
+	ms.SaveUserPerm(&UserPerm{UserID: 1, PermID: 3, CIDRs: []string{"10.0.0.0/8"}})
+	s := NewServer(ms)
+	cases := []struct {
+		name string
+		addr string
+		exp  bool
+	}{
+		{"override", "10.0.0.1", false},
+		{"store", "10.0.0.1", true},
+		{"store", "192.168.0.1", false},
+		{"store", "", false},
+	}
+
+	for _, c := range cases {
+		res, err := s.Auth(context.Background(), &ptypes.AuthRequest{
+			Token: &ptypes.TokenRequest{Token: "test"},
+			Perm:  &ptypes.PermRequest{Service: "test", Name: c.name},
+			Addr:  c.addr,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.Ok != c.exp {
+			t.Errorf("Ok expected: %v, got: %v for %v from %v", c.exp, res.Ok, c.name,
+				c.addr)
+		}
+	}
+	pctx := peer.NewContext(context.Background(),
+		&peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5000}})
+	res, err := s.Auth(pctx, &ptypes.AuthRequest{
+		Token: &ptypes.TokenRequest{Token: "test"},
+		Perm:  &ptypes.PermRequest{Service: "test", Name: "store"},
+	})
+	if err != nil || res.Ok {
+		t.Errorf("Expected no fallback to the caller address, got: %v, %v", res, err)
+	}
+}
+
+func newTestImpersonateStore() *MemoryStore {
+	ms := newTestStore()
+	ms.SavePerm(&Perm{Service: ImpersonatePerm.Service, Name: ImpersonatePerm.Name})
+	ms.SavePerm(&Perm{Service: "test", Name: "other"})
+	ms.SaveUser(&User{User: "target", Name: "target"})
+	ms.SaveUserPerm(&UserPerm{UserID: 1, PermID: 2})
+	ms.SaveUserPerm(&UserPerm{UserID: 2, PermID: 1})
+	ms.SaveUserPerm(&UserPerm{UserID: 2, PermID: 3})
+	return ms
+}
+
+func TestServerImpersonate(t *testing.T) {
+	la := &FakeLogAccessor{}
+	s := NewServer(newTestImpersonateStore())
+	s.Audit = NewAuditor(la)
+	ctx := context.Background()
+	tr, err := s.Impersonate(ctx, &ptypes.ImpersonateRequest{
+		Token:  "test",
+		UserID: 2,
+		TTL:    60,
+		Reason: "ticket 42",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tr.UserID != 2 || tr.ActorID != 1 {
+		t.Errorf("Expected token for user 2 by actor 1, got: %v", tr)
+	}
+
+	if d := tr.Expires.Seconds - tr.Created.Seconds; d != 60 {
+		t.Errorf("TTL expected: 60, got: %v", d)
+	}
+
+	cases := []struct {
+		name string
+		exp  bool
+	}{
+		{"test", true},
+		{"other", false},
+	}
+
+	for _, c := range cases {
+		res, err := s.Auth(ctx, &ptypes.AuthRequest{
+			Token: &ptypes.TokenRequest{Token: tr.Token},
+			Perm:  &ptypes.PermRequest{Service: "test", Name: c.name},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.Ok != c.exp {
+			t.Errorf("Ok expected: %v, got: %v for %v", c.exp, res.Ok, c.name)
+		}
+
+		if res.User == nil || res.User.ID != 2 || res.Actor == nil || res.Actor.ID != 1 {
+			t.Errorf("Expected user 2 and actor 1, got: %v, %v", res.User, res.Actor)
+		}
+	}
+
+	u := &User{ID: 2, User: "target", Actor: &User{ID: 1, User: "test"}}
+	s.DeleteUserPerms(NewUserContext(ctx, u), &ptypes.UserPermRequest{PermID: 3})
+	s.Audit.Flush()
+	events := la.events()
+	if len(events) != 3 {
+		t.Fatalf("Length expected: 3, got: %v", len(events))
+	}
+
+	e := events[0]
+	if e.Action != AuditImpersonate || e.ActorID != 1 || e.Target != "user:2" ||
+		e.Detail != "ticket 42" || e.Outcome != AuditSuccess {
+		t.Errorf("Expected impersonation event, got: %v", e)
+	}
+
+	for _, e := range events[1:] {
+		if e.ActorID != 2 || e.ImpersonatorID != 1 || e.Impersonator != "test" {
+			t.Errorf("Expected event by user 2 impersonated by user 1, got: %v", e)
+		}
+	}
+}
+
+func TestServerImpersonateDenied(t *testing.T) {
+	ms := newTestImpersonateStore()
+	s := NewServer(ms)
+	ctx := context.Background()
+	tr, err := s.Impersonate(ctx, &ptypes.ImpersonateRequest{Token: "test", UserID: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		token  string
+		userID int64
+		ttl    int64
+		code   int
+	}{
+		{"", 2, 0, 400},
+		{"test", 2, -1, 400},
+		{"wrong", 2, 0, 401},
+		{tr.Token, 1, 0, 403},
+		{"test", 1, 0, 400},
+		{"test", 9, 0, 404},
+	}
+
+	for _, c := range cases {
+		_, err := s.Impersonate(ctx, &ptypes.ImpersonateRequest{
+			Token:  c.token,
+			UserID: c.userID,
+			TTL:    c.ttl,
+		})
+		if e, ok := err.(*dlib.Error); !ok || e.Code != c.code {
+			t.Errorf("Error expected: %v, got: %v", c.code, err)
+		}
+	}
+
+	pid := int64(2)
+	ms.DeleteUserPerms(&UserPermFind{PermID: &pid})
+	_, err = s.Impersonate(ctx, &ptypes.ImpersonateRequest{Token: "test", UserID: 2})
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 403 {
+		t.Errorf("Error expected: 403, got: %v", err)
+	}
+}
+
+func TestServerAuthScoped(t *testing.T) {
+	ms := newTestStore()
+	ms.SavePerm(&Perm{Service: "test", Name: "read"})
+	s := NewServer(ms)
+	_, err := s.ScopeToken(context.Background(), &ptypes.TokenRequest{
+		Token: "test",
+		Scope: []string{"invalid"},
+	})
+
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 400 {
+		t.Errorf("Error expected: 400, got: %v", err)
+	}
+
+	tk, err := s.ScopeToken(context.Background(), &ptypes.TokenRequest{
+		Token: "test",
+		Scope: []string{"test:read"},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		exp  bool
+	}{
+		{"read", false},
+		{"test", false},
+	}
+
+	for _, c := range cases {
+		res, err := s.Auth(context.Background(), &ptypes.AuthRequest{
+			Token: &ptypes.TokenRequest{Token: tk.Token},
+			Perm:  &ptypes.PermRequest{Service: "test", Name: c.name},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.Ok != c.exp {
+			t.Errorf("Ok for %v expected: %v, got: %v", c.name, c.exp, res.Ok)
+		}
+	}
+
+	ms.SaveUserPerm(&UserPerm{UserID: 1, PermID: 2})
+	res, err := s.Auth(context.Background(), &ptypes.AuthRequest{
+		Token: &ptypes.TokenRequest{Token: tk.Token},
+		Perm:  &ptypes.PermRequest{Service: "test", Name: "read"},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !res.Ok {
+		t.Errorf("Ok expected: true, got: %v", res.Ok)
+	}
+}
+
+type FakeSaveRolesServer struct {
+	FakeServerStream
+	req []*ptypes.RoleRequest
+	res []*ptypes.RoleResponse
+}
+
+func (fk *FakeSaveRolesServer) Send(m *ptypes.RoleResponse) error {
+	fk.res = append(fk.res, m)
+	return nil
+}
+
+func (fk *FakeSaveRolesServer) Recv() (*ptypes.RoleRequest, error) {
+	if len(fk.req) == 0 {
+		return nil, io.EOF
+	}
+
+	m := fk.req[0]
+	fk.req = fk.req[1:]
+	return m, nil
+}
+
+func TestServerSaveRoles(t *testing.T) {
+	s := NewServer(NewMemoryStore())
+	stream := FakeSaveRolesServer{
+		req: []*ptypes.RoleRequest{
+			{Name: "a"},
+			{Name: "b", ParentID: 1},
+			{ID: 1, Name: "a", ParentID: 2},
+		},
+	}
+
+	if err := s.SaveRoles(&stream); err != ErrRoleCycle {
+		t.Errorf("Error expected: %v, got: %v", ErrRoleCycle, err)
+	}
+
+	if len(stream.res) != 2 {
+		t.Errorf("Length expected: 2, got: %v", len(stream.res))
+	}
+}
+
+func TestServerAuthRole(t *testing.T) {
+	ms := newTestStore()
+	pid := int64(1)
+	ms.DeleteUserPerms(&UserPermFind{PermID: &pid})
+	ms.SaveRole(&Role{Name: "parent"})
+	ms.SaveRole(&Role{Name: "child", ParentID: 1})
+	ms.SaveRolePerm(&RolePerm{RoleID: 1, PermID: 1})
+	ms.SaveUserRole(&UserRole{UserID: 1, RoleID: 2})
+	s := NewServer(ms)
+	res, err := s.Auth(context.Background(), &ptypes.AuthRequest{
+		Token: &ptypes.TokenRequest{Token: "test"},
+		Perm:  &ptypes.PermRequest{Service: "test", Name: "test"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !res.Ok {
+		t.Errorf("Ok expected: true, got: %v", res.Ok)
+	}
+}
+
+func TestServerAuthWildcard(t *testing.T) {
+	ms := newTestStore()
+	ms.SavePerm(&Perm{Service: "orders", Name: "read.*"})
+	ms.SaveUserPerm(&UserPerm{UserID: 1, PermID: 2})
+	s := NewServer(ms)
+	cases := []struct {
+		perm *ptypes.PermRequest
+		ok   bool
+	}{
+		{&ptypes.PermRequest{Service: "orders", Name: "read.all"}, true},
+		{&ptypes.PermRequest{Service: "orders", Name: "write"}, false},
+		{&ptypes.PermRequest{ID: 1}, true},
+	}
+
+	for _, c := range cases {
+		res, err := s.Auth(context.Background(), &ptypes.AuthRequest{
+			Token: &ptypes.TokenRequest{Token: "test"},
+			Perm:  c.perm,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.Ok != c.ok {
+			t.Errorf("Ok expected: %v, got: %v", c.ok, res.Ok)
+		}
+	}
+}
+
+func TestServerAPIKeys(t *testing.T) {
+	ms := newTestStore()
+	ms.SavePerm(&Perm{Service: "test", Name: "other"})
+	ms.SaveUserPerm(&UserPerm{UserID: 1, PermID: 2})
+	s := NewServer(ms)
+	ctx := NewUserContext(context.Background(), &User{ID: 1})
+	res, err := s.CreateAPIKey(ctx, &ptypes.APIKeyRequest{
+		Name:  "batch",
+		Scope: []string{"test:test"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.UserID != 1 || !IsAPIKey(res.Key) {
+		t.Errorf("Expected key for user 1, got: %v", res)
+	}
+
+	cases := []struct {
+		perm string
+		ok   bool
+	}{
+		{"test", true},
+		{"other", false},
+	}
+
+	for _, c := range cases {
+		ares, err := s.Auth(ctx, &ptypes.AuthRequest{
+			Token: &ptypes.TokenRequest{Token: res.Key},
+			Perm:  &ptypes.PermRequest{Service: "test", Name: c.perm},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ares.Ok != c.ok || ares.User == nil {
+			t.Errorf("Expected Ok for %v: %v, got: %v", c.perm, c.ok, ares)
+		}
+	}
+
+	fk := &FakeGetAPIKeysServer{}
+	if err := s.GetAPIKeys(&ptypes.APIKeyRequest{UserID: 1}, fk); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fk.res) != 1 || fk.res[0].Key != "" || fk.res[0].Prefix == "" {
+		t.Errorf("Expected listed key without secret, got: %v", fk.res)
+	}
+
+	dres, err := s.RevokeAPIKeys(ctx, &ptypes.APIKeyRequest{ID: res.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dres.Num != 1 {
+		t.Errorf("Num expected: 1, got: %v", dres.Num)
+	}
+
+	ares, err := s.Auth(ctx, &ptypes.AuthRequest{
+		Token: &ptypes.TokenRequest{Token: res.Key},
+		Perm:  &ptypes.PermRequest{Service: "test", Name: "test"},
+	})
+	if err != nil || ares.Ok || ares.User != nil {
+		t.Errorf("Expected revoked key to be rejected, got: %v, %v", ares, err)
+	}
+
+	_, err = s.CreateAPIKey(context.Background(), &ptypes.APIKeyRequest{Name: "batch"})
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 400 {
+		t.Errorf("Error expected: 400, got: %v", err)
+	}
+
+	_, err = s.CreateAPIKey(ctx, &ptypes.APIKeyRequest{Name: "batch", UserID: 9})
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 404 {
+		t.Errorf("Error expected: 404, got: %v", err)
+	}
+}
+
+func TestServerCreateAPIKeyDenied(t *testing.T) {
+	ms := newTenantTestStore()
+	ms.SaveUser(&User{User: "svc", TenantID: 2})
+	s := NewServer(ms)
+	ctx := NewUserContext(context.Background(), &User{ID: 2, TenantID: 2})
+	cases := []struct {
+		userID int64
+		scope  []string
+		code   int
+	}{
+		{0, nil, 0},
+		{3, []string{"test:test"}, 0},
+		{3, nil, 403},
+		{0, []string{"test:other"}, 403},
+		{0, []string{"test:*"}, 403},
+		{1, []string{"test:test"}, 403},
+	}
+
+	for _, c := range cases {
+		res, err := s.CreateAPIKey(ctx, &ptypes.APIKeyRequest{
+			Name:   "batch",
+			UserID: c.userID,
+			Scope:  c.scope,
+		})
+		if c.code == 0 {
+			if err != nil || res.UserID == 0 {
+				t.Errorf("Expected key for %v, got: %v, %v", c, res, err)
+			}
+
+			continue
+		}
+
+		if e, ok := err.(*dlib.Error); !ok || e.Code != c.code {
+			t.Errorf("Error expected: %v, got: %v for %v", c.code, err, c)
+		}
+	}
+}
+
+type FakeGetAPIKeysServer struct {
+	FakeServerStream
+	res []*ptypes.APIKeyResponse
+}
+
+func (fk *FakeGetAPIKeysServer) Send(m *ptypes.APIKeyResponse) error {
+	fk.res = append(fk.res, m)
+	return nil
+}
+
+func newTenantTestStore() *MemoryStore {
+	ms := newTestStore()
+	pass, _ := testHasher.Hash("test")
+	now := time.Now()
+	exp := now.Add(time.Hour)
+	ms.SaveUser(&User{User: "tenant", Pass: pass, TenantID: 2})
+	ms.SavePerm(&Perm{Service: "test", Name: "test", TenantID: 2})
+	ms.SaveUserPerm(&UserPerm{UserID: 2, PermID: 2, TenantID: 2})
+	tk := NewToken(0, HashToken("tenant"), 2, &now, &exp)
+	tk.TenantID = 2
+	ms.SaveToken(tk)
+	return ms
+}
+
+func TestServerTenantScope(t *testing.T) {
+	s := NewServer(newTenantTestStore())
+	ctx := NewUserContext(context.Background(), &User{ID: 2, TenantID: 2})
+	stream := FakeGetTokensServer{FakeServerStream: FakeServerStream{ctx: ctx}}
+	if err := s.GetTokens(&ptypes.TokenRequest{}, &stream); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stream.res) != 1 || stream.res[0].TenantID != 2 {
+		t.Errorf("Expected one token in tenant 2, got: %v", stream.res)
+	}
+
+	stream = FakeGetTokensServer{FakeServerStream: FakeServerStream{ctx: ctx}}
+	err := s.GetTokens(&ptypes.TokenRequest{TenantID: 3}, &stream)
+	if err != ErrCrossTenant {
+		t.Errorf("Error expected: %v, got: %v", ErrCrossTenant, err)
+	}
+
+	us := FakeSaveUsersServer{
+		FakeServerStream: FakeServerStream{ctx: ctx},
+		req: []*ptypes.UserRequest{
+			{User: "new", Pass: "test"},
+			{ID: 1, Name: "taken"},
+		},
+	}
+
+	err = s.SaveUsers(&us)
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 404 {
+		t.Errorf("Error expected: 404, got: %v", err)
+	}
+
+	if len(us.res) != 1 || us.res[0].TenantID != 2 {
+		t.Errorf("Expected new user in tenant 2, got: %v", us.res)
+	}
+
+	res, err := s.DeleteUsers(ctx, &ptypes.UserRequest{ID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Num != 0 {
+		t.Errorf("Num expected: 0, got: %v", res.Num)
+	}
+
+	if _, err := s.DeleteUsers(ctx, &ptypes.UserRequest{}); err != errNoCriteria {
+		t.Errorf("Error expected: %v, got: %v", errNoCriteria, err)
+	}
+}
+
+func TestServerAuthTenant(t *testing.T) {
+	ms := newTenantTestStore()
+	s := NewServer(ms)
+	cases := []struct {
+		token  string
+		tenant int64
+		ok     bool
+		user   bool
+	}{
+		{"tenant", 0, true, true},
+		{"tenant", 2, true, true},
+		{"tenant", 3, false, true},
+		{"test", 2, true, true},
+	}
+
+	for _, c := range cases {
+		res, err := s.Auth(context.Background(), &ptypes.AuthRequest{
+			Token:    &ptypes.TokenRequest{Token: c.token},
+			Perm:     &ptypes.PermRequest{Service: "test", Name: "test"},
+			TenantID: c.tenant,
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.Ok != c.ok || (res.User != nil) != c.user {
+			t.Errorf("Auth %v %v expected: %v, got: %v", c.token, c.tenant, c.ok, res)
+		}
+	}
+
+	ms.SavePerm(&Perm{ID: 2, Service: "test", Name: "test", TenantID: 3})
+	res, err := s.Auth(context.Background(), &ptypes.AuthRequest{
+		Token: &ptypes.TokenRequest{Token: "tenant"},
+		Perm:  &ptypes.PermRequest{Service: "test", Name: "test"},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Ok {
+		t.Errorf("Ok expected: false, got: %v", res.Ok)
+	}
+
+	ms.SaveUser(&User{ID: 2, User: "tenant", TenantID: 3})
+	res, err = s.Auth(context.Background(), &ptypes.AuthRequest{
+		Token: &ptypes.TokenRequest{Token: "tenant"},
+		Perm:  &ptypes.PermRequest{Service: "test", Name: "test"},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Ok || res.User != nil {
+		t.Errorf("Expected token rejected, got: %v", res)
+	}
+}
+
+type FakeGetAuditEventsServer struct {
+	FakeServerStream
+	res []*ptypes.AuditResponse
+}
+
+func (fk *FakeGetAuditEventsServer) Send(m *ptypes.AuditResponse) error {
+	fk.res = append(fk.res, m)
+	return nil
+}
+
+func TestServerErrorStatus(t *testing.T) {
+	s := NewServer(newTestStore())
+	ctx := context.Background()
+	_, err := s.Login(ctx, &ptypes.UserRequest{User: "test", Pass: "wrong"})
+	if code := status.Code(err); code != codes.Unauthenticated {
+		t.Errorf("Code expected: %v, got: %v", codes.Unauthenticated, code)
+	}
+
+	_, err = s.DeleteUsers(ctx, &ptypes.UserRequest{})
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("Code expected: %v, got: %v", codes.InvalidArgument, code)
+	}
+
+	tctx := NewUserContext(ctx, &User{ID: 2, TenantID: 2})
+	_, err = s.CreateAPIKey(tctx, &ptypes.APIKeyRequest{Name: "key", UserID: 100})
+	if code := status.Code(err); code != codes.NotFound {
+		t.Errorf("Code expected: %v, got: %v", codes.NotFound, code)
+	}
+}
+
+func TestServerAudit(t *testing.T) {
+	la := &FakeLogAccessor{}
+	s := NewServer(newTestStore())
+	s.Audit = NewAuditor(la)
+	ctx := context.Background()
+	s.Login(ctx, &ptypes.UserRequest{User: "test", Pass: "test"})
+	s.Login(ctx, &ptypes.UserRequest{User: "test", Pass: "wrong"})
+	s.Auth(ctx, &ptypes.AuthRequest{
+		Token: &ptypes.TokenRequest{Token: "test"},
+		Perm:  &ptypes.PermRequest{Service: "test", Name: "other"},
+		Addr:  "203.0.113.5",
+	})
+
+	actx := NewUserContext(ctx, &User{ID: 1, User: "test"})
+	us := FakeSaveUsersServer{
+		FakeServerStream: FakeServerStream{ctx: actx},
+		req:              []*ptypes.UserRequest{{User: "new", Pass: "new"}},
+	}
+
+	if err := s.SaveUsers(&us); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.DeleteUserPerms(actx, &ptypes.UserPermRequest{UserID: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	k, err := s.CreateAPIKey(actx, &ptypes.APIKeyRequest{Name: "key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.RevokeAPIKeys(actx, &ptypes.APIKeyRequest{ID: k.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Unlock(actx, &ptypes.LockoutRequest{User: "test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.DisableMFA(actx, &ptypes.UserRequest{ID: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Logout(ctx, &ptypes.TokenRequest{Token: "test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		action  string
+		actorID int64
+		actor   string
+		target  string
+		outcome string
+		detail  string
+	}{
+		{AuditLogin, 1, "test", "", AuditSuccess, ""},
+		{AuditLogin, 0, "test", "", AuditFailure, "invalid user or pass"},
+		{AuditAuthFailed, 1, "test", "test:other", AuditFailure, ""},
+		{AuditUserSave, 1, "test", "user:2", AuditSuccess, ""},
+		{AuditPermDelete, 1, "test", "user_perm", AuditSuccess, `{"user_id":1}`},
+		{AuditAPIKeyCreate, 1, "test", "api_key:1", AuditSuccess, "key"},
+		{AuditAPIKeyRevoke, 1, "test", "api_key", AuditSuccess, `{"id":1}`},
+		{AuditUnlock, 1, "test", "lockout", AuditSuccess, `{"user":"test"}`},
+		{AuditMFADisable, 1, "test", "user:1", AuditSuccess, ""},
+		{AuditLogout, 1, "", "token:1", AuditSuccess, ""},
+	}
+
+	s.Audit.Flush()
+	events := la.events()
+	if len(events) != len(cases) {
+		t.Fatalf("Length expected: %v, got: %v", len(cases), len(events))
+	}
+
+	for i, c := range cases {
+		e := events[i]
+		if e.Action != c.action || e.ActorID != c.actorID || e.Actor != c.actor ||
+			e.Target != c.target || e.Outcome != c.outcome {
+			t.Errorf("Event expected: %+v, got: %v", c, e)
+		}
+
+		if c.detail != "" && !strings.Contains(e.Detail, c.detail) {
+			t.Errorf("Detail expected: %v, got: %v", c.detail, e.Detail)
+		}
+	}
+
+	if events[2].Addr != "203.0.113.5" {
+		t.Errorf("Addr expected: 203.0.113.5, got: %v", events[2].Addr)
+	}
+
+	stream := FakeGetAuditEventsServer{}
+	err = s.GetAuditEvents(&ptypes.AuditRequest{Action: AuditLogin}, &stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stream.res) != 2 || stream.res[0].Outcome != AuditFailure {
+		t.Errorf("Expected two logins, most recent first, got: %v", stream.res)
+	}
+
+	tctx := NewUserContext(ctx, &User{ID: 2, TenantID: 2})
+	stream = FakeGetAuditEventsServer{FakeServerStream: FakeServerStream{ctx: tctx}}
+	if err := s.GetAuditEvents(&ptypes.AuditRequest{}, &stream); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stream.res) != 0 {
+		t.Errorf("Length expected: 0, got: %v", len(stream.res))
+	}
+}
+
+type FakeGetUsersServer struct {
+	FakeServerStream
+	res []*ptypes.UserResponse
+}
+
+func (fk *FakeGetUsersServer) Send(m *ptypes.UserResponse) error {
+	fk.res = append(fk.res, m)
+	return nil
+}
+
+func TestServerGetUsersPage(t *testing.T) {
+	ms := newTestStore()
+	for _, name := range []string{"b", "c", "d", "e"} {
+		ms.SaveUser(&User{User: name})
+	}
+
+	s := NewServer(ms)
+	ids := []int64{}
+	req := ptypes.UserRequest{PageSize: 2}
+	for i := 0; i < 4; i++ {
+		stream := FakeGetUsersServer{}
+		if err := s.GetUsers(&req, &stream); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(stream.res) == 0 {
+			break
+		}
+
+		for _, u := range stream.res {
+			ids = append(ids, u.ID)
+		}
+
+		req.PageToken = stream.res[len(stream.res)-1].NextPageToken
+	}
+
+	exp := []int64{1, 2, 3, 4, 5}
+	if !reflect.DeepEqual(ids, exp) {
+		t.Errorf("Value expected: %v, got: %v", exp, ids)
+	}
+
+	stream := FakeGetUsersServer{}
+	err := s.GetUsers(&ptypes.UserRequest{Order: "up"}, &stream)
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 400 {
+		t.Errorf("Error expected: 400, got: %v", err)
+	}
+}
+
+func TestServerSaveUsersMask(t *testing.T) {
+	ms := newTestStore()
+	ms.SaveUser(&User{ID: 1, User: "test", Pass: "hash", Name: "test", Email: "test@test.com"})
+	s := NewServer(ms)
+	stream := FakeSaveUsersServer{
+		req: []*ptypes.UserRequest{
+			{
+				ID:         1,
+				Name:       "ignored",
+				UpdateMask: &field_mask.FieldMask{Paths: []string{"email"}},
+			},
+		},
+	}
+
+	if err := s.SaveUsers(&stream); err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := getUser(ms, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := User{ID: 1, User: "test", Pass: "hash", Name: "test"}
+	if !u.Equals(&exp) {
+		t.Errorf("Value expected: %v, got: %v", exp.Unredacted(), u.Unredacted())
+	}
+
+	stream = FakeSaveUsersServer{
+		req: []*ptypes.UserRequest{
+			{ID: 1, UpdateMask: &field_mask.FieldMask{Paths: []string{"bogus"}}},
+		},
+	}
+
+	err = s.SaveUsers(&stream)
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 400 {
+		t.Errorf("Error expected: 400, got: %v", err)
+	}
+}
+
+func TestServerSaveRolesPartial(t *testing.T) {
+	ms := newTestStore()
+	ms.SaveRole(&Role{Name: "parent"})
+	ms.SaveRole(&Role{Name: "child", ParentID: 1})
+	s := NewServer(ms)
+	stream := FakeSaveRolesServer{req: []*ptypes.RoleRequest{{ID: 2, Name: "renamed"}}}
+	if err := s.SaveRoles(&stream); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stream.res) != 1 || stream.res[0].ParentID != 1 {
+		t.Errorf("ParentID expected: 1, got: %v", stream.res)
+	}
+
+	stream = FakeSaveRolesServer{req: []*ptypes.RoleRequest{
+		{ID: 2, UpdateMask: &field_mask.FieldMask{Paths: []string{"parent_id"}}},
+	}}
+
+	if err := s.SaveRoles(&stream); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stream.res) != 1 || stream.res[0].ParentID != 0 || stream.res[0].Name != "renamed" {
+		t.Errorf("Expected cleared parent, got: %v", stream.res)
+	}
+}
+
+func TestServerAuthBatch(t *testing.T) {
+	s := NewServer(newTestStore())
+	res, err := s.AuthBatch(context.Background(), &ptypes.AuthBatchRequest{
+		Token: &ptypes.TokenRequest{Token: "test"},
+		Perms: []*ptypes.PermRequest{
+			{Service: "test", Name: "test"},
+			{ID: 1},
+			{Service: "test", Name: "other"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.User == nil || res.User.ID != 1 {
+		t.Fatalf("User expected: 1, got: %v", res.User)
+	}
+
+	exp := []bool{true, true, false}
+	if len(res.Decisions) != len(exp) {
+		t.Fatalf("Length expected: %v, got: %v", len(exp), len(res.Decisions))
+	}
+
+	for i, ok := range exp {
+		d := res.Decisions[i]
+		if d.Ok != ok || (d.Grant != nil) != ok {
+			t.Errorf("Decision %v expected: %v, got: %v", i, ok, d)
+		}
+	}
+
+	if len(res.Perms) != 1 || res.Perms[0].ID != 1 {
+		t.Errorf("Expected effective perm 1, got: %v", res.Perms)
+	}
+
+	res, err = s.AuthBatch(context.Background(), &ptypes.AuthBatchRequest{
+		Token: &ptypes.TokenRequest{Token: "wrong"},
+		Perms: []*ptypes.PermRequest{{Service: "test", Name: "test"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.User != nil || len(res.Decisions) != 0 {
+		t.Errorf("Expected empty response, got: %v", res)
+	}
+
+	_, err = s.AuthBatch(context.Background(), &ptypes.AuthBatchRequest{
+		Token: &ptypes.TokenRequest{Token: "test"},
+	})
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 400 {
+		t.Errorf("Error expected: 400, got: %v", err)
+	}
+}
+
+type FakeSaveRolePermsServer struct {
+	FakeServerStream
+	req []*ptypes.RolePermRequest
+	res []*ptypes.RolePermResponse
+}
+
+func (fk *FakeSaveRolePermsServer) Send(m *ptypes.RolePermResponse) error {
+	fk.res = append(fk.res, m)
+	return nil
+}
+
+func (fk *FakeSaveRolePermsServer) Recv() (*ptypes.RolePermRequest, error) {
+	if len(fk.req) == 0 {
+		return nil, io.EOF
+	}
+
+	m := fk.req[0]
+	fk.req = fk.req[1:]
+	return m, nil
+}
+
+type FakeSaveUserRolesServer struct {
+	FakeServerStream
+	req []*ptypes.UserRoleRequest
+	res []*ptypes.UserRoleResponse
+}
+
+func (fk *FakeSaveUserRolesServer) Send(m *ptypes.UserRoleResponse) error {
+	fk.res = append(fk.res, m)
+	return nil
+}
+
+func (fk *FakeSaveUserRolesServer) Recv() (*ptypes.UserRoleRequest, error) {
+	if len(fk.req) == 0 {
+		return nil, io.EOF
+	}
+
+	m := fk.req[0]
+	fk.req = fk.req[1:]
+	return m, nil
+}
+
+func TestServerRoleTenantScope(t *testing.T) {
+	ms := newTenantTestStore()
+	ms.SaveRole(&Role{Name: "admin"})
+	s := NewServer(ms)
+	ctx := NewUserContext(context.Background(), &User{ID: 2, TenantID: 2})
+	ss := FakeServerStream{ctx: ctx}
+	rs := FakeSaveRolesServer{FakeServerStream: ss, req: []*ptypes.RoleRequest{
+		{Name: "ops"},
+		{Name: "sub", ParentID: 1},
+	}}
+
+	if err := s.SaveRoles(&rs); err != ErrCrossTenant {
+		t.Errorf("Error expected: %v, got: %v", ErrCrossTenant, err)
+	}
+
+	if len(rs.res) != 1 || rs.res[0].TenantID != 2 {
+		t.Fatalf("Expected role in tenant 2, got: %v", rs.res)
+	}
+
+	roleID := rs.res[0].ID
+	cases := []struct {
+		userID int64
+		roleID int64
+		permID int64
+		err    int
+	}{
+		{2, roleID, 2, 0},
+		{1, roleID, 1, 403},
+		{2, 1, 1, 403},
+		{2, 9, 9, 404},
+	}
+
+	for _, c := range cases {
+		us := FakeSaveUserRolesServer{FakeServerStream: ss, req: []*ptypes.UserRoleRequest{
+			{UserID: c.userID, RoleID: c.roleID},
+		}}
+
+		ps := FakeSaveRolePermsServer{FakeServerStream: ss, req: []*ptypes.RolePermRequest{
+			{RoleID: c.roleID, PermID: c.permID},
+		}}
+
+		for _, err := range []error{s.SaveUserRoles(&us), s.SaveRolePerms(&ps)} {
+			if c.err == 0 && err != nil {
+				t.Errorf("Expected save for %v, got: %v", c, err)
+			}
+
+			if e, ok := err.(*dlib.Error); c.err != 0 && (!ok || e.Code != c.err) {
+				t.Errorf("Error expected: %v, got: %v for %v", c.err, err, c)
+			}
+		}
+
+		if c.err == 0 && (len(us.res) != 1 || us.res[0].TenantID != 2 ||
+			len(ps.res) != 1 || ps.res[0].TenantID != 2) {
+			t.Errorf("Expected assignments in tenant 2, got: %v, %v", us.res, ps.res)
+		}
+	}
+
+	ms.SaveLockout(&Lockout{User: "test", Failures: 5})
+	ms.SaveAPIKey(&APIKey{Name: "root", UserID: 1})
+	dels := []struct {
+		name string
+		del  func() (*ptypes.DeleteResponse, error)
+	}{
+		{"role", func() (*ptypes.DeleteResponse, error) {
+			return s.DeleteRoles(ctx, &ptypes.RoleRequest{ID: 1})
+		}},
+		{"lockout", func() (*ptypes.DeleteResponse, error) {
+			return s.Unlock(ctx, &ptypes.LockoutRequest{User: "test"})
+		}},
+		{"api key", func() (*ptypes.DeleteResponse, error) {
+			return s.RevokeAPIKeys(ctx, &ptypes.APIKeyRequest{UserID: 1})
+		}},
+		{"mfa", func() (*ptypes.DeleteResponse, error) {
+			return s.DisableMFA(ctx, &ptypes.UserRequest{ID: 1})
+		}},
+	}
+
+	for _, d := range dels {
+		res, err := d.del()
+		if err != nil || res.Num != 0 {
+			t.Errorf("Expected no %v deleted in tenant 2, got: %v, %v", d.name, res, err)
+		}
+	}
+
+	if n := len(ms.roles) + len(ms.lockouts) + len(ms.apiKeys); n != 4 {
+		t.Errorf("Records expected: 4, got: %v", n)
+	}
+}
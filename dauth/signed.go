@@ -0,0 +1,407 @@
+package dauth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dhaifley/dlib"
+	"golang.org/x/crypto/ed25519"
+)
+
+// Signing algorithms supported for signed tokens, named as in the JWT alg
+// header.
+const (
+	AlgEdDSA = "EdDSA"
+	AlgHS256 = "HS256"
+)
+
+// ErrInvalidToken is returned when a signed token is malformed, has an
+// invalid signature, was signed with an unknown or retired key, or has
+// expired.
+var ErrInvalidToken = &dlib.Error{Code: 401, Msg: "invalid token"}
+
+// SigningKey values are keys used to sign and verify tokens. Ed25519 keys
+// hold a private key for signing and a public key for verification; a key
+// with only a public key can verify but not sign. HMAC keys hold a shared
+// secret used for both. Tokens signed by a key stop verifying once the key
+// expires.
+type SigningKey struct {
+	ID      string
+	Alg     string
+	Private []byte
+	Public  []byte
+	Secret  []byte
+	Expires *time.Time
+}
+
+// newKeyID returns a random key ID.
+func newKeyID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// NewEd25519Key initializes and returns a pointer to a new signing key value
+// holding a newly generated Ed25519 key pair and a random key ID.
+func NewEd25519Key() (*SigningKey, error) {
+	id, err := newKeyID()
+	if err != nil {
+		return nil, err
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SigningKey{ID: id, Alg: AlgEdDSA, Private: priv, Public: pub}, nil
+}
+
+// NewHMACKey initializes and returns a pointer to a new signing key value
+// holding a newly generated 256 bit HMAC secret and a random key ID.
+func NewHMACKey() (*SigningKey, error) {
+	id, err := newKeyID()
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	return &SigningKey{ID: id, Alg: AlgHS256, Secret: b}, nil
+}
+
+// VerifyOnly returns a copy of the key without the material needed to sign.
+// For HMAC keys, which use the same secret for both, the copy is unchanged.
+func (k *SigningKey) VerifyOnly() *SigningKey {
+	b := *k
+	b.Private = nil
+	return &b
+}
+
+// sign returns the signature of a message.
+func (k *SigningKey) sign(msg []byte) ([]byte, error) {
+	switch {
+	case k.Alg == AlgEdDSA && len(k.Private) == ed25519.PrivateKeySize:
+		return ed25519.Sign(ed25519.PrivateKey(k.Private), msg), nil
+	case k.Alg == AlgHS256 && len(k.Secret) > 0:
+		h := hmac.New(sha256.New, k.Secret)
+		h.Write(msg)
+		return h.Sum(nil), nil
+	default:
+		return nil, dlib.NewError(500, "signing key can not sign: "+k.ID)
+	}
+}
+
+// verify tests whether a signature of a message is valid.
+func (k *SigningKey) verify(msg, sig []byte) bool {
+	switch {
+	case k.Alg == AlgEdDSA && len(k.Public) == ed25519.PublicKeySize:
+		return ed25519.Verify(ed25519.PublicKey(k.Public), msg, sig)
+	case k.Alg == AlgHS256 && len(k.Secret) > 0:
+		h := hmac.New(sha256.New, k.Secret)
+		h.Write(msg)
+		return hmac.Equal(h.Sum(nil), sig)
+	default:
+		return false
+	}
+}
+
+// KeySet values hold the keys used to sign and verify tokens. New tokens
+// are signed with the current key. Rotating the set replaces the current
+// key while retired keys continue to verify until they expire. KeySet
+// values are safe for concurrent use.
+type KeySet struct {
+	mu      sync.RWMutex
+	keys    map[string]*SigningKey
+	current string
+}
+
+// NewKeySet initializes and returns a pointer to a new key set value using
+// the provided key as its current signing key.
+func NewKeySet(k *SigningKey) *KeySet {
+	ks := KeySet{keys: map[string]*SigningKey{}}
+	if k != nil {
+		ks.keys[k.ID] = k
+		ks.current = k.ID
+	}
+
+	return &ks
+}
+
+// Add adds a key to the set without making it the current signing key,
+// such as a verify only key for tokens signed by another service.
+func (ks *KeySet) Add(k *SigningKey) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.keys[k.ID] = k
+}
+
+// Rotate makes a key the current signing key. The previous signing key is
+// retained for verification for the provided grace period, which should be
+// at least the lifetime of the tokens it signed.
+func (ks *KeySet) Rotate(k *SigningKey, grace time.Duration) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if old, ok := ks.keys[ks.current]; ok && old.Expires == nil {
+		exp := time.Now().Add(grace)
+		old.Expires = &exp
+	}
+
+	ks.keys[k.ID] = k
+	ks.current = k.ID
+}
+
+// Current returns the current signing key, or nil if there is none.
+func (ks *KeySet) Current() *SigningKey {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return ks.keys[ks.current]
+}
+
+// Get returns the unexpired key with the provided ID, or nil if there is
+// none.
+func (ks *KeySet) Get(id string) *SigningKey {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	k := ks.keys[id]
+	if k == nil || (k.Expires != nil && k.Expires.Before(time.Now())) {
+		return nil
+	}
+
+	return k
+}
+
+// Prune removes expired keys from the set.
+// It returns the number of keys removed.
+func (ks *KeySet) Prune() int {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	n := 0
+	now := time.Now()
+	for id, k := range ks.keys {
+		if id != ks.current && k.Expires != nil && k.Expires.Before(now) {
+			delete(ks.keys, id)
+			n++
+		}
+	}
+
+	return n
+}
+
+// Claims values are the contents of a signed token. Scope, if not empty,
+// limits the token to those of the permissions which it allows.
+type Claims struct {
+	Subject  string   `json:"sub"`
+	UserID   int64    `json:"uid"`
+	TenantID int64    `json:"tid,omitempty"`
+	Perms    []string `json:"perms,omitempty"`
+	Scope    []string `json:"scope,omitempty"`
+	Issued   int64    `json:"iat"`
+	Expires  int64    `json:"exp"`
+	AuthTime int64    `json:"auth_time,omitempty"`
+}
+
+// PermClaim returns the claim string for a permission.
+func PermClaim(service, name string) string {
+	return service + ":" + name
+}
+
+// HasPerm tests whether the claims include a permission which implies a
+// requested permission, and whether the scope, if any, allows it.
+func (c *Claims) HasPerm(service, name string) bool {
+	req := Perm{Service: service, Name: name}
+	if !scopeAllows(c.Scope, &req) {
+		return false
+	}
+
+	for _, pc := range c.Perms {
+		if p, ok := permFromClaim(pc); ok && p.Implies(&req) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ToToken returns a token value created from the claims and the signed
+// token string that carried them. The creation time of the token is the
+// auth time of a token restricted from another, and the issue time
+// otherwise.
+func (c *Claims) ToToken(token string) Token {
+	iat := time.Unix(c.Issued, 0)
+	if c.AuthTime != 0 {
+		iat = time.Unix(c.AuthTime, 0)
+	}
+
+	exp := time.Unix(c.Expires, 0)
+	t := NewToken(0, token, c.UserID, &iat, &exp)
+	t.Scope = c.Scope
+	t.TenantID = c.TenantID
+	return *t
+}
+
+// jwtHeader values are the header of a signed token.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+// IsSignedToken tests whether a token string is a signed token rather
+// than an opaque token.
+func IsSignedToken(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// TokenSigner values issue and verify signed, stateless tokens in JWT
+// format, which can be verified without a store.
+type TokenSigner struct {
+	Keys *KeySet
+	TTL  time.Duration
+}
+
+// NewTokenSigner initializes and returns a pointer to a new token signer
+// value. Signed tokens expire after the provided TTL.
+func NewTokenSigner(ks *KeySet, ttl time.Duration) *TokenSigner {
+	return &TokenSigner{Keys: ks, TTL: ttl}
+}
+
+// Sign returns a new token value for a user holding a signed token string
+// which carries the user ID and permissions as claims.
+func (ts *TokenSigner) Sign(userID int64, perms []Perm) (*Token, error) {
+	return ts.SignScoped(userID, SuperTenant, perms, nil)
+}
+
+// SignScoped returns a new signed token value for a user in a tenant
+// limited to a scope, which is carried as a claim along with the
+// permissions.
+func (ts *TokenSigner) SignScoped(userID, tenantID int64, perms []Perm,
+	scope []string) (*Token, error) {
+	ttl := ts.TTL
+	if ttl <= 0 {
+		ttl = DefaultTokenTTL
+	}
+
+	now := time.Now()
+	c := Claims{
+		Subject:  strconv.FormatInt(userID, 10),
+		UserID:   userID,
+		TenantID: tenantID,
+		Issued:   now.Unix(),
+		Expires:  now.Add(ttl).Unix(),
+		Scope:    scope,
+	}
+
+	return ts.sign(&c, perms)
+}
+
+// restrict returns a new signed token value for the user of a token,
+// limited to a scope and expiring at the provided time. The creation time
+// of the original token is carried as the auth time of the new one, so
+// that restricting a token repeatedly does not extend its lifetime.
+func (ts *TokenSigner) restrict(t *Token, perms []Perm, scope []string,
+	exp time.Time) (*Token, error) {
+	c := Claims{
+		Subject:  strconv.FormatInt(t.UserID, 10),
+		UserID:   t.UserID,
+		TenantID: t.TenantID,
+		Issued:   time.Now().Unix(),
+		Expires:  exp.Unix(),
+		AuthTime: t.Created.Unix(),
+		Scope:    scope,
+	}
+
+	return ts.sign(&c, perms)
+}
+
+// sign returns a new token value holding a signed token string which
+// carries the claims, with the permissions added to them.
+func (ts *TokenSigner) sign(c *Claims, perms []Perm) (*Token, error) {
+	k := ts.Keys.Current()
+	if k == nil {
+		return nil, dlib.NewError(500, "no signing key")
+	}
+
+	for _, p := range perms {
+		c.Perms = append(c.Perms, PermClaim(p.Service, p.Name))
+	}
+
+	hb, err := json.Marshal(jwtHeader{Alg: k.Alg, Typ: "JWT", Kid: k.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	cb, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := base64.RawURLEncoding.EncodeToString(hb) + "." +
+		base64.RawURLEncoding.EncodeToString(cb)
+	sig, err := k.sign([]byte(msg))
+	if err != nil {
+		return nil, err
+	}
+
+	t := c.ToToken(msg + "." + base64.RawURLEncoding.EncodeToString(sig))
+	return &t, nil
+}
+
+// Verify checks the signature and expiration of a signed token string.
+// It returns the claims carried by the token.
+func (ts *TokenSigner) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	hb, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	h := jwtHeader{}
+	if err := json.Unmarshal(hb, &h); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	k := ts.Keys.Get(h.Kid)
+	if k == nil || k.Alg != h.Alg {
+		return nil, ErrInvalidToken
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !k.verify([]byte(parts[0]+"."+parts[1]), sig) {
+		return nil, ErrInvalidToken
+	}
+
+	cb, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	c := Claims{}
+	if err := json.Unmarshal(cb, &c); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if time.Now().Unix() >= c.Expires {
+		return nil, ErrInvalidToken
+	}
+
+	return &c, nil
+}
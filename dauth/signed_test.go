@@ -0,0 +1,224 @@
+package dauth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenSignerSignVerify(t *testing.T) {
+	ek, err := NewEd25519Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hk, err := NewHMACKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, k := range []*SigningKey{ek, hk} {
+		ts := NewTokenSigner(NewKeySet(k), time.Minute)
+		tk, err := ts.Sign(1, []Perm{{Service: "test", Name: "read"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !IsSignedToken(tk.Token) {
+			t.Errorf("Expected signed token, got: %v", tk.Token)
+		}
+
+		if tk.UserID != 1 || tk.Expires == nil {
+			t.Errorf("Expected token for user 1, got: %v", tk)
+		}
+
+		c, err := ts.Verify(tk.Token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if c.UserID != 1 || c.Subject != "1" {
+			t.Errorf("UserID expected: 1, got: %v", c.UserID)
+		}
+
+		if !c.HasPerm("test", "read") || c.HasPerm("test", "write") {
+			t.Errorf("Expected perm test:read only, got: %v", c.Perms)
+		}
+
+		parts := strings.Split(tk.Token, ".")
+		cases := []string{
+			"test",
+			parts[0] + "." + parts[1] + ".",
+			parts[0] + "." + parts[0] + "." + parts[2],
+			tk.Token + "x",
+		}
+
+		for _, c := range cases {
+			if _, err := ts.Verify(c); err != ErrInvalidToken {
+				t.Errorf("Error expected: %v, got: %v", ErrInvalidToken, err)
+			}
+		}
+	}
+}
+
+func TestTokenSignerExpired(t *testing.T) {
+	k, _ := NewHMACKey()
+	ts := NewTokenSigner(NewKeySet(k), time.Nanosecond)
+	tk, _ := ts.Sign(1, nil)
+	if _, err := ts.Verify(tk.Token); err != ErrInvalidToken {
+		t.Errorf("Error expected: %v, got: %v", ErrInvalidToken, err)
+	}
+}
+
+func TestTokenSignerVerifyOnly(t *testing.T) {
+	k, _ := NewEd25519Key()
+	ts := NewTokenSigner(NewKeySet(k), time.Minute)
+	tk, _ := ts.Sign(1, nil)
+	vs := NewTokenSigner(NewKeySet(nil), time.Minute)
+	vs.Keys.Add(k.VerifyOnly())
+	if _, err := vs.Verify(tk.Token); err != nil {
+		t.Error(err)
+	}
+
+	vs.Keys.Rotate(k.VerifyOnly(), 0)
+	if _, err := vs.Sign(1, nil); err == nil {
+		t.Error("Expected error signing with verify only key")
+	}
+}
+
+func TestKeySetRotate(t *testing.T) {
+	k1, _ := NewEd25519Key()
+	k2, _ := NewEd25519Key()
+	k3, _ := NewHMACKey()
+	ks := NewKeySet(k1)
+	ts := NewTokenSigner(ks, time.Minute)
+	old, _ := ts.Sign(1, nil)
+	ks.Rotate(k2, time.Hour)
+	if ks.Current() != k2 {
+		t.Errorf("Key expected: %v, got: %v", k2.ID, ks.Current().ID)
+	}
+
+	if _, err := ts.Verify(old.Token); err != nil {
+		t.Errorf("Expected old key to verify, got: %v", err)
+	}
+
+	ks.Rotate(k3, -time.Second)
+	if _, err := ts.Verify(old.Token); err != nil {
+		t.Errorf("Expected old key to verify, got: %v", err)
+	}
+
+	tk, _ := ts.Sign(1, nil)
+	if _, err := ts.Verify(tk.Token); err != nil {
+		t.Error(err)
+	}
+
+	exp := time.Now().Add(-time.Second)
+	k1.Expires = &exp
+	if _, err := ts.Verify(old.Token); err != ErrInvalidToken {
+		t.Errorf("Error expected: %v, got: %v", ErrInvalidToken, err)
+	}
+
+	if n := ks.Prune(); n != 2 {
+		t.Errorf("Num expected: 2, got: %v", n)
+	}
+
+	if ks.Get(k3.ID) != k3 {
+		t.Errorf("Expected current key to remain")
+	}
+}
+
+func TestTokenIssuerSigned(t *testing.T) {
+	k, _ := NewEd25519Key()
+	ti := NewTokenIssuer(newTestStore(), time.Minute)
+	ti.Signer = NewTokenSigner(NewKeySet(k), time.Minute)
+	tk, err := ti.Issue(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, _ := ti.Signer.Verify(tk.Token)
+	if !c.HasPerm("test", "test") {
+		t.Errorf("Expected perm test:test, got: %v", c.Perms)
+	}
+
+	ft, err := ti.Find(tk.Token)
+	if err != nil || ft == nil || ft.UserID != 1 {
+		t.Errorf("Expected token for user 1, got: %v, %v", ft, err)
+	}
+
+	if ft, _ := ti.Find(tk.Token + "x"); ft != nil {
+		t.Errorf("Expected invalid token, got: %v", ft)
+	}
+
+	if _, err := ti.Logout(tk.Token); err == nil {
+		t.Error("Expected error revoking signed token")
+	}
+}
+
+func TestTokenIssuerRestrictSigned(t *testing.T) {
+	k, _ := NewEd25519Key()
+	ti := NewTokenIssuer(newTestStore(), time.Hour)
+	ti.Signer = NewTokenSigner(NewKeySet(k), time.Minute)
+	tk, err := ti.Issue(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ti.Signer.TTL = time.Hour
+	token := tk.Token
+	for i := 0; i < 3; i++ {
+		st, err := ti.Restrict(token, []string{"test:test"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if st.Expires.After(*tk.Expires) || !st.Created.Equal(*tk.Created) {
+			t.Errorf("Expected token within %v to %v, got: %v to %v", tk.Created,
+				tk.Expires, st.Created, st.Expires)
+		}
+
+		token = st.Token
+	}
+
+	ti.MaxLifetime = time.Second
+	st, err := ti.Restrict(tk.Token, []string{"test:test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if max := tk.Created.Add(time.Second); st.Expires.After(max) {
+		t.Errorf("Expires expected before: %v, got: %v", max, st.Expires)
+	}
+}
+
+func TestTokenSignerSignScoped(t *testing.T) {
+	k, _ := NewHMACKey()
+	ts := NewTokenSigner(NewKeySet(k), time.Minute)
+	perms := []Perm{{Service: "test", Name: "*"}}
+	tk, err := ts.SignScoped(1, 2, perms, []string{"test:read"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := ts.Verify(tk.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !c.HasPerm("test", "read") {
+		t.Errorf("Expected perm test:read, got: %v", c)
+	}
+
+	if c.HasPerm("test", "write") {
+		t.Errorf("Expected no perm test:write, got: %v", c)
+	}
+
+	ft := c.ToToken(tk.Token)
+	if len(ft.Scope) != 1 || ft.Scope[0] != "test:read" {
+		t.Errorf("Scope expected: [test:read], got: %v", ft.Scope)
+	}
+
+	if ft.TenantID != 2 {
+		t.Errorf("TenantID expected: 2, got: %v", ft.TenantID)
+	}
+}
@@ -0,0 +1,135 @@
+package dauth
+
+// SQLSchema contains the PostgreSQL statements creating the tables and
+// indexes used by SQLStore. Every statement may be run against an existing
+// database, adding only the tables, columns and indexes which are missing.
+// The unique indexes ensure that token and API key hashes identify a single
+// row, that user names are unique within each tenant, and that each user
+// has at most one MFA enrollment.
+const SQLSchema = `
+CREATE TABLE IF NOT EXISTS "user" (
+	id BIGSERIAL PRIMARY KEY,
+	"user" TEXT NOT NULL,
+	pass TEXT NOT NULL DEFAULT '',
+	name TEXT,
+	email TEXT
+);
+
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS tenant_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS provider TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS user_tenant_user_idx ON "user" (tenant_id, "user");
+
+CREATE TABLE IF NOT EXISTS token (
+	id BIGSERIAL PRIMARY KEY,
+	token TEXT NOT NULL,
+	user_id BIGINT NOT NULL,
+	created TIMESTAMPTZ,
+	expires TIMESTAMPTZ
+);
+
+ALTER TABLE token ADD COLUMN IF NOT EXISTS scope TEXT NOT NULL DEFAULT '';
+ALTER TABLE token ADD COLUMN IF NOT EXISTS tenant_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE token ADD COLUMN IF NOT EXISTS actor_id BIGINT;
+CREATE UNIQUE INDEX IF NOT EXISTS token_token_idx ON token (token);
+CREATE INDEX IF NOT EXISTS token_user_id_idx ON token (user_id);
+CREATE INDEX IF NOT EXISTS token_expires_idx ON token (expires);
+
+CREATE TABLE IF NOT EXISTS perm (
+	id BIGSERIAL PRIMARY KEY,
+	service TEXT NOT NULL,
+	name TEXT NOT NULL
+);
+
+ALTER TABLE perm ADD COLUMN IF NOT EXISTS tenant_id BIGINT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS perm_service_name_idx ON perm (service, name);
+
+CREATE TABLE IF NOT EXISTS user_perm (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL,
+	perm_id BIGINT NOT NULL
+);
+
+ALTER TABLE user_perm ADD COLUMN IF NOT EXISTS tenant_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE user_perm ADD COLUMN IF NOT EXISTS expires TIMESTAMPTZ;
+ALTER TABLE user_perm ADD COLUMN IF NOT EXISTS hours TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_perm ADD COLUMN IF NOT EXISTS days TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_perm ADD COLUMN IF NOT EXISTS zone TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_perm ADD COLUMN IF NOT EXISTS cidrs TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS user_perm_user_id_idx ON user_perm (user_id);
+
+CREATE TABLE IF NOT EXISTS role (
+	id BIGSERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	parent_id BIGINT,
+	tenant_id BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS role_perm (
+	id BIGSERIAL PRIMARY KEY,
+	role_id BIGINT NOT NULL,
+	perm_id BIGINT NOT NULL,
+	tenant_id BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS role_perm_role_id_idx ON role_perm (role_id);
+
+CREATE TABLE IF NOT EXISTS user_role (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL,
+	role_id BIGINT NOT NULL,
+	tenant_id BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS user_role_user_id_idx ON user_role (user_id);
+
+CREATE TABLE IF NOT EXISTS lockout (
+	id BIGSERIAL PRIMARY KEY,
+	"user" TEXT NOT NULL,
+	addr TEXT NOT NULL DEFAULT '',
+	failures BIGINT NOT NULL DEFAULT 0,
+	last TIMESTAMPTZ,
+	until TIMESTAMPTZ,
+	tenant_id BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS lockout_user_idx ON lockout ("user");
+
+CREATE TABLE IF NOT EXISTS user_mfa (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL,
+	secret TEXT NOT NULL,
+	enabled BOOLEAN NOT NULL DEFAULT FALSE,
+	last_step BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_mfa_user_id_idx ON user_mfa (user_id);
+
+CREATE TABLE IF NOT EXISTS recovery_code (
+	id BIGSERIAL PRIMARY KEY,
+	user_id BIGINT NOT NULL,
+	code TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS recovery_code_user_id_idx ON recovery_code (user_id);
+
+CREATE TABLE IF NOT EXISTS api_key (
+	id BIGSERIAL PRIMARY KEY,
+	name TEXT NOT NULL DEFAULT '',
+	prefix TEXT NOT NULL,
+	key TEXT NOT NULL,
+	user_id BIGINT NOT NULL,
+	scope TEXT NOT NULL DEFAULT '',
+	created TIMESTAMPTZ,
+	expires TIMESTAMPTZ,
+	tenant_id BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS api_key_key_idx ON api_key (key);
+CREATE INDEX IF NOT EXISTS api_key_prefix_idx ON api_key (prefix);
+`
+
+// CreateSchema creates the tables and indexes described by SQLSchema.
+func (ss *SQLStore) CreateSchema() error {
+	_, err := ss.DB.Exec(SQLSchema)
+	return err
+}
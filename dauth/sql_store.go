@@ -0,0 +1,1372 @@
+package dauth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dhaifley/dlib"
+)
+
+// SQLStore values implement the Store interface using a SQL database.
+type SQLStore struct {
+	DB  dlib.SQLExecutor
+	ctx context.Context
+}
+
+// NewSQLStore initializes and returns a pointer to a new SQL store value.
+func NewSQLStore(db dlib.SQLExecutor) *SQLStore {
+	return &SQLStore{DB: db}
+}
+
+// WithContext returns a copy of the store whose find operations stop,
+// closing their rows and Result channel, once ctx is done. Consumers which
+// may stop receiving results early should use it to release their queries.
+func (ss *SQLStore) WithContext(ctx context.Context) Store {
+	cp := *ss
+	cp.ctx = ctx
+	return &cp
+}
+
+// send sends a result to a Result channel. It returns false without
+// sending if the context of the store is done first.
+func (ss *SQLStore) send(c chan<- dlib.Result, r dlib.Result) bool {
+	var done <-chan struct{}
+	if ss.ctx != nil {
+		done = ss.ctx.Done()
+	}
+
+	select {
+	case c <- r:
+		return true
+	case <-done:
+		return false
+	}
+}
+
+// sqlWhere values accumulate the conditions and arguments of a SQL
+// WHERE clause using numbered placeholders.
+type sqlWhere struct {
+	conds []string
+	args  []interface{}
+}
+
+// add appends a condition, formatted with the placeholder number of arg.
+func (w *sqlWhere) add(cond string, arg interface{}) {
+	w.args = append(w.args, arg)
+	w.conds = append(w.conds, fmt.Sprintf(cond, len(w.args)))
+}
+
+// in appends a condition matching a column to any of a list of IDs. An
+// empty list matches no rows.
+func (w *sqlWhere) in(col string, ids []int64) {
+	if len(ids) == 0 {
+		w.conds = append(w.conds, "FALSE")
+		return
+	}
+
+	ph := make([]string, len(ids))
+	for i, id := range ids {
+		w.args = append(w.args, id)
+		ph[i] = fmt.Sprintf("$%d", len(w.args))
+	}
+
+	w.conds = append(w.conds, col+" IN ("+strings.Join(ph, ", ")+")")
+}
+
+// String formats the accumulated conditions as a WHERE clause.
+func (w *sqlWhere) String() string {
+	if len(w.conds) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(w.conds, " AND ")
+}
+
+// exec executes a SQL statement and returns the number of rows affected.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) exec(query string, args ...interface{}) <-chan dlib.Result {
+	c := make(chan dlib.Result, 1)
+
+	go func() {
+		defer close(c)
+		res, err := ss.DB.Exec(query, args...)
+		if err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		c <- dlib.Result{Num: int(n)}
+	}()
+
+	return c
+}
+
+// insert executes a SQL insert statement returning the new row ID.
+func (ss *SQLStore) insert(query string, args ...interface{}) (int64, error) {
+	rows, err := ss.DB.Query(query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+	var id int64
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+
+		return 0, dlib.NewError(500, "insert returned no id")
+	}
+
+	if err := rows.Scan(&id); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// tokenWhere builds a WHERE clause from a token find value.
+func tokenWhere(f *TokenFind) *sqlWhere {
+	w := sqlWhere{}
+	if f.ID != nil {
+		w.add("id = $%d", *f.ID)
+	}
+
+	if f.Token != nil {
+		w.add("token = $%d", *f.Token)
+	}
+
+	if f.UserID != nil {
+		w.add("user_id = $%d", *f.UserID)
+	}
+
+	if f.Created != nil {
+		w.add("created = $%d", *f.Created)
+	}
+
+	if f.Expires != nil {
+		w.add("expires = $%d", *f.Expires)
+	}
+
+	if f.Start != nil {
+		w.add("created >= $%d", *f.Start)
+	}
+
+	if f.End != nil {
+		w.add("created <= $%d", *f.End)
+	}
+
+	if f.Old != nil {
+		w.add("created < $%d", *f.Old)
+	}
+
+	if f.Expired != nil {
+		w.add("expires < $%d", *f.Expired)
+	}
+
+	if f.TenantID != nil {
+		w.add("tenant_id = $%d", *f.TenantID)
+	}
+
+	if f.ActorID != nil {
+		w.add("actor_id = $%d", *f.ActorID)
+	}
+
+	return &w
+}
+
+// GetTokens finds tokens in the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) GetTokens(f *TokenFind) <-chan dlib.Result {
+	c := make(chan dlib.Result, 256)
+
+	go func() {
+		defer close(c)
+		w := tokenWhere(f)
+		order := f.Page.sql(w)
+		rows, err := ss.DB.Query("SELECT id, token, user_id, created, expires, "+
+			"scope, tenant_id, actor_id FROM token"+w.String()+order, w.args...)
+		if err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		defer rows.Close()
+		for rows.Next() {
+			tr := TokenRow{}
+			err := rows.Scan(&tr.ID, &tr.Token, &tr.UserID, &tr.Created, &tr.Expires,
+				&tr.Scope, &tr.TenantID, &tr.ActorID)
+			if err != nil {
+				ss.send(c, dlib.Result{Err: err})
+				return
+			}
+
+			t := tr.ToToken()
+			if !ss.send(c, dlib.Result{Val: &t}) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			ss.send(c, dlib.Result{Err: err})
+		}
+	}()
+
+	return c
+}
+
+// SaveToken inserts or updates a token in the database. A token with no ID
+// is inserted and receives the ID assigned by the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) SaveToken(t *Token) <-chan dlib.Result {
+	c := make(chan dlib.Result, 1)
+
+	go func() {
+		defer close(c)
+		tr := TokenRow{}
+		if err := tr.FromToken(t); err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		if tr.ID == 0 {
+			id, err := ss.insert("INSERT INTO token (token, user_id, created, expires, "+
+				"scope, tenant_id, actor_id) VALUES ($1, $2, $3, $4, $5, $6, $7) "+
+				"RETURNING id", tr.Token, tr.UserID, tr.Created, tr.Expires, tr.Scope,
+				tr.TenantID, tr.ActorID)
+			if err != nil {
+				c <- dlib.Result{Err: err}
+				return
+			}
+
+			t.ID = id
+			c <- dlib.Result{Val: t, Num: 1}
+			return
+		}
+
+		r := ss.update("UPDATE token SET token = $2, user_id = $3, created = $4, "+
+			"expires = $5, scope = $6, tenant_id = $7, actor_id = $8 WHERE id = $1",
+			tr.ID, tr.Token, tr.UserID, tr.Created, tr.Expires, tr.Scope, tr.TenantID,
+			tr.ActorID)
+		r.Val = t
+		c <- r
+	}()
+
+	return c
+}
+
+// DeleteTokens deletes tokens from the database. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) DeleteTokens(f *TokenFind) <-chan dlib.Result {
+	return ss.deleteWhere("token", tokenWhere(f))
+}
+
+// update executes a SQL statement updating a single row by ID. A 404 error
+// is returned if no row has the ID.
+func (ss *SQLStore) update(query string, args ...interface{}) dlib.Result {
+	r := <-ss.exec(query, args...)
+	if r.Err == nil && r.Num == 0 {
+		r.Err = errNotFound
+	}
+
+	return r
+}
+
+// deleteWhere deletes the rows of a table matching a WHERE clause, refusing
+// to delete every row when no conditions are provided.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) deleteWhere(table string, w *sqlWhere) <-chan dlib.Result {
+	if len(w.conds) == 0 {
+		return errorResult(errNoCriteria)
+	}
+
+	return ss.exec("DELETE FROM "+table+w.String(), w.args...)
+}
+
+// GetUsers finds users in the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) GetUsers(f *UserFind) <-chan dlib.Result {
+	c := make(chan dlib.Result, 256)
+
+	go func() {
+		defer close(c)
+		w := userWhere(f)
+		order := f.Page.sql(w)
+		rows, err := ss.DB.Query(`SELECT id, "user", pass, name, email, tenant_id, `+
+			`provider FROM "user"`+w.String()+order, w.args...)
+		if err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		defer rows.Close()
+		for rows.Next() {
+			ur := UserRow{}
+			err := rows.Scan(&ur.ID, &ur.User, &ur.Pass, &ur.Name, &ur.Email,
+				&ur.TenantID, &ur.Provider)
+			if err != nil {
+				ss.send(c, dlib.Result{Err: err})
+				return
+			}
+
+			u := ur.ToUser()
+			if !ss.send(c, dlib.Result{Val: &u}) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			ss.send(c, dlib.Result{Err: err})
+		}
+	}()
+
+	return c
+}
+
+// userWhere builds a WHERE clause from a user find value.
+func userWhere(f *UserFind) *sqlWhere {
+	w := sqlWhere{}
+	if f.ID != nil {
+		w.add("id = $%d", *f.ID)
+	}
+
+	if f.User != nil {
+		w.add(`"user" = $%d`, *f.User)
+	}
+
+	if f.Pass != nil {
+		w.add("pass = $%d", *f.Pass)
+	}
+
+	if f.Name != nil {
+		w.add("name = $%d", *f.Name)
+	}
+
+	if f.Email != nil {
+		w.add("email = $%d", *f.Email)
+	}
+
+	if f.TenantID != nil {
+		w.add("tenant_id = $%d", *f.TenantID)
+	}
+
+	if f.Provider != nil {
+		w.add("provider = $%d", *f.Provider)
+	}
+
+	return &w
+}
+
+// SaveUser inserts or updates a user in the database. A user with no ID
+// is inserted and receives the ID assigned by the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) SaveUser(u *User) <-chan dlib.Result {
+	c := make(chan dlib.Result, 1)
+
+	go func() {
+		defer close(c)
+		ur := UserRow{}
+		if err := ur.FromUser(u); err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		if ur.ID == 0 {
+			id, err := ss.insert(`INSERT INTO "user" ("user", pass, name, email, `+
+				"tenant_id, provider) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id",
+				ur.User, ur.Pass, ur.Name, ur.Email, ur.TenantID, ur.Provider)
+			if err != nil {
+				c <- dlib.Result{Err: err}
+				return
+			}
+
+			u.ID = id
+			c <- dlib.Result{Val: u, Num: 1}
+			return
+		}
+
+		r := ss.update(`UPDATE "user" SET "user" = $2, pass = $3, name = $4, `+
+			"email = $5, tenant_id = $6, provider = $7 WHERE id = $1",
+			ur.ID, ur.User, ur.Pass, ur.Name, ur.Email, ur.TenantID, ur.Provider)
+		r.Val = u
+		c <- r
+	}()
+
+	return c
+}
+
+// DeleteUsers deletes users from the database. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) DeleteUsers(f *UserFind) <-chan dlib.Result {
+	return ss.deleteWhere(`"user"`, userWhere(f))
+}
+
+// permWhere builds a WHERE clause from a perm find value.
+func permWhere(f *PermFind) *sqlWhere {
+	w := sqlWhere{}
+	if f.ID != nil {
+		w.add("id = $%d", *f.ID)
+	}
+
+	if f.IDs != nil {
+		w.in("id", f.IDs)
+	}
+
+	if f.Service != nil {
+		w.add("service = $%d", *f.Service)
+	}
+
+	if f.Name != nil {
+		w.add("name = $%d", *f.Name)
+	}
+
+	if f.TenantID != nil {
+		w.add("tenant_id = $%d", *f.TenantID)
+	}
+
+	return &w
+}
+
+// GetPerms finds permissions in the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) GetPerms(f *PermFind) <-chan dlib.Result {
+	c := make(chan dlib.Result, 256)
+
+	go func() {
+		defer close(c)
+		w := permWhere(f)
+		order := f.Page.sql(w)
+		rows, err := ss.DB.Query("SELECT id, service, name, tenant_id FROM perm"+
+			w.String()+order, w.args...)
+		if err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		defer rows.Close()
+		for rows.Next() {
+			pr := PermRow{}
+			err := rows.Scan(&pr.ID, &pr.Service, &pr.Name, &pr.TenantID)
+			if err != nil {
+				ss.send(c, dlib.Result{Err: err})
+				return
+			}
+
+			p := pr.ToPerm()
+			if !ss.send(c, dlib.Result{Val: &p}) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			ss.send(c, dlib.Result{Err: err})
+		}
+	}()
+
+	return c
+}
+
+// SavePerm inserts or updates a permission in the database. A permission
+// with no ID is inserted and receives the ID assigned by the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) SavePerm(p *Perm) <-chan dlib.Result {
+	c := make(chan dlib.Result, 1)
+
+	go func() {
+		defer close(c)
+		pr := PermRow{}
+		if err := pr.FromPerm(p); err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		if pr.ID == 0 {
+			id, err := ss.insert("INSERT INTO perm (service, name, tenant_id) "+
+				"VALUES ($1, $2, $3) RETURNING id", pr.Service, pr.Name, pr.TenantID)
+			if err != nil {
+				c <- dlib.Result{Err: err}
+				return
+			}
+
+			p.ID = id
+			c <- dlib.Result{Val: p, Num: 1}
+			return
+		}
+
+		r := ss.update("UPDATE perm SET service = $2, name = $3, tenant_id = $4 "+
+			"WHERE id = $1", pr.ID, pr.Service, pr.Name, pr.TenantID)
+		r.Val = p
+		c <- r
+	}()
+
+	return c
+}
+
+// DeletePerms deletes permissions from the database. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) DeletePerms(f *PermFind) <-chan dlib.Result {
+	return ss.deleteWhere("perm", permWhere(f))
+}
+
+// userPermWhere builds a WHERE clause from a user_perm find value.
+func userPermWhere(f *UserPermFind) *sqlWhere {
+	w := sqlWhere{}
+	if f.ID != nil {
+		w.add("id = $%d", *f.ID)
+	}
+
+	if f.UserID != nil {
+		w.add("user_id = $%d", *f.UserID)
+	}
+
+	if f.PermID != nil {
+		w.add("perm_id = $%d", *f.PermID)
+	}
+
+	if f.TenantID != nil {
+		w.add("tenant_id = $%d", *f.TenantID)
+	}
+
+	if f.Expired != nil {
+		w.add("expires < $%d", *f.Expired)
+	}
+
+	return &w
+}
+
+// GetUserPerms finds user permissions in the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) GetUserPerms(f *UserPermFind) <-chan dlib.Result {
+	c := make(chan dlib.Result, 256)
+
+	go func() {
+		defer close(c)
+		w := userPermWhere(f)
+		order := f.Page.sql(w)
+		rows, err := ss.DB.Query("SELECT id, user_id, perm_id, tenant_id, expires, hours, "+
+			"days, zone, cidrs FROM user_perm"+
+			w.String()+order, w.args...)
+		if err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		defer rows.Close()
+		for rows.Next() {
+			upr := UserPermRow{}
+			err := rows.Scan(&upr.ID, &upr.UserID, &upr.PermID, &upr.TenantID,
+				&upr.Expires, &upr.Hours, &upr.Days, &upr.Zone, &upr.CIDRs)
+			if err != nil {
+				ss.send(c, dlib.Result{Err: err})
+				return
+			}
+
+			up := upr.ToUserPerm()
+			if !ss.send(c, dlib.Result{Val: &up}) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			ss.send(c, dlib.Result{Err: err})
+		}
+	}()
+
+	return c
+}
+
+// SaveUserPerm inserts or updates a user permission in the database. A
+// user permission with no ID is inserted and receives the ID assigned by
+// the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) SaveUserPerm(up *UserPerm) <-chan dlib.Result {
+	c := make(chan dlib.Result, 1)
+
+	go func() {
+		defer close(c)
+		upr := UserPermRow{}
+		if err := upr.FromUserPerm(up); err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		if upr.ID == 0 {
+			id, err := ss.insert("INSERT INTO user_perm (user_id, perm_id, tenant_id, "+
+				"expires, hours, days, zone, cidrs) "+
+				"VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id",
+				upr.UserID, upr.PermID, upr.TenantID, upr.Expires, upr.Hours, upr.Days,
+				upr.Zone, upr.CIDRs)
+			if err != nil {
+				c <- dlib.Result{Err: err}
+				return
+			}
+
+			up.ID = id
+			c <- dlib.Result{Val: up, Num: 1}
+			return
+		}
+
+		r := ss.update("UPDATE user_perm SET user_id = $2, perm_id = $3, "+
+			"tenant_id = $4, expires = $5, hours = $6, days = $7, zone = $8, "+
+			"cidrs = $9 WHERE id = $1",
+			upr.ID, upr.UserID, upr.PermID, upr.TenantID, upr.Expires, upr.Hours,
+			upr.Days, upr.Zone, upr.CIDRs)
+		r.Val = up
+		c <- r
+	}()
+
+	return c
+}
+
+// DeleteUserPerms deletes user permissions from the database. At least one
+// search criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) DeleteUserPerms(f *UserPermFind) <-chan dlib.Result {
+	return ss.deleteWhere("user_perm", userPermWhere(f))
+}
+
+// roleWhere builds a WHERE clause from a role find value.
+func roleWhere(f *RoleFind) *sqlWhere {
+	w := sqlWhere{}
+	if f.ID != nil {
+		w.add("id = $%d", *f.ID)
+	}
+
+	if f.Name != nil {
+		w.add("name = $%d", *f.Name)
+	}
+
+	if f.ParentID != nil {
+		w.add("parent_id = $%d", *f.ParentID)
+	}
+
+	if f.TenantID != nil {
+		w.add("tenant_id = $%d", *f.TenantID)
+	}
+
+	return &w
+}
+
+// GetRoles finds roles in the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) GetRoles(f *RoleFind) <-chan dlib.Result {
+	c := make(chan dlib.Result, 256)
+
+	go func() {
+		defer close(c)
+		w := roleWhere(f)
+		rows, err := ss.DB.Query("SELECT id, name, parent_id, tenant_id FROM role"+
+			w.String()+" ORDER BY id", w.args...)
+		if err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		defer rows.Close()
+		for rows.Next() {
+			rr := RoleRow{}
+			if err := rows.Scan(&rr.ID, &rr.Name, &rr.ParentID, &rr.TenantID); err != nil {
+				ss.send(c, dlib.Result{Err: err})
+				return
+			}
+
+			ro := rr.ToRole()
+			if !ss.send(c, dlib.Result{Val: &ro}) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			ss.send(c, dlib.Result{Err: err})
+		}
+	}()
+
+	return c
+}
+
+// SaveRole inserts or updates a role in the database. A role with no
+// ID is inserted and receives the ID assigned by the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) SaveRole(ro *Role) <-chan dlib.Result {
+	c := make(chan dlib.Result, 1)
+
+	go func() {
+		defer close(c)
+		rr := RoleRow{}
+		if err := rr.FromRole(ro); err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		if rr.ID == 0 {
+			id, err := ss.insert("INSERT INTO role (name, parent_id, tenant_id) "+
+				"VALUES ($1, $2, $3) RETURNING id", rr.Name, rr.ParentID, rr.TenantID)
+			if err != nil {
+				c <- dlib.Result{Err: err}
+				return
+			}
+
+			ro.ID = id
+			c <- dlib.Result{Val: ro, Num: 1}
+			return
+		}
+
+		r := ss.update("UPDATE role SET name = $2, parent_id = $3, tenant_id = $4 "+
+			"WHERE id = $1", rr.ID, rr.Name, rr.ParentID, rr.TenantID)
+		r.Val = ro
+		c <- r
+	}()
+
+	return c
+}
+
+// DeleteRoles deletes roles from the database. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) DeleteRoles(f *RoleFind) <-chan dlib.Result {
+	return ss.deleteWhere("role", roleWhere(f))
+}
+
+// rolePermWhere builds a WHERE clause from a role_perm find value.
+func rolePermWhere(f *RolePermFind) *sqlWhere {
+	w := sqlWhere{}
+	if f.ID != nil {
+		w.add("id = $%d", *f.ID)
+	}
+
+	if f.RoleID != nil {
+		w.add("role_id = $%d", *f.RoleID)
+	}
+
+	if f.RoleIDs != nil {
+		w.in("role_id", f.RoleIDs)
+	}
+
+	if f.PermID != nil {
+		w.add("perm_id = $%d", *f.PermID)
+	}
+
+	if f.TenantID != nil {
+		w.add("tenant_id = $%d", *f.TenantID)
+	}
+
+	return &w
+}
+
+// GetRolePerms finds role permissions in the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) GetRolePerms(f *RolePermFind) <-chan dlib.Result {
+	c := make(chan dlib.Result, 256)
+
+	go func() {
+		defer close(c)
+		w := rolePermWhere(f)
+		rows, err := ss.DB.Query("SELECT id, role_id, perm_id, tenant_id FROM role_perm"+
+			w.String()+" ORDER BY id", w.args...)
+		if err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		defer rows.Close()
+		for rows.Next() {
+			rpr := RolePermRow{}
+			if err := rows.Scan(&rpr.ID, &rpr.RoleID, &rpr.PermID, &rpr.TenantID); err != nil {
+				ss.send(c, dlib.Result{Err: err})
+				return
+			}
+
+			rp := rpr.ToRolePerm()
+			if !ss.send(c, dlib.Result{Val: &rp}) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			ss.send(c, dlib.Result{Err: err})
+		}
+	}()
+
+	return c
+}
+
+// SaveRolePerm inserts or updates a role permission in the database. A
+// role permission with no ID is inserted and receives the ID assigned by
+// the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) SaveRolePerm(rp *RolePerm) <-chan dlib.Result {
+	c := make(chan dlib.Result, 1)
+
+	go func() {
+		defer close(c)
+		rpr := RolePermRow{}
+		if err := rpr.FromRolePerm(rp); err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		if rpr.ID == 0 {
+			id, err := ss.insert("INSERT INTO role_perm (role_id, perm_id, tenant_id) "+
+				"VALUES ($1, $2, $3) RETURNING id", rpr.RoleID, rpr.PermID, rpr.TenantID)
+			if err != nil {
+				c <- dlib.Result{Err: err}
+				return
+			}
+
+			rp.ID = id
+			c <- dlib.Result{Val: rp, Num: 1}
+			return
+		}
+
+		r := ss.update("UPDATE role_perm SET role_id = $2, perm_id = $3, "+
+			"tenant_id = $4 WHERE id = $1", rpr.ID, rpr.RoleID, rpr.PermID, rpr.TenantID)
+		r.Val = rp
+		c <- r
+	}()
+
+	return c
+}
+
+// DeleteRolePerms deletes role permissions from the database. At least one
+// search criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) DeleteRolePerms(f *RolePermFind) <-chan dlib.Result {
+	return ss.deleteWhere("role_perm", rolePermWhere(f))
+}
+
+// userRoleWhere builds a WHERE clause from a user_role find value.
+func userRoleWhere(f *UserRoleFind) *sqlWhere {
+	w := sqlWhere{}
+	if f.ID != nil {
+		w.add("id = $%d", *f.ID)
+	}
+
+	if f.UserID != nil {
+		w.add("user_id = $%d", *f.UserID)
+	}
+
+	if f.RoleID != nil {
+		w.add("role_id = $%d", *f.RoleID)
+	}
+
+	if f.TenantID != nil {
+		w.add("tenant_id = $%d", *f.TenantID)
+	}
+
+	return &w
+}
+
+// GetUserRoles finds user roles in the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) GetUserRoles(f *UserRoleFind) <-chan dlib.Result {
+	c := make(chan dlib.Result, 256)
+
+	go func() {
+		defer close(c)
+		w := userRoleWhere(f)
+		rows, err := ss.DB.Query("SELECT id, user_id, role_id, tenant_id FROM user_role"+
+			w.String()+" ORDER BY id", w.args...)
+		if err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		defer rows.Close()
+		for rows.Next() {
+			urr := UserRoleRow{}
+			if err := rows.Scan(&urr.ID, &urr.UserID, &urr.RoleID, &urr.TenantID); err != nil {
+				ss.send(c, dlib.Result{Err: err})
+				return
+			}
+
+			ur := urr.ToUserRole()
+			if !ss.send(c, dlib.Result{Val: &ur}) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			ss.send(c, dlib.Result{Err: err})
+		}
+	}()
+
+	return c
+}
+
+// SaveUserRole inserts or updates a user role in the database. A
+// user role with no ID is inserted and receives the ID assigned by
+// the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) SaveUserRole(ur *UserRole) <-chan dlib.Result {
+	c := make(chan dlib.Result, 1)
+
+	go func() {
+		defer close(c)
+		urr := UserRoleRow{}
+		if err := urr.FromUserRole(ur); err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		if urr.ID == 0 {
+			id, err := ss.insert("INSERT INTO user_role (user_id, role_id, tenant_id) "+
+				"VALUES ($1, $2, $3) RETURNING id", urr.UserID, urr.RoleID, urr.TenantID)
+			if err != nil {
+				c <- dlib.Result{Err: err}
+				return
+			}
+
+			ur.ID = id
+			c <- dlib.Result{Val: ur, Num: 1}
+			return
+		}
+
+		r := ss.update("UPDATE user_role SET user_id = $2, role_id = $3, "+
+			"tenant_id = $4 WHERE id = $1", urr.ID, urr.UserID, urr.RoleID, urr.TenantID)
+		r.Val = ur
+		c <- r
+	}()
+
+	return c
+}
+
+// DeleteUserRoles deletes user roles from the database. At least one
+// search criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) DeleteUserRoles(f *UserRoleFind) <-chan dlib.Result {
+	return ss.deleteWhere("user_role", userRoleWhere(f))
+}
+
+// lockoutWhere builds a WHERE clause from a lockout find value.
+func lockoutWhere(f *LockoutFind) *sqlWhere {
+	w := sqlWhere{}
+	if f.ID != nil {
+		w.add("id = $%d", *f.ID)
+	}
+
+	if f.User != nil {
+		w.add("\"user\" = $%d", *f.User)
+	}
+
+	if f.Addr != nil {
+		w.add("addr = $%d", *f.Addr)
+	}
+
+	if f.TenantID != nil {
+		w.add("tenant_id = $%d", *f.TenantID)
+	}
+
+	if f.Expired != nil {
+		w.add("until < $%d", *f.Expired)
+	}
+
+	return &w
+}
+
+// GetLockouts finds lockouts in the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) GetLockouts(f *LockoutFind) <-chan dlib.Result {
+	c := make(chan dlib.Result, 256)
+
+	go func() {
+		defer close(c)
+		w := lockoutWhere(f)
+		rows, err := ss.DB.Query("SELECT id, \"user\", addr, failures, last, until, "+
+			"tenant_id FROM lockout"+w.String()+" ORDER BY id", w.args...)
+		if err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		defer rows.Close()
+		for rows.Next() {
+			lr := LockoutRow{}
+			if err := rows.Scan(&lr.ID, &lr.User, &lr.Addr, &lr.Failures,
+				&lr.Last, &lr.Until, &lr.TenantID); err != nil {
+				ss.send(c, dlib.Result{Err: err})
+				return
+			}
+
+			l := lr.ToLockout()
+			if !ss.send(c, dlib.Result{Val: &l}) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			ss.send(c, dlib.Result{Err: err})
+		}
+	}()
+
+	return c
+}
+
+// SaveLockout inserts or updates a lockout in the database. A lockout with
+// no ID is inserted and receives the ID assigned by the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) SaveLockout(l *Lockout) <-chan dlib.Result {
+	c := make(chan dlib.Result, 1)
+
+	go func() {
+		defer close(c)
+		lr := LockoutRow{}
+		if err := lr.FromLockout(l); err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		if lr.ID == 0 {
+			id, err := ss.insert("INSERT INTO lockout (\"user\", addr, failures, last, until, "+
+				"tenant_id) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id",
+				lr.User, lr.Addr, lr.Failures, lr.Last, lr.Until, lr.TenantID)
+			if err != nil {
+				c <- dlib.Result{Err: err}
+				return
+			}
+
+			l.ID = id
+			c <- dlib.Result{Val: l, Num: 1}
+			return
+		}
+
+		r := ss.update("UPDATE lockout SET \"user\" = $2, addr = $3, failures = $4, "+
+			"last = $5, until = $6, tenant_id = $7 WHERE id = $1",
+			lr.ID, lr.User, lr.Addr, lr.Failures, lr.Last, lr.Until, lr.TenantID)
+		r.Val = l
+		c <- r
+	}()
+
+	return c
+}
+
+// DeleteLockouts deletes lockouts from the database. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) DeleteLockouts(f *LockoutFind) <-chan dlib.Result {
+	return ss.deleteWhere("lockout", lockoutWhere(f))
+}
+
+// userMFAWhere builds a WHERE clause from a user MFA find value.
+func userMFAWhere(f *UserMFAFind) *sqlWhere {
+	w := sqlWhere{}
+	if f.ID != nil {
+		w.add("id = $%d", *f.ID)
+	}
+
+	if f.UserID != nil {
+		w.add("user_id = $%d", *f.UserID)
+	}
+
+	if f.Enabled != nil {
+		w.add("enabled = $%d", *f.Enabled)
+	}
+
+	return &w
+}
+
+// GetUserMFAs finds user MFAs in the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) GetUserMFAs(f *UserMFAFind) <-chan dlib.Result {
+	c := make(chan dlib.Result, 256)
+
+	go func() {
+		defer close(c)
+		w := userMFAWhere(f)
+		rows, err := ss.DB.Query("SELECT id, user_id, secret, enabled, last_step FROM user_mfa"+
+			w.String()+" ORDER BY id", w.args...)
+		if err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		defer rows.Close()
+		for rows.Next() {
+			rr := UserMFARow{}
+			if err := rows.Scan(&rr.ID, &rr.UserID, &rr.Secret, &rr.Enabled,
+				&rr.LastStep); err != nil {
+				ss.send(c, dlib.Result{Err: err})
+				return
+			}
+
+			v := rr.ToUserMFA()
+			if !ss.send(c, dlib.Result{Val: &v}) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			ss.send(c, dlib.Result{Err: err})
+		}
+	}()
+
+	return c
+}
+
+// SaveUserMFA inserts or updates a user MFA in the database. A user MFA
+// with no ID is inserted and receives the ID assigned by the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) SaveUserMFA(um *UserMFA) <-chan dlib.Result {
+	c := make(chan dlib.Result, 1)
+
+	go func() {
+		defer close(c)
+		rr := UserMFARow{}
+		if err := rr.FromUserMFA(um); err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		if rr.ID == 0 {
+			id, err := ss.insert("INSERT INTO user_mfa (user_id, secret, enabled, "+
+				"last_step) VALUES ($1, $2, $3, $4) RETURNING id",
+				rr.UserID, rr.Secret, rr.Enabled, rr.LastStep)
+			if err != nil {
+				c <- dlib.Result{Err: err}
+				return
+			}
+
+			um.ID = id
+			c <- dlib.Result{Val: um, Num: 1}
+			return
+		}
+
+		r := ss.update("UPDATE user_mfa SET user_id = $2, secret = $3, "+
+			"enabled = $4, last_step = $5 WHERE id = $1",
+			rr.ID, rr.UserID, rr.Secret, rr.Enabled, rr.LastStep)
+		r.Val = um
+		c <- r
+	}()
+
+	return c
+}
+
+// DeleteUserMFAs deletes user MFAs from the database. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) DeleteUserMFAs(f *UserMFAFind) <-chan dlib.Result {
+	return ss.deleteWhere("user_mfa", userMFAWhere(f))
+}
+
+// recoveryCodeWhere builds a WHERE clause from a recovery code find value.
+func recoveryCodeWhere(f *RecoveryCodeFind) *sqlWhere {
+	w := sqlWhere{}
+	if f.ID != nil {
+		w.add("id = $%d", *f.ID)
+	}
+
+	if f.UserID != nil {
+		w.add("user_id = $%d", *f.UserID)
+	}
+
+	if f.Code != nil {
+		w.add("code = $%d", *f.Code)
+	}
+
+	return &w
+}
+
+// GetRecoveryCodes finds recovery codes in the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) GetRecoveryCodes(f *RecoveryCodeFind) <-chan dlib.Result {
+	c := make(chan dlib.Result, 256)
+
+	go func() {
+		defer close(c)
+		w := recoveryCodeWhere(f)
+		rows, err := ss.DB.Query("SELECT id, user_id, code FROM recovery_code"+
+			w.String()+" ORDER BY id", w.args...)
+		if err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		defer rows.Close()
+		for rows.Next() {
+			rr := RecoveryCodeRow{}
+			if err := rows.Scan(&rr.ID, &rr.UserID, &rr.Code); err != nil {
+				ss.send(c, dlib.Result{Err: err})
+				return
+			}
+
+			v := rr.ToRecoveryCode()
+			if !ss.send(c, dlib.Result{Val: &v}) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			ss.send(c, dlib.Result{Err: err})
+		}
+	}()
+
+	return c
+}
+
+// SaveRecoveryCode inserts or updates a recovery code in the database. A
+// recovery code with no ID is inserted and receives the ID assigned by the
+// database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) SaveRecoveryCode(rc *RecoveryCode) <-chan dlib.Result {
+	c := make(chan dlib.Result, 1)
+
+	go func() {
+		defer close(c)
+		rr := RecoveryCodeRow{}
+		if err := rr.FromRecoveryCode(rc); err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		if rr.ID == 0 {
+			id, err := ss.insert("INSERT INTO recovery_code (user_id, code) "+
+				"VALUES ($1, $2) RETURNING id", rr.UserID, rr.Code)
+			if err != nil {
+				c <- dlib.Result{Err: err}
+				return
+			}
+
+			rc.ID = id
+			c <- dlib.Result{Val: rc, Num: 1}
+			return
+		}
+
+		r := ss.update("UPDATE recovery_code SET user_id = $2, code = $3 WHERE id = $1",
+			rr.ID, rr.UserID, rr.Code)
+		r.Val = rc
+		c <- r
+	}()
+
+	return c
+}
+
+// DeleteRecoveryCodes deletes recovery codes from the database. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) DeleteRecoveryCodes(f *RecoveryCodeFind) <-chan dlib.Result {
+	return ss.deleteWhere("recovery_code", recoveryCodeWhere(f))
+}
+
+// apiKeyWhere builds a WHERE clause from a API key find value.
+func apiKeyWhere(f *APIKeyFind) *sqlWhere {
+	w := sqlWhere{}
+	if f.ID != nil {
+		w.add("id = $%d", *f.ID)
+	}
+
+	if f.Name != nil {
+		w.add("name = $%d", *f.Name)
+	}
+
+	if f.Prefix != nil {
+		w.add("prefix = $%d", *f.Prefix)
+	}
+
+	if f.Key != nil {
+		w.add("key = $%d", *f.Key)
+	}
+
+	if f.UserID != nil {
+		w.add("user_id = $%d", *f.UserID)
+	}
+
+	if f.TenantID != nil {
+		w.add("tenant_id = $%d", *f.TenantID)
+	}
+
+	return &w
+}
+
+// GetAPIKeys finds API keys in the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) GetAPIKeys(f *APIKeyFind) <-chan dlib.Result {
+	c := make(chan dlib.Result, 256)
+
+	go func() {
+		defer close(c)
+		w := apiKeyWhere(f)
+		rows, err := ss.DB.Query("SELECT id, name, prefix, key, user_id, scope, "+
+			"created, expires, tenant_id FROM api_key"+w.String()+" ORDER BY id", w.args...)
+		if err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		defer rows.Close()
+		for rows.Next() {
+			rr := APIKeyRow{}
+			if err := rows.Scan(&rr.ID, &rr.Name, &rr.Prefix, &rr.Key, &rr.UserID,
+				&rr.Scope, &rr.Created, &rr.Expires, &rr.TenantID); err != nil {
+				ss.send(c, dlib.Result{Err: err})
+				return
+			}
+
+			v := rr.ToAPIKey()
+			if !ss.send(c, dlib.Result{Val: &v}) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			ss.send(c, dlib.Result{Err: err})
+		}
+	}()
+
+	return c
+}
+
+// SaveAPIKey inserts or updates a API key in the database. A API key with no
+// ID is inserted and receives the ID assigned by the database.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) SaveAPIKey(k *APIKey) <-chan dlib.Result {
+	c := make(chan dlib.Result, 1)
+
+	go func() {
+		defer close(c)
+		rr := APIKeyRow{}
+		if err := rr.FromAPIKey(k); err != nil {
+			c <- dlib.Result{Err: err}
+			return
+		}
+
+		if rr.ID == 0 {
+			id, err := ss.insert("INSERT INTO api_key (name, prefix, key, user_id, "+
+				"scope, created, expires, tenant_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) "+
+				"RETURNING id", rr.Name, rr.Prefix, rr.Key, rr.UserID, rr.Scope,
+				rr.Created, rr.Expires, rr.TenantID)
+			if err != nil {
+				c <- dlib.Result{Err: err}
+				return
+			}
+
+			k.ID = id
+			c <- dlib.Result{Val: k, Num: 1}
+			return
+		}
+
+		r := ss.update("UPDATE api_key SET name = $2, prefix = $3, key = $4, "+
+			"user_id = $5, scope = $6, created = $7, expires = $8, tenant_id = $9 "+
+			"WHERE id = $1", rr.ID, rr.Name, rr.Prefix, rr.Key, rr.UserID, rr.Scope,
+			rr.Created, rr.Expires, rr.TenantID)
+		r.Val = k
+		c <- r
+	}()
+
+	return c
+}
+
+// DeleteAPIKeys deletes API keys from the database. At least one search
+// criteria is required.
+// It returns the results of the operation in a Result channel.
+func (ss *SQLStore) DeleteAPIKeys(f *APIKeyFind) <-chan dlib.Result {
+	return ss.deleteWhere("api_key", apiKeyWhere(f))
+}
@@ -0,0 +1,472 @@
+package dauth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhaifley/dlib"
+)
+
+type FakeSQLResult struct {
+	n int64
+}
+
+func (fr FakeSQLResult) LastInsertId() (int64, error) {
+	return 0, nil
+}
+
+func (fr FakeSQLResult) RowsAffected() (int64, error) {
+	return fr.n, nil
+}
+
+type FakeSQLRows struct {
+	rows [][]interface{}
+	i    int
+	err  error
+}
+
+func (fr *FakeSQLRows) Close() error {
+	return nil
+}
+
+func (fr *FakeSQLRows) Err() error {
+	return fr.err
+}
+
+func (fr *FakeSQLRows) Next() bool {
+	fr.i++
+	return fr.i <= len(fr.rows)
+}
+
+func (fr *FakeSQLRows) Scan(dest ...interface{}) error {
+	for i, d := range dest {
+		v := fr.rows[fr.i-1][i]
+		if sc, ok := d.(sql.Scanner); ok {
+			if err := sc.Scan(v); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		reflect.ValueOf(d).Elem().Set(reflect.ValueOf(v))
+	}
+
+	return nil
+}
+
+type FakeSQLExecutor struct {
+	tables  map[string][][]interface{}
+	queries []string
+	missing bool
+	err     error
+}
+
+func (fk *FakeSQLExecutor) Exec(query string, args ...interface{}) (sql.Result, error) {
+	fk.queries = append(fk.queries, query)
+	if fk.missing && strings.HasPrefix(query, "UPDATE") {
+		return FakeSQLResult{}, nil
+	}
+
+	return FakeSQLResult{n: 1}, nil
+}
+
+func (fk *FakeSQLExecutor) Query(query string, args ...interface{}) (dlib.SQLRows, error) {
+	fk.queries = append(fk.queries, query)
+	if strings.HasPrefix(query, "INSERT") {
+		return &FakeSQLRows{rows: [][]interface{}{{int64(1)}}}, nil
+	}
+
+	for name, rows := range fk.tables {
+		if strings.Contains(query, "FROM "+name+" ") ||
+			strings.HasSuffix(query, "FROM "+name) {
+			return &FakeSQLRows{rows: rows, err: fk.err}, nil
+		}
+	}
+
+	return &FakeSQLRows{}, nil
+}
+
+func (fk *FakeSQLExecutor) Close() error {
+	return nil
+}
+
+func (fk *FakeSQLExecutor) Ping() error {
+	return nil
+}
+
+func (fk *FakeSQLExecutor) Stats() sql.DBStats {
+	return sql.DBStats{}
+}
+
+func newFakeSQLExecutor() *FakeSQLExecutor {
+	exp := time.Now().Add(time.Hour)
+	return &FakeSQLExecutor{
+		tables: map[string][][]interface{}{
+			"token": {{int64(1), "test", int64(1), time.Now(), exp, "test:read",
+				int64(0), nil}},
+			`"user"`: {{int64(1), "test", "test", "test", nil, int64(0), nil}},
+			"perm":   {{int64(1), "test", "test", int64(0)}},
+			"user_perm": {{int64(1), int64(1), int64(1), int64(0), nil, "", "", "",
+				""}},
+			"role": {{int64(1), "test", nil, int64(0)},
+				{int64(2), "test", int64(1), int64(0)}},
+			"lockout": {{int64(1), "test", "", int64(5), time.Now(), exp, int64(0)}},
+			"api_key": {{int64(1), "test", "dk_test", "hash", int64(1),
+				"test:test test:other", time.Now(), nil, int64(0)}},
+		},
+	}
+}
+
+func TestSQLStoreGetTokens(t *testing.T) {
+	db := newFakeSQLExecutor()
+	ss := NewSQLStore(db)
+	uid := int64(1)
+	n := 0
+	for r := range ss.GetTokens(&TokenFind{UserID: &uid}) {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+
+		n++
+		exp := "test"
+		tk := r.Val.(*Token)
+		if tk.Token != exp {
+			t.Errorf("Value expected: %v, got: %v", exp, tk.Token)
+		}
+
+		if len(tk.Scope) != 1 || tk.Scope[0] != "test:read" {
+			t.Errorf("Scope expected: [test:read], got: %v", tk.Scope)
+		}
+	}
+
+	if n != 1 {
+		t.Errorf("Count expected: 1, got: %v", n)
+	}
+
+	exp := "SELECT id, token, user_id, created, expires, scope, tenant_id, actor_id " +
+		"FROM token WHERE user_id = $1 ORDER BY id"
+	if db.queries[0] != exp {
+		t.Errorf("Query expected: %v, got: %v", exp, db.queries[0])
+	}
+}
+
+func TestSQLStoreGetUsers(t *testing.T) {
+	ss := NewSQLStore(newFakeSQLExecutor())
+	for r := range ss.GetUsers(&UserFind{}) {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+
+		u := r.Val.(*User)
+		if u.Name != "test" || u.Email != "" {
+			t.Errorf("User expected: test, got: %v", u)
+		}
+	}
+}
+
+func TestSQLStoreGetUsersErr(t *testing.T) {
+	db := newFakeSQLExecutor()
+	db.err = errors.New("test")
+	ss := NewSQLStore(db)
+	n := 0
+	var err error
+	for r := range ss.GetUsers(&UserFind{}) {
+		n++
+		err = r.Err
+	}
+
+	if n != 2 || err != db.err {
+		t.Errorf("Error expected: %v, got: %v after %v results", db.err, err, n)
+	}
+}
+
+func TestSQLStoreGetUsersContext(t *testing.T) {
+	db := newFakeSQLExecutor()
+	rows := [][]interface{}{}
+	for i := 0; i < 1000; i++ {
+		rows = append(rows, []interface{}{int64(i + 1), "test", "test", "test",
+			nil, int64(0), nil})
+	}
+
+	db.tables[`"user"`] = rows
+	ctx, cancel := context.WithCancel(context.Background())
+	c := NewSQLStore(db).WithContext(ctx).GetUsers(&UserFind{})
+	<-c
+	cancel()
+	n := 1
+	for range c {
+		n++
+	}
+
+	if n >= len(rows) {
+		t.Errorf("Count expected: < %v, got: %v", len(rows), n)
+	}
+}
+
+func TestSQLStoreSaveUser(t *testing.T) {
+	db := newFakeSQLExecutor()
+	ss := NewSQLStore(db)
+	u := User{User: "test"}
+	for r := range ss.SaveUser(&u) {
+		if r.Err != nil {
+			t.Error(r.Err)
+		}
+	}
+
+	if u.ID != 1 {
+		t.Errorf("ID expected: 1, got: %v", u.ID)
+	}
+
+	for r := range ss.SaveUser(&u) {
+		if r.Err != nil {
+			t.Error(r.Err)
+		}
+
+		if r.Num != 1 {
+			t.Errorf("Num expected: 1, got: %v", r.Num)
+		}
+	}
+
+	if !strings.HasPrefix(db.queries[1], `UPDATE "user"`) {
+		t.Errorf("Expected update query, got: %v", db.queries[1])
+	}
+
+	db.missing = true
+	for r := range ss.SaveUser(&u) {
+		if e, ok := r.Err.(*dlib.Error); !ok || e.Code != 404 {
+			t.Errorf("Error expected: 404, got: %v", r.Err)
+		}
+	}
+}
+
+func TestSQLStoreGetPermsIDs(t *testing.T) {
+	db := newFakeSQLExecutor()
+	ss := NewSQLStore(db)
+	for _, ids := range [][]int64{{1, 3}, {}} {
+		for r := range ss.GetPerms(&PermFind{IDs: ids}) {
+			if r.Err != nil {
+				t.Fatal(r.Err)
+			}
+		}
+	}
+
+	exp := []string{
+		"SELECT id, service, name, tenant_id FROM perm WHERE id IN ($1, $2) ORDER BY id",
+		"SELECT id, service, name, tenant_id FROM perm WHERE FALSE ORDER BY id",
+	}
+
+	if !reflect.DeepEqual(db.queries, exp) {
+		t.Errorf("Queries expected: %v, got: %v", exp, db.queries)
+	}
+}
+
+func TestSQLStoreCreateSchema(t *testing.T) {
+	db := newFakeSQLExecutor()
+	if err := NewSQLStore(db).CreateSchema(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, exp := range []string{
+		"UNIQUE INDEX IF NOT EXISTS token_token_idx ON token (token)",
+		"UNIQUE INDEX IF NOT EXISTS api_key_key_idx ON api_key (key)",
+		`UNIQUE INDEX IF NOT EXISTS user_tenant_user_idx ON "user" (tenant_id, "user")`,
+	} {
+		if !strings.Contains(db.queries[0], exp) {
+			t.Errorf("Index expected: %v", exp)
+		}
+	}
+}
+
+func TestSQLStoreDeletePerms(t *testing.T) {
+	db := newFakeSQLExecutor()
+	ss := NewSQLStore(db)
+	svc := "test"
+	for r := range ss.DeletePerms(&PermFind{Service: &svc}) {
+		if r.Err != nil {
+			t.Error(r.Err)
+		}
+
+		if r.Num != 1 {
+			t.Errorf("Num expected: 1, got: %v", r.Num)
+		}
+	}
+
+	exp := "DELETE FROM perm WHERE service = $1"
+	if db.queries[0] != exp {
+		t.Errorf("Query expected: %v, got: %v", exp, db.queries[0])
+	}
+
+	for r := range ss.DeletePerms(&PermFind{}) {
+		if r.Err == nil {
+			t.Error("Expected error for delete without criteria")
+		}
+	}
+}
+
+func TestSQLStoreDeleteTokensExpired(t *testing.T) {
+	db := newFakeSQLExecutor()
+	ss := NewSQLStore(db)
+	now := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	for r := range ss.DeleteTokens(&TokenFind{Expired: &now}) {
+		if r.Err != nil {
+			t.Error(r.Err)
+		}
+	}
+
+	exp := "DELETE FROM token WHERE expires < $1"
+	if db.queries[0] != exp {
+		t.Errorf("Query expected: %v, got: %v", exp, db.queries[0])
+	}
+}
+
+func TestSQLStoreGetRoles(t *testing.T) {
+	db := newFakeSQLExecutor()
+	ss := NewSQLStore(db)
+	roles := []*Role{}
+	for r := range ss.GetRoles(&RoleFind{}) {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+
+		roles = append(roles, r.Val.(*Role))
+	}
+
+	if len(roles) != 2 || roles[0].ParentID != 0 || roles[1].ParentID != 1 {
+		t.Errorf("Roles expected: 2 with parent 1, got: %v", roles)
+	}
+
+	ro := Role{Name: "test"}
+	for r := range ss.SaveRole(&ro) {
+		if r.Err != nil {
+			t.Error(r.Err)
+		}
+	}
+
+	exp := "INSERT INTO role (name, parent_id, tenant_id) VALUES ($1, $2, $3) RETURNING id"
+	if db.queries[1] != exp {
+		t.Errorf("Query expected: %v, got: %v", exp, db.queries[1])
+	}
+}
+
+func TestSQLStoreGetLockouts(t *testing.T) {
+	db := newFakeSQLExecutor()
+	ss := NewSQLStore(db)
+	user := "test"
+	n := 0
+	for r := range ss.GetLockouts(&LockoutFind{User: &user}) {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+
+		n++
+		if l := r.Val.(*Lockout); !l.Locked(time.Now()) {
+			t.Errorf("Expected locked lockout, got: %v", l)
+		}
+	}
+
+	if n != 1 {
+		t.Errorf("Count expected: 1, got: %v", n)
+	}
+
+	exp := `SELECT id, "user", addr, failures, last, until, tenant_id FROM lockout ` +
+		`WHERE "user" = $1 ORDER BY id`
+	if db.queries[0] != exp {
+		t.Errorf("Query expected: %v, got: %v", exp, db.queries[0])
+	}
+}
+
+func TestSQLStoreSaveUserMFA(t *testing.T) {
+	db := newFakeSQLExecutor()
+	ss := NewSQLStore(db)
+	um := UserMFA{UserID: 1, Secret: "test"}
+	for r := range ss.SaveUserMFA(&um) {
+		if r.Err != nil {
+			t.Error(r.Err)
+		}
+	}
+
+	if um.ID != 1 {
+		t.Errorf("ID expected: 1, got: %v", um.ID)
+	}
+
+	um.Enabled = true
+	for r := range ss.SaveUserMFA(&um) {
+		if r.Err != nil {
+			t.Error(r.Err)
+		}
+	}
+
+	exp := "UPDATE user_mfa SET user_id = $2, secret = $3, enabled = $4, " +
+		"last_step = $5 WHERE id = $1"
+	if db.queries[1] != exp {
+		t.Errorf("Query expected: %v, got: %v", exp, db.queries[1])
+	}
+}
+
+func TestSQLStoreGetAPIKeys(t *testing.T) {
+	db := newFakeSQLExecutor()
+	ss := NewSQLStore(db)
+	prefix := "dk_test"
+	n := 0
+	for r := range ss.GetAPIKeys(&APIKeyFind{Prefix: &prefix}) {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+
+		n++
+		k := r.Val.(*APIKey)
+		if len(k.Scope) != 2 || k.Expires != nil {
+			t.Errorf("Unexpected API key: %v", k)
+		}
+	}
+
+	if n != 1 {
+		t.Errorf("Count expected: 1, got: %v", n)
+	}
+
+	exp := "SELECT id, name, prefix, key, user_id, scope, created, expires, tenant_id " +
+		"FROM api_key WHERE prefix = $1 ORDER BY id"
+	if db.queries[0] != exp {
+		t.Errorf("Query expected: %v, got: %v", exp, db.queries[0])
+	}
+}
+
+func TestSQLStoreGetUsersTenant(t *testing.T) {
+	db := newFakeSQLExecutor()
+	ss := NewSQLStore(db)
+	tid := int64(2)
+	for r := range ss.GetUsers(&UserFind{TenantID: &tid}) {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+	}
+
+	exp := `SELECT id, "user", pass, name, email, tenant_id, provider FROM "user" ` +
+		"WHERE tenant_id = $1 ORDER BY id"
+	if db.queries[0] != exp {
+		t.Errorf("Query expected: %v, got: %v", exp, db.queries[0])
+	}
+}
+
+func TestSQLStoreGetUsersPage(t *testing.T) {
+	db := newFakeSQLExecutor()
+	ss := NewSQLStore(db)
+	after := int64(20)
+	for r := range ss.GetUsers(&UserFind{Page: Page{Limit: 10, After: &after}}) {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+	}
+
+	exp := `SELECT id, "user", pass, name, email, tenant_id, provider FROM "user" ` +
+		"WHERE id > $1 ORDER BY id LIMIT 10"
+	if db.queries[0] != exp {
+		t.Errorf("Query expected: %v, got: %v", exp, db.queries[0])
+	}
+}
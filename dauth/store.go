@@ -0,0 +1,84 @@
+package dauth
+
+import (
+	"context"
+
+	"github.com/dhaifley/dlib"
+)
+
+// Store is an interface describing types capable of persisting users,
+// permissions, roles, their assignments, tokens, API keys, login lockouts
+// and MFA enrollments. Find operations are driven by the corresponding find
+// values, where nil fields match any value. Delete operations require at
+// least one search criteria and report the number of values removed in the
+// Num field of their result. Save operations insert values with no ID,
+// assigning a new one, and replace the existing value for values with an
+// ID, returning a 404 error if there is none.
+type Store interface {
+	GetTokens(f *TokenFind) <-chan dlib.Result
+	SaveToken(t *Token) <-chan dlib.Result
+	DeleteTokens(f *TokenFind) <-chan dlib.Result
+	GetUsers(f *UserFind) <-chan dlib.Result
+	SaveUser(u *User) <-chan dlib.Result
+	DeleteUsers(f *UserFind) <-chan dlib.Result
+	GetPerms(f *PermFind) <-chan dlib.Result
+	SavePerm(p *Perm) <-chan dlib.Result
+	DeletePerms(f *PermFind) <-chan dlib.Result
+	GetUserPerms(f *UserPermFind) <-chan dlib.Result
+	SaveUserPerm(up *UserPerm) <-chan dlib.Result
+	DeleteUserPerms(f *UserPermFind) <-chan dlib.Result
+	GetRoles(f *RoleFind) <-chan dlib.Result
+	SaveRole(ro *Role) <-chan dlib.Result
+	DeleteRoles(f *RoleFind) <-chan dlib.Result
+	GetRolePerms(f *RolePermFind) <-chan dlib.Result
+	SaveRolePerm(rp *RolePerm) <-chan dlib.Result
+	DeleteRolePerms(f *RolePermFind) <-chan dlib.Result
+	GetUserRoles(f *UserRoleFind) <-chan dlib.Result
+	SaveUserRole(ur *UserRole) <-chan dlib.Result
+	DeleteUserRoles(f *UserRoleFind) <-chan dlib.Result
+	GetLockouts(f *LockoutFind) <-chan dlib.Result
+	SaveLockout(l *Lockout) <-chan dlib.Result
+	DeleteLockouts(f *LockoutFind) <-chan dlib.Result
+	GetUserMFAs(f *UserMFAFind) <-chan dlib.Result
+	SaveUserMFA(um *UserMFA) <-chan dlib.Result
+	DeleteUserMFAs(f *UserMFAFind) <-chan dlib.Result
+	GetRecoveryCodes(f *RecoveryCodeFind) <-chan dlib.Result
+	SaveRecoveryCode(rc *RecoveryCode) <-chan dlib.Result
+	DeleteRecoveryCodes(f *RecoveryCodeFind) <-chan dlib.Result
+	GetAPIKeys(f *APIKeyFind) <-chan dlib.Result
+	SaveAPIKey(k *APIKey) <-chan dlib.Result
+	DeleteAPIKeys(f *APIKeyFind) <-chan dlib.Result
+}
+
+// ContextStore is an interface describing stores which can bind their find
+// operations to a context, so that a query stops once its results are no
+// longer wanted.
+type ContextStore interface {
+	WithContext(ctx context.Context) Store
+}
+
+// withContext returns a store bound to ctx, or st itself if it is not a
+// ContextStore.
+func withContext(st Store, ctx context.Context) Store {
+	if cs, ok := st.(ContextStore); ok {
+		return cs.WithContext(ctx)
+	}
+
+	return st
+}
+
+// errNoCriteria is returned by delete operations called without any
+// search criteria.
+var errNoCriteria = &dlib.Error{Code: 400, Msg: "delete requires search criteria"}
+
+// errNotFound is returned by save operations for a value whose ID does not
+// exist.
+var errNotFound = &dlib.Error{Code: 404, Msg: "record not found"}
+
+// errorResult returns a closed Result channel containing only an error.
+func errorResult(err error) <-chan dlib.Result {
+	c := make(chan dlib.Result, 1)
+	c <- dlib.Result{Err: err}
+	close(c)
+	return c
+}
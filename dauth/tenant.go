@@ -0,0 +1,79 @@
+package dauth
+
+import (
+	"context"
+
+	"github.com/dhaifley/dlib"
+)
+
+// SuperTenant is the tenant of platform administrators. Users in the
+// super-tenant may access the records of every tenant. Records created
+// before tenants were introduced belong to it.
+const SuperTenant int64 = 0
+
+// ErrCrossTenant is returned when a user restricted to a tenant attempts
+// to access the records of another tenant.
+var ErrCrossTenant = &dlib.Error{Code: 403, Msg: "cross-tenant access denied"}
+
+// TenantAllows tests whether a user in a tenant may access the records of
+// a target tenant.
+func TenantAllows(tenantID, target int64) bool {
+	return tenantID == SuperTenant || tenantID == target
+}
+
+// tenantFromContext returns the tenant to which the authenticated user
+// carried by a context is restricted. It returns false if there is no
+// authenticated user or the user is in the super-tenant, in which case
+// access is not restricted.
+func tenantFromContext(ctx context.Context) (int64, bool) {
+	u, ok := UserFromContext(ctx)
+	if !ok || u == nil || u.TenantID == SuperTenant {
+		return SuperTenant, false
+	}
+
+	return u.TenantID, true
+}
+
+// scopeTenant sets the tenant of a record being saved by the user carried
+// by a context, if the user is restricted to a tenant. It returns
+// ErrCrossTenant if the record belongs to another tenant.
+func scopeTenant(ctx context.Context, tenantID *int64) error {
+	t, ok := tenantFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	if *tenantID != SuperTenant && *tenantID != t {
+		return ErrCrossTenant
+	}
+
+	*tenantID = t
+	return nil
+}
+
+// scopeFind limits the tenant criteria of a find value to the tenant of
+// the user carried by a context, if the user is restricted to a tenant.
+// It returns ErrCrossTenant if the criteria name another tenant.
+func scopeFind(ctx context.Context, tenantID **int64) error {
+	t, ok := tenantFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	if *tenantID != nil && **tenantID != t {
+		return ErrCrossTenant
+	}
+
+	*tenantID = &t
+	return nil
+}
+
+// checkTenant returns ErrCrossTenant if the user carried by a context is
+// restricted to a tenant other than the tenant of a record.
+func checkTenant(ctx context.Context, tenantID int64) error {
+	if t, ok := tenantFromContext(ctx); ok && t != tenantID {
+		return ErrCrossTenant
+	}
+
+	return nil
+}
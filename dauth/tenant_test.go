@@ -0,0 +1,63 @@
+package dauth
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTenantAllows(t *testing.T) {
+	cases := []struct {
+		tenant int64
+		target int64
+		exp    bool
+	}{
+		{SuperTenant, 2, true},
+		{2, 2, true},
+		{2, 3, false},
+		{2, SuperTenant, false},
+	}
+
+	for _, c := range cases {
+		if v := TenantAllows(c.tenant, c.target); v != c.exp {
+			t.Errorf("TenantAllows %v %v expected: %v, got: %v", c.tenant, c.target, c.exp, v)
+		}
+	}
+}
+
+func TestScopeTenant(t *testing.T) {
+	ctx := NewUserContext(context.Background(), &User{ID: 1, TenantID: 2})
+	id := int64(0)
+	if err := scopeTenant(ctx, &id); err != nil || id != 2 {
+		t.Errorf("TenantID expected: 2, got: %v, %v", id, err)
+	}
+
+	id = 3
+	if err := scopeTenant(ctx, &id); err != ErrCrossTenant {
+		t.Errorf("Error expected: %v, got: %v", ErrCrossTenant, err)
+	}
+
+	id = 3
+	if err := scopeTenant(context.Background(), &id); err != nil || id != 3 {
+		t.Errorf("TenantID expected: 3, got: %v, %v", id, err)
+	}
+}
+
+func TestScopeFind(t *testing.T) {
+	ctx := NewUserContext(context.Background(), &User{ID: 1, TenantID: 2})
+	f := UserFind{}
+	if err := scopeFind(ctx, &f.TenantID); err != nil || f.TenantID == nil || *f.TenantID != 2 {
+		t.Errorf("TenantID expected: 2, got: %v, %v", f.TenantID, err)
+	}
+
+	other := int64(3)
+	f.TenantID = &other
+	if err := scopeFind(ctx, &f.TenantID); err != ErrCrossTenant {
+		t.Errorf("Error expected: %v, got: %v", ErrCrossTenant, err)
+	}
+
+	super := NewUserContext(context.Background(), &User{ID: 1})
+	f.TenantID = nil
+	if err := scopeFind(super, &f.TenantID); err != nil || f.TenantID != nil {
+		t.Errorf("TenantID expected: nil, got: %v, %v", f.TenantID, err)
+	}
+}
@@ -0,0 +1,216 @@
+package dauth
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/dhaifley/dlib"
+	"google.golang.org/grpc/peer"
+)
+
+// Default login throttle settings.
+const (
+	DefaultMaxFailures  = 5
+	DefaultBaseDelay    = time.Second
+	DefaultMaxDelay     = time.Minute
+	DefaultLockDuration = 15 * time.Minute
+	DefaultFailWindow   = time.Hour
+)
+
+// ErrLockedOut is returned when a login is refused because of earlier
+// failed attempts for the same user name or from the same address.
+var ErrLockedOut = &dlib.Error{Code: 429, Msg: "too many failed login attempts"}
+
+// LoginThrottle values track failed login attempts per user name and per
+// client address. User names are only unique within a tenant, so failures
+// are tracked per tenant and user name. After each failure, further
+// attempts are refused for a delay which doubles with every consecutive
+// failure, starting at BaseDelay and capped at MaxDelay. After MaxFailures
+// consecutive failures, attempts are refused for LockDuration. Failures
+// older than Window are forgotten.
+//
+// Lockout state is kept in the store, so it is shared by every replica
+// using the same store. Concurrent failures on different replicas may be
+// counted once rather than twice, which only delays the lockout slightly.
+// Failures are recorded whether or not the user name exists, so that the
+// throttle does not reveal which do, and old lockouts are deleted by a
+// Reaper with a LockoutAge.
+type LoginThrottle struct {
+	Store        Store
+	MaxFailures  int64
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	LockDuration time.Duration
+	Window       time.Duration
+}
+
+// NewLoginThrottle initializes and returns a pointer to a new login
+// throttle value using the default settings.
+func NewLoginThrottle(st Store) *LoginThrottle {
+	return &LoginThrottle{
+		Store:        st,
+		MaxFailures:  DefaultMaxFailures,
+		BaseDelay:    DefaultBaseDelay,
+		MaxDelay:     DefaultMaxDelay,
+		LockDuration: DefaultLockDuration,
+		Window:       DefaultFailWindow,
+	}
+}
+
+// lockoutFinds returns the lockout find values for a user name in a tenant
+// and a client address, skipping empty ones.
+func lockoutFinds(tenantID int64, user, addr string) []LockoutFind {
+	fs := []LockoutFind{}
+	if user != "" {
+		fs = append(fs, LockoutFind{User: &user, TenantID: &tenantID})
+	}
+
+	if addr != "" {
+		fs = append(fs, LockoutFind{Addr: &addr})
+	}
+
+	return fs
+}
+
+// get returns the lockout matching a find value, or nil if there is none.
+func (lt *LoginThrottle) get(f *LockoutFind) (*Lockout, error) {
+	var l *Lockout
+	for r := range lt.Store.GetLockouts(f) {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		l = r.Val.(*Lockout)
+	}
+
+	return l, nil
+}
+
+// Check tests whether a login for a user name in a tenant from a client
+// address may be attempted now. It returns ErrLockedOut if either is locked
+// out.
+func (lt *LoginThrottle) Check(tenantID int64, user, addr string) error {
+	now := time.Now()
+	for _, f := range lockoutFinds(tenantID, user, addr) {
+		l, err := lt.get(&f)
+		if err != nil {
+			return err
+		}
+
+		if l != nil && l.Locked(now) {
+			return ErrLockedOut
+		}
+	}
+
+	return nil
+}
+
+// delay returns the time logins are refused for after a number of
+// consecutive failures.
+func (lt *LoginThrottle) delay(failures int64) time.Duration {
+	if lt.MaxFailures > 0 && failures >= lt.MaxFailures {
+		return lt.LockDuration
+	}
+
+	d := lt.BaseDelay
+	for i := int64(1); i < failures && (lt.MaxDelay <= 0 || d < lt.MaxDelay); i++ {
+		d *= 2
+	}
+
+	if lt.MaxDelay > 0 && d > lt.MaxDelay {
+		d = lt.MaxDelay
+	}
+
+	return d
+}
+
+// Fail records a failed login for a user name in a tenant from a client
+// address.
+func (lt *LoginThrottle) Fail(tenantID int64, user, addr string) error {
+	now := time.Now()
+	for _, f := range lockoutFinds(tenantID, user, addr) {
+		l, err := lt.get(&f)
+		if err != nil {
+			return err
+		}
+
+		if l == nil {
+			l = &Lockout{}
+			if f.User != nil {
+				l.User, l.TenantID = *f.User, tenantID
+			} else {
+				l.Addr = *f.Addr
+			}
+		}
+
+		if l.Last != nil && lt.Window > 0 && now.Sub(*l.Last) > lt.Window {
+			l.Failures = 0
+		}
+
+		if lt.MaxFailures > 0 && l.Failures >= lt.MaxFailures && !l.Locked(now) {
+			l.Failures = 0
+		}
+
+		l.Failures++
+		until := now.Add(lt.delay(l.Failures))
+		l.Last = &now
+		l.Until = &until
+		for r := range lt.Store.SaveLockout(l) {
+			if r.Err != nil {
+				return r.Err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Succeed clears the failed logins recorded for a user name in a tenant
+// after a successful login. Failures recorded for client addresses are
+// kept, so that a valid login to one account does not reset guessing
+// against others.
+func (lt *LoginThrottle) Succeed(tenantID int64, user string) error {
+	if user == "" {
+		return nil
+	}
+
+	f := LockoutFind{User: &user, TenantID: &tenantID}
+	for r := range lt.Store.DeleteLockouts(&f) {
+		if r.Err != nil {
+			return r.Err
+		}
+	}
+
+	return nil
+}
+
+// addrKey is the context key for a client address.
+type addrKey struct{}
+
+// NewAddrContext returns a copy of a context carrying the address of a
+// client, such as the client of an HTTP request, which ClientAddr returns
+// in place of the gRPC peer address.
+func NewAddrContext(ctx context.Context, addr string) context.Context {
+	return context.WithValue(ctx, addrKey{}, addr)
+}
+
+// ClientAddr returns the host address of a client carried by a context, or
+// else of the client of a gRPC call, or an empty string if it is not known.
+func ClientAddr(ctx context.Context) string {
+	if addr, ok := ctx.Value(addrKey{}).(string); ok {
+		return addr
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
@@ -0,0 +1,107 @@
+package dauth
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/peer"
+)
+
+func TestLoginThrottleDelay(t *testing.T) {
+	lt := NewLoginThrottle(NewMemoryStore())
+	cases := []struct {
+		failures int64
+		exp      time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{4, 8 * time.Second},
+		{5, DefaultLockDuration},
+	}
+
+	for _, c := range cases {
+		if d := lt.delay(c.failures); d != c.exp {
+			t.Errorf("Delay expected: %v, got: %v", c.exp, d)
+		}
+	}
+
+	lt.MaxFailures = 0
+	if d := lt.delay(20); d != DefaultMaxDelay {
+		t.Errorf("Delay expected: %v, got: %v", DefaultMaxDelay, d)
+	}
+}
+
+func TestLoginThrottle(t *testing.T) {
+	ms := NewMemoryStore()
+	lt := NewLoginThrottle(ms)
+	lt.BaseDelay = 0
+	lt.MaxFailures = 3
+	for i := 0; i < 2; i++ {
+		if err := lt.Check(0, "test", "127.0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := lt.Fail(0, "test", "127.0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := lt.Check(0, "test", "127.0.0.2"); err != nil {
+		t.Errorf("Expected no error before lockout, got: %v", err)
+	}
+
+	lt.Fail(0, "test", "127.0.0.1")
+	if err := lt.Check(0, "test", "127.0.0.2"); err != ErrLockedOut {
+		t.Errorf("Error expected: %v, got: %v", ErrLockedOut, err)
+	}
+
+	if err := lt.Check(2, "test", "127.0.0.2"); err != nil {
+		t.Errorf("Expected no error for user of another tenant, got: %v", err)
+	}
+
+	if err := lt.Check(0, "other", "127.0.0.1"); err != ErrLockedOut {
+		t.Errorf("Error expected: %v, got: %v", ErrLockedOut, err)
+	}
+
+	if err := lt.Succeed(0, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := lt.Check(0, "test", "127.0.0.2"); err != nil {
+		t.Errorf("Expected no error after success, got: %v", err)
+	}
+
+	if err := lt.Check(0, "test", "127.0.0.1"); err != ErrLockedOut {
+		t.Errorf("Error expected: %v, got: %v", ErrLockedOut, err)
+	}
+}
+
+func TestLoginThrottleWindow(t *testing.T) {
+	ms := NewMemoryStore()
+	old := time.Now().Add(-2 * DefaultFailWindow)
+	ms.SaveLockout(&Lockout{User: "test", Failures: 4, Last: &old, Until: &old})
+	lt := NewLoginThrottle(ms)
+	lt.Fail(0, "test", "")
+	user := "test"
+	for r := range ms.GetLockouts(&LockoutFind{User: &user}) {
+		if l := r.Val.(*Lockout); l.Failures != 1 {
+			t.Errorf("Failures expected: 1, got: %v", l.Failures)
+		}
+	}
+}
+
+func TestClientAddr(t *testing.T) {
+	if v := ClientAddr(context.Background()); v != "" {
+		t.Errorf("Value expected: \"\", got: %v", v)
+	}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 1234},
+	})
+
+	if v := ClientAddr(ctx); v != "127.0.0.1" {
+		t.Errorf("Value expected: 127.0.0.1, got: %v", v)
+	}
+}
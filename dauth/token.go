@@ -1,9 +1,11 @@
 package dauth
 
 import (
+	"database/sql"
 	"encoding/json"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dhaifley/dlib"
@@ -11,34 +13,51 @@ import (
 	"github.com/golang/protobuf/ptypes/timestamp"
 )
 
-// Token values represenst a single API token.
+// Token values represenst a single API token. Scope, if not empty, limits
+// the token to a subset of its user's permissions, as "service:name"
+// permission claims which may use wildcards. ActorID, if set, is the ID of
+// the user impersonating the user of the token. It is set only when an
+// impersonation token is issued, and is not changed by updates.
 type Token struct {
-	ID      int64      `json:"id,omitempty"`
-	Token   string     `json:"token,omitempty"`
-	UserID  int64      `json:"user_id,omitempty"`
-	Created *time.Time `json:"created,omitempty"`
-	Expires *time.Time `json:"expires,omitempty"`
+	ID       int64      `json:"id,omitempty" bson:"_id"`
+	Token    string     `json:"token,omitempty" bson:"token"`
+	UserID   int64      `json:"user_id,omitempty" bson:"user_id"`
+	Created  *time.Time `json:"created,omitempty" bson:"created,omitempty"`
+	Expires  *time.Time `json:"expires,omitempty" bson:"expires,omitempty"`
+	Scope    []string   `json:"scope,omitempty" bson:"scope,omitempty"`
+	TenantID int64      `json:"tenant_id,omitempty" bson:"tenant_id"`
+	ActorID  int64      `json:"actor_id,omitempty" bson:"actor_id,omitempty"`
 }
 
-// TokenRow values represent a single row in the token table.
+// TokenRow values represent a single row in the token table. The scope is
+// stored as a space separated list, and the actor is null for tokens which
+// are not impersonation tokens.
 type TokenRow struct {
-	ID      int64
-	Token   string
-	UserID  int64
-	Created dlib.NullTime
-	Expires dlib.NullTime
+	ID       int64
+	Token    string
+	UserID   int64
+	Created  dlib.NullTime
+	Expires  dlib.NullTime
+	Scope    string
+	TenantID int64
+	ActorID  sql.NullInt64
 }
 
 // TokenFind values are used to find token records in the database.
+// The page limits and orders the records found.
 type TokenFind struct {
-	ID      *int64     `json:"id,omitempty"`
-	Token   *string    `json:"token,omitempty"`
-	UserID  *int64     `json:"user_id,omitempty"`
-	Created *time.Time `json:"created,omitempty"`
-	Expires *time.Time `json:"expires,omitempty"`
-	Start   *time.Time `json:"start,omitempty"`
-	End     *time.Time `json:"end,omitempty"`
-	Old     *time.Time `json:"old,omitempty"`
+	ID       *int64     `json:"id,omitempty"`
+	Token    *string    `json:"token,omitempty"`
+	UserID   *int64     `json:"user_id,omitempty"`
+	Created  *time.Time `json:"created,omitempty"`
+	Expires  *time.Time `json:"expires,omitempty"`
+	Start    *time.Time `json:"start,omitempty"`
+	End      *time.Time `json:"end,omitempty"`
+	Old      *time.Time `json:"old,omitempty"`
+	Expired  *time.Time `json:"expired,omitempty"`
+	TenantID *int64     `json:"tenant_id,omitempty"`
+	ActorID  *int64     `json:"actor_id,omitempty"`
+	Page
 }
 
 // NewToken initializes and returns a pointer to a new token value.
@@ -73,6 +92,12 @@ func (t *Token) Equals(b *Token) bool {
 		return false
 	case t.Expires != nil && b.Expires != nil && *t.Expires != *b.Expires:
 		return false
+	case strings.Join(t.Scope, " ") != strings.Join(b.Scope, " "):
+		return false
+	case t.TenantID != b.TenantID:
+		return false
+	case t.ActorID != b.ActorID:
+		return false
 	default:
 		return true
 	}
@@ -84,6 +109,8 @@ func (t *Token) Copy() Token {
 	b.ID = t.ID
 	b.Token = t.Token
 	b.UserID = t.UserID
+	b.TenantID = t.TenantID
+	b.ActorID = t.ActorID
 	if t.Created != nil {
 		d := *t.Created
 		b.Created = &d
@@ -94,9 +121,19 @@ func (t *Token) Copy() Token {
 		b.Expires = &d
 	}
 
+	if t.Scope != nil {
+		b.Scope = append([]string{}, t.Scope...)
+	}
+
 	return b
 }
 
+// Allows tests whether the scope of the token includes a permission. A
+// token with no scope allows all of its user's permissions.
+func (t *Token) Allows(p *Perm) bool {
+	return scopeAllows(t.Scope, p)
+}
+
 // String formats an Token value as a JSON format string.
 func (t *Token) String() string {
 	str, err := json.Marshal(t)
@@ -112,6 +149,9 @@ func (t *Token) FromRequest(req *ptypes.TokenRequest) error {
 	t.ID = req.ID
 	t.Token = req.Token
 	t.UserID = req.UserID
+	t.Scope = req.Scope
+	t.TenantID = req.TenantID
+	t.ActorID = req.ActorID
 	if req.Created != nil {
 		tt := time.Unix(req.Created.Seconds, 0)
 		t.Created = &tt
@@ -125,12 +165,63 @@ func (t *Token) FromRequest(req *ptypes.TokenRequest) error {
 	return nil
 }
 
+// Update applies a token protobuf request to this value, subject to the
+// update mask of the request. The ID is set from the request if it has one.
+func (t *Token) Update(req *ptypes.TokenRequest) error {
+	m, err := NewUpdateMask(req.UpdateMask, "token", "user_id", "created", "expires",
+		"scope", "tenant_id")
+	if err != nil {
+		return err
+	}
+
+	if req.ID != 0 {
+		t.ID = req.ID
+	}
+
+	if m.Applies("token", req.Token != "") {
+		t.Token = req.Token
+	}
+
+	if m.Applies("user_id", req.UserID != 0) {
+		t.UserID = req.UserID
+	}
+
+	if m.Applies("created", req.Created != nil) {
+		t.Created = nil
+		if req.Created != nil {
+			tt := time.Unix(req.Created.Seconds, 0)
+			t.Created = &tt
+		}
+	}
+
+	if m.Applies("expires", req.Expires != nil) {
+		t.Expires = nil
+		if req.Expires != nil {
+			tt := time.Unix(req.Expires.Seconds, 0)
+			t.Expires = &tt
+		}
+	}
+
+	if m.Applies("scope", len(req.Scope) > 0) {
+		t.Scope = req.Scope
+	}
+
+	if m.Applies("tenant_id", req.TenantID != 0) {
+		t.TenantID = req.TenantID
+	}
+
+	return nil
+}
+
 // ToRequest returns a token protobuf message created from this value.
 func (t *Token) ToRequest() ptypes.TokenRequest {
 	req := ptypes.TokenRequest{}
 	req.ID = t.ID
 	req.Token = t.Token
 	req.UserID = t.UserID
+	req.Scope = t.Scope
+	req.TenantID = t.TenantID
+	req.ActorID = t.ActorID
 	if t.Created != nil {
 		req.Created = &timestamp.Timestamp{Seconds: t.Created.Unix(), Nanos: 0}
 	}
@@ -147,6 +238,9 @@ func (t *Token) FromResponse(res *ptypes.TokenResponse) error {
 	t.ID = res.ID
 	t.Token = res.Token
 	t.UserID = res.UserID
+	t.Scope = res.Scope
+	t.TenantID = res.TenantID
+	t.ActorID = res.ActorID
 	if res.Created != nil {
 		tt := time.Unix(res.Created.Seconds, 0)
 		t.Created = &tt
@@ -166,6 +260,9 @@ func (t *Token) ToResponse() ptypes.TokenResponse {
 	res.ID = t.ID
 	res.Token = t.Token
 	res.UserID = t.UserID
+	res.Scope = t.Scope
+	res.TenantID = t.TenantID
+	res.ActorID = t.ActorID
 	if t.Created != nil {
 		res.Created = &timestamp.Timestamp{Seconds: t.Created.Unix(), Nanos: 0}
 	}
@@ -219,6 +316,21 @@ func (t *Token) FromQueryValues(vals url.Values) error {
 		t.Expires = &pt
 	}
 
+	t.Scope = vals["scope"]
+	t.TenantID = 0
+	if vals.Get("tenant_id") != "" {
+		if t.TenantID, err = strconv.ParseInt(vals.Get("tenant_id"), 10, 64); err != nil {
+			return err
+		}
+	}
+
+	t.ActorID = 0
+	if vals.Get("actor_id") != "" {
+		if t.ActorID, err = strconv.ParseInt(vals.Get("actor_id"), 10, 64); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -227,6 +339,9 @@ func (r *TokenRow) FromToken(t *Token) error {
 	r.ID = t.ID
 	r.Token = t.Token
 	r.UserID = t.UserID
+	r.Scope = strings.Join(t.Scope, " ")
+	r.TenantID = t.TenantID
+	r.ActorID = sql.NullInt64{Int64: t.ActorID, Valid: t.ActorID != 0}
 	if t.Created != nil {
 		r.Created = dlib.NullTime{Valid: true, Time: *t.Created}
 	}
@@ -244,6 +359,12 @@ func (r *TokenRow) ToToken() Token {
 	t.ID = r.ID
 	t.Token = r.Token
 	t.UserID = r.UserID
+	t.TenantID = r.TenantID
+	t.ActorID = r.ActorID.Int64
+	if r.Scope != "" {
+		t.Scope = strings.Fields(r.Scope)
+	}
+
 	if r.Created.Valid {
 		tt := r.Created.Time
 		t.Created = &tt
@@ -286,6 +407,16 @@ func (f *TokenFind) FromToken(t *Token) error {
 		f.Expires = &dt
 	}
 
+	f.TenantID = nil
+	if t.TenantID != 0 {
+		f.TenantID = &t.TenantID
+	}
+
+	f.ActorID = nil
+	if t.ActorID != 0 {
+		f.ActorID = &t.ActorID
+	}
+
 	return nil
 }
 
@@ -336,5 +467,21 @@ func (f *TokenFind) FromTokenRequest(r *ptypes.TokenRequest) error {
 		f.Old = &dt
 	}
 
+	f.Expired = nil
+	if r.Expired != nil {
+		dt := time.Unix(r.Expired.Seconds, 0)
+		f.Expired = &dt
+	}
+
+	f.TenantID = nil
+	if r.TenantID != 0 {
+		f.TenantID = &r.TenantID
+	}
+
+	f.ActorID = nil
+	if r.ActorID != 0 {
+		f.ActorID = &r.ActorID
+	}
+
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/dhaifley/dlib/ptypes"
+	"google.golang.org/genproto/protobuf/field_mask"
 )
 
 func TestTokenEquals(t *testing.T) {
@@ -95,6 +96,26 @@ func TestTokenFromRequest(t *testing.T) {
 	}
 }
 
+func TestTokenUpdate(t *testing.T) {
+	exp := time.Now().Add(time.Hour)
+	tok := Token{ID: 1, Token: "test", UserID: 1, Expires: &exp, Scope: []string{"a:b"}}
+	err := tok.Update(&ptypes.TokenRequest{
+		UserID:     2,
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"expires", "scope"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tok.Expires != nil || tok.Scope != nil {
+		t.Errorf("Expected cleared expires and scope, got: %v", tok.String())
+	}
+
+	if tok.UserID != 1 || tok.Token != "test" {
+		t.Errorf("Expected unchanged user_id and token, got: %v", tok.String())
+	}
+}
+
 func TestTokenToRequest(t *testing.T) {
 	dv := Token{
 		ID:    int64(1),
@@ -155,8 +176,10 @@ func TestTokenToResponse(t *testing.T) {
 
 func TestTokenRowFromToken(t *testing.T) {
 	tk := Token{
-		ID:    1,
-		Token: "test",
+		ID:      1,
+		Token:   "test",
+		Scope:   []string{"test:read", "test:list"},
+		ActorID: 2,
 	}
 
 	tr := TokenRow{}
@@ -173,6 +196,58 @@ func TestTokenRowFromToken(t *testing.T) {
 	if tr.Token != exp {
 		t.Errorf("Value expected: %v, got: %v", exp, tk.Token)
 	}
+
+	exp = "test:read test:list"
+	if tr.Scope != exp {
+		t.Errorf("Scope expected: %v, got: %v", exp, tr.Scope)
+	}
+
+	if !tr.ActorID.Valid || tr.ActorID.Int64 != 2 {
+		t.Errorf("ActorID expected: 2, got: %v", tr.ActorID)
+	}
+
+	if rt := tr.ToToken(); !rt.Equals(&tk) {
+		t.Errorf("Token expected: %v, got: %v", tk, rt)
+	}
+}
+
+func TestTokenScopeRequest(t *testing.T) {
+	tk := Token{ID: 1, Token: "test", Scope: []string{"test:read"}}
+	req := tk.ToRequest()
+	rt := Token{}
+	rt.FromRequest(&req)
+	if !rt.Equals(&tk) {
+		t.Errorf("Token expected: %v, got: %v", tk, rt)
+	}
+
+	res := tk.ToResponse()
+	rt = Token{}
+	rt.FromResponse(&res)
+	if !rt.Equals(&tk) {
+		t.Errorf("Token expected: %v, got: %v", tk, rt)
+	}
+}
+
+func TestTokenAllows(t *testing.T) {
+	cases := []struct {
+		scope []string
+		perm  *Perm
+		exp   bool
+	}{
+		{nil, NewPerm(0, "test", "write"), true},
+		{[]string{"test:read"}, NewPerm(0, "test", "read"), true},
+		{[]string{"test:read"}, NewPerm(0, "test", "write"), false},
+		{[]string{"test:read.*"}, NewPerm(0, "test", "read.all"), true},
+		{[]string{"other:*", "test:read"}, NewPerm(0, "other", "write"), true},
+		{[]string{"invalid"}, NewPerm(0, "test", "read"), false},
+	}
+
+	for _, c := range cases {
+		tk := Token{Scope: c.scope}
+		if v := tk.Allows(c.perm); v != c.exp {
+			t.Errorf("Allows %v %v expected: %v, got: %v", c.scope, c.perm, c.exp, v)
+		}
+	}
 }
 
 func TestTokenRowToToken(t *testing.T) {
@@ -0,0 +1,141 @@
+package dauth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Default TOTP settings, which are those understood by common
+// authenticator apps.
+const (
+	DefaultTOTPDigits = 6
+	DefaultTOTPPeriod = 30 * time.Second
+	DefaultTOTPSkew   = 1
+)
+
+// totpSecretBytes is the number of random bytes in a TOTP secret.
+const totpSecretBytes = 20
+
+// totpEncoding is the unpadded base32 encoding used for TOTP secrets.
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTP values generate and validate RFC 6238 time-based one time passwords
+// using HMAC-SHA1. Codes from up to Skew periods before or after the
+// current one are accepted to allow for clock drift.
+type TOTP struct {
+	Digits int
+	Period time.Duration
+	Skew   int64
+}
+
+// NewTOTP initializes and returns a pointer to a new TOTP value using the
+// default settings.
+func NewTOTP() *TOTP {
+	return &TOTP{
+		Digits: DefaultTOTPDigits,
+		Period: DefaultTOTPPeriod,
+		Skew:   DefaultTOTPSkew,
+	}
+}
+
+// NewTOTPSecret returns a new random TOTP secret in base32 encoding.
+func NewTOTPSecret() (string, error) {
+	b := make([]byte, totpSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return totpEncoding.EncodeToString(b), nil
+}
+
+// Step returns the time step containing a time.
+func (tp *TOTP) Step(t time.Time) int64 {
+	return t.Unix() / int64(tp.Period/time.Second)
+}
+
+// code returns the code for a decoded secret and time step.
+func (tp *TOTP) code(key []byte, step int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(step))
+	h := hmac.New(sha1.New, key)
+	h.Write(msg)
+	sum := h.Sum(nil)
+	off := sum[len(sum)-1] & 0x0f
+	v := binary.BigEndian.Uint32(sum[off:off+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < tp.Digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", tp.Digits, v%mod)
+}
+
+// decodeSecret decodes a base32 TOTP secret.
+func decodeSecret(secret string) ([]byte, error) {
+	return totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+}
+
+// Code returns the code for a secret at a time.
+func (tp *TOTP) Code(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return tp.code(key, tp.Step(t)), nil
+}
+
+// Validate tests whether a code is valid for a secret at a time, within the
+// allowed drift. Codes from time steps up to and including after are
+// rejected, so that a code can not be used twice. It returns the time step
+// the code matched, which should be passed as after on the next call.
+func (tp *TOTP) Validate(secret, code string, t time.Time, after int64) (int64, bool, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if len(code) != tp.Digits {
+		return 0, false, nil
+	}
+
+	step := tp.Step(t)
+	for i := -tp.Skew; i <= tp.Skew; i++ {
+		s := step + i
+		if s <= after {
+			continue
+		}
+
+		if hmac.Equal([]byte(tp.code(key, s)), []byte(code)) {
+			return s, true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+// URI returns the otpauth provisioning URI for a secret, which can be
+// rendered as a QR code for authenticator apps to scan.
+func (tp *TOTP) URI(issuer, account, secret string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", issuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprint(tp.Digits))
+	v.Set("period", fmt.Sprint(int64(tp.Period/time.Second)))
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: v.Encode(),
+	}
+
+	return u.String()
+}
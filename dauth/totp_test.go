@@ -0,0 +1,92 @@
+package dauth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 test secret from RFC 6238 in base32.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	tp := &TOTP{Digits: 8, Period: 30 * time.Second}
+	cases := []struct {
+		t   int64
+		exp string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+	}
+
+	for _, c := range cases {
+		v, err := tp.Code(rfcSecret, time.Unix(c.t, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v != c.exp {
+			t.Errorf("Value expected: %v, got: %v", c.exp, v)
+		}
+	}
+}
+
+func TestTOTPValidate(t *testing.T) {
+	tp := NewTOTP()
+	now := time.Unix(1234567890, 0)
+	prev, _ := tp.Code(rfcSecret, now.Add(-30*time.Second))
+	old, _ := tp.Code(rfcSecret, now.Add(-90*time.Second))
+	step := tp.Step(now)
+	cases := []struct {
+		code  string
+		after int64
+		ok    bool
+	}{
+		{prev, 0, true},
+		{prev, step - 1, false},
+		{old, 0, false},
+		{"12345", 0, false},
+	}
+
+	for _, c := range cases {
+		s, ok, err := tp.Validate(rfcSecret, c.code, now, c.after)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ok != c.ok {
+			t.Errorf("Expected bool: %v, got: %v", c.ok, ok)
+		}
+
+		if ok && s != step-1 {
+			t.Errorf("Step expected: %v, got: %v", step-1, s)
+		}
+	}
+
+	if _, _, err := tp.Validate("not base32!", "123456", now, 0); err == nil {
+		t.Error("Expected error for invalid secret")
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	v := NewTOTP().URI("dauth", "test", rfcSecret)
+	exp := "otpauth://totp/dauth:test?algorithm=SHA1&digits=6&issuer=dauth&period=30&secret=" +
+		rfcSecret
+	if v != exp {
+		t.Errorf("Value expected: %v, got: %v", exp, v)
+	}
+}
+
+func TestNewTOTPSecret(t *testing.T) {
+	s, err := NewTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s) != 32 || strings.ContainsAny(s, "=") {
+		t.Errorf("Unexpected secret: %v", s)
+	}
+}
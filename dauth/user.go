@@ -6,34 +6,53 @@ import (
 	"net/url"
 	"strconv"
 
+	"github.com/dhaifley/dlib"
 	"github.com/dhaifley/dlib/ptypes"
 )
 
-// User represensts a single API user.
+// errClearPass is returned by updates which would clear the pass of a user.
+var errClearPass = &dlib.Error{Code: 400, Msg: "pass can not be cleared"}
+
+// User represensts a single API user. The pass field is secret and is
+// redacted from string, JSON and protobuf response output unless the value is
+// explicitly converted with Unredacted. Actor, if set, is the user acting as
+// this user through an impersonation token. It is set when the user is
+// authenticated, and is not stored. Provider names the external identity
+// provider which verified the user when it was provisioned into the store,
+// and is empty for users created in the store.
 type User struct {
-	ID    int64  `json:"id,omitempty"`
-	User  string `json:"user,omitempty"`
-	Pass  string `json:"pass,omitempty"`
-	Name  string `json:"name,omitempty"`
-	Email string `json:"email,omitempty"`
+	ID       int64  `json:"id,omitempty" bson:"_id"`
+	User     string `json:"user,omitempty" bson:"user"`
+	Pass     string `json:"pass,omitempty" bson:"pass,omitempty" secret:"true"`
+	Name     string `json:"name,omitempty" bson:"name,omitempty"`
+	Email    string `json:"email,omitempty" bson:"email,omitempty"`
+	TenantID int64  `json:"tenant_id,omitempty" bson:"tenant_id"`
+	Provider string `json:"provider,omitempty" bson:"provider,omitempty"`
+	Actor    *User  `json:"actor,omitempty" bson:"-"`
 }
 
 // UserRow values represent a single row in the user table.
 type UserRow struct {
-	ID    int64
-	User  string
-	Pass  string
-	Name  sql.NullString
-	Email sql.NullString
+	ID       int64
+	User     string
+	Pass     string
+	Name     sql.NullString
+	Email    sql.NullString
+	TenantID int64
+	Provider sql.NullString
 }
 
 // UserFind values are used to find user records in the database.
+// The page limits and orders the records found.
 type UserFind struct {
-	ID    *int64  `json:"id,omitempty"`
-	User  *string `json:"user,omitempty"`
-	Pass  *string `json:"pass,omitempty"`
-	Name  *string `json:"name,omitempty"`
-	Email *string `json:"email,omitempty"`
+	ID       *int64  `json:"id,omitempty"`
+	User     *string `json:"user,omitempty"`
+	Pass     *string `json:"pass,omitempty" secret:"true"`
+	Name     *string `json:"name,omitempty"`
+	Email    *string `json:"email,omitempty"`
+	TenantID *int64  `json:"tenant_id,omitempty"`
+	Provider *string `json:"provider,omitempty"`
+	Page
 }
 
 // NewUser initializes and returns a pointer to a new user value.
@@ -64,6 +83,10 @@ func (u *User) Equals(b *User) bool {
 		return false
 	case u.Email != b.Email:
 		return false
+	case u.TenantID != b.TenantID:
+		return false
+	case u.Provider != b.Provider:
+		return false
 	default:
 		return true
 	}
@@ -77,10 +100,45 @@ func (u *User) Copy() User {
 	b.Pass = u.Pass
 	b.Name = u.Name
 	b.Email = u.Email
+	b.TenantID = u.TenantID
+	b.Provider = u.Provider
+	if u.Actor != nil {
+		a := u.Actor.Copy()
+		b.Actor = &a
+	}
+
 	return b
 }
 
-// String formats a user value as a JSON format string.
+// UnredactedUser values are user values whose secret fields are included
+// in string, JSON and protobuf response output.
+type UnredactedUser User
+
+// Redact returns a copy of the value with its secret fields removed.
+func (u *User) Redact() interface{} {
+	b := u.Copy()
+	b.Pass = ""
+	if b.Actor != nil {
+		b.Actor.Pass = ""
+	}
+
+	return &b
+}
+
+// Unredacted returns a copy of the value which includes its secret fields
+// in output. It must only be used where exposing secrets is intended.
+func (u *User) Unredacted() *UnredactedUser {
+	b := UnredactedUser(u.Copy())
+	return &b
+}
+
+// MarshalJSON encodes a user value as JSON with its secret fields redacted.
+func (u User) MarshalJSON() ([]byte, error) {
+	return json.Marshal(UnredactedUser(*u.Redact().(*User)))
+}
+
+// String formats a user value as a JSON format string with its secret
+// fields redacted.
 func (u *User) String() string {
 	str, err := json.Marshal(u)
 	if err != nil {
@@ -112,6 +170,61 @@ func (u *User) FromRequest(req *ptypes.UserRequest) error {
 		u.Email = req.Email
 	}
 
+	if req.TenantID != 0 {
+		u.TenantID = req.TenantID
+	}
+
+	if req.Provider != "" {
+		u.Provider = req.Provider
+	}
+
+	return nil
+}
+
+// Update applies a user protobuf request to this value, subject to the
+// update mask of the request. The ID is set from the request if it has one.
+// The pass can not be cleared, since a user without a password hash could
+// be adopted by an external identity provider, so a mask naming the pass
+// with an empty pass in the request returns a 400 error.
+func (u *User) Update(req *ptypes.UserRequest) error {
+	m, err := NewUpdateMask(req.UpdateMask, "user", "pass", "name", "email", "tenant_id",
+		"provider")
+	if err != nil {
+		return err
+	}
+
+	if req.Pass == "" && m.Applies("pass", false) {
+		return errClearPass
+	}
+
+	if req.ID != 0 {
+		u.ID = req.ID
+	}
+
+	if m.Applies("user", req.User != "") {
+		u.User = req.User
+	}
+
+	if m.Applies("pass", req.Pass != "") {
+		u.Pass = req.Pass
+	}
+
+	if m.Applies("name", req.Name != "") {
+		u.Name = req.Name
+	}
+
+	if m.Applies("email", req.Email != "") {
+		u.Email = req.Email
+	}
+
+	if m.Applies("tenant_id", req.TenantID != 0) {
+		u.TenantID = req.TenantID
+	}
+
+	if m.Applies("provider", req.Provider != "") {
+		u.Provider = req.Provider
+	}
+
 	return nil
 }
 
@@ -123,6 +236,8 @@ func (u *User) ToRequest() ptypes.UserRequest {
 	req.Pass = u.Pass
 	req.Name = u.Name
 	req.Email = u.Email
+	req.TenantID = u.TenantID
+	req.Provider = u.Provider
 	return req
 }
 
@@ -148,17 +263,44 @@ func (u *User) FromResponse(res *ptypes.UserResponse) error {
 		u.Email = res.Email
 	}
 
+	if res.TenantID != 0 {
+		u.TenantID = res.TenantID
+	}
+
+	if res.Provider != "" {
+		u.Provider = res.Provider
+	}
+
 	return nil
 }
 
-// ToResponse returns a user protobuf message created from this value.
+// ToResponse returns a user protobuf message created from this value with
+// its secret fields redacted.
 func (u *User) ToResponse() ptypes.UserResponse {
+	return u.Redact().(*User).Unredacted().ToResponse()
+}
+
+// String formats an unredacted user value as a JSON format string.
+func (u *UnredactedUser) String() string {
+	str, err := json.Marshal(u)
+	if err != nil {
+		return ""
+	}
+
+	return string(str)
+}
+
+// ToResponse returns a user protobuf message created from this value,
+// including its secret fields.
+func (u *UnredactedUser) ToResponse() ptypes.UserResponse {
 	res := ptypes.UserResponse{}
 	res.ID = u.ID
 	res.User = u.User
 	res.Pass = u.Pass
 	res.Name = u.Name
 	res.Email = u.Email
+	res.TenantID = u.TenantID
+	res.Provider = u.Provider
 	return res
 }
 
@@ -188,6 +330,29 @@ func (u *User) FromQueryValues(vals url.Values) error {
 		u.Email = vals.Get("email")
 	}
 
+	if vals.Get("tenant_id") != "" {
+		u.TenantID, err = strconv.ParseInt(vals.Get("tenant_id"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	if vals.Get("provider") != "" {
+		u.Provider = vals.Get("provider")
+	}
+
+	return nil
+}
+
+// FromUser populates a user row value from a user value.
+func (r *UserRow) FromUser(u *User) error {
+	r.ID = u.ID
+	r.User = u.User
+	r.Pass = u.Pass
+	r.Name = sql.NullString{String: u.Name, Valid: u.Name != ""}
+	r.Email = sql.NullString{String: u.Email, Valid: u.Email != ""}
+	r.TenantID = u.TenantID
+	r.Provider = sql.NullString{String: u.Provider, Valid: u.Provider != ""}
 	return nil
 }
 
@@ -197,6 +362,7 @@ func (r UserRow) ToUser() User {
 	u.ID = r.ID
 	u.User = r.User
 	u.Pass = r.Pass
+	u.TenantID = r.TenantID
 	if r.Name.Valid {
 		u.Name = r.Name.String
 	}
@@ -205,55 +371,89 @@ func (r UserRow) ToUser() User {
 		u.Email = r.Email.String
 	}
 
+	if r.Provider.Valid {
+		u.Provider = r.Provider.String
+	}
+
 	return u
 }
 
 // FromUser populates a user find value from a user value.
 func (f *UserFind) FromUser(u *User) error {
+	f.ID = nil
 	if u.ID != 0 {
 		f.ID = &u.ID
 	}
 
+	f.User = nil
 	if u.User != "" {
 		f.User = &u.User
 	}
 
+	f.Pass = nil
 	if u.Pass != "" {
 		f.Pass = &u.Pass
 	}
 
+	f.Name = nil
 	if u.Name != "" {
 		f.Name = &u.Name
 	}
 
+	f.Email = nil
 	if u.Email != "" {
 		f.Email = &u.Email
 	}
 
+	f.TenantID = nil
+	if u.TenantID != 0 {
+		f.TenantID = &u.TenantID
+	}
+
+	f.Provider = nil
+	if u.Provider != "" {
+		f.Provider = &u.Provider
+	}
+
 	return nil
 }
 
 // FromUserRequest populates a user find value from a user protobuf request.
 func (f *UserFind) FromUserRequest(r *ptypes.UserRequest) error {
+	f.ID = nil
 	if r.ID != 0 {
 		f.ID = &r.ID
 	}
 
+	f.User = nil
 	if r.User != "" {
 		f.User = &r.User
 	}
 
+	f.Pass = nil
 	if r.Pass != "" {
 		f.Pass = &r.Pass
 	}
 
+	f.Name = nil
 	if r.Name != "" {
 		f.Name = &r.Name
 	}
 
+	f.Email = nil
 	if r.Email != "" {
 		f.Email = &r.Email
 	}
 
+	f.TenantID = nil
+	if r.TenantID != 0 {
+		f.TenantID = &r.TenantID
+	}
+
+	f.Provider = nil
+	if r.Provider != "" {
+		f.Provider = &r.Provider
+	}
+
 	return nil
 }
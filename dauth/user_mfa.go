@@ -0,0 +1,215 @@
+package dauth
+
+import "encoding/json"
+
+// UserMFA values represent the multi-factor authentication enrollment of a
+// single user. Secret holds the encrypted TOTP secret. Logins require a
+// code once the enrollment has been confirmed and Enabled is set. LastStep
+// is the TOTP time step of the last code accepted, so that no code is
+// accepted twice.
+type UserMFA struct {
+	ID       int64  `json:"id,omitempty" bson:"_id"`
+	UserID   int64  `json:"user_id,omitempty" bson:"user_id"`
+	Secret   string `json:"secret,omitempty" bson:"secret" secret:"true"`
+	Enabled  bool   `json:"enabled,omitempty" bson:"enabled"`
+	LastStep int64  `json:"last_step,omitempty" bson:"last_step"`
+}
+
+// UserMFARow values represent a single row in the user_mfa table.
+type UserMFARow struct {
+	ID       int64
+	UserID   int64
+	Secret   string
+	Enabled  bool
+	LastStep int64
+}
+
+// UserMFAFind values are used to find user MFA records in the database.
+type UserMFAFind struct {
+	ID      *int64 `json:"id,omitempty"`
+	UserID  *int64 `json:"user_id,omitempty"`
+	Enabled *bool  `json:"enabled,omitempty"`
+}
+
+// NewUserMFA initializes and returns a pointer to a new user MFA value.
+func NewUserMFA(id, userID int64, secret string, enabled bool, lastStep int64) *UserMFA {
+	return &UserMFA{
+		ID:       id,
+		UserID:   userID,
+		Secret:   secret,
+		Enabled:  enabled,
+		LastStep: lastStep,
+	}
+}
+
+// Equals tests for deep equality between user MFA values.
+func (um *UserMFA) Equals(b *UserMFA) bool {
+	switch {
+	case um == nil || b == nil:
+		return false
+	case *um != *b:
+		return false
+	default:
+		return true
+	}
+}
+
+// Copy returns an exact copy of the value.
+func (um *UserMFA) Copy() UserMFA {
+	return *um
+}
+
+// String formats a user MFA value as a JSON format string. The secret is
+// redacted.
+func (um *UserMFA) String() string {
+	b := *um
+	b.Secret = ""
+	str, err := json.Marshal(&b)
+	if err != nil {
+		return ""
+	}
+
+	return string(str)
+}
+
+// FromUserMFA populates a row value from a user MFA value.
+func (r *UserMFARow) FromUserMFA(um *UserMFA) error {
+	r.ID = um.ID
+	r.UserID = um.UserID
+	r.Secret = um.Secret
+	r.Enabled = um.Enabled
+	r.LastStep = um.LastStep
+	return nil
+}
+
+// ToUserMFA returns a value created from this row value.
+func (r *UserMFARow) ToUserMFA() UserMFA {
+	return UserMFA{
+		ID:       r.ID,
+		UserID:   r.UserID,
+		Secret:   r.Secret,
+		Enabled:  r.Enabled,
+		LastStep: r.LastStep,
+	}
+}
+
+// FromUserMFA populates a user MFA find value from a user MFA value.
+func (f *UserMFAFind) FromUserMFA(um *UserMFA) error {
+	f.ID = nil
+	if um.ID != 0 {
+		f.ID = &um.ID
+	}
+
+	f.UserID = nil
+	if um.UserID != 0 {
+		f.UserID = &um.UserID
+	}
+
+	f.Enabled = nil
+	if um.Enabled {
+		f.Enabled = &um.Enabled
+	}
+
+	return nil
+}
+
+// RecoveryCode values represent a single use recovery code which can be
+// used in place of a TOTP code. Only the hash of the code is stored.
+type RecoveryCode struct {
+	ID     int64  `json:"id,omitempty" bson:"_id"`
+	UserID int64  `json:"user_id,omitempty" bson:"user_id"`
+	Code   string `json:"code,omitempty" bson:"code" secret:"true"`
+}
+
+// RecoveryCodeRow values represent a single row in the recovery_code table.
+type RecoveryCodeRow struct {
+	ID     int64
+	UserID int64
+	Code   string
+}
+
+// RecoveryCodeFind values are used to find recovery code records in the
+// database.
+type RecoveryCodeFind struct {
+	ID     *int64  `json:"id,omitempty"`
+	UserID *int64  `json:"user_id,omitempty"`
+	Code   *string `json:"code,omitempty" secret:"true"`
+}
+
+// NewRecoveryCode initializes and returns a pointer to a new recovery code
+// value.
+func NewRecoveryCode(id, userID int64, code string) *RecoveryCode {
+	return &RecoveryCode{
+		ID:     id,
+		UserID: userID,
+		Code:   code,
+	}
+}
+
+// Equals tests for deep equality between recovery code values.
+func (rc *RecoveryCode) Equals(b *RecoveryCode) bool {
+	switch {
+	case rc == nil || b == nil:
+		return false
+	case *rc != *b:
+		return false
+	default:
+		return true
+	}
+}
+
+// Copy returns an exact copy of the value.
+func (rc *RecoveryCode) Copy() RecoveryCode {
+	return *rc
+}
+
+// String formats a recovery code value as a JSON format string. The code
+// is redacted.
+func (rc *RecoveryCode) String() string {
+	b := *rc
+	b.Code = ""
+	str, err := json.Marshal(&b)
+	if err != nil {
+		return ""
+	}
+
+	return string(str)
+}
+
+// FromRecoveryCode populates a row value from a recovery code value.
+func (r *RecoveryCodeRow) FromRecoveryCode(rc *RecoveryCode) error {
+	r.ID = rc.ID
+	r.UserID = rc.UserID
+	r.Code = rc.Code
+	return nil
+}
+
+// ToRecoveryCode returns a value created from this row value.
+func (r *RecoveryCodeRow) ToRecoveryCode() RecoveryCode {
+	return RecoveryCode{
+		ID:     r.ID,
+		UserID: r.UserID,
+		Code:   r.Code,
+	}
+}
+
+// FromRecoveryCode populates a recovery code find value from a recovery
+// code value.
+func (f *RecoveryCodeFind) FromRecoveryCode(rc *RecoveryCode) error {
+	f.ID = nil
+	if rc.ID != 0 {
+		f.ID = &rc.ID
+	}
+
+	f.UserID = nil
+	if rc.UserID != 0 {
+		f.UserID = &rc.UserID
+	}
+
+	f.Code = nil
+	if rc.Code != "" {
+		f.Code = &rc.Code
+	}
+
+	return nil
+}
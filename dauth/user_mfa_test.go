@@ -0,0 +1,94 @@
+package dauth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUserMFAEquals(t *testing.T) {
+	cases := []struct {
+		a        *UserMFA
+		b        *UserMFA
+		expected bool
+	}{
+		{NewUserMFA(1, 1, "test", true, 0), NewUserMFA(1, 1, "test", true, 0), true},
+		{NewUserMFA(1, 1, "test", true, 0), NewUserMFA(1, 1, "test", false, 0), false},
+		{NewUserMFA(1, 1, "test", true, 0), nil, false},
+	}
+
+	for _, c := range cases {
+		result := c.a.Equals(c.b)
+		if result != c.expected {
+			t.Errorf("Expected bool: %v, got: %v", c.expected, result)
+		}
+	}
+}
+
+func TestUserMFAString(t *testing.T) {
+	a := NewUserMFA(1, 2, "secret", true, 0)
+	result := a.String()
+	if strings.Contains(result, "secret\"") {
+		t.Errorf("Expected redacted secret, got: %v", result)
+	}
+
+	expected := `{"id":1,"user_id":2,"enabled":true}`
+	if result != expected {
+		t.Errorf("Expected string: %v, got: %v", expected, result)
+	}
+}
+
+func TestUserMFARow(t *testing.T) {
+	a := NewUserMFA(1, 2, "test", true, 3)
+	r := UserMFARow{}
+	if err := r.FromUserMFA(a); err != nil {
+		t.Error(err)
+	}
+
+	result := r.ToUserMFA()
+	if !result.Equals(a) {
+		t.Errorf("Expected user MFA: %v, got: %v", a, result)
+	}
+}
+
+func TestUserMFAFindFromUserMFA(t *testing.T) {
+	f := UserMFAFind{}
+	if err := f.FromUserMFA(NewUserMFA(0, 2, "test", false, 0)); err != nil {
+		t.Error(err)
+	}
+
+	if f.ID != nil || f.UserID == nil || *f.UserID != 2 || f.Enabled != nil {
+		t.Errorf("Unexpected find: %v", f)
+	}
+}
+
+func TestRecoveryCodeString(t *testing.T) {
+	a := NewRecoveryCode(1, 2, "test")
+	expected := `{"id":1,"user_id":2}`
+	if result := a.String(); result != expected {
+		t.Errorf("Expected string: %v, got: %v", expected, result)
+	}
+}
+
+func TestRecoveryCodeRow(t *testing.T) {
+	a := NewRecoveryCode(1, 2, "test")
+	r := RecoveryCodeRow{}
+	if err := r.FromRecoveryCode(a); err != nil {
+		t.Error(err)
+	}
+
+	result := r.ToRecoveryCode()
+	if !result.Equals(a) {
+		t.Errorf("Expected recovery code: %v, got: %v", a, result)
+	}
+}
+
+func TestRecoveryCodeFindFromRecoveryCode(t *testing.T) {
+	f := RecoveryCodeFind{}
+	if err := f.FromRecoveryCode(NewRecoveryCode(0, 2, "test")); err != nil {
+		t.Error(err)
+	}
+
+	if f.ID != nil || *f.UserID != 2 || *f.Code != "test" {
+		t.Errorf("Unexpected find: %v", f)
+	}
+}
@@ -2,31 +2,78 @@ package dauth
 
 import (
 	"encoding/json"
+	"net"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/dhaifley/dlib"
 	"github.com/dhaifley/dlib/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 )
 
 // UserPerm values represenst a single API user permission assignment.
+// A grant may be conditional. Expires, if set, is the time the grant ends.
+// Hours, such as "09:00-17:00", and Days, such as "mon-fri" or "sat,sun",
+// limit the grant to times of day and days of the week in the time zone
+// Zone, which is UTC if empty. A window of hours which ends before it
+// starts runs past midnight, and a window which ends when it starts is
+// invalid. CIDRs, if not empty, limits the grant to
+// requests from client addresses in any of the listed ranges.
 type UserPerm struct {
-	ID     int64 `json:"id,omitempty"`
-	UserID int64 `json:"user_id,omitempty"`
-	PermID int64 `json:"perm_id,omitempty"`
+	ID       int64      `json:"id,omitempty" bson:"_id"`
+	UserID   int64      `json:"user_id,omitempty" bson:"user_id"`
+	PermID   int64      `json:"perm_id,omitempty" bson:"perm_id"`
+	TenantID int64      `json:"tenant_id,omitempty" bson:"tenant_id"`
+	Expires  *time.Time `json:"expires,omitempty" bson:"expires,omitempty"`
+	Hours    string     `json:"hours,omitempty" bson:"hours,omitempty"`
+	Days     string     `json:"days,omitempty" bson:"days,omitempty"`
+	Zone     string     `json:"zone,omitempty" bson:"zone,omitempty"`
+	CIDRs    []string   `json:"cidrs,omitempty" bson:"cidrs,omitempty"`
 }
 
 // UserPermRow values represent a single row in the user_perm table.
 type UserPermRow struct {
-	ID     int64
-	UserID int64
-	PermID int64
+	ID       int64
+	UserID   int64
+	PermID   int64
+	TenantID int64
+	Expires  dlib.NullTime
+	Hours    string
+	Days     string
+	Zone     string
+	CIDRs    string
 }
 
 // UserPermFind values are used to find user_perm records in the database.
+// Expired finds grants which expire before a time. The page limits and
+// orders the records found.
 type UserPermFind struct {
-	ID     *int64 `json:"id,omitempty"`
-	UserID *int64 `json:"user_id,omitempty"`
-	PermID *int64 `json:"perm_id,omitempty"`
+	ID       *int64     `json:"id,omitempty"`
+	UserID   *int64     `json:"user_id,omitempty"`
+	PermID   *int64     `json:"perm_id,omitempty"`
+	TenantID *int64     `json:"tenant_id,omitempty"`
+	Expired  *time.Time `json:"expired,omitempty"`
+	Page
+}
+
+// GrantContext values hold the attributes of a request against which the
+// conditions of user permission grants are evaluated.
+type GrantContext struct {
+	Time time.Time
+	Addr string
+}
+
+// weekdays maps the names of days of the week used in grant conditions.
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
 }
 
 // NewUserPerm initializes and returns a pointer to a new
@@ -44,7 +91,16 @@ func (up *UserPerm) Equals(b *UserPerm) bool {
 	switch {
 	case up == nil || b == nil:
 		return false
-	case *up != *b:
+	case up.ID != b.ID || up.UserID != b.UserID || up.PermID != b.PermID ||
+		up.TenantID != b.TenantID:
+		return false
+	case (up.Expires == nil) != (b.Expires == nil):
+		return false
+	case up.Expires != nil && !up.Expires.Equal(*b.Expires):
+		return false
+	case up.Hours != b.Hours || up.Days != b.Days || up.Zone != b.Zone:
+		return false
+	case strings.Join(up.CIDRs, " ") != strings.Join(b.CIDRs, " "):
 		return false
 	default:
 		return true
@@ -53,11 +109,186 @@ func (up *UserPerm) Equals(b *UserPerm) bool {
 
 // Copy returns an exact deep copy of the value.
 func (up *UserPerm) Copy() UserPerm {
-	return UserPerm{
-		ID:     up.ID,
-		UserID: up.UserID,
-		PermID: up.PermID,
+	b := UserPerm{
+		ID:       up.ID,
+		UserID:   up.UserID,
+		PermID:   up.PermID,
+		TenantID: up.TenantID,
+		Hours:    up.Hours,
+		Days:     up.Days,
+		Zone:     up.Zone,
+	}
+
+	if up.Expires != nil {
+		d := *up.Expires
+		b.Expires = &d
+	}
+
+	if up.CIDRs != nil {
+		b.CIDRs = append([]string{}, up.CIDRs...)
+	}
+
+	return b
+}
+
+// Conditional tests whether the grant has any conditions.
+func (up *UserPerm) Conditional() bool {
+	return up.Expires != nil || up.Hours != "" || up.Days != "" || len(up.CIDRs) > 0
+}
+
+// Validate checks the conditions of the grant. It returns a 400 error if
+// any of them can not be parsed.
+func (up *UserPerm) Validate() error {
+	if _, err := time.LoadLocation(up.Zone); err != nil {
+		return dlib.NewError(400, "invalid zone: "+up.Zone)
+	}
+
+	if up.Hours != "" {
+		if _, _, ok := parseHours(up.Hours); !ok {
+			return dlib.NewError(400, "invalid hours: "+up.Hours)
+		}
+	}
+
+	if up.Days != "" {
+		if _, ok := parseDays(up.Days); !ok {
+			return dlib.NewError(400, "invalid days: "+up.Days)
+		}
+	}
+
+	for _, c := range up.CIDRs {
+		if _, _, err := net.ParseCIDR(c); err != nil {
+			return dlib.NewError(400, "invalid CIDR: "+c)
+		}
+	}
+
+	return nil
+}
+
+// Allows tests whether the conditions of the grant hold for a request.
+// Conditions which can not be parsed never hold.
+func (up *UserPerm) Allows(gc *GrantContext) bool {
+	if up.Expires != nil && !gc.Time.Before(*up.Expires) {
+		return false
+	}
+
+	if len(up.CIDRs) > 0 && !cidrsContain(up.CIDRs, gc.Addr) {
+		return false
+	}
+
+	if up.Hours == "" && up.Days == "" {
+		return true
+	}
+
+	loc, err := time.LoadLocation(up.Zone)
+	if err != nil {
+		return false
 	}
+
+	t := gc.Time.In(loc)
+	if up.Days != "" {
+		days, ok := parseDays(up.Days)
+		if !ok || !days[t.Weekday()] {
+			return false
+		}
+	}
+
+	if up.Hours != "" {
+		start, end, ok := parseHours(up.Hours)
+		if !ok {
+			return false
+		}
+
+		m := t.Hour()*60 + t.Minute()
+		if start <= end && (m < start || m >= end) {
+			return false
+		}
+
+		if start > end && m < start && m >= end {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseHours parses a window of hours, such as "09:00-17:00", and returns
+// its start and end as minutes past midnight. Empty windows, which end when
+// they start, are not valid.
+func parseHours(s string) (int, int, bool) {
+	v := strings.Split(s, "-")
+	if len(v) != 2 {
+		return 0, 0, false
+	}
+
+	m := [2]int{}
+	for i, h := range v {
+		t, err := time.Parse("15:04", strings.TrimSpace(h))
+		if err != nil {
+			return 0, 0, false
+		}
+
+		m[i] = t.Hour()*60 + t.Minute()
+	}
+
+	if m[0] == m[1] {
+		return 0, 0, false
+	}
+
+	return m[0], m[1], true
+}
+
+// parseDays parses a comma separated list of days of the week or ranges of
+// days, such as "mon-fri,sun", and returns the set of days.
+func parseDays(s string) (map[time.Weekday]bool, bool) {
+	days := map[time.Weekday]bool{}
+	for _, d := range strings.Split(strings.ToLower(s), ",") {
+		v := strings.Split(strings.TrimSpace(d), "-")
+		if len(v) > 2 {
+			return nil, false
+		}
+
+		start, ok := weekdays[v[0]]
+		if !ok {
+			return nil, false
+		}
+
+		end := start
+		if len(v) == 2 {
+			if end, ok = weekdays[v[1]]; !ok {
+				return nil, false
+			}
+		}
+
+		for wd := start; ; wd = (wd + 1) % 7 {
+			days[wd] = true
+			if wd == end {
+				break
+			}
+		}
+	}
+
+	return days, true
+}
+
+// cidrsContain tests whether an address, which may include a port, is in
+// any of a list of CIDR ranges.
+func cidrsContain(cidrs []string, addr string) bool {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+
+	for _, c := range cidrs {
+		if _, n, err := net.ParseCIDR(c); err == nil && n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // String formats a user_perm value as a JSON format string.
@@ -75,17 +306,91 @@ func (up *UserPerm) FromRequest(req *ptypes.UserPermRequest) error {
 	up.ID = req.ID
 	up.UserID = req.UserID
 	up.PermID = req.PermID
+	up.TenantID = req.TenantID
+	up.Expires = nil
+	if req.Expires != nil {
+		tt := time.Unix(req.Expires.Seconds, 0)
+		up.Expires = &tt
+	}
+
+	up.Hours = req.Hours
+	up.Days = req.Days
+	up.Zone = req.Zone
+	up.CIDRs = req.CIDRs
 	return nil
+}
+
+// Update applies a user permission protobuf request to this value, subject
+// to the update mask of the request. The ID is set from the request if it
+// has one.
+func (up *UserPerm) Update(req *ptypes.UserPermRequest) error {
+	m, err := NewUpdateMask(req.UpdateMask, "user_id", "perm_id", "tenant_id",
+		"expires", "hours", "days", "zone", "cidrs")
+	if err != nil {
+		return err
+	}
+
+	if req.ID != 0 {
+		up.ID = req.ID
+	}
+
+	if m.Applies("user_id", req.UserID != 0) {
+		up.UserID = req.UserID
+	}
+
+	if m.Applies("perm_id", req.PermID != 0) {
+		up.PermID = req.PermID
+	}
+
+	if m.Applies("tenant_id", req.TenantID != 0) {
+		up.TenantID = req.TenantID
+	}
+
+	if m.Applies("expires", req.Expires != nil) {
+		up.Expires = nil
+		if req.Expires != nil {
+			tt := time.Unix(req.Expires.Seconds, 0)
+			up.Expires = &tt
+		}
+	}
+
+	if m.Applies("hours", req.Hours != "") {
+		up.Hours = req.Hours
+	}
+
+	if m.Applies("days", req.Days != "") {
+		up.Days = req.Days
+	}
+
+	if m.Applies("zone", req.Zone != "") {
+		up.Zone = req.Zone
+	}
+
+	if m.Applies("cidrs", len(req.CIDRs) > 0) {
+		up.CIDRs = req.CIDRs
+	}
 
+	return nil
 }
 
 // ToRequest returns a protobuf request created from this value.
 func (up *UserPerm) ToRequest() ptypes.UserPermRequest {
-	return ptypes.UserPermRequest{
-		ID:     up.ID,
-		UserID: up.UserID,
-		PermID: up.PermID,
+	req := ptypes.UserPermRequest{
+		ID:       up.ID,
+		UserID:   up.UserID,
+		PermID:   up.PermID,
+		TenantID: up.TenantID,
+		Hours:    up.Hours,
+		Days:     up.Days,
+		Zone:     up.Zone,
+		CIDRs:    up.CIDRs,
+	}
+
+	if up.Expires != nil {
+		req.Expires = &timestamp.Timestamp{Seconds: up.Expires.Unix(), Nanos: 0}
 	}
+
+	return req
 }
 
 // FromResponse populates this value from a protobuf response.
@@ -93,16 +398,38 @@ func (up *UserPerm) FromResponse(res *ptypes.UserPermResponse) error {
 	up.ID = res.ID
 	up.UserID = res.UserID
 	up.PermID = res.PermID
+	up.TenantID = res.TenantID
+	up.Expires = nil
+	if res.Expires != nil {
+		tt := time.Unix(res.Expires.Seconds, 0)
+		up.Expires = &tt
+	}
+
+	up.Hours = res.Hours
+	up.Days = res.Days
+	up.Zone = res.Zone
+	up.CIDRs = res.CIDRs
 	return nil
 }
 
 // ToResponse returns a protobuf response created from this value.
 func (up *UserPerm) ToResponse() ptypes.UserPermResponse {
-	return ptypes.UserPermResponse{
-		ID:     up.ID,
-		UserID: up.UserID,
-		PermID: up.PermID,
+	res := ptypes.UserPermResponse{
+		ID:       up.ID,
+		UserID:   up.UserID,
+		PermID:   up.PermID,
+		TenantID: up.TenantID,
+		Hours:    up.Hours,
+		Days:     up.Days,
+		Zone:     up.Zone,
+		CIDRs:    up.CIDRs,
+	}
+
+	if up.Expires != nil {
+		res.Expires = &timestamp.Timestamp{Seconds: up.Expires.Unix(), Nanos: 0}
 	}
+
+	return res
 }
 
 // FromQueryValues populates this value from a query string map.
@@ -132,16 +459,72 @@ func (up *UserPerm) FromQueryValues(vals url.Values) error {
 		}
 	}
 
+	up.TenantID = 0
+	if vals.Get("tenant_id") != "" {
+		up.TenantID, err = strconv.ParseInt(vals.Get("tenant_id"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	up.Expires = nil
+	if vals.Get("expires") != "" {
+		pt, err := time.ParseInLocation("2006-01-02T15:04:05-0700",
+			vals.Get("expires"), time.Local)
+		if err != nil {
+			return err
+		}
+
+		up.Expires = &pt
+	}
+
+	up.Hours = vals.Get("hours")
+	up.Days = vals.Get("days")
+	up.Zone = vals.Get("zone")
+	up.CIDRs = vals["cidrs"]
+	return nil
+}
+
+// FromUserPerm populates a row value from a user_perm value.
+func (r *UserPermRow) FromUserPerm(up *UserPerm) error {
+	r.ID = up.ID
+	r.UserID = up.UserID
+	r.PermID = up.PermID
+	r.TenantID = up.TenantID
+	r.Expires = dlib.NullTime{}
+	if up.Expires != nil {
+		r.Expires = dlib.NullTime{Valid: true, Time: *up.Expires}
+	}
+
+	r.Hours = up.Hours
+	r.Days = up.Days
+	r.Zone = up.Zone
+	r.CIDRs = strings.Join(up.CIDRs, " ")
 	return nil
 }
 
 // ToUserPerm returns a value created from this row value.
 func (r UserPermRow) ToUserPerm() UserPerm {
-	return UserPerm{
-		ID:     r.ID,
-		UserID: r.UserID,
-		PermID: r.PermID,
+	up := UserPerm{
+		ID:       r.ID,
+		UserID:   r.UserID,
+		PermID:   r.PermID,
+		TenantID: r.TenantID,
+		Hours:    r.Hours,
+		Days:     r.Days,
+		Zone:     r.Zone,
+	}
+
+	if r.Expires.Valid {
+		tt := r.Expires.Time
+		up.Expires = &tt
 	}
+
+	if r.CIDRs != "" {
+		up.CIDRs = strings.Fields(r.CIDRs)
+	}
+
+	return up
 }
 
 // FromUserPerm populates a user_perm find value from a user_perm value.
@@ -161,6 +544,11 @@ func (f *UserPermFind) FromUserPerm(up *UserPerm) error {
 		f.PermID = &up.PermID
 	}
 
+	f.TenantID = nil
+	if up.TenantID != 0 {
+		f.TenantID = &up.TenantID
+	}
+
 	return nil
 }
 
@@ -182,5 +570,16 @@ func (f *UserPermFind) FromUserPermRequest(r *ptypes.UserPermRequest) error {
 		f.PermID = &r.PermID
 	}
 
+	f.TenantID = nil
+	if r.TenantID != 0 {
+		f.TenantID = &r.TenantID
+	}
+
+	f.Expired = nil
+	if r.Expired != nil {
+		dt := time.Unix(r.Expired.Seconds, 0)
+		f.Expired = &dt
+	}
+
 	return nil
 }
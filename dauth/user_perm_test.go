@@ -3,6 +3,7 @@ package dauth
 import (
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/dhaifley/dlib/ptypes"
 )
@@ -50,6 +51,81 @@ func TestUserPermCopy(t *testing.T) {
 	}
 }
 
+func TestUserPermCopyConditions(t *testing.T) {
+	exp := time.Unix(1528128000, 0)
+	a := UserPerm{ID: 1, Expires: &exp, Hours: "09:00-17:00",
+		CIDRs: []string{"10.0.0.0/8"}}
+	result := a.Copy()
+	if !result.Equals(&a) {
+		t.Errorf("Expected perm: %v, got: %v", a, result)
+	}
+
+	result.CIDRs[0] = "192.168.0.0/16"
+	if a.CIDRs[0] != "10.0.0.0/8" {
+		t.Errorf("CIDRs expected: [10.0.0.0/8], got: %v", a.CIDRs)
+	}
+}
+
+func TestUserPermAllows(t *testing.T) {
+	now := time.Date(2018, 6, 4, 10, 30, 0, 0, time.UTC)
+	before, after := now.Add(-time.Minute), now.Add(time.Minute)
+	cases := []struct {
+		up   UserPerm
+		addr string
+		exp  bool
+	}{
+		{UserPerm{}, "", true},
+		{UserPerm{Expires: &after}, "", true},
+		{UserPerm{Expires: &before}, "", false},
+		{UserPerm{CIDRs: []string{"10.0.0.0/8"}}, "10.1.2.3:5000", true},
+		{UserPerm{CIDRs: []string{"10.0.0.0/8", "::1/128"}}, "::1", true},
+		{UserPerm{CIDRs: []string{"10.0.0.0/8"}}, "192.168.1.1", false},
+		{UserPerm{CIDRs: []string{"10.0.0.0/8"}}, "", false},
+		{UserPerm{Hours: "09:00-17:00"}, "", true},
+		{UserPerm{Hours: "11:00-17:00"}, "", false},
+		{UserPerm{Hours: "22:00-11:00"}, "", true},
+		{UserPerm{Hours: "22:00-06:00"}, "", false},
+		{UserPerm{Days: "mon-fri"}, "", true},
+		{UserPerm{Days: "sat,sun"}, "", false},
+		{UserPerm{Days: "fri-mon"}, "", true},
+		{UserPerm{Hours: "09:00-17:00", Zone: "America/New_York"}, "", false},
+		{UserPerm{Hours: "06:00-07:00", Zone: "America/New_York"}, "", true},
+		{UserPerm{Hours: "invalid"}, "", false},
+	}
+
+	for _, c := range cases {
+		gc := GrantContext{Time: now, Addr: c.addr}
+		if result := c.up.Allows(&gc); result != c.exp {
+			t.Errorf("Allows expected: %v, got: %v for %v", c.exp, result, c.up.String())
+		}
+	}
+}
+
+func TestUserPermValidate(t *testing.T) {
+	cases := []struct {
+		up  UserPerm
+		exp bool
+	}{
+		{UserPerm{}, true},
+		{UserPerm{Hours: "09:00-17:00", Days: "Mon-Fri,sun", Zone: "UTC",
+			CIDRs: []string{"10.0.0.0/8"}}, true},
+		{UserPerm{Hours: "09:00"}, false},
+		{UserPerm{Hours: "9am-5pm"}, false},
+		{UserPerm{Hours: "09:00-09:00"}, false},
+		{UserPerm{Days: "weekdays"}, false},
+		{UserPerm{Days: "mon-tue-wed"}, false},
+		{UserPerm{Zone: "Nowhere/Invalid"}, false},
+		{UserPerm{CIDRs: []string{"10.0.0.1"}}, false},
+	}
+
+	for _, c := range cases {
+		err := c.up.Validate()
+		if (err == nil) != c.exp {
+			t.Errorf("Valid expected: %v, got: %v for %v", c.exp, err, c.up.String())
+		}
+	}
+}
+
 func TestUserPermString(t *testing.T) {
 	a := UserPerm{
 		ID:     1,
@@ -119,6 +195,22 @@ func TestUserPermToResponse(t *testing.T) {
 	}
 }
 
+func TestUserPermRowFromUserPerm(t *testing.T) {
+	up := UserPerm{
+		ID:     1,
+		UserID: 1,
+	}
+
+	upr := UserPermRow{}
+	if err := upr.FromUserPerm(&up); err != nil {
+		t.Error(err)
+	}
+
+	if upr.UserID != 1 {
+		t.Errorf("UserID expected: 1, got: %v", upr.UserID)
+	}
+}
+
 func TestUserPermRowToUserPerm(t *testing.T) {
 	ur := UserPermRow{
 		ID:     1,
@@ -131,6 +223,25 @@ func TestUserPermRowToUserPerm(t *testing.T) {
 	}
 }
 
+func TestUserPermRowConditions(t *testing.T) {
+	exp := time.Unix(1528128000, 0)
+	up := UserPerm{ID: 1, Expires: &exp, Days: "mon-fri",
+		CIDRs: []string{"10.0.0.0/8", "192.168.0.0/16"}}
+	upr := UserPermRow{}
+	if err := upr.FromUserPerm(&up); err != nil {
+		t.Fatal(err)
+	}
+
+	if upr.CIDRs != "10.0.0.0/8 192.168.0.0/16" {
+		t.Errorf("CIDRs expected: 10.0.0.0/8 192.168.0.0/16, got: %v", upr.CIDRs)
+	}
+
+	result := upr.ToUserPerm()
+	if !result.Equals(&up) {
+		t.Errorf("Expected perm: %v, got: %v", up.String(), result.String())
+	}
+}
+
 func TestUserPermFromQueryValues(t *testing.T) {
 	vals := url.Values{}
 	vals.Add("id", "1")
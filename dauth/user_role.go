@@ -0,0 +1,249 @@
+package dauth
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/dhaifley/dlib/ptypes"
+)
+
+// UserRole values represenst a single API user role assignment.
+type UserRole struct {
+	ID       int64 `json:"id,omitempty" bson:"_id"`
+	UserID   int64 `json:"user_id,omitempty" bson:"user_id"`
+	RoleID   int64 `json:"role_id,omitempty" bson:"role_id"`
+	TenantID int64 `json:"tenant_id,omitempty" bson:"tenant_id"`
+}
+
+// UserRoleRow values represent a single row in the user_role table.
+type UserRoleRow struct {
+	ID       int64
+	UserID   int64
+	RoleID   int64
+	TenantID int64
+}
+
+// UserRoleFind values are used to find user_role records in the database.
+type UserRoleFind struct {
+	ID       *int64 `json:"id,omitempty"`
+	UserID   *int64 `json:"user_id,omitempty"`
+	RoleID   *int64 `json:"role_id,omitempty"`
+	TenantID *int64 `json:"tenant_id,omitempty"`
+}
+
+// NewUserRole initializes and returns a pointer to a new
+// user role value.
+func NewUserRole(id, userID, roleID int64) *UserRole {
+	return &UserRole{
+		ID:     id,
+		UserID: userID,
+		RoleID: roleID,
+	}
+}
+
+// Equals tests for deep equality between user role assignments.
+func (ur *UserRole) Equals(b *UserRole) bool {
+	switch {
+	case ur == nil || b == nil:
+		return false
+	case *ur != *b:
+		return false
+	default:
+		return true
+	}
+}
+
+// Copy returns an exact deep copy of the value.
+func (ur *UserRole) Copy() UserRole {
+	return UserRole{
+		ID:       ur.ID,
+		UserID:   ur.UserID,
+		RoleID:   ur.RoleID,
+		TenantID: ur.TenantID,
+	}
+}
+
+// String formats a user_role value as a JSON format string.
+func (ur *UserRole) String() string {
+	str, err := json.Marshal(ur)
+	if err != nil {
+		return ""
+	}
+
+	return string(str)
+}
+
+// FromRequest populates this value from a protobuf request.
+func (ur *UserRole) FromRequest(req *ptypes.UserRoleRequest) error {
+	ur.ID = req.ID
+	ur.UserID = req.UserID
+	ur.RoleID = req.RoleID
+	ur.TenantID = req.TenantID
+	return nil
+
+}
+
+// Update applies a user role protobuf request to this value, subject to the
+// update mask of the request. The ID is set from the request if it has one.
+func (ur *UserRole) Update(req *ptypes.UserRoleRequest) error {
+	m, err := NewUpdateMask(req.UpdateMask, "user_id", "role_id", "tenant_id")
+	if err != nil {
+		return err
+	}
+
+	if req.ID != 0 {
+		ur.ID = req.ID
+	}
+
+	if m.Applies("user_id", req.UserID != 0) {
+		ur.UserID = req.UserID
+	}
+
+	if m.Applies("role_id", req.RoleID != 0) {
+		ur.RoleID = req.RoleID
+	}
+
+	if m.Applies("tenant_id", req.TenantID != 0) {
+		ur.TenantID = req.TenantID
+	}
+
+	return nil
+}
+
+// ToRequest returns a protobuf request created from this value.
+func (ur *UserRole) ToRequest() ptypes.UserRoleRequest {
+	return ptypes.UserRoleRequest{
+		ID:       ur.ID,
+		UserID:   ur.UserID,
+		RoleID:   ur.RoleID,
+		TenantID: ur.TenantID,
+	}
+}
+
+// FromResponse populates this value from a protobuf response.
+func (ur *UserRole) FromResponse(res *ptypes.UserRoleResponse) error {
+	ur.ID = res.ID
+	ur.UserID = res.UserID
+	ur.RoleID = res.RoleID
+	ur.TenantID = res.TenantID
+	return nil
+}
+
+// ToResponse returns a protobuf response created from this value.
+func (ur *UserRole) ToResponse() ptypes.UserRoleResponse {
+	return ptypes.UserRoleResponse{
+		ID:       ur.ID,
+		UserID:   ur.UserID,
+		RoleID:   ur.RoleID,
+		TenantID: ur.TenantID,
+	}
+}
+
+// FromQueryValues populates this value from a query string map.
+func (ur *UserRole) FromQueryValues(vals url.Values) error {
+	var err error
+	ur.ID = 0
+	if vals.Get("id") != "" {
+		ur.ID, err = strconv.ParseInt(vals.Get("id"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	ur.UserID = 0
+	if vals.Get("user_id") != "" {
+		ur.UserID, err = strconv.ParseInt(vals.Get("user_id"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	ur.RoleID = 0
+	if vals.Get("role_id") != "" {
+		ur.RoleID, err = strconv.ParseInt(vals.Get("role_id"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	ur.TenantID = 0
+	if vals.Get("tenant_id") != "" {
+		ur.TenantID, err = strconv.ParseInt(vals.Get("tenant_id"), 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FromUserRole populates a row value from a user_role value.
+func (r *UserRoleRow) FromUserRole(ur *UserRole) error {
+	r.ID = ur.ID
+	r.UserID = ur.UserID
+	r.RoleID = ur.RoleID
+	r.TenantID = ur.TenantID
+	return nil
+}
+
+// ToUserRole returns a value created from this row value.
+func (r UserRoleRow) ToUserRole() UserRole {
+	return UserRole{
+		ID:       r.ID,
+		UserID:   r.UserID,
+		RoleID:   r.RoleID,
+		TenantID: r.TenantID,
+	}
+}
+
+// FromUserRole populates a user_role find value from a user_role value.
+func (f *UserRoleFind) FromUserRole(ur *UserRole) error {
+	f.ID = nil
+	if ur.ID != 0 {
+		f.ID = &ur.ID
+	}
+
+	f.UserID = nil
+	if ur.UserID != 0 {
+		f.UserID = &ur.UserID
+	}
+
+	f.RoleID = nil
+	if ur.RoleID != 0 {
+		f.RoleID = &ur.RoleID
+	}
+
+	f.TenantID = nil
+	if ur.TenantID != 0 {
+		f.TenantID = &ur.TenantID
+	}
+
+	return nil
+}
+
+// FromUserRoleRequest populates a user_role find value from a user_role
+// protobuf request.
+func (f *UserRoleFind) FromUserRoleRequest(r *ptypes.UserRoleRequest) error {
+	f.ID = nil
+	if r.ID != 0 {
+		f.ID = &r.ID
+	}
+
+	f.UserID = nil
+	if r.UserID != 0 {
+		f.UserID = &r.UserID
+	}
+
+	f.RoleID = nil
+	if r.RoleID != 0 {
+		f.RoleID = &r.RoleID
+	}
+
+	f.TenantID = nil
+	if r.TenantID != 0 {
+		f.TenantID = &r.TenantID
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+package dauth
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/dhaifley/dlib/ptypes"
+)
+
+func TestUserRoleEquals(t *testing.T) {
+	cases := []struct {
+		a        *UserRole
+		b        *UserRole
+		expected bool
+	}{
+		{
+			a:        NewUserRole(1, 1, 1),
+			b:        NewUserRole(1, 1, 1),
+			expected: true,
+		},
+		{
+			a:        NewUserRole(1, 1, 1),
+			b:        NewUserRole(1, 1, 2),
+			expected: false,
+		},
+	}
+
+	for _, c := range cases {
+		result := c.a.Equals(c.b)
+		if result != c.expected {
+			t.Errorf("Expected bool: %v, got: %v", c.expected, result)
+		}
+	}
+}
+
+func TestUserRoleCopy(t *testing.T) {
+	a := UserRole{
+		ID:     1,
+		UserID: 1,
+	}
+
+	result := a.Copy()
+	expected := UserRole{
+		ID:     1,
+		UserID: 1,
+	}
+
+	if !result.Equals(&expected) {
+		t.Errorf("Expected user role: %v, got: %v", expected, result)
+	}
+}
+
+func TestUserRoleString(t *testing.T) {
+	a := UserRole{
+		ID:     1,
+		UserID: 1,
+	}
+
+	expected := `{"id":1,"user_id":1}`
+	result := a.String()
+	if result != expected {
+		t.Errorf("Expected string: %v, got: %v", expected, result)
+	}
+}
+
+func TestUserRoleFromRequest(t *testing.T) {
+	req := ptypes.UserRoleRequest{
+		ID:     1,
+		UserID: 1,
+	}
+
+	dv := UserRole{}
+	if err := dv.FromRequest(&req); err != nil {
+		t.Error(err)
+	}
+
+	if dv.UserID != 1 {
+		t.Errorf("UserID expected: 1, got: %v", dv.UserID)
+	}
+}
+
+func TestUserRoleToRequest(t *testing.T) {
+	dv := UserRole{
+		ID:     1,
+		UserID: 1,
+	}
+
+	msg := dv.ToRequest()
+	if msg.UserID != 1 {
+		t.Errorf("UserID expected: 1, got: %v", msg.UserID)
+	}
+}
+
+func TestUserRoleFromResponse(t *testing.T) {
+	res := ptypes.UserRoleResponse{
+		ID:     1,
+		UserID: 1,
+	}
+
+	dv := UserRole{}
+	if err := dv.FromResponse(&res); err != nil {
+		t.Error(err)
+	}
+
+	if dv.UserID != 1 {
+		t.Errorf("UserID expected: 1, got: %v", dv.UserID)
+	}
+}
+
+func TestUserRoleToResponse(t *testing.T) {
+	dv := UserRole{
+		ID:     1,
+		UserID: 1,
+	}
+
+	msg := dv.ToResponse()
+	if msg.UserID != 1 {
+		t.Errorf("UserID expected: 1, got: %v", msg.UserID)
+	}
+}
+
+func TestUserRoleRowFromUserRole(t *testing.T) {
+	ur := UserRole{
+		ID:     1,
+		UserID: 1,
+	}
+
+	urr := UserRoleRow{}
+	if err := urr.FromUserRole(&ur); err != nil {
+		t.Error(err)
+	}
+
+	if urr.UserID != 1 {
+		t.Errorf("UserID expected: 1, got: %v", urr.UserID)
+	}
+}
+
+func TestUserRoleRowToUserRole(t *testing.T) {
+	ur := UserRoleRow{
+		ID:     1,
+		UserID: 1,
+	}
+
+	u := ur.ToUserRole()
+	if u.UserID != 1 {
+		t.Errorf("UserID expected: 1, got: %v", u.UserID)
+	}
+}
+
+func TestUserRoleFromQueryValues(t *testing.T) {
+	vals := url.Values{}
+	vals.Add("id", "1")
+	vals.Add("user_id", "1")
+	dv := UserRole{}
+	dv.FromQueryValues(vals)
+	if dv.UserID != int64(1) {
+		t.Errorf("UserID expected: 1, got: %v", dv.UserID)
+	}
+
+	if dv.ID != int64(1) {
+		t.Errorf("ID expected: 1, got: %v", dv.ID)
+	}
+}
+
+func TestUserRoleFindFromUserRole(t *testing.T) {
+	u := UserRole{
+		ID:     1,
+		UserID: 1,
+	}
+
+	uf := UserRoleFind{}
+	err := uf.FromUserRole(&u)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if *uf.ID != 1 {
+		t.Errorf("ID expected: 1, got: %v", *uf.ID)
+	}
+
+	if *uf.UserID != 1 {
+		t.Errorf("Value expected: 1, got: %v", *uf.UserID)
+	}
+}
+
+func TestUserRoleFindFromUserRoleRequest(t *testing.T) {
+	r := ptypes.UserRoleRequest{
+		ID:     1,
+		UserID: 1,
+	}
+
+	uf := UserRoleFind{}
+	err := uf.FromUserRoleRequest(&r)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if *uf.ID != 1 {
+		t.Errorf("ID expected: 1, got: %v", *uf.ID)
+	}
+
+	if *uf.UserID != 1 {
+		t.Errorf("Value expected: 1, got: %v", *uf.UserID)
+	}
+}
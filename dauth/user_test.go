@@ -1,10 +1,13 @@
 package dauth
 
 import (
+	"encoding/json"
 	"net/url"
 	"testing"
 
+	"github.com/dhaifley/dlib"
 	"github.com/dhaifley/dlib/ptypes"
+	"google.golang.org/genproto/protobuf/field_mask"
 )
 
 func TestUserEquals(t *testing.T) {
@@ -59,11 +62,72 @@ func TestUserString(t *testing.T) {
 		Pass: "test",
 	}
 
-	expected := `{"id":1,"user":"test","pass":"test"}`
+	expected := `{"id":1,"user":"test"}`
 	result := a.String()
 	if result != expected {
 		t.Errorf("Expected string: %v, got: %v", expected, result)
 	}
+
+	expected = `{"id":1,"user":"test","pass":"test"}`
+	result = a.Unredacted().String()
+	if result != expected {
+		t.Errorf("Expected string: %v, got: %v", expected, result)
+	}
+}
+
+func TestUserMarshalJSON(t *testing.T) {
+	a := User{ID: 1, User: "test", Pass: "test"}
+	cases := []struct {
+		v   interface{}
+		exp string
+	}{
+		{a, `{"id":1,"user":"test"}`},
+		{&a, `{"id":1,"user":"test"}`},
+		{[]User{a}, `[{"id":1,"user":"test"}]`},
+		{a.Unredacted(), `{"id":1,"user":"test","pass":"test"}`},
+	}
+
+	for _, c := range cases {
+		b, err := json.Marshal(c.v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(b) != c.exp {
+			t.Errorf("Expected string: %v, got: %v", c.exp, string(b))
+		}
+	}
+
+	if a.Pass != "test" {
+		t.Errorf("Value expected: test, got: %v", a.Pass)
+	}
+}
+
+func TestUserRedact(t *testing.T) {
+	a := User{ID: 1, User: "test", Pass: "test"}
+	cases := []interface{}{
+		dlib.Redact(&a),
+		dlib.Redact(a),
+	}
+
+	for _, c := range cases {
+		var u User
+		switch v := c.(type) {
+		case *User:
+			u = *v
+		case User:
+			u = v
+		}
+
+		if u.ID != 1 || u.Pass != "" {
+			t.Errorf("Expected redacted user, got: %v", u.Unredacted())
+		}
+	}
+
+	uf := UserFind{Pass: &a.Pass}
+	if r := dlib.Redact(uf).(UserFind); r.Pass != nil {
+		t.Errorf("Expected nil pass, got: %v", *r.Pass)
+	}
 }
 
 func TestUserFromRequest(t *testing.T) {
@@ -82,6 +146,73 @@ func TestUserFromRequest(t *testing.T) {
 	}
 }
 
+func TestUserUpdate(t *testing.T) {
+	cases := []struct {
+		req ptypes.UserRequest
+		exp User
+	}{
+		{
+			ptypes.UserRequest{Name: "new"},
+			User{ID: 1, User: "test", Name: "new", Email: "test@test.com"},
+		},
+		{
+			ptypes.UserRequest{
+				ID:         1,
+				Name:       "ignored",
+				UpdateMask: &field_mask.FieldMask{Paths: []string{"email"}},
+			},
+			User{ID: 1, User: "test", Name: "test"},
+		},
+	}
+
+	for _, c := range cases {
+		u := User{ID: 1, User: "test", Name: "test", Email: "test@test.com"}
+		if err := u.Update(&c.req); err != nil {
+			t.Fatal(err)
+		}
+
+		if !u.Equals(&c.exp) {
+			t.Errorf("Value expected: %v, got: %v", c.exp.Unredacted(), u.Unredacted())
+		}
+	}
+
+	u := User{}
+	err := u.Update(&ptypes.UserRequest{
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"id"}},
+	})
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 400 {
+		t.Errorf("Error expected: 400, got: %v", err)
+	}
+
+	u = User{ID: 1, User: "test", Pass: "hash"}
+	err = u.Update(&ptypes.UserRequest{
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"pass"}},
+	})
+	if e, ok := err.(*dlib.Error); !ok || e.Code != 400 || u.Pass != "hash" {
+		t.Errorf("Error expected: 400 with pass unchanged, got: %v, %v", err, u.Pass)
+	}
+}
+
+func TestUserUpdateClear(t *testing.T) {
+	u := User{ID: 1, User: "test", Name: "test", Email: "test@test.com"}
+	err := u.Update(&ptypes.UserRequest{
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"name"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := UserRow{}
+	r.FromUser(&u)
+	if r.Name.Valid || !r.Email.Valid {
+		t.Errorf("NULL name expected, got: %v, %v", r.Name, r.Email)
+	}
+
+	if v := r.ToUser(); v.Name != "" || v.Email != "test@test.com" {
+		t.Errorf("Empty name expected, got: %v", v.Unredacted())
+	}
+}
+
 func TestUserToRequest(t *testing.T) {
 	dv := User{
 		ID:   1,
@@ -120,6 +251,36 @@ func TestUserToResponse(t *testing.T) {
 	if msg.ID != 1 {
 		t.Errorf("ID expected: 1, got: %v", msg.User)
 	}
+
+	dv.Pass = "test"
+	if msg = dv.ToResponse(); msg.Pass != "" {
+		t.Errorf("Expected redacted pass, got: %v", msg.Pass)
+	}
+
+	if msg = dv.Unredacted().ToResponse(); msg.Pass != "test" {
+		t.Errorf("Value expected: test, got: %v", msg.Pass)
+	}
+}
+
+func TestUserRowFromUser(t *testing.T) {
+	u := User{
+		ID:   1,
+		User: "test",
+		Name: "test",
+	}
+
+	ur := UserRow{}
+	if err := ur.FromUser(&u); err != nil {
+		t.Error(err)
+	}
+
+	if !ur.Name.Valid || ur.Name.String != "test" {
+		t.Errorf("Value expected: test, got: %v", ur.Name)
+	}
+
+	if ur.Email.Valid {
+		t.Errorf("Valid expected: false, got: %v", ur.Email.Valid)
+	}
 }
 
 func TestUserRowToUser(t *testing.T) {
@@ -156,12 +317,17 @@ func TestUserFindFromUser(t *testing.T) {
 		User: "test",
 	}
 
-	uf := UserFind{}
+	name := "old"
+	uf := UserFind{Name: &name}
 	err := uf.FromUser(&u)
 	if err != nil {
 		t.Error(err)
 	}
 
+	if uf.Name != nil {
+		t.Errorf("Name expected: nil, got: %v", *uf.Name)
+	}
+
 	if *uf.ID != 1 {
 		t.Errorf("ID expected: 1, got: %v", *uf.ID)
 	}
@@ -3,6 +3,9 @@ package dlib
 import (
 	"encoding/json"
 	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Error values contain information about error conditions.
@@ -32,6 +35,46 @@ func (e *Error) Error() string {
 	return e.String()
 }
 
+// GRPCStatus returns the gRPC status equivalent to the error, so that an
+// error returned by a gRPC handler reaches the client with the matching
+// status code and message, rather than as an unknown error.
+func (e *Error) GRPCStatus() *status.Status {
+	return status.New(grpcCode(e.Code), e.Msg)
+}
+
+// grpcCode returns the gRPC status code equivalent to an HTTP status code.
+// Unmapped server error codes are internal errors.
+func grpcCode(code int) codes.Code {
+	switch code {
+	case 400:
+		return codes.InvalidArgument
+	case 401:
+		return codes.Unauthenticated
+	case 403:
+		return codes.PermissionDenied
+	case 404:
+		return codes.NotFound
+	case 409:
+		return codes.AlreadyExists
+	case 412:
+		return codes.FailedPrecondition
+	case 429:
+		return codes.ResourceExhausted
+	case 501:
+		return codes.Unimplemented
+	case 503:
+		return codes.Unavailable
+	case 504:
+		return codes.DeadlineExceeded
+	}
+
+	if code >= 500 && code < 600 {
+		return codes.Internal
+	}
+
+	return codes.Unknown
+}
+
 // Equals tests for deep equality between error values.
 func (e *Error) Equals(b *Error) bool {
 	switch {
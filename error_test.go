@@ -2,6 +2,9 @@ package dlib
 
 import (
 	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestErrorString(t *testing.T) {
@@ -13,6 +16,28 @@ func TestErrorString(t *testing.T) {
 	}
 }
 
+func TestErrorGRPCStatus(t *testing.T) {
+	cases := []struct {
+		code int
+		exp  codes.Code
+	}{
+		{400, codes.InvalidArgument},
+		{401, codes.Unauthenticated},
+		{403, codes.PermissionDenied},
+		{404, codes.NotFound},
+		{429, codes.ResourceExhausted},
+		{500, codes.Internal},
+		{1, codes.Unknown},
+	}
+
+	for _, c := range cases {
+		st, ok := status.FromError(NewError(c.code, "test"))
+		if !ok || st.Code() != c.exp || st.Message() != "test" {
+			t.Errorf("Status expected: %v test, got: %v", c.exp, st)
+		}
+	}
+}
+
 func TestErrorEquals(t *testing.T) {
 	cases := []struct {
 		a        *Error
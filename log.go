@@ -8,7 +8,9 @@ import (
 	"gopkg.in/mgo.v2/bson"
 )
 
-// Log values represent entries in the log database.
+// Log values represent entries in the log database. Secret values in
+// entries are redacted, using Redact, when a log value is encoded as JSON
+// or saved to the database.
 type Log struct {
 	ID    bson.ObjectId `json:"id,omitempty" bson:"_id,omitempty"`
 	TS    *time.Time    `json:"ts,string,omitempty" bson:"ts,omitempty"`
@@ -49,6 +51,14 @@ func (l Log) Equals(b Log) bool {
 	return true
 }
 
+// MarshalJSON encodes a log value as JSON with its entry redacted.
+func (l Log) MarshalJSON() ([]byte, error) {
+	type log Log
+	lv := log(l)
+	lv.Entry = Redact(l.Entry)
+	return json.Marshal(lv)
+}
+
 // String formats a log entry as a JSON format string.
 func (l Log) String() string {
 	str, err := json.Marshal(l)
@@ -61,29 +71,36 @@ func (l Log) String() string {
 
 // Save persists the log entry to the database.
 // Save ensures that primary key uniqueness is preserved.
+// Secret values in the entry are redacted in the saved copy, which is the
+// value of the result, and the entry of the log value is left unchanged.
 // It will return a Result channel.
 func (l *Log) Save(dbs MongoDBDatabase) <-chan Result {
 	c := make(chan Result, 256)
+	isNew := l.ID == bson.ObjectId("") || !l.ID.Valid()
+	if isNew {
+		l.ID = bson.NewObjectId()
+	}
+
+	cp := *l
+	cp.Entry = Redact(l.Entry)
 
 	go func() {
 		defer close(c)
-		if l.ID == bson.ObjectId("") || !l.ID.Valid() {
-			l.ID = bson.NewObjectId()
-		} else {
-			err := dbs.C("logs").RemoveID(l.ID)
+		if !isNew {
+			err := dbs.C("logs").RemoveID(cp.ID)
 			if err != nil {
 				c <- Result{Err: err}
 				return
 			}
 		}
 
-		err := dbs.C("logs").Insert(l)
+		err := dbs.C("logs").Insert(&cp)
 		if err != nil {
 			c <- Result{Err: err}
 			return
 		}
 
-		c <- Result{Val: l, Err: nil}
+		c <- Result{Val: &cp, Err: nil}
 	}()
 
 	return c
@@ -79,6 +79,10 @@ func (fk *FakeMongoDBCollectionLog) RemoveID(id interface{}) error {
 	return nil
 }
 
+func (fk *FakeMongoDBCollectionLog) UpdateID(id interface{}, update interface{}) error {
+	return nil
+}
+
 func (fk *FakeMongoDBCollectionLog) EnsureIndex(index mgo.Index) error {
 	return nil
 }
@@ -142,6 +146,15 @@ func TestLogString(t *testing.T) {
 	}
 }
 
+func TestLogStringRedacted(t *testing.T) {
+	a := Log{Entry: &FakeSecret{User: "test", Pass: "test"}}
+	expected := `{"entry":{"User":"test","Pass":"","Key":null}}`
+	result := a.String()
+	if result != expected {
+		t.Errorf("Expected string: %s, got: %s", expected, result)
+	}
+}
+
 func TestLogSave(t *testing.T) {
 	a := Log{ID: bson.ObjectIdHex("4d88e15b60f486e428412dc9")}
 	c := a.Save(&FakeMongoDBDatabaseLog{})
@@ -155,6 +168,18 @@ func TestLogSave(t *testing.T) {
 	if a.ID != expected {
 		t.Errorf("ID expected: %q, got: %q", expected, a.ID)
 	}
+
+	s := FakeSecret{User: "test", Pass: "test"}
+	a.Entry = &s
+	for r := range a.Save(&FakeMongoDBDatabaseLog{}) {
+		if e := r.Val.(*Log).Entry.(*FakeSecret); e.Pass != "" {
+			t.Errorf("Expected redacted entry, got: %v", e)
+		}
+	}
+
+	if a.Entry != &s || s.Pass != "test" {
+		t.Errorf("Expected unchanged entry, got: %v", a.Entry)
+	}
 }
 
 func TestLogFromQueryValues(t *testing.T) {
@@ -49,6 +49,7 @@ type MongoDBCollection interface {
 	Insert(docs ...interface{}) error
 	RemoveAll(selector interface{}) (info *mgo.ChangeInfo, err error)
 	RemoveID(id interface{}) error
+	UpdateID(id interface{}, update interface{}) error
 	EnsureIndex(index mgo.Index) error
 }
 
@@ -72,6 +73,11 @@ func (c MongoCollection) RemoveID(id interface{}) error {
 	return c.Collection.RemoveId(id)
 }
 
+// UpdateID shadows *mgo UpdateId function.
+func (c MongoCollection) UpdateID(id interface{}, update interface{}) error {
+	return c.Collection.UpdateId(id, update)
+}
+
 // MongoDBDatabase types are used to access a MongoDB database.
 type MongoDBDatabase interface {
 	C(name string) MongoDBCollection
@@ -7,6 +7,7 @@ import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
 import timestamp "github.com/golang/protobuf/ptypes/timestamp"
+import field_mask "google.golang.org/genproto/protobuf/field_mask"
 
 import (
 	context "context"
@@ -27,6 +28,9 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 // Timestamp from public import google/protobuf/timestamp.proto
 type Timestamp = timestamp.Timestamp
 
+// FieldMask from public import google/protobuf/field_mask.proto
+type FieldMask = field_mask.FieldMask
+
 // ErrorReponse messages contain information about error conditions.
 type ErrorResponse struct {
 	Code                 int64                `protobuf:"varint,1,opt,name=Code,proto3" json:"Code,omitempty"`
@@ -41,7 +45,7 @@ func (m *ErrorResponse) Reset()         { *m = ErrorResponse{} }
 func (m *ErrorResponse) String() string { return proto.CompactTextString(m) }
 func (*ErrorResponse) ProtoMessage()    {}
 func (*ErrorResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dlib_d3d99ff7dc552fce, []int{0}
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{0}
 }
 func (m *ErrorResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ErrorResponse.Unmarshal(m, b)
@@ -101,7 +105,7 @@ func (m *ResultReponse) Reset()         { *m = ResultReponse{} }
 func (m *ResultReponse) String() string { return proto.CompactTextString(m) }
 func (*ResultReponse) ProtoMessage()    {}
 func (*ResultReponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dlib_d3d99ff7dc552fce, []int{1}
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{1}
 }
 func (m *ResultReponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ResultReponse.Unmarshal(m, b)
@@ -189,7 +193,7 @@ func (m *DeleteResponse) Reset()         { *m = DeleteResponse{} }
 func (m *DeleteResponse) String() string { return proto.CompactTextString(m) }
 func (*DeleteResponse) ProtoMessage()    {}
 func (*DeleteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dlib_d3d99ff7dc552fce, []int{2}
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{2}
 }
 func (m *DeleteResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteResponse.Unmarshal(m, b)
@@ -218,19 +222,24 @@ func (m *DeleteResponse) GetNum() int64 {
 
 // PermRequest messages represent permission request values.
 type PermRequest struct {
-	ID                   int64    `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	Service              string   `protobuf:"bytes,2,opt,name=Service,proto3" json:"Service,omitempty"`
-	Name                 string   `protobuf:"bytes,3,opt,name=Name,proto3" json:"Name,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	ID                   int64                 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Service              string                `protobuf:"bytes,2,opt,name=Service,proto3" json:"Service,omitempty"`
+	Name                 string                `protobuf:"bytes,3,opt,name=Name,proto3" json:"Name,omitempty"`
+	TenantID             int64                 `protobuf:"varint,4,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	PageSize             int64                 `protobuf:"varint,5,opt,name=PageSize,proto3" json:"PageSize,omitempty"`
+	PageToken            string                `protobuf:"bytes,6,opt,name=PageToken,proto3" json:"PageToken,omitempty"`
+	Order                string                `protobuf:"bytes,7,opt,name=Order,proto3" json:"Order,omitempty"`
+	UpdateMask           *field_mask.FieldMask `protobuf:"bytes,8,opt,name=UpdateMask,proto3" json:"UpdateMask,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
 func (m *PermRequest) Reset()         { *m = PermRequest{} }
 func (m *PermRequest) String() string { return proto.CompactTextString(m) }
 func (*PermRequest) ProtoMessage()    {}
 func (*PermRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dlib_d3d99ff7dc552fce, []int{3}
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{3}
 }
 func (m *PermRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PermRequest.Unmarshal(m, b)
@@ -271,11 +280,48 @@ func (m *PermRequest) GetName() string {
 	return ""
 }
 
+func (m *PermRequest) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
+}
+
+func (m *PermRequest) GetPageSize() int64 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *PermRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *PermRequest) GetOrder() string {
+	if m != nil {
+		return m.Order
+	}
+	return ""
+}
+
+func (m *PermRequest) GetUpdateMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
 // PermResponse messages represent permission response values.
 type PermResponse struct {
 	ID                   int64    `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
 	Service              string   `protobuf:"bytes,2,opt,name=Service,proto3" json:"Service,omitempty"`
 	Name                 string   `protobuf:"bytes,3,opt,name=Name,proto3" json:"Name,omitempty"`
+	TenantID             int64    `protobuf:"varint,4,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	NextPageToken        string   `protobuf:"bytes,5,opt,name=NextPageToken,proto3" json:"NextPageToken,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -285,7 +331,7 @@ func (m *PermResponse) Reset()         { *m = PermResponse{} }
 func (m *PermResponse) String() string { return proto.CompactTextString(m) }
 func (*PermResponse) ProtoMessage()    {}
 func (*PermResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dlib_d3d99ff7dc552fce, []int{4}
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{4}
 }
 func (m *PermResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PermResponse.Unmarshal(m, b)
@@ -326,26 +372,48 @@ func (m *PermResponse) GetName() string {
 	return ""
 }
 
+func (m *PermResponse) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
+}
+
+func (m *PermResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
 // TokenRequest messages represent token request values.
 type TokenRequest struct {
-	ID                   int64                `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	Token                string               `protobuf:"bytes,2,opt,name=Token,proto3" json:"Token,omitempty"`
-	UserID               int64                `protobuf:"varint,3,opt,name=UserID,proto3" json:"UserID,omitempty"`
-	Created              *timestamp.Timestamp `protobuf:"bytes,4,opt,name=Created,proto3" json:"Created,omitempty"`
-	Expires              *timestamp.Timestamp `protobuf:"bytes,5,opt,name=Expires,proto3" json:"Expires,omitempty"`
-	Start                *timestamp.Timestamp `protobuf:"bytes,6,opt,name=Start,proto3" json:"Start,omitempty"`
-	End                  *timestamp.Timestamp `protobuf:"bytes,7,opt,name=End,proto3" json:"End,omitempty"`
-	Old                  *timestamp.Timestamp `protobuf:"bytes,8,opt,name=Old,proto3" json:"Old,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	ID                   int64                 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Token                string                `protobuf:"bytes,2,opt,name=Token,proto3" json:"Token,omitempty"`
+	UserID               int64                 `protobuf:"varint,3,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	Created              *timestamp.Timestamp  `protobuf:"bytes,4,opt,name=Created,proto3" json:"Created,omitempty"`
+	Expires              *timestamp.Timestamp  `protobuf:"bytes,5,opt,name=Expires,proto3" json:"Expires,omitempty"`
+	Start                *timestamp.Timestamp  `protobuf:"bytes,6,opt,name=Start,proto3" json:"Start,omitempty"`
+	End                  *timestamp.Timestamp  `protobuf:"bytes,7,opt,name=End,proto3" json:"End,omitempty"`
+	Old                  *timestamp.Timestamp  `protobuf:"bytes,8,opt,name=Old,proto3" json:"Old,omitempty"`
+	Expired              *timestamp.Timestamp  `protobuf:"bytes,9,opt,name=Expired,proto3" json:"Expired,omitempty"`
+	Scope                []string              `protobuf:"bytes,10,rep,name=Scope,proto3" json:"Scope,omitempty"`
+	TenantID             int64                 `protobuf:"varint,11,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	PageSize             int64                 `protobuf:"varint,12,opt,name=PageSize,proto3" json:"PageSize,omitempty"`
+	PageToken            string                `protobuf:"bytes,13,opt,name=PageToken,proto3" json:"PageToken,omitempty"`
+	Order                string                `protobuf:"bytes,14,opt,name=Order,proto3" json:"Order,omitempty"`
+	UpdateMask           *field_mask.FieldMask `protobuf:"bytes,15,opt,name=UpdateMask,proto3" json:"UpdateMask,omitempty"`
+	ActorID              int64                 `protobuf:"varint,16,opt,name=ActorID,proto3" json:"ActorID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
 func (m *TokenRequest) Reset()         { *m = TokenRequest{} }
 func (m *TokenRequest) String() string { return proto.CompactTextString(m) }
 func (*TokenRequest) ProtoMessage()    {}
 func (*TokenRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dlib_d3d99ff7dc552fce, []int{5}
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{5}
 }
 func (m *TokenRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TokenRequest.Unmarshal(m, b)
@@ -421,6 +489,62 @@ func (m *TokenRequest) GetOld() *timestamp.Timestamp {
 	return nil
 }
 
+func (m *TokenRequest) GetExpired() *timestamp.Timestamp {
+	if m != nil {
+		return m.Expired
+	}
+	return nil
+}
+
+func (m *TokenRequest) GetScope() []string {
+	if m != nil {
+		return m.Scope
+	}
+	return nil
+}
+
+func (m *TokenRequest) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
+}
+
+func (m *TokenRequest) GetPageSize() int64 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *TokenRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *TokenRequest) GetOrder() string {
+	if m != nil {
+		return m.Order
+	}
+	return ""
+}
+
+func (m *TokenRequest) GetUpdateMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
+func (m *TokenRequest) GetActorID() int64 {
+	if m != nil {
+		return m.ActorID
+	}
+	return 0
+}
+
 // TokenResponse messages represent token response values.
 type TokenResponse struct {
 	ID                   int64                `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
@@ -428,6 +552,12 @@ type TokenResponse struct {
 	UserID               int64                `protobuf:"varint,3,opt,name=UserID,proto3" json:"UserID,omitempty"`
 	Created              *timestamp.Timestamp `protobuf:"bytes,4,opt,name=Created,proto3" json:"Created,omitempty"`
 	Expires              *timestamp.Timestamp `protobuf:"bytes,5,opt,name=Expires,proto3" json:"Expires,omitempty"`
+	MFARequired          bool                 `protobuf:"varint,6,opt,name=MFARequired,proto3" json:"MFARequired,omitempty"`
+	Challenge            string               `protobuf:"bytes,7,opt,name=Challenge,proto3" json:"Challenge,omitempty"`
+	Scope                []string             `protobuf:"bytes,8,rep,name=Scope,proto3" json:"Scope,omitempty"`
+	TenantID             int64                `protobuf:"varint,9,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	NextPageToken        string               `protobuf:"bytes,10,opt,name=NextPageToken,proto3" json:"NextPageToken,omitempty"`
+	ActorID              int64                `protobuf:"varint,11,opt,name=ActorID,proto3" json:"ActorID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
 	XXX_unrecognized     []byte               `json:"-"`
 	XXX_sizecache        int32                `json:"-"`
@@ -437,7 +567,7 @@ func (m *TokenResponse) Reset()         { *m = TokenResponse{} }
 func (m *TokenResponse) String() string { return proto.CompactTextString(m) }
 func (*TokenResponse) ProtoMessage()    {}
 func (*TokenResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dlib_d3d99ff7dc552fce, []int{6}
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{6}
 }
 func (m *TokenResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TokenResponse.Unmarshal(m, b)
@@ -492,23 +622,73 @@ func (m *TokenResponse) GetExpires() *timestamp.Timestamp {
 	return nil
 }
 
+func (m *TokenResponse) GetMFARequired() bool {
+	if m != nil {
+		return m.MFARequired
+	}
+	return false
+}
+
+func (m *TokenResponse) GetChallenge() string {
+	if m != nil {
+		return m.Challenge
+	}
+	return ""
+}
+
+func (m *TokenResponse) GetScope() []string {
+	if m != nil {
+		return m.Scope
+	}
+	return nil
+}
+
+func (m *TokenResponse) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
+}
+
+func (m *TokenResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func (m *TokenResponse) GetActorID() int64 {
+	if m != nil {
+		return m.ActorID
+	}
+	return 0
+}
+
 // UserRequest messages represent user request values.
 type UserRequest struct {
-	ID                   int64    `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	User                 string   `protobuf:"bytes,2,opt,name=User,proto3" json:"User,omitempty"`
-	Pass                 string   `protobuf:"bytes,3,opt,name=Pass,proto3" json:"Pass,omitempty"`
-	Name                 string   `protobuf:"bytes,4,opt,name=Name,proto3" json:"Name,omitempty"`
-	Email                string   `protobuf:"bytes,5,opt,name=Email,proto3" json:"Email,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	ID                   int64                 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	User                 string                `protobuf:"bytes,2,opt,name=User,proto3" json:"User,omitempty"`
+	Pass                 string                `protobuf:"bytes,3,opt,name=Pass,proto3" json:"Pass,omitempty"`
+	Name                 string                `protobuf:"bytes,4,opt,name=Name,proto3" json:"Name,omitempty"`
+	Email                string                `protobuf:"bytes,5,opt,name=Email,proto3" json:"Email,omitempty"`
+	Code                 string                `protobuf:"bytes,6,opt,name=Code,proto3" json:"Code,omitempty"`
+	Challenge            string                `protobuf:"bytes,7,opt,name=Challenge,proto3" json:"Challenge,omitempty"`
+	TenantID             int64                 `protobuf:"varint,8,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	PageSize             int64                 `protobuf:"varint,9,opt,name=PageSize,proto3" json:"PageSize,omitempty"`
+	PageToken            string                `protobuf:"bytes,10,opt,name=PageToken,proto3" json:"PageToken,omitempty"`
+	Order                string                `protobuf:"bytes,11,opt,name=Order,proto3" json:"Order,omitempty"`
+	UpdateMask           *field_mask.FieldMask `protobuf:"bytes,12,opt,name=UpdateMask,proto3" json:"UpdateMask,omitempty"`
+	Provider             string                `protobuf:"bytes,13,opt,name=Provider,proto3" json:"Provider,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
 func (m *UserRequest) Reset()         { *m = UserRequest{} }
 func (m *UserRequest) String() string { return proto.CompactTextString(m) }
 func (*UserRequest) ProtoMessage()    {}
 func (*UserRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dlib_d3d99ff7dc552fce, []int{7}
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{7}
 }
 func (m *UserRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UserRequest.Unmarshal(m, b)
@@ -563,6 +743,62 @@ func (m *UserRequest) GetEmail() string {
 	return ""
 }
 
+func (m *UserRequest) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *UserRequest) GetChallenge() string {
+	if m != nil {
+		return m.Challenge
+	}
+	return ""
+}
+
+func (m *UserRequest) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
+}
+
+func (m *UserRequest) GetPageSize() int64 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *UserRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *UserRequest) GetOrder() string {
+	if m != nil {
+		return m.Order
+	}
+	return ""
+}
+
+func (m *UserRequest) GetUpdateMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
+func (m *UserRequest) GetProvider() string {
+	if m != nil {
+		return m.Provider
+	}
+	return ""
+}
+
 // UserMessage messages represent user response values.
 type UserResponse struct {
 	ID                   int64    `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
@@ -570,6 +806,9 @@ type UserResponse struct {
 	Pass                 string   `protobuf:"bytes,3,opt,name=Pass,proto3" json:"Pass,omitempty"`
 	Name                 string   `protobuf:"bytes,4,opt,name=Name,proto3" json:"Name,omitempty"`
 	Email                string   `protobuf:"bytes,5,opt,name=Email,proto3" json:"Email,omitempty"`
+	TenantID             int64    `protobuf:"varint,6,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	NextPageToken        string   `protobuf:"bytes,7,opt,name=NextPageToken,proto3" json:"NextPageToken,omitempty"`
+	Provider             string   `protobuf:"bytes,8,opt,name=Provider,proto3" json:"Provider,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -579,7 +818,7 @@ func (m *UserResponse) Reset()         { *m = UserResponse{} }
 func (m *UserResponse) String() string { return proto.CompactTextString(m) }
 func (*UserResponse) ProtoMessage()    {}
 func (*UserResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dlib_d3d99ff7dc552fce, []int{8}
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{8}
 }
 func (m *UserResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UserResponse.Unmarshal(m, b)
@@ -634,21 +873,53 @@ func (m *UserResponse) GetEmail() string {
 	return ""
 }
 
+func (m *UserResponse) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
+}
+
+func (m *UserResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func (m *UserResponse) GetProvider() string {
+	if m != nil {
+		return m.Provider
+	}
+	return ""
+}
+
 // UserPermRequest messages represent user permission request values.
 type UserPermRequest struct {
-	ID                   int64    `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	UserID               int64    `protobuf:"varint,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
-	PermID               int64    `protobuf:"varint,3,opt,name=PermID,proto3" json:"PermID,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	ID                   int64                 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	UserID               int64                 `protobuf:"varint,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	PermID               int64                 `protobuf:"varint,3,opt,name=PermID,proto3" json:"PermID,omitempty"`
+	TenantID             int64                 `protobuf:"varint,4,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	PageSize             int64                 `protobuf:"varint,5,opt,name=PageSize,proto3" json:"PageSize,omitempty"`
+	PageToken            string                `protobuf:"bytes,6,opt,name=PageToken,proto3" json:"PageToken,omitempty"`
+	Order                string                `protobuf:"bytes,7,opt,name=Order,proto3" json:"Order,omitempty"`
+	UpdateMask           *field_mask.FieldMask `protobuf:"bytes,8,opt,name=UpdateMask,proto3" json:"UpdateMask,omitempty"`
+	Expires              *timestamp.Timestamp  `protobuf:"bytes,9,opt,name=Expires,proto3" json:"Expires,omitempty"`
+	Hours                string                `protobuf:"bytes,10,opt,name=Hours,proto3" json:"Hours,omitempty"`
+	Days                 string                `protobuf:"bytes,11,opt,name=Days,proto3" json:"Days,omitempty"`
+	Zone                 string                `protobuf:"bytes,12,opt,name=Zone,proto3" json:"Zone,omitempty"`
+	CIDRs                []string              `protobuf:"bytes,13,rep,name=CIDRs,proto3" json:"CIDRs,omitempty"`
+	Expired              *timestamp.Timestamp  `protobuf:"bytes,14,opt,name=Expired,proto3" json:"Expired,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
 func (m *UserPermRequest) Reset()         { *m = UserPermRequest{} }
 func (m *UserPermRequest) String() string { return proto.CompactTextString(m) }
 func (*UserPermRequest) ProtoMessage()    {}
 func (*UserPermRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dlib_d3d99ff7dc552fce, []int{9}
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{9}
 }
 func (m *UserPermRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UserPermRequest.Unmarshal(m, b)
@@ -689,21 +960,105 @@ func (m *UserPermRequest) GetPermID() int64 {
 	return 0
 }
 
+func (m *UserPermRequest) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
+}
+
+func (m *UserPermRequest) GetPageSize() int64 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *UserPermRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *UserPermRequest) GetOrder() string {
+	if m != nil {
+		return m.Order
+	}
+	return ""
+}
+
+func (m *UserPermRequest) GetUpdateMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
+func (m *UserPermRequest) GetExpires() *timestamp.Timestamp {
+	if m != nil {
+		return m.Expires
+	}
+	return nil
+}
+
+func (m *UserPermRequest) GetHours() string {
+	if m != nil {
+		return m.Hours
+	}
+	return ""
+}
+
+func (m *UserPermRequest) GetDays() string {
+	if m != nil {
+		return m.Days
+	}
+	return ""
+}
+
+func (m *UserPermRequest) GetZone() string {
+	if m != nil {
+		return m.Zone
+	}
+	return ""
+}
+
+func (m *UserPermRequest) GetCIDRs() []string {
+	if m != nil {
+		return m.CIDRs
+	}
+	return nil
+}
+
+func (m *UserPermRequest) GetExpired() *timestamp.Timestamp {
+	if m != nil {
+		return m.Expired
+	}
+	return nil
+}
+
 // UserPermResponse messages represent user permission response values.
 type UserPermResponse struct {
-	ID                   int64    `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	UserID               int64    `protobuf:"varint,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
-	PermID               int64    `protobuf:"varint,3,opt,name=PermID,proto3" json:"PermID,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	ID                   int64                `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	UserID               int64                `protobuf:"varint,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	PermID               int64                `protobuf:"varint,3,opt,name=PermID,proto3" json:"PermID,omitempty"`
+	TenantID             int64                `protobuf:"varint,4,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	NextPageToken        string               `protobuf:"bytes,5,opt,name=NextPageToken,proto3" json:"NextPageToken,omitempty"`
+	Expires              *timestamp.Timestamp `protobuf:"bytes,6,opt,name=Expires,proto3" json:"Expires,omitempty"`
+	Hours                string               `protobuf:"bytes,7,opt,name=Hours,proto3" json:"Hours,omitempty"`
+	Days                 string               `protobuf:"bytes,8,opt,name=Days,proto3" json:"Days,omitempty"`
+	Zone                 string               `protobuf:"bytes,9,opt,name=Zone,proto3" json:"Zone,omitempty"`
+	CIDRs                []string             `protobuf:"bytes,10,rep,name=CIDRs,proto3" json:"CIDRs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *UserPermResponse) Reset()         { *m = UserPermResponse{} }
 func (m *UserPermResponse) String() string { return proto.CompactTextString(m) }
 func (*UserPermResponse) ProtoMessage()    {}
 func (*UserPermResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dlib_d3d99ff7dc552fce, []int{10}
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{10}
 }
 func (m *UserPermResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UserPermResponse.Unmarshal(m, b)
@@ -744,182 +1099,2271 @@ func (m *UserPermResponse) GetPermID() int64 {
 	return 0
 }
 
-// AuthRequest messages represent requests to authenticate tokens.
-type AuthRequest struct {
-	Token                *TokenRequest `protobuf:"bytes,1,opt,name=Token,proto3" json:"Token,omitempty"`
-	Perm                 *PermRequest  `protobuf:"bytes,2,opt,name=Perm,proto3" json:"Perm,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+func (m *UserPermResponse) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
 }
 
-func (m *AuthRequest) Reset()         { *m = AuthRequest{} }
-func (m *AuthRequest) String() string { return proto.CompactTextString(m) }
-func (*AuthRequest) ProtoMessage()    {}
-func (*AuthRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dlib_d3d99ff7dc552fce, []int{11}
+func (m *UserPermResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
 }
-func (m *AuthRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_AuthRequest.Unmarshal(m, b)
+
+func (m *UserPermResponse) GetExpires() *timestamp.Timestamp {
+	if m != nil {
+		return m.Expires
+	}
+	return nil
 }
-func (m *AuthRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_AuthRequest.Marshal(b, m, deterministic)
+
+func (m *UserPermResponse) GetHours() string {
+	if m != nil {
+		return m.Hours
+	}
+	return ""
 }
-func (dst *AuthRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_AuthRequest.Merge(dst, src)
+
+func (m *UserPermResponse) GetDays() string {
+	if m != nil {
+		return m.Days
+	}
+	return ""
 }
-func (m *AuthRequest) XXX_Size() int {
-	return xxx_messageInfo_AuthRequest.Size(m)
+
+func (m *UserPermResponse) GetZone() string {
+	if m != nil {
+		return m.Zone
+	}
+	return ""
 }
-func (m *AuthRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_AuthRequest.DiscardUnknown(m)
+
+func (m *UserPermResponse) GetCIDRs() []string {
+	if m != nil {
+		return m.CIDRs
+	}
+	return nil
 }
 
-var xxx_messageInfo_AuthRequest proto.InternalMessageInfo
+// RoleRequest messages represent role request values.
+type RoleRequest struct {
+	ID                   int64                 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Name                 string                `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	ParentID             int64                 `protobuf:"varint,3,opt,name=ParentID,proto3" json:"ParentID,omitempty"`
+	UpdateMask           *field_mask.FieldMask `protobuf:"bytes,4,opt,name=UpdateMask,proto3" json:"UpdateMask,omitempty"`
+	TenantID             int64                 `protobuf:"varint,5,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
 
-func (m *AuthRequest) GetToken() *TokenRequest {
+func (m *RoleRequest) Reset()         { *m = RoleRequest{} }
+func (m *RoleRequest) String() string { return proto.CompactTextString(m) }
+func (*RoleRequest) ProtoMessage()    {}
+func (*RoleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{11}
+}
+func (m *RoleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoleRequest.Unmarshal(m, b)
+}
+func (m *RoleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoleRequest.Marshal(b, m, deterministic)
+}
+func (dst *RoleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoleRequest.Merge(dst, src)
+}
+func (m *RoleRequest) XXX_Size() int {
+	return xxx_messageInfo_RoleRequest.Size(m)
+}
+func (m *RoleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoleRequest proto.InternalMessageInfo
+
+func (m *RoleRequest) GetID() int64 {
 	if m != nil {
-		return m.Token
+		return m.ID
 	}
-	return nil
+	return 0
 }
 
-func (m *AuthRequest) GetPerm() *PermRequest {
+func (m *RoleRequest) GetName() string {
 	if m != nil {
-		return m.Perm
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RoleRequest) GetParentID() int64 {
+	if m != nil {
+		return m.ParentID
+	}
+	return 0
+}
+
+func (m *RoleRequest) GetUpdateMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.UpdateMask
 	}
 	return nil
 }
 
-// AuthResponse messages represent responses to authentication requests.
-type AuthResponse struct {
-	Ok                   bool          `protobuf:"varint,1,opt,name=Ok,proto3" json:"Ok,omitempty"`
-	User                 *UserResponse `protobuf:"bytes,2,opt,name=User,proto3" json:"User,omitempty"`
-	Perm                 *PermResponse `protobuf:"bytes,3,opt,name=Perm,proto3" json:"Perm,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
+func (m *RoleRequest) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
 }
 
-func (m *AuthResponse) Reset()         { *m = AuthResponse{} }
-func (m *AuthResponse) String() string { return proto.CompactTextString(m) }
-func (*AuthResponse) ProtoMessage()    {}
-func (*AuthResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dlib_d3d99ff7dc552fce, []int{12}
+// RoleResponse messages represent role response values.
+type RoleResponse struct {
+	ID                   int64    `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	ParentID             int64    `protobuf:"varint,3,opt,name=ParentID,proto3" json:"ParentID,omitempty"`
+	TenantID             int64    `protobuf:"varint,4,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
-func (m *AuthResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_AuthResponse.Unmarshal(m, b)
+
+func (m *RoleResponse) Reset()         { *m = RoleResponse{} }
+func (m *RoleResponse) String() string { return proto.CompactTextString(m) }
+func (*RoleResponse) ProtoMessage()    {}
+func (*RoleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{12}
 }
-func (m *AuthResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_AuthResponse.Marshal(b, m, deterministic)
+func (m *RoleResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoleResponse.Unmarshal(m, b)
 }
-func (dst *AuthResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_AuthResponse.Merge(dst, src)
+func (m *RoleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoleResponse.Marshal(b, m, deterministic)
 }
-func (m *AuthResponse) XXX_Size() int {
-	return xxx_messageInfo_AuthResponse.Size(m)
+func (dst *RoleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoleResponse.Merge(dst, src)
 }
-func (m *AuthResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_AuthResponse.DiscardUnknown(m)
+func (m *RoleResponse) XXX_Size() int {
+	return xxx_messageInfo_RoleResponse.Size(m)
+}
+func (m *RoleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoleResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_AuthResponse proto.InternalMessageInfo
+var xxx_messageInfo_RoleResponse proto.InternalMessageInfo
 
-func (m *AuthResponse) GetOk() bool {
+func (m *RoleResponse) GetID() int64 {
 	if m != nil {
-		return m.Ok
+		return m.ID
 	}
-	return false
+	return 0
 }
 
-func (m *AuthResponse) GetUser() *UserResponse {
+func (m *RoleResponse) GetName() string {
 	if m != nil {
-		return m.User
+		return m.Name
 	}
-	return nil
+	return ""
 }
 
-func (m *AuthResponse) GetPerm() *PermResponse {
+func (m *RoleResponse) GetParentID() int64 {
 	if m != nil {
-		return m.Perm
+		return m.ParentID
+	}
+	return 0
+}
+
+func (m *RoleResponse) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
+}
+
+// RolePermRequest messages represent role permission request values.
+type RolePermRequest struct {
+	ID                   int64                 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	RoleID               int64                 `protobuf:"varint,2,opt,name=RoleID,proto3" json:"RoleID,omitempty"`
+	PermID               int64                 `protobuf:"varint,3,opt,name=PermID,proto3" json:"PermID,omitempty"`
+	UpdateMask           *field_mask.FieldMask `protobuf:"bytes,4,opt,name=UpdateMask,proto3" json:"UpdateMask,omitempty"`
+	TenantID             int64                 `protobuf:"varint,5,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *RolePermRequest) Reset()         { *m = RolePermRequest{} }
+func (m *RolePermRequest) String() string { return proto.CompactTextString(m) }
+func (*RolePermRequest) ProtoMessage()    {}
+func (*RolePermRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{13}
+}
+func (m *RolePermRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RolePermRequest.Unmarshal(m, b)
+}
+func (m *RolePermRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RolePermRequest.Marshal(b, m, deterministic)
+}
+func (dst *RolePermRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RolePermRequest.Merge(dst, src)
+}
+func (m *RolePermRequest) XXX_Size() int {
+	return xxx_messageInfo_RolePermRequest.Size(m)
+}
+func (m *RolePermRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RolePermRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RolePermRequest proto.InternalMessageInfo
+
+func (m *RolePermRequest) GetID() int64 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *RolePermRequest) GetRoleID() int64 {
+	if m != nil {
+		return m.RoleID
+	}
+	return 0
+}
+
+func (m *RolePermRequest) GetPermID() int64 {
+	if m != nil {
+		return m.PermID
+	}
+	return 0
+}
+
+func (m *RolePermRequest) GetUpdateMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.UpdateMask
 	}
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*ErrorResponse)(nil), "dlib.ErrorResponse")
-	proto.RegisterType((*ResultReponse)(nil), "dlib.ResultReponse")
-	proto.RegisterType((*DeleteResponse)(nil), "dlib.DeleteResponse")
-	proto.RegisterType((*PermRequest)(nil), "dlib.PermRequest")
-	proto.RegisterType((*PermResponse)(nil), "dlib.PermResponse")
-	proto.RegisterType((*TokenRequest)(nil), "dlib.TokenRequest")
-	proto.RegisterType((*TokenResponse)(nil), "dlib.TokenResponse")
-	proto.RegisterType((*UserRequest)(nil), "dlib.UserRequest")
-	proto.RegisterType((*UserResponse)(nil), "dlib.UserResponse")
-	proto.RegisterType((*UserPermRequest)(nil), "dlib.UserPermRequest")
-	proto.RegisterType((*UserPermResponse)(nil), "dlib.UserPermResponse")
-	proto.RegisterType((*AuthRequest)(nil), "dlib.AuthRequest")
-	proto.RegisterType((*AuthResponse)(nil), "dlib.AuthResponse")
+func (m *RolePermRequest) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+// RolePermResponse messages represent role permission response values.
+type RolePermResponse struct {
+	ID                   int64    `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	RoleID               int64    `protobuf:"varint,2,opt,name=RoleID,proto3" json:"RoleID,omitempty"`
+	PermID               int64    `protobuf:"varint,3,opt,name=PermID,proto3" json:"PermID,omitempty"`
+	TenantID             int64    `protobuf:"varint,4,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *RolePermResponse) Reset()         { *m = RolePermResponse{} }
+func (m *RolePermResponse) String() string { return proto.CompactTextString(m) }
+func (*RolePermResponse) ProtoMessage()    {}
+func (*RolePermResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{14}
+}
+func (m *RolePermResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RolePermResponse.Unmarshal(m, b)
+}
+func (m *RolePermResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RolePermResponse.Marshal(b, m, deterministic)
+}
+func (dst *RolePermResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RolePermResponse.Merge(dst, src)
+}
+func (m *RolePermResponse) XXX_Size() int {
+	return xxx_messageInfo_RolePermResponse.Size(m)
+}
+func (m *RolePermResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RolePermResponse.DiscardUnknown(m)
+}
 
-// AuthClient is the client API for Auth service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type AuthClient interface {
-	// GetTokens returns a stream of tokens from the database.
-	GetTokens(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (Auth_GetTokensClient, error)
-	// PostTokens serializes a stream of tokens to the database.
-	SaveTokens(ctx context.Context, opts ...grpc.CallOption) (Auth_SaveTokensClient, error)
-	// DeleteTokens deletes tokens from the database.
-	DeleteTokens(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
-	// GetUsers returns a stream of users from the database.
-	GetUsers(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (Auth_GetUsersClient, error)
-	// PostUsers serializes a stream of users to the database.
-	SaveUsers(ctx context.Context, opts ...grpc.CallOption) (Auth_SaveUsersClient, error)
-	// DeleteUsers deletes users from the database.
-	DeleteUsers(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
-	// GetPerms returns a stream of permissions from the database.
-	GetPerms(ctx context.Context, in *PermRequest, opts ...grpc.CallOption) (Auth_GetPermsClient, error)
-	// PostPerms serializes a stream of permissions to the database.
-	SavePerms(ctx context.Context, opts ...grpc.CallOption) (Auth_SavePermsClient, error)
-	// DeletePerms deletes permissions from the database.
-	DeletePerms(ctx context.Context, in *PermRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
-	// GetUserPerms returns a stream of user permissions from the database.
-	GetUserPerms(ctx context.Context, in *UserPermRequest, opts ...grpc.CallOption) (Auth_GetUserPermsClient, error)
-	// PostUserPerms serializes a stream of user permissions to the database.
-	SaveUserPerms(ctx context.Context, opts ...grpc.CallOption) (Auth_SaveUserPermsClient, error)
-	// DeleteUserPerms deletes user permissions from the database.
-	DeleteUserPerms(ctx context.Context, in *UserPermRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
-	// Login authenticates a provided user and creates a new token.
-	Login(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*TokenResponse, error)
-	// Logout destroys the provided token.
-	Logout(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*TokenResponse, error)
-	// Auth authenticates a provided token and returns a user value.
-	Auth(ctx context.Context, in *AuthRequest, opts ...grpc.CallOption) (*AuthResponse, error)
+var xxx_messageInfo_RolePermResponse proto.InternalMessageInfo
+
+func (m *RolePermResponse) GetID() int64 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
 }
 
-type authClient struct {
-	cc *grpc.ClientConn
+func (m *RolePermResponse) GetRoleID() int64 {
+	if m != nil {
+		return m.RoleID
+	}
+	return 0
+}
+
+func (m *RolePermResponse) GetPermID() int64 {
+	if m != nil {
+		return m.PermID
+	}
+	return 0
+}
+
+func (m *RolePermResponse) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
+}
+
+// UserRoleRequest messages represent user role request values.
+type UserRoleRequest struct {
+	ID                   int64                 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	UserID               int64                 `protobuf:"varint,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	RoleID               int64                 `protobuf:"varint,3,opt,name=RoleID,proto3" json:"RoleID,omitempty"`
+	UpdateMask           *field_mask.FieldMask `protobuf:"bytes,4,opt,name=UpdateMask,proto3" json:"UpdateMask,omitempty"`
+	TenantID             int64                 `protobuf:"varint,5,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *UserRoleRequest) Reset()         { *m = UserRoleRequest{} }
+func (m *UserRoleRequest) String() string { return proto.CompactTextString(m) }
+func (*UserRoleRequest) ProtoMessage()    {}
+func (*UserRoleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{15}
+}
+func (m *UserRoleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UserRoleRequest.Unmarshal(m, b)
+}
+func (m *UserRoleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UserRoleRequest.Marshal(b, m, deterministic)
+}
+func (dst *UserRoleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UserRoleRequest.Merge(dst, src)
+}
+func (m *UserRoleRequest) XXX_Size() int {
+	return xxx_messageInfo_UserRoleRequest.Size(m)
+}
+func (m *UserRoleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UserRoleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UserRoleRequest proto.InternalMessageInfo
+
+func (m *UserRoleRequest) GetID() int64 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *UserRoleRequest) GetUserID() int64 {
+	if m != nil {
+		return m.UserID
+	}
+	return 0
+}
+
+func (m *UserRoleRequest) GetRoleID() int64 {
+	if m != nil {
+		return m.RoleID
+	}
+	return 0
+}
+
+func (m *UserRoleRequest) GetUpdateMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
+func (m *UserRoleRequest) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
+}
+
+// UserRoleResponse messages represent user role response values.
+type UserRoleResponse struct {
+	ID                   int64    `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	UserID               int64    `protobuf:"varint,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	RoleID               int64    `protobuf:"varint,3,opt,name=RoleID,proto3" json:"RoleID,omitempty"`
+	TenantID             int64    `protobuf:"varint,4,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UserRoleResponse) Reset()         { *m = UserRoleResponse{} }
+func (m *UserRoleResponse) String() string { return proto.CompactTextString(m) }
+func (*UserRoleResponse) ProtoMessage()    {}
+func (*UserRoleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{16}
+}
+func (m *UserRoleResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UserRoleResponse.Unmarshal(m, b)
+}
+func (m *UserRoleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UserRoleResponse.Marshal(b, m, deterministic)
+}
+func (dst *UserRoleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UserRoleResponse.Merge(dst, src)
+}
+func (m *UserRoleResponse) XXX_Size() int {
+	return xxx_messageInfo_UserRoleResponse.Size(m)
+}
+func (m *UserRoleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UserRoleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UserRoleResponse proto.InternalMessageInfo
+
+func (m *UserRoleResponse) GetID() int64 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *UserRoleResponse) GetUserID() int64 {
+	if m != nil {
+		return m.UserID
+	}
+	return 0
+}
+
+func (m *UserRoleResponse) GetRoleID() int64 {
+	if m != nil {
+		return m.RoleID
+	}
+	return 0
+}
+
+func (m *UserRoleResponse) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
+}
+
+// LockoutRequest messages represent login lockout request values.
+type LockoutRequest struct {
+	ID                   int64                `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	User                 string               `protobuf:"bytes,2,opt,name=User,proto3" json:"User,omitempty"`
+	Addr                 string               `protobuf:"bytes,3,opt,name=Addr,proto3" json:"Addr,omitempty"`
+	Failures             int64                `protobuf:"varint,4,opt,name=Failures,proto3" json:"Failures,omitempty"`
+	Last                 *timestamp.Timestamp `protobuf:"bytes,5,opt,name=Last,proto3" json:"Last,omitempty"`
+	Until                *timestamp.Timestamp `protobuf:"bytes,6,opt,name=Until,proto3" json:"Until,omitempty"`
+	TenantID             int64                `protobuf:"varint,7,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *LockoutRequest) Reset()         { *m = LockoutRequest{} }
+func (m *LockoutRequest) String() string { return proto.CompactTextString(m) }
+func (*LockoutRequest) ProtoMessage()    {}
+func (*LockoutRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{17}
+}
+func (m *LockoutRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LockoutRequest.Unmarshal(m, b)
+}
+func (m *LockoutRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LockoutRequest.Marshal(b, m, deterministic)
+}
+func (dst *LockoutRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LockoutRequest.Merge(dst, src)
+}
+func (m *LockoutRequest) XXX_Size() int {
+	return xxx_messageInfo_LockoutRequest.Size(m)
+}
+func (m *LockoutRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LockoutRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LockoutRequest proto.InternalMessageInfo
+
+func (m *LockoutRequest) GetID() int64 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *LockoutRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *LockoutRequest) GetAddr() string {
+	if m != nil {
+		return m.Addr
+	}
+	return ""
+}
+
+func (m *LockoutRequest) GetFailures() int64 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *LockoutRequest) GetLast() *timestamp.Timestamp {
+	if m != nil {
+		return m.Last
+	}
+	return nil
+}
+
+func (m *LockoutRequest) GetUntil() *timestamp.Timestamp {
+	if m != nil {
+		return m.Until
+	}
+	return nil
+}
+
+func (m *LockoutRequest) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
+}
+
+// LockoutResponse messages represent login lockout response values.
+type LockoutResponse struct {
+	ID                   int64                `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	User                 string               `protobuf:"bytes,2,opt,name=User,proto3" json:"User,omitempty"`
+	Addr                 string               `protobuf:"bytes,3,opt,name=Addr,proto3" json:"Addr,omitempty"`
+	Failures             int64                `protobuf:"varint,4,opt,name=Failures,proto3" json:"Failures,omitempty"`
+	Last                 *timestamp.Timestamp `protobuf:"bytes,5,opt,name=Last,proto3" json:"Last,omitempty"`
+	Until                *timestamp.Timestamp `protobuf:"bytes,6,opt,name=Until,proto3" json:"Until,omitempty"`
+	TenantID             int64                `protobuf:"varint,7,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *LockoutResponse) Reset()         { *m = LockoutResponse{} }
+func (m *LockoutResponse) String() string { return proto.CompactTextString(m) }
+func (*LockoutResponse) ProtoMessage()    {}
+func (*LockoutResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{18}
+}
+func (m *LockoutResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LockoutResponse.Unmarshal(m, b)
+}
+func (m *LockoutResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LockoutResponse.Marshal(b, m, deterministic)
+}
+func (dst *LockoutResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LockoutResponse.Merge(dst, src)
+}
+func (m *LockoutResponse) XXX_Size() int {
+	return xxx_messageInfo_LockoutResponse.Size(m)
+}
+func (m *LockoutResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LockoutResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LockoutResponse proto.InternalMessageInfo
+
+func (m *LockoutResponse) GetID() int64 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *LockoutResponse) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *LockoutResponse) GetAddr() string {
+	if m != nil {
+		return m.Addr
+	}
+	return ""
+}
+
+func (m *LockoutResponse) GetFailures() int64 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *LockoutResponse) GetLast() *timestamp.Timestamp {
+	if m != nil {
+		return m.Last
+	}
+	return nil
+}
+
+func (m *LockoutResponse) GetUntil() *timestamp.Timestamp {
+	if m != nil {
+		return m.Until
+	}
+	return nil
+}
+
+func (m *LockoutResponse) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
+}
+
+// MFAResponse messages represent new MFA enrollments.
+type MFAResponse struct {
+	Secret               string   `protobuf:"bytes,1,opt,name=Secret,proto3" json:"Secret,omitempty"`
+	URI                  string   `protobuf:"bytes,2,opt,name=URI,proto3" json:"URI,omitempty"`
+	RecoveryCodes        []string `protobuf:"bytes,3,rep,name=RecoveryCodes,proto3" json:"RecoveryCodes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MFAResponse) Reset()         { *m = MFAResponse{} }
+func (m *MFAResponse) String() string { return proto.CompactTextString(m) }
+func (*MFAResponse) ProtoMessage()    {}
+func (*MFAResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{19}
+}
+func (m *MFAResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MFAResponse.Unmarshal(m, b)
+}
+func (m *MFAResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MFAResponse.Marshal(b, m, deterministic)
+}
+func (dst *MFAResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MFAResponse.Merge(dst, src)
+}
+func (m *MFAResponse) XXX_Size() int {
+	return xxx_messageInfo_MFAResponse.Size(m)
+}
+func (m *MFAResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MFAResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MFAResponse proto.InternalMessageInfo
+
+func (m *MFAResponse) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *MFAResponse) GetURI() string {
+	if m != nil {
+		return m.URI
+	}
+	return ""
+}
+
+func (m *MFAResponse) GetRecoveryCodes() []string {
+	if m != nil {
+		return m.RecoveryCodes
+	}
+	return nil
+}
+
+// APIKeyRequest messages represent API key request values.
+type APIKeyRequest struct {
+	ID                   int64                `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Name                 string               `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	Prefix               string               `protobuf:"bytes,3,opt,name=Prefix,proto3" json:"Prefix,omitempty"`
+	UserID               int64                `protobuf:"varint,4,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	Scope                []string             `protobuf:"bytes,5,rep,name=Scope,proto3" json:"Scope,omitempty"`
+	Expires              *timestamp.Timestamp `protobuf:"bytes,6,opt,name=Expires,proto3" json:"Expires,omitempty"`
+	TenantID             int64                `protobuf:"varint,7,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *APIKeyRequest) Reset()         { *m = APIKeyRequest{} }
+func (m *APIKeyRequest) String() string { return proto.CompactTextString(m) }
+func (*APIKeyRequest) ProtoMessage()    {}
+func (*APIKeyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{20}
+}
+func (m *APIKeyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_APIKeyRequest.Unmarshal(m, b)
+}
+func (m *APIKeyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_APIKeyRequest.Marshal(b, m, deterministic)
+}
+func (dst *APIKeyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_APIKeyRequest.Merge(dst, src)
+}
+func (m *APIKeyRequest) XXX_Size() int {
+	return xxx_messageInfo_APIKeyRequest.Size(m)
+}
+func (m *APIKeyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_APIKeyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_APIKeyRequest proto.InternalMessageInfo
+
+func (m *APIKeyRequest) GetID() int64 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *APIKeyRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *APIKeyRequest) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *APIKeyRequest) GetUserID() int64 {
+	if m != nil {
+		return m.UserID
+	}
+	return 0
+}
+
+func (m *APIKeyRequest) GetScope() []string {
+	if m != nil {
+		return m.Scope
+	}
+	return nil
+}
+
+func (m *APIKeyRequest) GetExpires() *timestamp.Timestamp {
+	if m != nil {
+		return m.Expires
+	}
+	return nil
+}
+
+func (m *APIKeyRequest) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
+}
+
+// APIKeyResponse messages represent API key response values.
+type APIKeyResponse struct {
+	ID                   int64                `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Name                 string               `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	Prefix               string               `protobuf:"bytes,3,opt,name=Prefix,proto3" json:"Prefix,omitempty"`
+	Key                  string               `protobuf:"bytes,4,opt,name=Key,proto3" json:"Key,omitempty"`
+	UserID               int64                `protobuf:"varint,5,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	Scope                []string             `protobuf:"bytes,6,rep,name=Scope,proto3" json:"Scope,omitempty"`
+	Created              *timestamp.Timestamp `protobuf:"bytes,7,opt,name=Created,proto3" json:"Created,omitempty"`
+	Expires              *timestamp.Timestamp `protobuf:"bytes,8,opt,name=Expires,proto3" json:"Expires,omitempty"`
+	TenantID             int64                `protobuf:"varint,9,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *APIKeyResponse) Reset()         { *m = APIKeyResponse{} }
+func (m *APIKeyResponse) String() string { return proto.CompactTextString(m) }
+func (*APIKeyResponse) ProtoMessage()    {}
+func (*APIKeyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{21}
+}
+func (m *APIKeyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_APIKeyResponse.Unmarshal(m, b)
+}
+func (m *APIKeyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_APIKeyResponse.Marshal(b, m, deterministic)
+}
+func (dst *APIKeyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_APIKeyResponse.Merge(dst, src)
+}
+func (m *APIKeyResponse) XXX_Size() int {
+	return xxx_messageInfo_APIKeyResponse.Size(m)
+}
+func (m *APIKeyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_APIKeyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_APIKeyResponse proto.InternalMessageInfo
+
+func (m *APIKeyResponse) GetID() int64 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *APIKeyResponse) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *APIKeyResponse) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *APIKeyResponse) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *APIKeyResponse) GetUserID() int64 {
+	if m != nil {
+		return m.UserID
+	}
+	return 0
+}
+
+func (m *APIKeyResponse) GetScope() []string {
+	if m != nil {
+		return m.Scope
+	}
+	return nil
+}
+
+func (m *APIKeyResponse) GetCreated() *timestamp.Timestamp {
+	if m != nil {
+		return m.Created
+	}
+	return nil
+}
+
+func (m *APIKeyResponse) GetExpires() *timestamp.Timestamp {
+	if m != nil {
+		return m.Expires
+	}
+	return nil
+}
+
+func (m *APIKeyResponse) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
+}
+
+// AuditRequest messages represent audit event request values.
+type AuditRequest struct {
+	ActorID              int64                `protobuf:"varint,1,opt,name=ActorID,proto3" json:"ActorID,omitempty"`
+	Actor                string               `protobuf:"bytes,2,opt,name=Actor,proto3" json:"Actor,omitempty"`
+	TenantID             int64                `protobuf:"varint,3,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	Action               string               `protobuf:"bytes,4,opt,name=Action,proto3" json:"Action,omitempty"`
+	Start                *timestamp.Timestamp `protobuf:"bytes,5,opt,name=Start,proto3" json:"Start,omitempty"`
+	End                  *timestamp.Timestamp `protobuf:"bytes,6,opt,name=End,proto3" json:"End,omitempty"`
+	Limit                int64                `protobuf:"varint,7,opt,name=Limit,proto3" json:"Limit,omitempty"`
+	ImpersonatorID       int64                `protobuf:"varint,8,opt,name=ImpersonatorID,proto3" json:"ImpersonatorID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *AuditRequest) Reset()         { *m = AuditRequest{} }
+func (m *AuditRequest) String() string { return proto.CompactTextString(m) }
+func (*AuditRequest) ProtoMessage()    {}
+func (*AuditRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{22}
+}
+func (m *AuditRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditRequest.Unmarshal(m, b)
+}
+func (m *AuditRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditRequest.Marshal(b, m, deterministic)
+}
+func (dst *AuditRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditRequest.Merge(dst, src)
+}
+func (m *AuditRequest) XXX_Size() int {
+	return xxx_messageInfo_AuditRequest.Size(m)
+}
+func (m *AuditRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditRequest proto.InternalMessageInfo
+
+func (m *AuditRequest) GetActorID() int64 {
+	if m != nil {
+		return m.ActorID
+	}
+	return 0
+}
+
+func (m *AuditRequest) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *AuditRequest) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
+}
+
+func (m *AuditRequest) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *AuditRequest) GetStart() *timestamp.Timestamp {
+	if m != nil {
+		return m.Start
+	}
+	return nil
+}
+
+func (m *AuditRequest) GetEnd() *timestamp.Timestamp {
+	if m != nil {
+		return m.End
+	}
+	return nil
+}
+
+func (m *AuditRequest) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *AuditRequest) GetImpersonatorID() int64 {
+	if m != nil {
+		return m.ImpersonatorID
+	}
+	return 0
+}
+
+// AuditResponse messages represent audit event response values.
+type AuditResponse struct {
+	ID                   string               `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	ActorID              int64                `protobuf:"varint,2,opt,name=ActorID,proto3" json:"ActorID,omitempty"`
+	Actor                string               `protobuf:"bytes,3,opt,name=Actor,proto3" json:"Actor,omitempty"`
+	TenantID             int64                `protobuf:"varint,4,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	Action               string               `protobuf:"bytes,5,opt,name=Action,proto3" json:"Action,omitempty"`
+	Target               string               `protobuf:"bytes,6,opt,name=Target,proto3" json:"Target,omitempty"`
+	Outcome              string               `protobuf:"bytes,7,opt,name=Outcome,proto3" json:"Outcome,omitempty"`
+	Detail               string               `protobuf:"bytes,8,opt,name=Detail,proto3" json:"Detail,omitempty"`
+	Addr                 string               `protobuf:"bytes,9,opt,name=Addr,proto3" json:"Addr,omitempty"`
+	Time                 *timestamp.Timestamp `protobuf:"bytes,10,opt,name=Time,proto3" json:"Time,omitempty"`
+	ImpersonatorID       int64                `protobuf:"varint,11,opt,name=ImpersonatorID,proto3" json:"ImpersonatorID,omitempty"`
+	Impersonator         string               `protobuf:"bytes,12,opt,name=Impersonator,proto3" json:"Impersonator,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *AuditResponse) Reset()         { *m = AuditResponse{} }
+func (m *AuditResponse) String() string { return proto.CompactTextString(m) }
+func (*AuditResponse) ProtoMessage()    {}
+func (*AuditResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{23}
+}
+func (m *AuditResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditResponse.Unmarshal(m, b)
+}
+func (m *AuditResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditResponse.Marshal(b, m, deterministic)
+}
+func (dst *AuditResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditResponse.Merge(dst, src)
+}
+func (m *AuditResponse) XXX_Size() int {
+	return xxx_messageInfo_AuditResponse.Size(m)
+}
+func (m *AuditResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditResponse proto.InternalMessageInfo
+
+func (m *AuditResponse) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *AuditResponse) GetActorID() int64 {
+	if m != nil {
+		return m.ActorID
+	}
+	return 0
+}
+
+func (m *AuditResponse) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *AuditResponse) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
+}
+
+func (m *AuditResponse) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *AuditResponse) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func (m *AuditResponse) GetOutcome() string {
+	if m != nil {
+		return m.Outcome
+	}
+	return ""
+}
+
+func (m *AuditResponse) GetDetail() string {
+	if m != nil {
+		return m.Detail
+	}
+	return ""
+}
+
+func (m *AuditResponse) GetAddr() string {
+	if m != nil {
+		return m.Addr
+	}
+	return ""
+}
+
+func (m *AuditResponse) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *AuditResponse) GetImpersonatorID() int64 {
+	if m != nil {
+		return m.ImpersonatorID
+	}
+	return 0
+}
+
+func (m *AuditResponse) GetImpersonator() string {
+	if m != nil {
+		return m.Impersonator
+	}
+	return ""
+}
+
+// ImpersonateRequest messages represent requests to act as another user.
+type ImpersonateRequest struct {
+	Token                string   `protobuf:"bytes,1,opt,name=Token,proto3" json:"Token,omitempty"`
+	UserID               int64    `protobuf:"varint,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	TTL                  int64    `protobuf:"varint,3,opt,name=TTL,proto3" json:"TTL,omitempty"`
+	Reason               string   `protobuf:"bytes,4,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImpersonateRequest) Reset()         { *m = ImpersonateRequest{} }
+func (m *ImpersonateRequest) String() string { return proto.CompactTextString(m) }
+func (*ImpersonateRequest) ProtoMessage()    {}
+func (*ImpersonateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{24}
+}
+func (m *ImpersonateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImpersonateRequest.Unmarshal(m, b)
+}
+func (m *ImpersonateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImpersonateRequest.Marshal(b, m, deterministic)
+}
+func (dst *ImpersonateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImpersonateRequest.Merge(dst, src)
+}
+func (m *ImpersonateRequest) XXX_Size() int {
+	return xxx_messageInfo_ImpersonateRequest.Size(m)
+}
+func (m *ImpersonateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImpersonateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImpersonateRequest proto.InternalMessageInfo
+
+func (m *ImpersonateRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *ImpersonateRequest) GetUserID() int64 {
+	if m != nil {
+		return m.UserID
+	}
+	return 0
+}
+
+func (m *ImpersonateRequest) GetTTL() int64 {
+	if m != nil {
+		return m.TTL
+	}
+	return 0
+}
+
+func (m *ImpersonateRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+// AuthRequest messages represent requests to authenticate tokens.
+type AuthRequest struct {
+	Token                *TokenRequest `protobuf:"bytes,1,opt,name=Token,proto3" json:"Token,omitempty"`
+	Perm                 *PermRequest  `protobuf:"bytes,2,opt,name=Perm,proto3" json:"Perm,omitempty"`
+	TenantID             int64         `protobuf:"varint,3,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	Addr                 string        `protobuf:"bytes,4,opt,name=Addr,proto3" json:"Addr,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *AuthRequest) Reset()         { *m = AuthRequest{} }
+func (m *AuthRequest) String() string { return proto.CompactTextString(m) }
+func (*AuthRequest) ProtoMessage()    {}
+func (*AuthRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{25}
+}
+func (m *AuthRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthRequest.Unmarshal(m, b)
+}
+func (m *AuthRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthRequest.Marshal(b, m, deterministic)
+}
+func (dst *AuthRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthRequest.Merge(dst, src)
+}
+func (m *AuthRequest) XXX_Size() int {
+	return xxx_messageInfo_AuthRequest.Size(m)
+}
+func (m *AuthRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthRequest proto.InternalMessageInfo
+
+func (m *AuthRequest) GetToken() *TokenRequest {
+	if m != nil {
+		return m.Token
+	}
+	return nil
+}
+
+func (m *AuthRequest) GetPerm() *PermRequest {
+	if m != nil {
+		return m.Perm
+	}
+	return nil
+}
+
+func (m *AuthRequest) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
+}
+
+func (m *AuthRequest) GetAddr() string {
+	if m != nil {
+		return m.Addr
+	}
+	return ""
+}
+
+// AuthResponse messages represent responses to authentication requests.
+type AuthResponse struct {
+	Ok                   bool          `protobuf:"varint,1,opt,name=Ok,proto3" json:"Ok,omitempty"`
+	User                 *UserResponse `protobuf:"bytes,2,opt,name=User,proto3" json:"User,omitempty"`
+	Perm                 *PermResponse `protobuf:"bytes,3,opt,name=Perm,proto3" json:"Perm,omitempty"`
+	Actor                *UserResponse `protobuf:"bytes,4,opt,name=Actor,proto3" json:"Actor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *AuthResponse) Reset()         { *m = AuthResponse{} }
+func (m *AuthResponse) String() string { return proto.CompactTextString(m) }
+func (*AuthResponse) ProtoMessage()    {}
+func (*AuthResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{26}
+}
+func (m *AuthResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthResponse.Unmarshal(m, b)
+}
+func (m *AuthResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthResponse.Marshal(b, m, deterministic)
+}
+func (dst *AuthResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthResponse.Merge(dst, src)
+}
+func (m *AuthResponse) XXX_Size() int {
+	return xxx_messageInfo_AuthResponse.Size(m)
+}
+func (m *AuthResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthResponse proto.InternalMessageInfo
+
+func (m *AuthResponse) GetOk() bool {
+	if m != nil {
+		return m.Ok
+	}
+	return false
+}
+
+func (m *AuthResponse) GetUser() *UserResponse {
+	if m != nil {
+		return m.User
+	}
+	return nil
+}
+
+func (m *AuthResponse) GetPerm() *PermResponse {
+	if m != nil {
+		return m.Perm
+	}
+	return nil
+}
+
+func (m *AuthResponse) GetActor() *UserResponse {
+	if m != nil {
+		return m.Actor
+	}
+	return nil
+}
+
+// AuthBatchRequest messages represent requests to check many permissions.
+type AuthBatchRequest struct {
+	Token                *TokenRequest  `protobuf:"bytes,1,opt,name=Token,proto3" json:"Token,omitempty"`
+	Perms                []*PermRequest `protobuf:"bytes,2,rep,name=Perms,proto3" json:"Perms,omitempty"`
+	TenantID             int64          `protobuf:"varint,3,opt,name=TenantID,proto3" json:"TenantID,omitempty"`
+	Addr                 string         `protobuf:"bytes,4,opt,name=Addr,proto3" json:"Addr,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *AuthBatchRequest) Reset()         { *m = AuthBatchRequest{} }
+func (m *AuthBatchRequest) String() string { return proto.CompactTextString(m) }
+func (*AuthBatchRequest) ProtoMessage()    {}
+func (*AuthBatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{27}
+}
+func (m *AuthBatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthBatchRequest.Unmarshal(m, b)
+}
+func (m *AuthBatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthBatchRequest.Marshal(b, m, deterministic)
+}
+func (dst *AuthBatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthBatchRequest.Merge(dst, src)
+}
+func (m *AuthBatchRequest) XXX_Size() int {
+	return xxx_messageInfo_AuthBatchRequest.Size(m)
+}
+func (m *AuthBatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthBatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthBatchRequest proto.InternalMessageInfo
+
+func (m *AuthBatchRequest) GetToken() *TokenRequest {
+	if m != nil {
+		return m.Token
+	}
+	return nil
+}
+
+func (m *AuthBatchRequest) GetPerms() []*PermRequest {
+	if m != nil {
+		return m.Perms
+	}
+	return nil
+}
+
+func (m *AuthBatchRequest) GetTenantID() int64 {
+	if m != nil {
+		return m.TenantID
+	}
+	return 0
+}
+
+func (m *AuthBatchRequest) GetAddr() string {
+	if m != nil {
+		return m.Addr
+	}
+	return ""
+}
+
+// AuthDecision messages represent the decision for a single permission.
+type AuthDecision struct {
+	Perm                 *PermRequest  `protobuf:"bytes,1,opt,name=Perm,proto3" json:"Perm,omitempty"`
+	Ok                   bool          `protobuf:"varint,2,opt,name=Ok,proto3" json:"Ok,omitempty"`
+	Grant                *PermResponse `protobuf:"bytes,3,opt,name=Grant,proto3" json:"Grant,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *AuthDecision) Reset()         { *m = AuthDecision{} }
+func (m *AuthDecision) String() string { return proto.CompactTextString(m) }
+func (*AuthDecision) ProtoMessage()    {}
+func (*AuthDecision) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{28}
+}
+func (m *AuthDecision) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthDecision.Unmarshal(m, b)
+}
+func (m *AuthDecision) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthDecision.Marshal(b, m, deterministic)
+}
+func (dst *AuthDecision) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthDecision.Merge(dst, src)
+}
+func (m *AuthDecision) XXX_Size() int {
+	return xxx_messageInfo_AuthDecision.Size(m)
+}
+func (m *AuthDecision) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthDecision.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthDecision proto.InternalMessageInfo
+
+func (m *AuthDecision) GetPerm() *PermRequest {
+	if m != nil {
+		return m.Perm
+	}
+	return nil
+}
+
+func (m *AuthDecision) GetOk() bool {
+	if m != nil {
+		return m.Ok
+	}
+	return false
+}
+
+func (m *AuthDecision) GetGrant() *PermResponse {
+	if m != nil {
+		return m.Grant
+	}
+	return nil
+}
+
+// AuthBatchResponse messages represent responses to batch authentication
+// requests.
+type AuthBatchResponse struct {
+	User                 *UserResponse   `protobuf:"bytes,1,opt,name=User,proto3" json:"User,omitempty"`
+	Decisions            []*AuthDecision `protobuf:"bytes,2,rep,name=Decisions,proto3" json:"Decisions,omitempty"`
+	Perms                []*PermResponse `protobuf:"bytes,3,rep,name=Perms,proto3" json:"Perms,omitempty"`
+	Actor                *UserResponse   `protobuf:"bytes,4,opt,name=Actor,proto3" json:"Actor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *AuthBatchResponse) Reset()         { *m = AuthBatchResponse{} }
+func (m *AuthBatchResponse) String() string { return proto.CompactTextString(m) }
+func (*AuthBatchResponse) ProtoMessage()    {}
+func (*AuthBatchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dlib_9a24797b487a39f3, []int{29}
+}
+func (m *AuthBatchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthBatchResponse.Unmarshal(m, b)
+}
+func (m *AuthBatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthBatchResponse.Marshal(b, m, deterministic)
+}
+func (dst *AuthBatchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthBatchResponse.Merge(dst, src)
+}
+func (m *AuthBatchResponse) XXX_Size() int {
+	return xxx_messageInfo_AuthBatchResponse.Size(m)
+}
+func (m *AuthBatchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthBatchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthBatchResponse proto.InternalMessageInfo
+
+func (m *AuthBatchResponse) GetUser() *UserResponse {
+	if m != nil {
+		return m.User
+	}
+	return nil
+}
+
+func (m *AuthBatchResponse) GetDecisions() []*AuthDecision {
+	if m != nil {
+		return m.Decisions
+	}
+	return nil
+}
+
+func (m *AuthBatchResponse) GetPerms() []*PermResponse {
+	if m != nil {
+		return m.Perms
+	}
+	return nil
+}
+
+func (m *AuthBatchResponse) GetActor() *UserResponse {
+	if m != nil {
+		return m.Actor
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ErrorResponse)(nil), "dlib.ErrorResponse")
+	proto.RegisterType((*ResultReponse)(nil), "dlib.ResultReponse")
+	proto.RegisterType((*DeleteResponse)(nil), "dlib.DeleteResponse")
+	proto.RegisterType((*PermRequest)(nil), "dlib.PermRequest")
+	proto.RegisterType((*PermResponse)(nil), "dlib.PermResponse")
+	proto.RegisterType((*TokenRequest)(nil), "dlib.TokenRequest")
+	proto.RegisterType((*TokenResponse)(nil), "dlib.TokenResponse")
+	proto.RegisterType((*UserRequest)(nil), "dlib.UserRequest")
+	proto.RegisterType((*UserResponse)(nil), "dlib.UserResponse")
+	proto.RegisterType((*UserPermRequest)(nil), "dlib.UserPermRequest")
+	proto.RegisterType((*UserPermResponse)(nil), "dlib.UserPermResponse")
+	proto.RegisterType((*RoleRequest)(nil), "dlib.RoleRequest")
+	proto.RegisterType((*RoleResponse)(nil), "dlib.RoleResponse")
+	proto.RegisterType((*RolePermRequest)(nil), "dlib.RolePermRequest")
+	proto.RegisterType((*RolePermResponse)(nil), "dlib.RolePermResponse")
+	proto.RegisterType((*UserRoleRequest)(nil), "dlib.UserRoleRequest")
+	proto.RegisterType((*UserRoleResponse)(nil), "dlib.UserRoleResponse")
+	proto.RegisterType((*LockoutRequest)(nil), "dlib.LockoutRequest")
+	proto.RegisterType((*LockoutResponse)(nil), "dlib.LockoutResponse")
+	proto.RegisterType((*MFAResponse)(nil), "dlib.MFAResponse")
+	proto.RegisterType((*APIKeyRequest)(nil), "dlib.APIKeyRequest")
+	proto.RegisterType((*APIKeyResponse)(nil), "dlib.APIKeyResponse")
+	proto.RegisterType((*AuditRequest)(nil), "dlib.AuditRequest")
+	proto.RegisterType((*AuditResponse)(nil), "dlib.AuditResponse")
+	proto.RegisterType((*ImpersonateRequest)(nil), "dlib.ImpersonateRequest")
+	proto.RegisterType((*AuthRequest)(nil), "dlib.AuthRequest")
+	proto.RegisterType((*AuthResponse)(nil), "dlib.AuthResponse")
+	proto.RegisterType((*AuthBatchRequest)(nil), "dlib.AuthBatchRequest")
+	proto.RegisterType((*AuthDecision)(nil), "dlib.AuthDecision")
+	proto.RegisterType((*AuthBatchResponse)(nil), "dlib.AuthBatchResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// AuthClient is the client API for Auth service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AuthClient interface {
+	// GetTokens returns a stream of tokens from the database.
+	GetTokens(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (Auth_GetTokensClient, error)
+	// PostTokens serializes a stream of tokens to the database.
+	SaveTokens(ctx context.Context, opts ...grpc.CallOption) (Auth_SaveTokensClient, error)
+	// DeleteTokens deletes tokens from the database.
+	DeleteTokens(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// GetUsers returns a stream of users from the database.
+	GetUsers(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (Auth_GetUsersClient, error)
+	// PostUsers serializes a stream of users to the database.
+	SaveUsers(ctx context.Context, opts ...grpc.CallOption) (Auth_SaveUsersClient, error)
+	// DeleteUsers deletes users from the database.
+	DeleteUsers(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// GetPerms returns a stream of permissions from the database.
+	GetPerms(ctx context.Context, in *PermRequest, opts ...grpc.CallOption) (Auth_GetPermsClient, error)
+	// PostPerms serializes a stream of permissions to the database.
+	SavePerms(ctx context.Context, opts ...grpc.CallOption) (Auth_SavePermsClient, error)
+	// DeletePerms deletes permissions from the database.
+	DeletePerms(ctx context.Context, in *PermRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// GetUserPerms returns a stream of user permissions from the database.
+	GetUserPerms(ctx context.Context, in *UserPermRequest, opts ...grpc.CallOption) (Auth_GetUserPermsClient, error)
+	// PostUserPerms serializes a stream of user permissions to the database.
+	SaveUserPerms(ctx context.Context, opts ...grpc.CallOption) (Auth_SaveUserPermsClient, error)
+	// DeleteUserPerms deletes user permissions from the database.
+	DeleteUserPerms(ctx context.Context, in *UserPermRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// GetRoles returns a stream of roles from the database.
+	GetRoles(ctx context.Context, in *RoleRequest, opts ...grpc.CallOption) (Auth_GetRolesClient, error)
+	// SaveRoles serializes a stream of roles to the database.
+	SaveRoles(ctx context.Context, opts ...grpc.CallOption) (Auth_SaveRolesClient, error)
+	// DeleteRoles deletes roles from the database.
+	DeleteRoles(ctx context.Context, in *RoleRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// GetRolePerms returns a stream of role permissions from the database.
+	GetRolePerms(ctx context.Context, in *RolePermRequest, opts ...grpc.CallOption) (Auth_GetRolePermsClient, error)
+	// SaveRolePerms serializes a stream of role permissions to the database.
+	SaveRolePerms(ctx context.Context, opts ...grpc.CallOption) (Auth_SaveRolePermsClient, error)
+	// DeleteRolePerms deletes role permissions from the database.
+	DeleteRolePerms(ctx context.Context, in *RolePermRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// GetUserRoles returns a stream of user roles from the database.
+	GetUserRoles(ctx context.Context, in *UserRoleRequest, opts ...grpc.CallOption) (Auth_GetUserRolesClient, error)
+	// SaveUserRoles serializes a stream of user roles to the database.
+	SaveUserRoles(ctx context.Context, opts ...grpc.CallOption) (Auth_SaveUserRolesClient, error)
+	// DeleteUserRoles deletes user roles from the database.
+	DeleteUserRoles(ctx context.Context, in *UserRoleRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// GetLockouts returns a stream of login lockouts from the database.
+	GetLockouts(ctx context.Context, in *LockoutRequest, opts ...grpc.CallOption) (Auth_GetLockoutsClient, error)
+	// Unlock deletes login lockouts from the database.
+	Unlock(ctx context.Context, in *LockoutRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Login authenticates a provided user and creates a new token.
+	Login(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*TokenResponse, error)
+	// Logout destroys the provided token.
+	Logout(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*TokenResponse, error)
+	// EnrollMFA starts MFA enrollment for the provided user.
+	EnrollMFA(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*MFAResponse, error)
+	// ConfirmMFA confirms the MFA enrollment of the provided user.
+	ConfirmMFA(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*UserResponse, error)
+	// DisableMFA removes the MFA enrollment of the provided users.
+	DisableMFA(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// CreateAPIKey creates a new API key.
+	CreateAPIKey(ctx context.Context, in *APIKeyRequest, opts ...grpc.CallOption) (*APIKeyResponse, error)
+	// GetAPIKeys returns a stream of API keys from the database.
+	GetAPIKeys(ctx context.Context, in *APIKeyRequest, opts ...grpc.CallOption) (Auth_GetAPIKeysClient, error)
+	// RevokeAPIKeys deletes API keys from the database.
+	RevokeAPIKeys(ctx context.Context, in *APIKeyRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Refresh replaces the provided token with a new one and revokes it.
+	Refresh(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*TokenResponse, error)
+	// ScopeToken issues a new token limited to a scope of the provided token.
+	ScopeToken(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*TokenResponse, error)
+	// Impersonate issues a short lived token to act as another user.
+	Impersonate(ctx context.Context, in *ImpersonateRequest, opts ...grpc.CallOption) (*TokenResponse, error)
+	// GetAuditEvents returns a stream of audit events from the audit log.
+	GetAuditEvents(ctx context.Context, in *AuditRequest, opts ...grpc.CallOption) (Auth_GetAuditEventsClient, error)
+	// Auth authenticates a provided token and returns a user value.
+	Auth(ctx context.Context, in *AuthRequest, opts ...grpc.CallOption) (*AuthResponse, error)
+	// AuthBatch authenticates a provided token and checks many permissions.
+	AuthBatch(ctx context.Context, in *AuthBatchRequest, opts ...grpc.CallOption) (*AuthBatchResponse, error)
+}
+
+type authClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAuthClient(cc *grpc.ClientConn) AuthClient {
+	return &authClient{cc}
+}
+
+func (c *authClient) GetTokens(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (Auth_GetTokensClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[0], "/dlib.Auth/GetTokens", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authGetTokensClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Auth_GetTokensClient interface {
+	Recv() (*TokenResponse, error)
+	grpc.ClientStream
+}
+
+type authGetTokensClient struct {
+	grpc.ClientStream
+}
+
+func (x *authGetTokensClient) Recv() (*TokenResponse, error) {
+	m := new(TokenResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authClient) SaveTokens(ctx context.Context, opts ...grpc.CallOption) (Auth_SaveTokensClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[1], "/dlib.Auth/SaveTokens", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authSaveTokensClient{stream}
+	return x, nil
+}
+
+type Auth_SaveTokensClient interface {
+	Send(*TokenRequest) error
+	Recv() (*TokenResponse, error)
+	grpc.ClientStream
+}
+
+type authSaveTokensClient struct {
+	grpc.ClientStream
+}
+
+func (x *authSaveTokensClient) Send(m *TokenRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *authSaveTokensClient) Recv() (*TokenResponse, error) {
+	m := new(TokenResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authClient) DeleteTokens(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/DeleteTokens", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GetUsers(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (Auth_GetUsersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[2], "/dlib.Auth/GetUsers", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authGetUsersClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Auth_GetUsersClient interface {
+	Recv() (*UserResponse, error)
+	grpc.ClientStream
+}
+
+type authGetUsersClient struct {
+	grpc.ClientStream
+}
+
+func (x *authGetUsersClient) Recv() (*UserResponse, error) {
+	m := new(UserResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authClient) SaveUsers(ctx context.Context, opts ...grpc.CallOption) (Auth_SaveUsersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[3], "/dlib.Auth/SaveUsers", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authSaveUsersClient{stream}
+	return x, nil
+}
+
+type Auth_SaveUsersClient interface {
+	Send(*UserRequest) error
+	Recv() (*UserResponse, error)
+	grpc.ClientStream
+}
+
+type authSaveUsersClient struct {
+	grpc.ClientStream
+}
+
+func (x *authSaveUsersClient) Send(m *UserRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *authSaveUsersClient) Recv() (*UserResponse, error) {
+	m := new(UserResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authClient) DeleteUsers(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/DeleteUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GetPerms(ctx context.Context, in *PermRequest, opts ...grpc.CallOption) (Auth_GetPermsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[4], "/dlib.Auth/GetPerms", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authGetPermsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Auth_GetPermsClient interface {
+	Recv() (*PermResponse, error)
+	grpc.ClientStream
+}
+
+type authGetPermsClient struct {
+	grpc.ClientStream
+}
+
+func (x *authGetPermsClient) Recv() (*PermResponse, error) {
+	m := new(PermResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authClient) SavePerms(ctx context.Context, opts ...grpc.CallOption) (Auth_SavePermsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[5], "/dlib.Auth/SavePerms", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authSavePermsClient{stream}
+	return x, nil
+}
+
+type Auth_SavePermsClient interface {
+	Send(*PermRequest) error
+	Recv() (*PermResponse, error)
+	grpc.ClientStream
+}
+
+type authSavePermsClient struct {
+	grpc.ClientStream
+}
+
+func (x *authSavePermsClient) Send(m *PermRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *authSavePermsClient) Recv() (*PermResponse, error) {
+	m := new(PermResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authClient) DeletePerms(ctx context.Context, in *PermRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/DeletePerms", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GetUserPerms(ctx context.Context, in *UserPermRequest, opts ...grpc.CallOption) (Auth_GetUserPermsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[6], "/dlib.Auth/GetUserPerms", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authGetUserPermsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Auth_GetUserPermsClient interface {
+	Recv() (*UserPermResponse, error)
+	grpc.ClientStream
+}
+
+type authGetUserPermsClient struct {
+	grpc.ClientStream
+}
+
+func (x *authGetUserPermsClient) Recv() (*UserPermResponse, error) {
+	m := new(UserPermResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authClient) SaveUserPerms(ctx context.Context, opts ...grpc.CallOption) (Auth_SaveUserPermsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[7], "/dlib.Auth/SaveUserPerms", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authSaveUserPermsClient{stream}
+	return x, nil
+}
+
+type Auth_SaveUserPermsClient interface {
+	Send(*UserPermRequest) error
+	Recv() (*UserPermResponse, error)
+	grpc.ClientStream
+}
+
+type authSaveUserPermsClient struct {
+	grpc.ClientStream
+}
+
+func (x *authSaveUserPermsClient) Send(m *UserPermRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *authSaveUserPermsClient) Recv() (*UserPermResponse, error) {
+	m := new(UserPermResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authClient) DeleteUserPerms(ctx context.Context, in *UserPermRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/DeleteUserPerms", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GetRoles(ctx context.Context, in *RoleRequest, opts ...grpc.CallOption) (Auth_GetRolesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[8], "/dlib.Auth/GetRoles", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authGetRolesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Auth_GetRolesClient interface {
+	Recv() (*RoleResponse, error)
+	grpc.ClientStream
+}
+
+type authGetRolesClient struct {
+	grpc.ClientStream
+}
+
+func (x *authGetRolesClient) Recv() (*RoleResponse, error) {
+	m := new(RoleResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authClient) SaveRoles(ctx context.Context, opts ...grpc.CallOption) (Auth_SaveRolesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[9], "/dlib.Auth/SaveRoles", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authSaveRolesClient{stream}
+	return x, nil
+}
+
+type Auth_SaveRolesClient interface {
+	Send(*RoleRequest) error
+	Recv() (*RoleResponse, error)
+	grpc.ClientStream
+}
+
+type authSaveRolesClient struct {
+	grpc.ClientStream
+}
+
+func (x *authSaveRolesClient) Send(m *RoleRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *authSaveRolesClient) Recv() (*RoleResponse, error) {
+	m := new(RoleResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authClient) DeleteRoles(ctx context.Context, in *RoleRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/DeleteRoles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GetRolePerms(ctx context.Context, in *RolePermRequest, opts ...grpc.CallOption) (Auth_GetRolePermsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[10], "/dlib.Auth/GetRolePerms", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authGetRolePermsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Auth_GetRolePermsClient interface {
+	Recv() (*RolePermResponse, error)
+	grpc.ClientStream
+}
+
+type authGetRolePermsClient struct {
+	grpc.ClientStream
+}
+
+func (x *authGetRolePermsClient) Recv() (*RolePermResponse, error) {
+	m := new(RolePermResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authClient) SaveRolePerms(ctx context.Context, opts ...grpc.CallOption) (Auth_SaveRolePermsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[11], "/dlib.Auth/SaveRolePerms", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authSaveRolePermsClient{stream}
+	return x, nil
+}
+
+type Auth_SaveRolePermsClient interface {
+	Send(*RolePermRequest) error
+	Recv() (*RolePermResponse, error)
+	grpc.ClientStream
+}
+
+type authSaveRolePermsClient struct {
+	grpc.ClientStream
+}
+
+func (x *authSaveRolePermsClient) Send(m *RolePermRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *authSaveRolePermsClient) Recv() (*RolePermResponse, error) {
+	m := new(RolePermResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authClient) DeleteRolePerms(ctx context.Context, in *RolePermRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/DeleteRolePerms", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GetUserRoles(ctx context.Context, in *UserRoleRequest, opts ...grpc.CallOption) (Auth_GetUserRolesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[12], "/dlib.Auth/GetUserRoles", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authGetUserRolesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Auth_GetUserRolesClient interface {
+	Recv() (*UserRoleResponse, error)
+	grpc.ClientStream
+}
+
+type authGetUserRolesClient struct {
+	grpc.ClientStream
+}
+
+func (x *authGetUserRolesClient) Recv() (*UserRoleResponse, error) {
+	m := new(UserRoleResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authClient) SaveUserRoles(ctx context.Context, opts ...grpc.CallOption) (Auth_SaveUserRolesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[13], "/dlib.Auth/SaveUserRoles", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authSaveUserRolesClient{stream}
+	return x, nil
+}
+
+type Auth_SaveUserRolesClient interface {
+	Send(*UserRoleRequest) error
+	Recv() (*UserRoleResponse, error)
+	grpc.ClientStream
+}
+
+type authSaveUserRolesClient struct {
+	grpc.ClientStream
+}
+
+func (x *authSaveUserRolesClient) Send(m *UserRoleRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *authSaveUserRolesClient) Recv() (*UserRoleResponse, error) {
+	m := new(UserRoleResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authClient) DeleteUserRoles(ctx context.Context, in *UserRoleRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/DeleteUserRoles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GetLockouts(ctx context.Context, in *LockoutRequest, opts ...grpc.CallOption) (Auth_GetLockoutsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[14], "/dlib.Auth/GetLockouts", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authGetLockoutsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Auth_GetLockoutsClient interface {
+	Recv() (*LockoutResponse, error)
+	grpc.ClientStream
+}
+
+type authGetLockoutsClient struct {
+	grpc.ClientStream
+}
+
+func (x *authGetLockoutsClient) Recv() (*LockoutResponse, error) {
+	m := new(LockoutResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authClient) Unlock(ctx context.Context, in *LockoutRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/Unlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) Login(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*TokenResponse, error) {
+	out := new(TokenResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/Login", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) Logout(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*TokenResponse, error) {
+	out := new(TokenResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/Logout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) EnrollMFA(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*MFAResponse, error) {
+	out := new(MFAResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/EnrollMFA", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ConfirmMFA(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*UserResponse, error) {
+	out := new(UserResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/ConfirmMFA", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) DisableMFA(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/DisableMFA", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) CreateAPIKey(ctx context.Context, in *APIKeyRequest, opts ...grpc.CallOption) (*APIKeyResponse, error) {
+	out := new(APIKeyResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/CreateAPIKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GetAPIKeys(ctx context.Context, in *APIKeyRequest, opts ...grpc.CallOption) (Auth_GetAPIKeysClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[15], "/dlib.Auth/GetAPIKeys", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authGetAPIKeysClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Auth_GetAPIKeysClient interface {
+	Recv() (*APIKeyResponse, error)
+	grpc.ClientStream
+}
+
+type authGetAPIKeysClient struct {
+	grpc.ClientStream
+}
+
+func (x *authGetAPIKeysClient) Recv() (*APIKeyResponse, error) {
+	m := new(APIKeyResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authClient) RevokeAPIKeys(ctx context.Context, in *APIKeyRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/RevokeAPIKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) Refresh(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*TokenResponse, error) {
+	out := new(TokenResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/Refresh", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ScopeToken(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*TokenResponse, error) {
+	out := new(TokenResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/ScopeToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func NewAuthClient(cc *grpc.ClientConn) AuthClient {
-	return &authClient{cc}
+func (c *authClient) Impersonate(ctx context.Context, in *ImpersonateRequest, opts ...grpc.CallOption) (*TokenResponse, error) {
+	out := new(TokenResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/Impersonate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (c *authClient) GetTokens(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (Auth_GetTokensClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[0], "/dlib.Auth/GetTokens", opts...)
+func (c *authClient) GetAuditEvents(ctx context.Context, in *AuditRequest, opts ...grpc.CallOption) (Auth_GetAuditEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[16], "/dlib.Auth/GetAuditEvents", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &authGetTokensClient{stream}
+	x := &authGetAuditEventsClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -929,640 +3373,839 @@ func (c *authClient) GetTokens(ctx context.Context, in *TokenRequest, opts ...gr
 	return x, nil
 }
 
-type Auth_GetTokensClient interface {
-	Recv() (*TokenResponse, error)
+type Auth_GetAuditEventsClient interface {
+	Recv() (*AuditResponse, error)
 	grpc.ClientStream
 }
 
-type authGetTokensClient struct {
+type authGetAuditEventsClient struct {
 	grpc.ClientStream
 }
 
-func (x *authGetTokensClient) Recv() (*TokenResponse, error) {
-	m := new(TokenResponse)
+func (x *authGetAuditEventsClient) Recv() (*AuditResponse, error) {
+	m := new(AuditResponse)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *authClient) SaveTokens(ctx context.Context, opts ...grpc.CallOption) (Auth_SaveTokensClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[1], "/dlib.Auth/SaveTokens", opts...)
+func (c *authClient) Auth(ctx context.Context, in *AuthRequest, opts ...grpc.CallOption) (*AuthResponse, error) {
+	out := new(AuthResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/Auth", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &authSaveTokensClient{stream}
-	return x, nil
+	return out, nil
 }
 
-type Auth_SaveTokensClient interface {
-	Send(*TokenRequest) error
-	Recv() (*TokenResponse, error)
-	grpc.ClientStream
+func (c *authClient) AuthBatch(ctx context.Context, in *AuthBatchRequest, opts ...grpc.CallOption) (*AuthBatchResponse, error) {
+	out := new(AuthBatchResponse)
+	err := c.cc.Invoke(ctx, "/dlib.Auth/AuthBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-type authSaveTokensClient struct {
-	grpc.ClientStream
+// AuthServer is the server API for Auth service.
+type AuthServer interface {
+	// GetTokens returns a stream of tokens from the database.
+	GetTokens(*TokenRequest, Auth_GetTokensServer) error
+	// PostTokens serializes a stream of tokens to the database.
+	SaveTokens(Auth_SaveTokensServer) error
+	// DeleteTokens deletes tokens from the database.
+	DeleteTokens(context.Context, *TokenRequest) (*DeleteResponse, error)
+	// GetUsers returns a stream of users from the database.
+	GetUsers(*UserRequest, Auth_GetUsersServer) error
+	// PostUsers serializes a stream of users to the database.
+	SaveUsers(Auth_SaveUsersServer) error
+	// DeleteUsers deletes users from the database.
+	DeleteUsers(context.Context, *UserRequest) (*DeleteResponse, error)
+	// GetPerms returns a stream of permissions from the database.
+	GetPerms(*PermRequest, Auth_GetPermsServer) error
+	// PostPerms serializes a stream of permissions to the database.
+	SavePerms(Auth_SavePermsServer) error
+	// DeletePerms deletes permissions from the database.
+	DeletePerms(context.Context, *PermRequest) (*DeleteResponse, error)
+	// GetUserPerms returns a stream of user permissions from the database.
+	GetUserPerms(*UserPermRequest, Auth_GetUserPermsServer) error
+	// PostUserPerms serializes a stream of user permissions to the database.
+	SaveUserPerms(Auth_SaveUserPermsServer) error
+	// DeleteUserPerms deletes user permissions from the database.
+	DeleteUserPerms(context.Context, *UserPermRequest) (*DeleteResponse, error)
+	// GetRoles returns a stream of roles from the database.
+	GetRoles(*RoleRequest, Auth_GetRolesServer) error
+	// SaveRoles serializes a stream of roles to the database.
+	SaveRoles(Auth_SaveRolesServer) error
+	// DeleteRoles deletes roles from the database.
+	DeleteRoles(context.Context, *RoleRequest) (*DeleteResponse, error)
+	// GetRolePerms returns a stream of role permissions from the database.
+	GetRolePerms(*RolePermRequest, Auth_GetRolePermsServer) error
+	// SaveRolePerms serializes a stream of role permissions to the database.
+	SaveRolePerms(Auth_SaveRolePermsServer) error
+	// DeleteRolePerms deletes role permissions from the database.
+	DeleteRolePerms(context.Context, *RolePermRequest) (*DeleteResponse, error)
+	// GetUserRoles returns a stream of user roles from the database.
+	GetUserRoles(*UserRoleRequest, Auth_GetUserRolesServer) error
+	// SaveUserRoles serializes a stream of user roles to the database.
+	SaveUserRoles(Auth_SaveUserRolesServer) error
+	// DeleteUserRoles deletes user roles from the database.
+	DeleteUserRoles(context.Context, *UserRoleRequest) (*DeleteResponse, error)
+	// GetLockouts returns a stream of login lockouts from the database.
+	GetLockouts(*LockoutRequest, Auth_GetLockoutsServer) error
+	// Unlock deletes login lockouts from the database.
+	Unlock(context.Context, *LockoutRequest) (*DeleteResponse, error)
+	// Login authenticates a provided user and creates a new token.
+	Login(context.Context, *UserRequest) (*TokenResponse, error)
+	// Logout destroys the provided token.
+	Logout(context.Context, *TokenRequest) (*TokenResponse, error)
+	// EnrollMFA starts MFA enrollment for the provided user.
+	EnrollMFA(context.Context, *UserRequest) (*MFAResponse, error)
+	// ConfirmMFA confirms the MFA enrollment of the provided user.
+	ConfirmMFA(context.Context, *UserRequest) (*UserResponse, error)
+	// DisableMFA removes the MFA enrollment of the provided users.
+	DisableMFA(context.Context, *UserRequest) (*DeleteResponse, error)
+	// CreateAPIKey creates a new API key.
+	CreateAPIKey(context.Context, *APIKeyRequest) (*APIKeyResponse, error)
+	// GetAPIKeys returns a stream of API keys from the database.
+	GetAPIKeys(*APIKeyRequest, Auth_GetAPIKeysServer) error
+	// RevokeAPIKeys deletes API keys from the database.
+	RevokeAPIKeys(context.Context, *APIKeyRequest) (*DeleteResponse, error)
+	// Refresh replaces the provided token with a new one and revokes it.
+	Refresh(context.Context, *TokenRequest) (*TokenResponse, error)
+	// ScopeToken issues a new token limited to a scope of the provided token.
+	ScopeToken(context.Context, *TokenRequest) (*TokenResponse, error)
+	// Impersonate issues a short lived token to act as another user.
+	Impersonate(context.Context, *ImpersonateRequest) (*TokenResponse, error)
+	// GetAuditEvents returns a stream of audit events from the audit log.
+	GetAuditEvents(*AuditRequest, Auth_GetAuditEventsServer) error
+	// Auth authenticates a provided token and returns a user value.
+	Auth(context.Context, *AuthRequest) (*AuthResponse, error)
+	// AuthBatch authenticates a provided token and checks many permissions.
+	AuthBatch(context.Context, *AuthBatchRequest) (*AuthBatchResponse, error)
 }
 
-func (x *authSaveTokensClient) Send(m *TokenRequest) error {
-	return x.ClientStream.SendMsg(m)
+func RegisterAuthServer(s *grpc.Server, srv AuthServer) {
+	s.RegisterService(&_Auth_serviceDesc, srv)
 }
 
-func (x *authSaveTokensClient) Recv() (*TokenResponse, error) {
-	m := new(TokenResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func _Auth_GetTokens_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TokenRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return m, nil
+	return srv.(AuthServer).GetTokens(m, &authGetTokensServer{stream})
 }
 
-func (c *authClient) DeleteTokens(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
-	out := new(DeleteResponse)
-	err := c.cc.Invoke(ctx, "/dlib.Auth/DeleteTokens", in, out, opts...)
-	if err != nil {
+type Auth_GetTokensServer interface {
+	Send(*TokenResponse) error
+	grpc.ServerStream
+}
+
+type authGetTokensServer struct {
+	grpc.ServerStream
+}
+
+func (x *authGetTokensServer) Send(m *TokenResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Auth_SaveTokens_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AuthServer).SaveTokens(&authSaveTokensServer{stream})
+}
+
+type Auth_SaveTokensServer interface {
+	Send(*TokenResponse) error
+	Recv() (*TokenRequest, error)
+	grpc.ServerStream
+}
+
+type authSaveTokensServer struct {
+	grpc.ServerStream
+}
+
+func (x *authSaveTokensServer) Send(m *TokenResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *authSaveTokensServer) Recv() (*TokenRequest, error) {
+	m := new(TokenRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return m, nil
 }
 
-func (c *authClient) GetUsers(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (Auth_GetUsersClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[2], "/dlib.Auth/GetUsers", opts...)
-	if err != nil {
+func _Auth_DeleteTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TokenRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &authGetUsersClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(AuthServer).DeleteTokens(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dlib.Auth/DeleteTokens",
 	}
-	return x, nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).DeleteTokens(ctx, req.(*TokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type Auth_GetUsersClient interface {
-	Recv() (*UserResponse, error)
-	grpc.ClientStream
+func _Auth_GetUsers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(UserRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AuthServer).GetUsers(m, &authGetUsersServer{stream})
 }
 
-type authGetUsersClient struct {
-	grpc.ClientStream
+type Auth_GetUsersServer interface {
+	Send(*UserResponse) error
+	grpc.ServerStream
 }
 
-func (x *authGetUsersClient) Recv() (*UserResponse, error) {
-	m := new(UserResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+type authGetUsersServer struct {
+	grpc.ServerStream
+}
+
+func (x *authGetUsersServer) Send(m *UserResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Auth_SaveUsers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AuthServer).SaveUsers(&authSaveUsersServer{stream})
+}
+
+type Auth_SaveUsersServer interface {
+	Send(*UserResponse) error
+	Recv() (*UserRequest, error)
+	grpc.ServerStream
+}
+
+type authSaveUsersServer struct {
+	grpc.ServerStream
+}
+
+func (x *authSaveUsersServer) Send(m *UserResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *authSaveUsersServer) Recv() (*UserRequest, error) {
+	m := new(UserRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *authClient) SaveUsers(ctx context.Context, opts ...grpc.CallOption) (Auth_SaveUsersClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[3], "/dlib.Auth/SaveUsers", opts...)
-	if err != nil {
+func _Auth_DeleteUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &authSaveUsersClient{stream}
-	return x, nil
+	if interceptor == nil {
+		return srv.(AuthServer).DeleteUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dlib.Auth/DeleteUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).DeleteUsers(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type Auth_SaveUsersClient interface {
-	Send(*UserRequest) error
-	Recv() (*UserResponse, error)
-	grpc.ClientStream
+func _Auth_GetPerms_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PermRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AuthServer).GetPerms(m, &authGetPermsServer{stream})
 }
 
-type authSaveUsersClient struct {
-	grpc.ClientStream
+type Auth_GetPermsServer interface {
+	Send(*PermResponse) error
+	grpc.ServerStream
 }
 
-func (x *authSaveUsersClient) Send(m *UserRequest) error {
-	return x.ClientStream.SendMsg(m)
+type authGetPermsServer struct {
+	grpc.ServerStream
 }
 
-func (x *authSaveUsersClient) Recv() (*UserResponse, error) {
-	m := new(UserResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+func (x *authGetPermsServer) Send(m *PermResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (c *authClient) DeleteUsers(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
-	out := new(DeleteResponse)
-	err := c.cc.Invoke(ctx, "/dlib.Auth/DeleteUsers", in, out, opts...)
-	if err != nil {
+func _Auth_SavePerms_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AuthServer).SavePerms(&authSavePermsServer{stream})
+}
+
+type Auth_SavePermsServer interface {
+	Send(*PermResponse) error
+	Recv() (*PermRequest, error)
+	grpc.ServerStream
+}
+
+type authSavePermsServer struct {
+	grpc.ServerStream
+}
+
+func (x *authSavePermsServer) Send(m *PermResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *authSavePermsServer) Recv() (*PermRequest, error) {
+	m := new(PermRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return m, nil
 }
 
-func (c *authClient) GetPerms(ctx context.Context, in *PermRequest, opts ...grpc.CallOption) (Auth_GetPermsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[4], "/dlib.Auth/GetPerms", opts...)
-	if err != nil {
+func _Auth_DeletePerms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PermRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &authGetPermsClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(AuthServer).DeletePerms(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dlib.Auth/DeletePerms",
 	}
-	return x, nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).DeletePerms(ctx, req.(*PermRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type Auth_GetPermsClient interface {
-	Recv() (*PermResponse, error)
-	grpc.ClientStream
+func _Auth_GetUserPerms_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(UserPermRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AuthServer).GetUserPerms(m, &authGetUserPermsServer{stream})
 }
 
-type authGetPermsClient struct {
-	grpc.ClientStream
+type Auth_GetUserPermsServer interface {
+	Send(*UserPermResponse) error
+	grpc.ServerStream
 }
 
-func (x *authGetPermsClient) Recv() (*PermResponse, error) {
-	m := new(PermResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+type authGetUserPermsServer struct {
+	grpc.ServerStream
 }
 
-func (c *authClient) SavePerms(ctx context.Context, opts ...grpc.CallOption) (Auth_SavePermsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[5], "/dlib.Auth/SavePerms", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &authSavePermsClient{stream}
-	return x, nil
+func (x *authGetUserPermsServer) Send(m *UserPermResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-type Auth_SavePermsClient interface {
-	Send(*PermRequest) error
-	Recv() (*PermResponse, error)
-	grpc.ClientStream
+func _Auth_SaveUserPerms_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AuthServer).SaveUserPerms(&authSaveUserPermsServer{stream})
 }
 
-type authSavePermsClient struct {
-	grpc.ClientStream
+type Auth_SaveUserPermsServer interface {
+	Send(*UserPermResponse) error
+	Recv() (*UserPermRequest, error)
+	grpc.ServerStream
 }
 
-func (x *authSavePermsClient) Send(m *PermRequest) error {
-	return x.ClientStream.SendMsg(m)
+type authSaveUserPermsServer struct {
+	grpc.ServerStream
 }
 
-func (x *authSavePermsClient) Recv() (*PermResponse, error) {
-	m := new(PermResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+func (x *authSaveUserPermsServer) Send(m *UserPermResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (c *authClient) DeletePerms(ctx context.Context, in *PermRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
-	out := new(DeleteResponse)
-	err := c.cc.Invoke(ctx, "/dlib.Auth/DeletePerms", in, out, opts...)
-	if err != nil {
+func (x *authSaveUserPermsServer) Recv() (*UserPermRequest, error) {
+	m := new(UserPermRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return m, nil
 }
 
-func (c *authClient) GetUserPerms(ctx context.Context, in *UserPermRequest, opts ...grpc.CallOption) (Auth_GetUserPermsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[6], "/dlib.Auth/GetUserPerms", opts...)
-	if err != nil {
+func _Auth_DeleteUserPerms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserPermRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &authGetUserPermsClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(AuthServer).DeleteUserPerms(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dlib.Auth/DeleteUserPerms",
 	}
-	return x, nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).DeleteUserPerms(ctx, req.(*UserPermRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type Auth_GetUserPermsClient interface {
-	Recv() (*UserPermResponse, error)
-	grpc.ClientStream
+func _Auth_GetRoles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RoleRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AuthServer).GetRoles(m, &authGetRolesServer{stream})
 }
 
-type authGetUserPermsClient struct {
-	grpc.ClientStream
+type Auth_GetRolesServer interface {
+	Send(*RoleResponse) error
+	grpc.ServerStream
 }
 
-func (x *authGetUserPermsClient) Recv() (*UserPermResponse, error) {
-	m := new(UserPermResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+type authGetRolesServer struct {
+	grpc.ServerStream
 }
 
-func (c *authClient) SaveUserPerms(ctx context.Context, opts ...grpc.CallOption) (Auth_SaveUserPermsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[7], "/dlib.Auth/SaveUserPerms", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &authSaveUserPermsClient{stream}
-	return x, nil
+func (x *authGetRolesServer) Send(m *RoleResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-type Auth_SaveUserPermsClient interface {
-	Send(*UserPermRequest) error
-	Recv() (*UserPermResponse, error)
-	grpc.ClientStream
+func _Auth_SaveRoles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AuthServer).SaveRoles(&authSaveRolesServer{stream})
 }
 
-type authSaveUserPermsClient struct {
-	grpc.ClientStream
+type Auth_SaveRolesServer interface {
+	Send(*RoleResponse) error
+	Recv() (*RoleRequest, error)
+	grpc.ServerStream
 }
 
-func (x *authSaveUserPermsClient) Send(m *UserPermRequest) error {
-	return x.ClientStream.SendMsg(m)
+type authSaveRolesServer struct {
+	grpc.ServerStream
 }
 
-func (x *authSaveUserPermsClient) Recv() (*UserPermResponse, error) {
-	m := new(UserPermResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+func (x *authSaveRolesServer) Send(m *RoleResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (c *authClient) DeleteUserPerms(ctx context.Context, in *UserPermRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
-	out := new(DeleteResponse)
-	err := c.cc.Invoke(ctx, "/dlib.Auth/DeleteUserPerms", in, out, opts...)
-	if err != nil {
+func (x *authSaveRolesServer) Recv() (*RoleRequest, error) {
+	m := new(RoleRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return m, nil
 }
 
-func (c *authClient) Login(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*TokenResponse, error) {
-	out := new(TokenResponse)
-	err := c.cc.Invoke(ctx, "/dlib.Auth/Login", in, out, opts...)
-	if err != nil {
+func _Auth_DeleteRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoleRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-func (c *authClient) Logout(ctx context.Context, in *TokenRequest, opts ...grpc.CallOption) (*TokenResponse, error) {
-	out := new(TokenResponse)
-	err := c.cc.Invoke(ctx, "/dlib.Auth/Logout", in, out, opts...)
-	if err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(AuthServer).DeleteRoles(ctx, in)
 	}
-	return out, nil
-}
-
-func (c *authClient) Auth(ctx context.Context, in *AuthRequest, opts ...grpc.CallOption) (*AuthResponse, error) {
-	out := new(AuthResponse)
-	err := c.cc.Invoke(ctx, "/dlib.Auth/Auth", in, out, opts...)
-	if err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dlib.Auth/DeleteRoles",
 	}
-	return out, nil
-}
-
-// AuthServer is the server API for Auth service.
-type AuthServer interface {
-	// GetTokens returns a stream of tokens from the database.
-	GetTokens(*TokenRequest, Auth_GetTokensServer) error
-	// PostTokens serializes a stream of tokens to the database.
-	SaveTokens(Auth_SaveTokensServer) error
-	// DeleteTokens deletes tokens from the database.
-	DeleteTokens(context.Context, *TokenRequest) (*DeleteResponse, error)
-	// GetUsers returns a stream of users from the database.
-	GetUsers(*UserRequest, Auth_GetUsersServer) error
-	// PostUsers serializes a stream of users to the database.
-	SaveUsers(Auth_SaveUsersServer) error
-	// DeleteUsers deletes users from the database.
-	DeleteUsers(context.Context, *UserRequest) (*DeleteResponse, error)
-	// GetPerms returns a stream of permissions from the database.
-	GetPerms(*PermRequest, Auth_GetPermsServer) error
-	// PostPerms serializes a stream of permissions to the database.
-	SavePerms(Auth_SavePermsServer) error
-	// DeletePerms deletes permissions from the database.
-	DeletePerms(context.Context, *PermRequest) (*DeleteResponse, error)
-	// GetUserPerms returns a stream of user permissions from the database.
-	GetUserPerms(*UserPermRequest, Auth_GetUserPermsServer) error
-	// PostUserPerms serializes a stream of user permissions to the database.
-	SaveUserPerms(Auth_SaveUserPermsServer) error
-	// DeleteUserPerms deletes user permissions from the database.
-	DeleteUserPerms(context.Context, *UserPermRequest) (*DeleteResponse, error)
-	// Login authenticates a provided user and creates a new token.
-	Login(context.Context, *UserRequest) (*TokenResponse, error)
-	// Logout destroys the provided token.
-	Logout(context.Context, *TokenRequest) (*TokenResponse, error)
-	// Auth authenticates a provided token and returns a user value.
-	Auth(context.Context, *AuthRequest) (*AuthResponse, error)
-}
-
-func RegisterAuthServer(s *grpc.Server, srv AuthServer) {
-	s.RegisterService(&_Auth_serviceDesc, srv)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).DeleteRoles(ctx, req.(*RoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Auth_GetTokens_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(TokenRequest)
+func _Auth_GetRolePerms_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RolePermRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(AuthServer).GetTokens(m, &authGetTokensServer{stream})
+	return srv.(AuthServer).GetRolePerms(m, &authGetRolePermsServer{stream})
 }
 
-type Auth_GetTokensServer interface {
-	Send(*TokenResponse) error
+type Auth_GetRolePermsServer interface {
+	Send(*RolePermResponse) error
 	grpc.ServerStream
 }
 
-type authGetTokensServer struct {
+type authGetRolePermsServer struct {
 	grpc.ServerStream
 }
 
-func (x *authGetTokensServer) Send(m *TokenResponse) error {
+func (x *authGetRolePermsServer) Send(m *RolePermResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _Auth_SaveTokens_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(AuthServer).SaveTokens(&authSaveTokensServer{stream})
+func _Auth_SaveRolePerms_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AuthServer).SaveRolePerms(&authSaveRolePermsServer{stream})
 }
 
-type Auth_SaveTokensServer interface {
-	Send(*TokenResponse) error
-	Recv() (*TokenRequest, error)
+type Auth_SaveRolePermsServer interface {
+	Send(*RolePermResponse) error
+	Recv() (*RolePermRequest, error)
 	grpc.ServerStream
 }
 
-type authSaveTokensServer struct {
+type authSaveRolePermsServer struct {
 	grpc.ServerStream
 }
 
-func (x *authSaveTokensServer) Send(m *TokenResponse) error {
+func (x *authSaveRolePermsServer) Send(m *RolePermResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func (x *authSaveTokensServer) Recv() (*TokenRequest, error) {
-	m := new(TokenRequest)
+func (x *authSaveRolePermsServer) Recv() (*RolePermRequest, error) {
+	m := new(RolePermRequest)
 	if err := x.ServerStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func _Auth_DeleteTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TokenRequest)
+func _Auth_DeleteRolePerms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RolePermRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AuthServer).DeleteTokens(ctx, in)
+		return srv.(AuthServer).DeleteRolePerms(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/dlib.Auth/DeleteTokens",
+		FullMethod: "/dlib.Auth/DeleteRolePerms",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServer).DeleteTokens(ctx, req.(*TokenRequest))
+		return srv.(AuthServer).DeleteRolePerms(ctx, req.(*RolePermRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Auth_GetUsers_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(UserRequest)
+func _Auth_GetUserRoles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(UserRoleRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(AuthServer).GetUsers(m, &authGetUsersServer{stream})
+	return srv.(AuthServer).GetUserRoles(m, &authGetUserRolesServer{stream})
 }
 
-type Auth_GetUsersServer interface {
-	Send(*UserResponse) error
+type Auth_GetUserRolesServer interface {
+	Send(*UserRoleResponse) error
 	grpc.ServerStream
 }
 
-type authGetUsersServer struct {
+type authGetUserRolesServer struct {
 	grpc.ServerStream
 }
 
-func (x *authGetUsersServer) Send(m *UserResponse) error {
+func (x *authGetUserRolesServer) Send(m *UserRoleResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
-
-func _Auth_SaveUsers_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(AuthServer).SaveUsers(&authSaveUsersServer{stream})
+
+func _Auth_SaveUserRoles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AuthServer).SaveUserRoles(&authSaveUserRolesServer{stream})
 }
 
-type Auth_SaveUsersServer interface {
-	Send(*UserResponse) error
-	Recv() (*UserRequest, error)
+type Auth_SaveUserRolesServer interface {
+	Send(*UserRoleResponse) error
+	Recv() (*UserRoleRequest, error)
 	grpc.ServerStream
 }
 
-type authSaveUsersServer struct {
+type authSaveUserRolesServer struct {
 	grpc.ServerStream
 }
 
-func (x *authSaveUsersServer) Send(m *UserResponse) error {
+func (x *authSaveUserRolesServer) Send(m *UserRoleResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func (x *authSaveUsersServer) Recv() (*UserRequest, error) {
-	m := new(UserRequest)
+func (x *authSaveUserRolesServer) Recv() (*UserRoleRequest, error) {
+	m := new(UserRoleRequest)
 	if err := x.ServerStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func _Auth_DeleteUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UserRequest)
+func _Auth_DeleteUserRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRoleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AuthServer).DeleteUsers(ctx, in)
+		return srv.(AuthServer).DeleteUserRoles(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/dlib.Auth/DeleteUsers",
+		FullMethod: "/dlib.Auth/DeleteUserRoles",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServer).DeleteUsers(ctx, req.(*UserRequest))
+		return srv.(AuthServer).DeleteUserRoles(ctx, req.(*UserRoleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Auth_GetPerms_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(PermRequest)
+func _Auth_GetLockouts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LockoutRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(AuthServer).GetPerms(m, &authGetPermsServer{stream})
+	return srv.(AuthServer).GetLockouts(m, &authGetLockoutsServer{stream})
 }
 
-type Auth_GetPermsServer interface {
-	Send(*PermResponse) error
+type Auth_GetLockoutsServer interface {
+	Send(*LockoutResponse) error
 	grpc.ServerStream
 }
 
-type authGetPermsServer struct {
+type authGetLockoutsServer struct {
 	grpc.ServerStream
 }
 
-func (x *authGetPermsServer) Send(m *PermResponse) error {
+func (x *authGetLockoutsServer) Send(m *LockoutResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _Auth_SavePerms_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(AuthServer).SavePerms(&authSavePermsServer{stream})
-}
-
-type Auth_SavePermsServer interface {
-	Send(*PermResponse) error
-	Recv() (*PermRequest, error)
-	grpc.ServerStream
-}
-
-type authSavePermsServer struct {
-	grpc.ServerStream
-}
-
-func (x *authSavePermsServer) Send(m *PermResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _Auth_Unlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).Unlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dlib.Auth/Unlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).Unlock(ctx, req.(*LockoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *authSavePermsServer) Recv() (*PermRequest, error) {
-	m := new(PermRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
+func _Auth_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(AuthServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dlib.Auth/Login",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).Login(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Auth_DeletePerms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PermRequest)
+func _Auth_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TokenRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AuthServer).DeletePerms(ctx, in)
+		return srv.(AuthServer).Logout(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/dlib.Auth/DeletePerms",
+		FullMethod: "/dlib.Auth/Logout",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServer).DeletePerms(ctx, req.(*PermRequest))
+		return srv.(AuthServer).Logout(ctx, req.(*TokenRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Auth_GetUserPerms_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(UserPermRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _Auth_EnrollMFA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(AuthServer).GetUserPerms(m, &authGetUserPermsServer{stream})
+	if interceptor == nil {
+		return srv.(AuthServer).EnrollMFA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dlib.Auth/EnrollMFA",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).EnrollMFA(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type Auth_GetUserPermsServer interface {
-	Send(*UserPermResponse) error
-	grpc.ServerStream
+func _Auth_ConfirmMFA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ConfirmMFA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dlib.Auth/ConfirmMFA",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ConfirmMFA(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type authGetUserPermsServer struct {
-	grpc.ServerStream
+func _Auth_DisableMFA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).DisableMFA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dlib.Auth/DisableMFA",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).DisableMFA(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *authGetUserPermsServer) Send(m *UserPermResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _Auth_CreateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(APIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).CreateAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dlib.Auth/CreateAPIKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).CreateAPIKey(ctx, req.(*APIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Auth_SaveUserPerms_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(AuthServer).SaveUserPerms(&authSaveUserPermsServer{stream})
+func _Auth_GetAPIKeys_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(APIKeyRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AuthServer).GetAPIKeys(m, &authGetAPIKeysServer{stream})
 }
 
-type Auth_SaveUserPermsServer interface {
-	Send(*UserPermResponse) error
-	Recv() (*UserPermRequest, error)
+type Auth_GetAPIKeysServer interface {
+	Send(*APIKeyResponse) error
 	grpc.ServerStream
 }
 
-type authSaveUserPermsServer struct {
+type authGetAPIKeysServer struct {
 	grpc.ServerStream
 }
 
-func (x *authSaveUserPermsServer) Send(m *UserPermResponse) error {
+func (x *authGetAPIKeysServer) Send(m *APIKeyResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func (x *authSaveUserPermsServer) Recv() (*UserPermRequest, error) {
-	m := new(UserPermRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
+func _Auth_RevokeAPIKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(APIKeyRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(AuthServer).RevokeAPIKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dlib.Auth/RevokeAPIKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RevokeAPIKeys(ctx, req.(*APIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Auth_DeleteUserPerms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UserPermRequest)
+func _Auth_Refresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TokenRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AuthServer).DeleteUserPerms(ctx, in)
+		return srv.(AuthServer).Refresh(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/dlib.Auth/DeleteUserPerms",
+		FullMethod: "/dlib.Auth/Refresh",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServer).DeleteUserPerms(ctx, req.(*UserPermRequest))
+		return srv.(AuthServer).Refresh(ctx, req.(*TokenRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Auth_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UserRequest)
+func _Auth_ScopeToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TokenRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AuthServer).Login(ctx, in)
+		return srv.(AuthServer).ScopeToken(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/dlib.Auth/Login",
+		FullMethod: "/dlib.Auth/ScopeToken",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServer).Login(ctx, req.(*UserRequest))
+		return srv.(AuthServer).ScopeToken(ctx, req.(*TokenRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Auth_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TokenRequest)
+func _Auth_Impersonate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImpersonateRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AuthServer).Logout(ctx, in)
+		return srv.(AuthServer).Impersonate(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/dlib.Auth/Logout",
+		FullMethod: "/dlib.Auth/Impersonate",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServer).Logout(ctx, req.(*TokenRequest))
+		return srv.(AuthServer).Impersonate(ctx, req.(*ImpersonateRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Auth_GetAuditEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AuditRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AuthServer).GetAuditEvents(m, &authGetAuditEventsServer{stream})
+}
+
+type Auth_GetAuditEventsServer interface {
+	Send(*AuditResponse) error
+	grpc.ServerStream
+}
+
+type authGetAuditEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *authGetAuditEventsServer) Send(m *AuditResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Auth_Auth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AuthRequest)
 	if err := dec(in); err != nil {
@@ -1581,6 +4224,24 @@ func _Auth_Auth_Handler(srv interface{}, ctx context.Context, dec func(interface
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Auth_AuthBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).AuthBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dlib.Auth/AuthBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).AuthBatch(ctx, req.(*AuthBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Auth_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "dlib.Auth",
 	HandlerType: (*AuthServer)(nil),
@@ -1601,6 +4262,22 @@ var _Auth_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteUserPerms",
 			Handler:    _Auth_DeleteUserPerms_Handler,
 		},
+		{
+			MethodName: "DeleteRoles",
+			Handler:    _Auth_DeleteRoles_Handler,
+		},
+		{
+			MethodName: "DeleteRolePerms",
+			Handler:    _Auth_DeleteRolePerms_Handler,
+		},
+		{
+			MethodName: "DeleteUserRoles",
+			Handler:    _Auth_DeleteUserRoles_Handler,
+		},
+		{
+			MethodName: "Unlock",
+			Handler:    _Auth_Unlock_Handler,
+		},
 		{
 			MethodName: "Login",
 			Handler:    _Auth_Login_Handler,
@@ -1609,10 +4286,46 @@ var _Auth_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Logout",
 			Handler:    _Auth_Logout_Handler,
 		},
+		{
+			MethodName: "EnrollMFA",
+			Handler:    _Auth_EnrollMFA_Handler,
+		},
+		{
+			MethodName: "ConfirmMFA",
+			Handler:    _Auth_ConfirmMFA_Handler,
+		},
+		{
+			MethodName: "DisableMFA",
+			Handler:    _Auth_DisableMFA_Handler,
+		},
+		{
+			MethodName: "CreateAPIKey",
+			Handler:    _Auth_CreateAPIKey_Handler,
+		},
+		{
+			MethodName: "RevokeAPIKeys",
+			Handler:    _Auth_RevokeAPIKeys_Handler,
+		},
+		{
+			MethodName: "Refresh",
+			Handler:    _Auth_Refresh_Handler,
+		},
+		{
+			MethodName: "ScopeToken",
+			Handler:    _Auth_ScopeToken_Handler,
+		},
+		{
+			MethodName: "Impersonate",
+			Handler:    _Auth_Impersonate_Handler,
+		},
 		{
 			MethodName: "Auth",
 			Handler:    _Auth_Auth_Handler,
 		},
+		{
+			MethodName: "AuthBatch",
+			Handler:    _Auth_AuthBatch_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1659,60 +4372,184 @@ var _Auth_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "GetRoles",
+			Handler:       _Auth_GetRoles_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SaveRoles",
+			Handler:       _Auth_SaveRoles_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "GetRolePerms",
+			Handler:       _Auth_GetRolePerms_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SaveRolePerms",
+			Handler:       _Auth_SaveRolePerms_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "GetUserRoles",
+			Handler:       _Auth_GetUserRoles_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SaveUserRoles",
+			Handler:       _Auth_SaveUserRoles_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "GetLockouts",
+			Handler:       _Auth_GetLockouts_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetAPIKeys",
+			Handler:       _Auth_GetAPIKeys_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetAuditEvents",
+			Handler:       _Auth_GetAuditEvents_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "ptypes/dlib.proto",
 }
 
-func init() { proto.RegisterFile("ptypes/dlib.proto", fileDescriptor_dlib_d3d99ff7dc552fce) }
-
-var fileDescriptor_dlib_d3d99ff7dc552fce = []byte{
-	// 762 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x54, 0xdd, 0x6e, 0xd3, 0x4c,
-	0x10, 0x8d, 0x13, 0x27, 0x6d, 0x26, 0x49, 0x7f, 0xb6, 0xfd, 0x2a, 0x2b, 0x37, 0x5f, 0x65, 0xa9,
-	0x55, 0x2e, 0x50, 0x52, 0x5a, 0x90, 0x2a, 0xb8, 0xa1, 0x6a, 0xa2, 0xaa, 0xa2, 0xb4, 0xc5, 0x0d,
-	0xbd, 0x44, 0x72, 0xc9, 0x10, 0xac, 0xda, 0xb1, 0xb1, 0x37, 0x15, 0x7d, 0x34, 0x5e, 0x80, 0x67,
-	0xe0, 0x3d, 0x78, 0x01, 0x34, 0xbb, 0xeb, 0x9f, 0x40, 0x6c, 0x1a, 0xe0, 0x82, 0xbb, 0xfd, 0x99,
-	0x39, 0xe7, 0xcc, 0x9c, 0xd9, 0x85, 0xf5, 0x80, 0xdf, 0x07, 0x18, 0xf5, 0x46, 0xae, 0x73, 0xd3,
-	0x0d, 0x42, 0x9f, 0xfb, 0x4c, 0xa7, 0x75, 0xfb, 0xff, 0xb1, 0xef, 0x8f, 0x5d, 0xec, 0x89, 0xb3,
-	0x9b, 0xe9, 0xfb, 0x1e, 0x77, 0x3c, 0x8c, 0xb8, 0xed, 0x05, 0x32, 0xcc, 0x44, 0x68, 0x0d, 0xc2,
-	0xd0, 0x0f, 0x2d, 0x8c, 0x02, 0x7f, 0x12, 0x21, 0x63, 0xa0, 0x1f, 0xfb, 0x23, 0x34, 0xb4, 0x6d,
-	0xad, 0x53, 0xb1, 0xc4, 0x9a, 0xad, 0x41, 0xe5, 0x55, 0x34, 0x36, 0xca, 0xdb, 0x5a, 0xa7, 0x6e,
-	0xd1, 0x92, 0x75, 0x41, 0x1f, 0x3a, 0x1e, 0x1a, 0x95, 0x6d, 0xad, 0xd3, 0xd8, 0x6f, 0x77, 0x25,
-	0x4d, 0x37, 0xa6, 0xe9, 0x0e, 0x63, 0x1a, 0x4b, 0xc4, 0x99, 0x5f, 0x35, 0x68, 0x59, 0x18, 0x4d,
-	0x5d, 0x6e, 0xe1, 0xcf, 0x3c, 0xf5, 0x94, 0xe7, 0xda, 0x76, 0x63, 0x9e, 0x6b, 0xdb, 0xa5, 0xa8,
-	0xe1, 0x7d, 0x20, 0x79, 0xea, 0x96, 0x58, 0x53, 0xd4, 0xf9, 0xd4, 0x33, 0x74, 0x21, 0x90, 0x96,
-	0xb1, 0xbe, 0x6a, 0xaa, 0x6f, 0x07, 0x2a, 0x83, 0x30, 0x34, 0x6a, 0x42, 0xde, 0x46, 0x57, 0xf4,
-	0x65, 0xa6, 0x4e, 0x8b, 0xee, 0x09, 0xbe, 0x6f, 0x73, 0xdb, 0x58, 0x92, 0xf0, 0xb4, 0x4e, 0x4a,
-	0x5b, 0x7e, 0x60, 0x69, 0x26, 0xac, 0xf4, 0xd1, 0x45, 0x8e, 0x49, 0x0b, 0x95, 0x40, 0x2d, 0x11,
-	0x68, 0xbe, 0x84, 0xc6, 0x25, 0x86, 0x9e, 0x85, 0x1f, 0xa7, 0x18, 0x71, 0xb6, 0x02, 0xe5, 0xd3,
-	0xbe, 0xba, 0x2f, 0x9f, 0xf6, 0x99, 0x01, 0x4b, 0x57, 0x18, 0xde, 0x39, 0xef, 0x50, 0xd5, 0x1e,
-	0x6f, 0x49, 0xe0, 0xb9, 0xed, 0x25, 0xf5, 0xd3, 0xda, 0x3c, 0x83, 0xa6, 0x04, 0x53, 0x74, 0x7f,
-	0x86, 0xf6, 0xa5, 0x0c, 0xcd, 0xa1, 0x7f, 0x8b, 0x93, 0x3c, 0x71, 0x9b, 0x50, 0x15, 0xf7, 0x0a,
-	0x4c, 0x6e, 0xd8, 0x16, 0xd4, 0xde, 0x44, 0x18, 0x9e, 0xf6, 0x05, 0x58, 0xc5, 0x52, 0x3b, 0xf6,
-	0x04, 0x96, 0x8e, 0x43, 0xb4, 0x39, 0x8e, 0x84, 0x41, 0xc5, 0x0d, 0x8c, 0x43, 0x29, 0x6b, 0xf0,
-	0x29, 0x70, 0x42, 0x8c, 0x84, 0x89, 0xbf, 0xc8, 0x52, 0xa1, 0x6c, 0x0f, 0xaa, 0x57, 0xdc, 0x0e,
-	0xb9, 0xb2, 0xb9, 0x28, 0x47, 0x06, 0xb2, 0x47, 0x50, 0x19, 0x4c, 0x46, 0xc2, 0xee, 0xe2, 0x78,
-	0x0a, 0xa3, 0xe8, 0x0b, 0x77, 0xf4, 0x80, 0x41, 0xa0, 0x30, 0xf3, 0xb3, 0x06, 0x2d, 0xd5, 0xc8,
-	0x1c, 0x63, 0xfe, 0xd9, 0x4e, 0x9a, 0x3e, 0x34, 0x88, 0x35, 0x6f, 0x04, 0x18, 0xe8, 0x74, 0xad,
-	0x74, 0x8b, 0x35, 0x9d, 0x5d, 0xda, 0x51, 0x14, 0xcf, 0x12, 0xad, 0x93, 0xf9, 0xd2, 0xd3, 0xf9,
-	0xa2, 0xa2, 0x07, 0x9e, 0xed, 0xb8, 0xea, 0x75, 0xca, 0x8d, 0x19, 0x40, 0x53, 0x12, 0xe6, 0xb4,
-	0xea, 0xef, 0x33, 0xbe, 0x86, 0x55, 0x42, 0x29, 0x7a, 0x86, 0xa9, 0x13, 0xe5, 0x19, 0x27, 0xb6,
-	0xa0, 0x46, 0x69, 0xa9, 0x43, 0x72, 0x67, 0x5a, 0xb0, 0x96, 0x42, 0xe6, 0x14, 0xb2, 0x28, 0xe6,
-	0x5b, 0x68, 0x1c, 0x4d, 0xf9, 0x87, 0x58, 0x62, 0x27, 0x1e, 0x19, 0x4d, 0x98, 0xc9, 0xe4, 0x4f,
-	0x96, 0x7d, 0xaf, 0xf1, 0x18, 0xed, 0x80, 0x4e, 0x10, 0x82, 0xa6, 0xb1, 0xbf, 0x2e, 0x03, 0x33,
-	0xd5, 0x5a, 0xe2, 0xda, 0x9c, 0x40, 0x53, 0xe2, 0xa7, 0x7a, 0x2f, 0x6e, 0x05, 0xfa, 0xb2, 0x55,
-	0xbe, 0xb8, 0x65, 0xbb, 0x99, 0xc6, 0x27, 0x7c, 0x59, 0xab, 0x94, 0x19, 0xbb, 0x8a, 0xae, 0x92,
-	0x8d, 0xcb, 0x76, 0x42, 0xf2, 0xed, 0x7f, 0xab, 0x81, 0x4e, 0x84, 0xec, 0x10, 0xea, 0x27, 0xc8,
-	0x85, 0xd6, 0x88, 0xcd, 0xa9, 0xa3, 0xbd, 0x31, 0x73, 0x26, 0x41, 0xcc, 0xd2, 0x9e, 0xc6, 0x9e,
-	0x03, 0x5c, 0xd9, 0x77, 0xb8, 0x70, 0x6a, 0x47, 0xdb, 0xd3, 0xd8, 0x33, 0x68, 0xca, 0xdf, 0xb9,
-	0x20, 0x7d, 0x53, 0x9e, 0xcd, 0xfe, 0xe2, 0x66, 0x89, 0x3d, 0x85, 0xe5, 0x13, 0xe4, 0x54, 0x6e,
-	0xc4, 0xd6, 0xb3, 0x9d, 0x90, 0x69, 0x73, 0x9a, 0x23, 0xf4, 0x1e, 0x42, 0x9d, 0xf4, 0x2e, 0x96,
-	0x27, 0xc4, 0x1e, 0x42, 0x43, 0x8a, 0xc8, 0xcd, 0x2d, 0x96, 0x4a, 0x1d, 0x4f, 0xd2, 0x32, 0xde,
-	0xb7, 0xe7, 0xf8, 0x93, 0x95, 0xba, 0x58, 0xde, 0xac, 0xd4, 0xdc, 0xdc, 0x3c, 0xa9, 0x47, 0xd0,
-	0x54, 0x5d, 0x95, 0xa9, 0xff, 0xa5, 0x55, 0x66, 0xd3, 0xb7, 0x7e, 0x3c, 0xce, 0xc8, 0xee, 0x43,
-	0x2b, 0xee, 0xf0, 0xef, 0x61, 0x88, 0x12, 0x5e, 0xc0, 0x6a, 0xda, 0xed, 0x42, 0x9c, 0xbc, 0x52,
-	0x1e, 0x43, 0xf5, 0xcc, 0x1f, 0x3b, 0x93, 0x79, 0x4e, 0xcd, 0x9f, 0x49, 0x76, 0x00, 0xb5, 0x33,
-	0x7f, 0xec, 0x4f, 0xf9, 0x02, 0x83, 0xcc, 0x7a, 0xea, 0x0d, 0x29, 0x9a, 0xcc, 0x07, 0x11, 0x3b,
-	0x94, 0x7d, 0xd3, 0x66, 0xe9, 0xb2, 0x74, 0x53, 0x13, 0xdf, 0xfd, 0xc1, 0xf7, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0x3a, 0xe5, 0x62, 0x89, 0x1a, 0x0a, 0x00, 0x00,
+func init() { proto.RegisterFile("ptypes/dlib.proto", fileDescriptor_dlib_9a24797b487a39f3) }
+
+var fileDescriptor_dlib_9a24797b487a39f3 = []byte{
+	// 1983 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x59, 0x4f, 0x6f, 0x23, 0x49,
+	0x15, 0x9f, 0x76, 0xfb, 0x5f, 0x3f, 0xdb, 0xf9, 0x53, 0x93, 0x0d, 0x96, 0x85, 0x44, 0xd4, 0x62,
+	0x87, 0x1c, 0x50, 0x26, 0xcc, 0x2c, 0xec, 0x68, 0x17, 0x56, 0x98, 0x38, 0x13, 0xac, 0xcd, 0x4c,
+	0xa2, 0x4e, 0xb2, 0x07, 0x24, 0x84, 0x3a, 0x76, 0x25, 0xd3, 0xa4, 0xdd, 0x6d, 0xba, 0xcb, 0xd1,
+	0x84, 0x0f, 0x81, 0x38, 0x80, 0xc4, 0x89, 0x1b, 0x07, 0x3e, 0x01, 0xe2, 0xc8, 0x19, 0x89, 0x13,
+	0x07, 0x2e, 0x5c, 0x40, 0x9c, 0x38, 0xf2, 0x05, 0xd0, 0xab, 0xaa, 0xee, 0xae, 0xb6, 0xbb, 0xdb,
+	0x6e, 0x6b, 0x47, 0xda, 0xb9, 0xd5, 0x2b, 0xbf, 0x57, 0xf5, 0xde, 0xaf, 0x7e, 0xf5, 0xea, 0xbd,
+	0x36, 0x6c, 0x4f, 0xd9, 0xc3, 0x94, 0x86, 0x4f, 0xc7, 0xae, 0x73, 0x7d, 0x30, 0x0d, 0x7c, 0xe6,
+	0x93, 0x2a, 0x8e, 0x7b, 0xdf, 0xb8, 0xf5, 0xfd, 0x5b, 0x97, 0x3e, 0xe5, 0x73, 0xd7, 0xb3, 0x9b,
+	0xa7, 0xcc, 0x99, 0xd0, 0x90, 0xd9, 0x93, 0xa9, 0x50, 0xeb, 0xed, 0xcd, 0x2b, 0xdc, 0x38, 0xd4,
+	0x1d, 0xff, 0x6c, 0x62, 0x87, 0x77, 0x42, 0xc3, 0xa4, 0xd0, 0x39, 0x0e, 0x02, 0x3f, 0xb0, 0x68,
+	0x38, 0xf5, 0xbd, 0x90, 0x12, 0x02, 0xd5, 0x23, 0x7f, 0x4c, 0xbb, 0xda, 0x9e, 0xb6, 0xaf, 0x5b,
+	0x7c, 0x4c, 0xb6, 0x40, 0x7f, 0x15, 0xde, 0x76, 0x2b, 0x7b, 0xda, 0xbe, 0x61, 0xe1, 0x90, 0x1c,
+	0x40, 0xf5, 0xd2, 0x99, 0xd0, 0xae, 0xbe, 0xa7, 0xed, 0xb7, 0x9e, 0xf5, 0x0e, 0xc4, 0x3e, 0x07,
+	0xd1, 0x3e, 0x07, 0x97, 0x91, 0x23, 0x16, 0xd7, 0x33, 0xff, 0xa1, 0x41, 0xc7, 0xa2, 0xe1, 0xcc,
+	0x65, 0x16, 0x5d, 0xdc, 0xc7, 0x48, 0xf6, 0xf9, 0xc2, 0x76, 0xa3, 0x7d, 0xbe, 0xb0, 0x5d, 0xd4,
+	0xba, 0x7c, 0x98, 0x8a, 0x7d, 0x0c, 0x8b, 0x8f, 0x51, 0xeb, 0xf5, 0x6c, 0xd2, 0xad, 0x72, 0x07,
+	0x71, 0x18, 0xf9, 0x57, 0x4b, 0xfc, 0xfb, 0x10, 0xf4, 0xe3, 0x20, 0xe8, 0xd6, 0xb9, 0x7b, 0x8f,
+	0x0f, 0x38, 0x72, 0xa9, 0x38, 0x2d, 0xfc, 0x1d, 0x97, 0x1f, 0xd8, 0xcc, 0xee, 0x36, 0xc4, 0xf2,
+	0x38, 0x8e, 0x43, 0x6b, 0xae, 0x18, 0x9a, 0x09, 0x1b, 0x03, 0xea, 0x52, 0x46, 0x63, 0x08, 0xa5,
+	0x83, 0x5a, 0xec, 0xa0, 0xf9, 0x3f, 0x0d, 0x5a, 0xe7, 0x34, 0x98, 0x58, 0xf4, 0x17, 0x33, 0x1a,
+	0x32, 0xb2, 0x01, 0x95, 0xe1, 0x40, 0x2a, 0x54, 0x86, 0x03, 0xd2, 0x85, 0xc6, 0x05, 0x0d, 0xee,
+	0x9d, 0x11, 0x95, 0xc1, 0x47, 0x22, 0x7a, 0xf8, 0xda, 0x9e, 0xc4, 0x00, 0xe0, 0x98, 0xf4, 0xa0,
+	0x79, 0x49, 0x3d, 0xdb, 0x63, 0xc3, 0x81, 0x44, 0x21, 0x96, 0xf1, 0xb7, 0x73, 0xfb, 0x96, 0x5e,
+	0x38, 0xbf, 0xa4, 0x1c, 0x0f, 0xdd, 0x8a, 0x65, 0xf2, 0x75, 0x30, 0x70, 0x7c, 0xe9, 0xdf, 0x51,
+	0x8f, 0x43, 0x63, 0x58, 0xc9, 0x04, 0xd9, 0x81, 0xda, 0x59, 0x30, 0xa6, 0x81, 0x04, 0x43, 0x08,
+	0xe4, 0x13, 0x80, 0xab, 0xe9, 0xd8, 0x66, 0xf4, 0x95, 0x1d, 0xde, 0xe5, 0x62, 0xf2, 0x12, 0x69,
+	0x85, 0x1a, 0x96, 0xa2, 0x6d, 0xfe, 0x4a, 0x83, 0xb6, 0x88, 0x5a, 0x02, 0xf3, 0xee, 0xc2, 0xfe,
+	0x26, 0x74, 0x5e, 0xd3, 0xb7, 0x2c, 0x09, 0x4f, 0x70, 0x21, 0x3d, 0x69, 0xfe, 0xbd, 0x0a, 0x6d,
+	0x3e, 0xca, 0x3b, 0x87, 0x1d, 0xa8, 0x09, 0x73, 0xe1, 0x8e, 0x10, 0xc8, 0x2e, 0xd4, 0xaf, 0x42,
+	0x1a, 0x0c, 0x07, 0xdc, 0x1d, 0xdd, 0x92, 0x12, 0xf9, 0x08, 0x1a, 0x47, 0x01, 0xb5, 0x19, 0x1d,
+	0x77, 0xab, 0x39, 0xc0, 0x24, 0x64, 0x89, 0x54, 0xd1, 0xea, 0xf8, 0xed, 0xd4, 0x09, 0x68, 0xd8,
+	0xad, 0x2d, 0xb7, 0x92, 0xaa, 0xe4, 0x10, 0x6a, 0x17, 0xcc, 0x0e, 0x58, 0xb7, 0xbe, 0xd4, 0x46,
+	0x28, 0x92, 0x6f, 0x83, 0x7e, 0xec, 0x8d, 0xbb, 0x8d, 0xa5, 0xfa, 0xa8, 0x86, 0xda, 0x67, 0xee,
+	0x78, 0x05, 0xd2, 0xa3, 0x5a, 0x12, 0xc3, 0xb8, 0x6b, 0xac, 0x1a, 0xc3, 0x18, 0xd1, 0xbd, 0x18,
+	0xf9, 0x53, 0xda, 0x85, 0x3d, 0x1d, 0xd1, 0xe5, 0x42, 0xea, 0x58, 0x5b, 0x05, 0x6c, 0x6e, 0x17,
+	0xb1, 0xb9, 0x93, 0xcb, 0xe6, 0x8d, 0x7c, 0x36, 0x6f, 0x96, 0x61, 0x33, 0x92, 0xb5, 0x3f, 0x62,
+	0x3e, 0xd2, 0x60, 0x8b, 0xbb, 0x12, 0x89, 0xe6, 0x7f, 0x2b, 0xd0, 0x91, 0xb4, 0xca, 0x21, 0xfa,
+	0x57, 0x97, 0x57, 0x7b, 0xd0, 0x7a, 0xf5, 0xb2, 0x8f, 0xf7, 0x81, 0x9f, 0x26, 0xb2, 0xab, 0x69,
+	0xa9, 0x53, 0x88, 0xf3, 0xd1, 0x1b, 0xdb, 0x75, 0xa9, 0x77, 0x4b, 0x65, 0x6e, 0x48, 0x26, 0x92,
+	0x33, 0x6d, 0xe6, 0x9d, 0xa9, 0xb1, 0xec, 0xaa, 0x42, 0xc6, 0x55, 0x55, 0xd1, 0x6e, 0xa5, 0xd1,
+	0xfe, 0x4f, 0x05, 0x5a, 0x08, 0x54, 0xde, 0x1d, 0x26, 0x50, 0xc5, 0x9f, 0x25, 0xd4, 0x7c, 0x8c,
+	0x73, 0xe7, 0x76, 0x18, 0x46, 0xe9, 0x04, 0xc7, 0x71, 0x8a, 0xa9, 0x2a, 0x29, 0x66, 0x07, 0x6a,
+	0xc7, 0x13, 0xdb, 0x71, 0x65, 0xfa, 0x10, 0x42, 0xfc, 0x54, 0xd5, 0x95, 0xa7, 0xaa, 0x18, 0x15,
+	0x35, 0xfe, 0x66, 0x01, 0xa7, 0x8d, 0x22, 0x4e, 0x43, 0x2e, 0xa7, 0x5b, 0xf9, 0x9c, 0x6e, 0x97,
+	0xe2, 0x34, 0xfa, 0x12, 0xf8, 0xf7, 0x0e, 0x2e, 0x2a, 0xae, 0x50, 0x2c, 0x9b, 0x7f, 0xd3, 0xa0,
+	0x2d, 0x70, 0xce, 0x21, 0xf5, 0x97, 0x0f, 0xb4, 0x0a, 0x5b, 0x7d, 0x19, 0x6d, 0x1a, 0x59, 0xb4,
+	0x51, 0x03, 0x6a, 0xce, 0x05, 0xf4, 0x67, 0x1d, 0x36, 0xd1, 0xc9, 0xa2, 0x87, 0x38, 0xb9, 0x92,
+	0x95, 0xd4, 0x95, 0xdc, 0x85, 0x3a, 0x9a, 0x25, 0x57, 0x55, 0x48, 0xef, 0xcb, 0x53, 0xac, 0x26,
+	0x07, 0x63, 0xf5, 0xe4, 0xb0, 0x03, 0xb5, 0x1f, 0xfb, 0xb3, 0x20, 0x94, 0x54, 0x14, 0x82, 0x28,
+	0x9a, 0x1e, 0x42, 0xc9, 0x42, 0x3e, 0xc6, 0xb9, 0x9f, 0xf8, 0x9e, 0x48, 0xd2, 0x86, 0xc5, 0xc7,
+	0x68, 0x7d, 0x34, 0x1c, 0x58, 0x61, 0xb7, 0x23, 0x52, 0x03, 0x17, 0xd4, 0xa7, 0x63, 0x63, 0xe5,
+	0xa7, 0xc3, 0xfc, 0x7d, 0x05, 0xb6, 0x92, 0xb3, 0xcb, 0x21, 0xe4, 0x97, 0x79, 0x78, 0x2b, 0x15,
+	0x14, 0x2a, 0xac, 0xf5, 0x35, 0x60, 0x6d, 0x64, 0xc1, 0xda, 0xcc, 0x80, 0xd5, 0xc8, 0x82, 0x15,
+	0x14, 0x58, 0xcd, 0x3f, 0x68, 0xd0, 0xb2, 0x7c, 0x97, 0x16, 0x64, 0x45, 0x7e, 0x09, 0x2b, 0xe9,
+	0x82, 0xea, 0xdc, 0x0e, 0xa8, 0xc7, 0x62, 0x64, 0x62, 0x79, 0x8e, 0x6c, 0xd5, 0xb2, 0x59, 0x25,
+	0xc6, 0xb5, 0x96, 0xc6, 0xd5, 0xfc, 0x39, 0xb4, 0x85, 0x9b, 0xf9, 0x49, 0xa5, 0x94, 0x9f, 0x05,
+	0x67, 0x68, 0xfe, 0x51, 0x83, 0x4d, 0xdc, 0x6c, 0xc9, 0x85, 0x47, 0x95, 0x84, 0x33, 0x42, 0xca,
+	0xe5, 0xcc, 0xbb, 0xc2, 0xc5, 0x83, 0xad, 0xc4, 0xd5, 0x7c, 0x7e, 0x97, 0xf2, 0x75, 0x19, 0x36,
+	0x3c, 0xbb, 0x17, 0x70, 0xa6, 0xe0, 0x3e, 0x49, 0x3f, 0xf4, 0x94, 0x1f, 0xef, 0x10, 0x9b, 0xc4,
+	0xd5, 0xf2, 0x77, 0x3f, 0xd3, 0xd7, 0x22, 0x6c, 0xfe, 0xa5, 0xc1, 0xc6, 0xa9, 0x3f, 0xba, 0xf3,
+	0x67, 0xac, 0x64, 0x91, 0xd1, 0x1f, 0x8f, 0x83, 0xe8, 0xed, 0xc3, 0x31, 0x6e, 0xf3, 0xd2, 0x76,
+	0xdc, 0x19, 0x66, 0x08, 0xb9, 0x4d, 0x24, 0x63, 0xa3, 0x79, 0x6a, 0x87, 0x6c, 0x85, 0x6a, 0x8d,
+	0xeb, 0x61, 0x0b, 0x70, 0xe5, 0x31, 0xc7, 0x5d, 0xa5, 0x05, 0xe0, 0x8a, 0xa9, 0x20, 0x1b, 0x73,
+	0x41, 0xfe, 0x5b, 0x83, 0xcd, 0x38, 0xc8, 0x72, 0x2f, 0xfc, 0x7b, 0x14, 0xe5, 0x4f, 0x65, 0x79,
+	0x2b, 0x03, 0xdc, 0x85, 0xfa, 0x05, 0x1d, 0x05, 0x94, 0xc9, 0xcf, 0x0e, 0x52, 0xc2, 0x8e, 0xfd,
+	0xca, 0x1a, 0x46, 0x1f, 0x1e, 0xae, 0xac, 0x21, 0xe6, 0x7f, 0x8b, 0x8e, 0xfc, 0x7b, 0x1a, 0x3c,
+	0x60, 0xbd, 0x87, 0x15, 0x0d, 0x66, 0xdb, 0xf4, 0xa4, 0xf9, 0x57, 0x0d, 0x3a, 0xfd, 0xf3, 0xe1,
+	0xe7, 0xf4, 0xa1, 0x4c, 0xde, 0xc5, 0xfb, 0x1a, 0xd0, 0x1b, 0xe7, 0xad, 0x04, 0x51, 0x4a, 0x0a,
+	0x87, 0xab, 0x29, 0x0e, 0xc7, 0x35, 0x76, 0x4d, 0xad, 0xb1, 0xd7, 0x7b, 0x7b, 0x8a, 0xc0, 0xfa,
+	0x4d, 0x05, 0x36, 0xa2, 0x68, 0x4a, 0xa4, 0xe7, 0xbc, 0x70, 0xb6, 0x40, 0xff, 0x9c, 0x3e, 0xc8,
+	0xb2, 0x0f, 0x87, 0x4a, 0x80, 0xb5, 0xec, 0x00, 0xeb, 0x73, 0x01, 0x46, 0x6d, 0x50, 0x63, 0xad,
+	0x36, 0xa8, 0xb9, 0x1e, 0x2c, 0x73, 0x0d, 0x8b, 0xf9, 0xeb, 0x0a, 0xb4, 0xfb, 0xb3, 0xb1, 0x13,
+	0x27, 0x03, 0xa5, 0x37, 0xd1, 0x52, 0xbd, 0x09, 0x06, 0xc2, 0x87, 0x51, 0x9f, 0xc7, 0x85, 0xd4,
+	0xe2, 0x7a, 0x7a, 0x71, 0x84, 0xa4, 0x3f, 0x62, 0x8e, 0xef, 0x49, 0x9c, 0xa4, 0x94, 0xf4, 0xfb,
+	0xb5, 0x92, 0xfd, 0x7e, 0x7d, 0xb5, 0x7e, 0x7f, 0x07, 0x6a, 0xa7, 0xce, 0xc4, 0x61, 0x92, 0x04,
+	0x42, 0x20, 0x4f, 0x60, 0x63, 0x38, 0x99, 0xd2, 0x20, 0xf4, 0x3d, 0x5b, 0x04, 0x28, 0xba, 0x97,
+	0xb9, 0x59, 0xf3, 0x9f, 0x15, 0xe8, 0x48, 0x48, 0x16, 0x88, 0x62, 0x44, 0x9f, 0x76, 0x22, 0x8c,
+	0x2a, 0x39, 0x18, 0xe9, 0x79, 0x18, 0x55, 0x73, 0x31, 0xaa, 0xa5, 0x30, 0xda, 0x85, 0xfa, 0xa5,
+	0x1d, 0xdc, 0x52, 0x26, 0x2b, 0x68, 0x29, 0xe1, 0xde, 0x67, 0x33, 0x36, 0xf2, 0x27, 0x51, 0x67,
+	0x16, 0x89, 0x68, 0x31, 0xa0, 0x0c, 0xfb, 0x0e, 0x51, 0x65, 0x49, 0x29, 0x4e, 0x6a, 0x86, 0x92,
+	0xd4, 0xa2, 0xef, 0x80, 0xb0, 0xda, 0x77, 0xc0, 0x0c, 0xec, 0x5a, 0x59, 0xd8, 0x11, 0x13, 0xda,
+	0xea, 0x8c, 0x2c, 0x99, 0x53, 0x73, 0xa6, 0x0b, 0x24, 0x91, 0xe3, 0xf7, 0x39, 0xfe, 0x8a, 0xa0,
+	0x65, 0x7f, 0x45, 0x48, 0xbf, 0x7c, 0x5b, 0xa0, 0x5f, 0x5e, 0x9e, 0x4a, 0xc2, 0xe1, 0x10, 0x35,
+	0x2d, 0x6a, 0x87, 0x09, 0xd7, 0x84, 0x84, 0xdf, 0xe9, 0x5a, 0xfd, 0x19, 0x7b, 0x13, 0xed, 0xb3,
+	0xaf, 0xee, 0xd3, 0x7a, 0x46, 0xc4, 0xe7, 0x53, 0xf5, 0xc3, 0x59, 0xb4, 0xf7, 0x87, 0x50, 0xc5,
+	0x5a, 0x83, 0xef, 0xdc, 0x7a, 0xb6, 0x2d, 0x14, 0x95, 0x72, 0xcb, 0xe2, 0x3f, 0x17, 0x5e, 0x80,
+	0x08, 0xfa, 0x6a, 0x02, 0xbd, 0xf9, 0x3b, 0x0d, 0xda, 0xc2, 0xa1, 0x84, 0x5d, 0x67, 0x77, 0xdc,
+	0x9d, 0xa6, 0x55, 0x39, 0xbb, 0x23, 0x4f, 0x94, 0x87, 0x29, 0x76, 0x50, 0x6d, 0x56, 0xe5, 0x63,
+	0xf5, 0x44, 0xfa, 0xa7, 0xab, 0x7a, 0x6a, 0x8d, 0x25, 0x1d, 0xdc, 0x8f, 0x38, 0x59, 0xcd, 0x5d,
+	0x50, 0x28, 0x98, 0xbf, 0xd5, 0x60, 0x0b, 0x5d, 0xfb, 0x91, 0xcd, 0x46, 0x6b, 0x00, 0xf6, 0x2d,
+	0xa8, 0xe1, 0x86, 0x61, 0xb7, 0xb2, 0xa7, 0x67, 0x23, 0x26, 0x7e, 0x2f, 0x0d, 0x99, 0x2f, 0x10,
+	0x1b, 0xd0, 0x91, 0x13, 0x3a, 0x7e, 0x72, 0x32, 0x5a, 0xf1, 0xc9, 0x08, 0x60, 0x2b, 0x31, 0xb0,
+	0xfb, 0x50, 0x3b, 0x09, 0x6c, 0x8f, 0x15, 0x20, 0x26, 0x14, 0xcc, 0xbf, 0x68, 0xb0, 0xad, 0x00,
+	0x21, 0x0f, 0x2a, 0x3a, 0x18, 0x6d, 0xc9, 0xc1, 0x1c, 0x82, 0x11, 0xb9, 0x1a, 0x61, 0x21, 0x95,
+	0xd5, 0x28, 0xac, 0x44, 0x09, 0x3d, 0x13, 0xc8, 0xe9, 0xaa, 0x76, 0xda, 0x33, 0x01, 0xdd, 0xca,
+	0x87, 0xf9, 0xec, 0x4f, 0xdb, 0x50, 0xc5, 0xfd, 0xc8, 0x0b, 0x30, 0x4e, 0x28, 0xe3, 0x47, 0x14,
+	0x92, 0x8c, 0xe3, 0xeb, 0x3d, 0x4e, 0xcd, 0x89, 0x55, 0xcc, 0x47, 0x87, 0x1a, 0xf9, 0x14, 0xe0,
+	0xc2, 0xbe, 0xa7, 0xa5, 0x4d, 0xf7, 0xb5, 0x43, 0x8d, 0x7c, 0x02, 0x6d, 0xf1, 0xd7, 0x41, 0x81,
+	0xf9, 0x8e, 0x98, 0x4b, 0xff, 0xc5, 0x60, 0x3e, 0x22, 0xdf, 0x85, 0xe6, 0x09, 0x65, 0x18, 0x55,
+	0x48, 0xb6, 0xd5, 0x10, 0x85, 0x59, 0x46, 0xd4, 0xdc, 0xdf, 0x17, 0x60, 0xa0, 0xbf, 0xe5, 0xec,
+	0xb8, 0xb3, 0x2f, 0xa0, 0x25, 0x9c, 0xc8, 0xb5, 0x2d, 0x76, 0x55, 0x1c, 0xce, 0x22, 0x13, 0x7b,
+	0x19, 0x47, 0xa9, 0xba, 0x5a, 0xce, 0x2e, 0xed, 0x6a, 0xae, 0x6d, 0x9e, 0xab, 0x7d, 0x68, 0x4b,
+	0x54, 0x85, 0xe9, 0x07, 0x49, 0x94, 0xaa, 0xf9, 0xee, 0xfc, 0xb4, 0xe2, 0xf6, 0x00, 0x3a, 0x11,
+	0xc2, 0xeb, 0xad, 0xc1, 0x43, 0xf8, 0x21, 0x6c, 0x26, 0x68, 0x17, 0xae, 0x53, 0x8c, 0x3a, 0xb6,
+	0x3b, 0x31, 0x02, 0x4a, 0xb3, 0xd7, 0x23, 0xea, 0xd4, 0x22, 0xea, 0xe5, 0xec, 0xd2, 0xa8, 0xe7,
+	0xda, 0x16, 0xa3, 0x1e, 0xf5, 0xbf, 0x71, 0xa4, 0x73, 0xbd, 0x7b, 0x6f, 0x77, 0x7e, 0x7a, 0x11,
+	0xf5, 0xf5, 0xd7, 0x48, 0xa3, 0xbe, 0x74, 0x9d, 0xe5, 0x04, 0x12, 0x28, 0x28, 0x87, 0xa6, 0x22,
+	0xb1, 0x3b, 0x3f, 0x9d, 0x4d, 0xa0, 0xf5, 0xd6, 0x58, 0x24, 0x50, 0xe1, 0x3a, 0x79, 0xa1, 0x7c,
+	0x06, 0xad, 0x13, 0xca, 0x64, 0x8f, 0x18, 0x12, 0xa9, 0x96, 0x6e, 0x8c, 0x7b, 0x1f, 0xcc, 0xcd,
+	0x2a, 0x71, 0x7c, 0x0f, 0xea, 0x57, 0x9e, 0xeb, 0x8f, 0xee, 0x72, 0x4c, 0xf3, 0xf6, 0xfd, 0x0e,
+	0xd4, 0x4e, 0xfd, 0x5b, 0xc7, 0xcb, 0x4a, 0x31, 0xd9, 0xc9, 0x94, 0x3c, 0x87, 0xfa, 0xa9, 0x7f,
+	0xeb, 0xcf, 0x58, 0x89, 0x0c, 0x4c, 0x9e, 0x83, 0x71, 0xec, 0x05, 0xbe, 0xeb, 0xbe, 0x7a, 0xd9,
+	0xcf, 0xda, 0x4b, 0x4e, 0x29, 0xfd, 0x23, 0xbf, 0x55, 0x70, 0xe4, 0x7b, 0x37, 0x4e, 0x30, 0xc9,
+	0xb1, 0xca, 0x4c, 0xa0, 0xe4, 0x63, 0x80, 0x81, 0x13, 0xda, 0xd7, 0x2e, 0xcd, 0x31, 0xcb, 0x03,
+	0xe3, 0x53, 0x68, 0x8b, 0xce, 0x46, 0x34, 0x66, 0x44, 0xc6, 0x92, 0x6a, 0x3a, 0x7b, 0x3b, 0xe9,
+	0x49, 0xc5, 0x18, 0x4e, 0x28, 0x13, 0xd3, 0x61, 0x29, 0xd3, 0x43, 0x8d, 0x7c, 0x1f, 0x3b, 0xe0,
+	0x7b, 0xff, 0x8e, 0xae, 0x62, 0xbf, 0xe0, 0xf7, 0x47, 0xd0, 0xb0, 0xe8, 0x4d, 0x40, 0xc3, 0x37,
+	0x65, 0x8e, 0xe4, 0x63, 0x00, 0xde, 0xfb, 0xf1, 0xf9, 0x32, 0x86, 0x9f, 0x41, 0x4b, 0x29, 0x98,
+	0x49, 0x57, 0x68, 0x2d, 0xd6, 0xd0, 0x79, 0xf6, 0x3f, 0x80, 0x0d, 0x44, 0x0a, 0x5b, 0x9a, 0xe3,
+	0x7b, 0xea, 0xb1, 0xf8, 0x2d, 0x56, 0x1b, 0xbf, 0xde, 0xe3, 0xd4, 0x9c, 0x82, 0xd5, 0x53, 0x59,
+	0x47, 0x6c, 0x27, 0x35, 0xcc, 0x1c, 0x1f, 0xd4, 0x72, 0x96, 0xfb, 0x6b, 0xc4, 0xc5, 0x13, 0xd9,
+	0x4d, 0x54, 0xd4, 0xb2, 0xb2, 0xf7, 0xb5, 0x85, 0xf9, 0xc8, 0xfe, 0xfc, 0xd1, 0xb9, 0x76, 0x5d,
+	0xe7, 0xad, 0xc8, 0xf3, 0xff, 0x0f, 0x00, 0xa0, 0x8d, 0xb8, 0xcf, 0x1f, 0x22, 0x00, 0x00,
 }
@@ -0,0 +1,181 @@
+package dlib
+
+import (
+	"reflect"
+)
+
+// Redactor is an interface describing types that contain secret values,
+// such as passwords, which must not appear in logs or API output. Redact
+// returns a copy of the value with all secret values removed.
+type Redactor interface {
+	Redact() interface{}
+}
+
+// redactorType is the reflected type of the Redactor interface.
+var redactorType = reflect.TypeOf((*Redactor)(nil)).Elem()
+
+// Redact returns a copy of a value with its secret values removed. Values
+// implementing Redactor are redacted by their Redact method. Otherwise,
+// fields tagged `secret:"true"` are set to their zero value. Structs,
+// pointers, slices, arrays, maps and interfaces are redacted recursively,
+// so secrets held by nested values are also removed, and the parts of the
+// value which hold secrets are copied rather than modified. All other
+// values are returned unchanged.
+func Redact(v interface{}) interface{} {
+	if r, ok := v.(Redactor); ok {
+		return r.Redact()
+	}
+
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || !hasSecrets(rv.Type(), map[reflect.Type]bool{}) {
+		return v
+	}
+
+	return redactValue(rv, map[uintptr]reflect.Value{}).Interface()
+}
+
+// hasSecrets tests whether values of a type may hold secrets, either in
+// fields tagged as secret or in values implementing Redactor. Interfaces
+// may hold any value, so they are assumed to. Types already visited are
+// assumed not to, so that recursive types terminate.
+func hasSecrets(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+
+	seen[t] = true
+	if t.Implements(redactorType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return hasSecrets(t.Elem(), seen)
+	case reflect.Map:
+		return hasSecrets(t.Key(), seen) || hasSecrets(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Tag.Get("secret") == "true" || hasSecrets(f.Type, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// redactValue returns a copy of a value with its secret values removed.
+// Pointers already copied are mapped to their copies, so that shared and
+// cyclic values are copied once.
+func redactValue(v reflect.Value, seen map[uintptr]reflect.Value) reflect.Value {
+	if !hasSecrets(v.Type(), map[reflect.Type]bool{}) {
+		return v
+	}
+
+	if r, ok := redactor(v); ok {
+		return r
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+
+		if cp, ok := seen[v.Pointer()]; ok {
+			return cp
+		}
+
+		cp := reflect.New(v.Type().Elem())
+		seen[v.Pointer()] = cp
+		cp.Elem().Set(redactValue(v.Elem(), seen))
+		return cp
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(redactValue(v.Elem(), seen))
+		return cp
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		cp := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			cp.Index(i).Set(redactValue(v.Index(i), seen))
+		}
+
+		return cp
+	case reflect.Array:
+		cp := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			cp.Index(i).Set(redactValue(v.Index(i), seen))
+		}
+
+		return cp
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		cp := reflect.MakeMap(v.Type())
+		for _, k := range v.MapKeys() {
+			cp.SetMapIndex(redactValue(k, seen), redactValue(v.MapIndex(k), seen))
+		}
+
+		return cp
+	case reflect.Struct:
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			f := cp.Field(i)
+			if !f.CanSet() {
+				continue
+			}
+
+			if v.Type().Field(i).Tag.Get("secret") == "true" {
+				f.Set(reflect.Zero(f.Type()))
+				continue
+			}
+
+			f.Set(redactValue(v.Field(i), seen))
+		}
+
+		return cp
+	default:
+		return v
+	}
+}
+
+// redactor redacts a value implementing Redactor by its Redact method. It
+// returns false if the value does not implement Redactor, can not be used
+// without exposing unexported fields, or is redacted to a value which can
+// not replace it.
+func redactor(v reflect.Value) (reflect.Value, bool) {
+	if v.Kind() == reflect.Interface || !v.CanInterface() ||
+		!v.Type().Implements(redactorType) || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return v, false
+	}
+
+	r := reflect.ValueOf(v.Interface().(Redactor).Redact())
+	switch {
+	case !r.IsValid():
+		return v, false
+	case r.Type().AssignableTo(v.Type()):
+		return r, true
+	case r.Kind() == reflect.Ptr && !r.IsNil() && r.Type().Elem() == v.Type():
+		return r.Elem(), true
+	case v.Kind() == reflect.Ptr && r.Type() == v.Type().Elem():
+		cp := reflect.New(r.Type())
+		cp.Elem().Set(r)
+		return cp, true
+	default:
+		return v, false
+	}
+}
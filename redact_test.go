@@ -0,0 +1,108 @@
+package dlib
+
+import (
+	"testing"
+)
+
+type FakeSecret struct {
+	User string
+	Pass string `secret:"true"`
+	Key  []byte `secret:"true"`
+}
+
+type FakeRedactor struct {
+	Val string
+}
+
+func (fk FakeRedactor) Redact() interface{} {
+	return FakeRedactor{Val: "redacted"}
+}
+
+func TestRedact(t *testing.T) {
+	s := FakeSecret{User: "test", Pass: "test", Key: []byte("test")}
+	cases := []struct {
+		v   interface{}
+		exp interface{}
+	}{
+		{"test", "test"},
+		{1, 1},
+		{FakeRedactor{Val: "test"}, FakeRedactor{Val: "redacted"}},
+		{Error{Code: 1, Msg: "test"}, Error{Code: 1, Msg: "test"}},
+		{nil, nil},
+	}
+
+	for _, c := range cases {
+		if r := Redact(c.v); r != c.exp {
+			t.Errorf("Value expected: %v, got: %v", c.exp, r)
+		}
+	}
+
+	r := Redact(s).(FakeSecret)
+	if r.User != "test" || r.Pass != "" || r.Key != nil {
+		t.Errorf("Expected redacted value, got: %v", r)
+	}
+
+	rp := Redact(&s).(*FakeSecret)
+	if rp == &s || rp.User != "test" || rp.Pass != "" || rp.Key != nil {
+		t.Errorf("Expected redacted copy, got: %v", rp)
+	}
+
+	if s.Pass != "test" {
+		t.Errorf("Value expected: test, got: %v", s.Pass)
+	}
+}
+
+type FakeNestedSecret struct {
+	Name    string
+	Secret  FakeSecret
+	Ptr     *FakeSecret
+	List    []FakeSecret
+	Map     map[string]interface{}
+	Entry   interface{}
+	Next    *FakeNestedSecret
+	Skipped []string
+}
+
+func TestRedactNested(t *testing.T) {
+	s := FakeSecret{User: "test", Pass: "test"}
+	n := FakeNestedSecret{
+		Name:    "test",
+		Secret:  s,
+		Ptr:     &s,
+		List:    []FakeSecret{s},
+		Map:     map[string]interface{}{"s": s, "r": FakeRedactor{Val: "test"}},
+		Entry:   []*FakeSecret{&s},
+		Skipped: []string{"test"},
+	}
+
+	n.Next = &n
+	r := Redact(&n).(*FakeNestedSecret)
+	if r == &n || r.Name != "test" || r.Skipped[0] != "test" {
+		t.Errorf("Expected redacted copy, got: %v", r)
+	}
+
+	secrets := []FakeSecret{r.Secret, *r.Ptr, r.List[0], r.Map["s"].(FakeSecret),
+		*r.Entry.([]*FakeSecret)[0]}
+	for _, v := range secrets {
+		if v.User != "test" || v.Pass != "" {
+			t.Errorf("Expected redacted value, got: %v", v)
+		}
+	}
+
+	if v := r.Map["r"].(FakeRedactor); v.Val != "redacted" {
+		t.Errorf("Value expected: redacted, got: %v", v.Val)
+	}
+
+	if r.Next != r {
+		t.Errorf("Expected cyclic pointer to the copy, got: %p", r.Next)
+	}
+
+	if s.Pass != "test" || n.Ptr.Pass != "test" || n.List[0].Pass != "test" {
+		t.Errorf("Expected unchanged original, got: %v", n)
+	}
+
+	l := Redact([]FakeSecret{s}).([]FakeSecret)
+	if l[0].Pass != "" {
+		t.Errorf("Expected redacted value, got: %v", l[0])
+	}
+}
@@ -7,6 +7,7 @@ import (
 // SQLRows types represent cursors iterating over SQL query results.
 type SQLRows interface {
 	Close() error
+	Err() error
 	Next() bool
 	Scan(dest ...interface{}) error
 }